@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/infra/storage"
+	"github.com/emur-uy/backend/internal/pkg/service/media"
+)
+
+// mediagc deletes the media joined to no record and the stored objects of no media, older than the grace period,
+// as the worker does every day. Run it with -dry-run to list the orphans before deleting them.
+func main() {
+	gracePeriod := flag.Duration("grace", media.OrphanGracePeriod, "age of the orphans to delete, longer than the upload sessions")
+	dryRun := flag.Bool("dry-run", false, "report the orphaned media without deleting them")
+	flag.Parse()
+
+	cfg := config.Get()
+	postgresql.Connect()
+	defer func() {
+		dbInstance, _ := postgresql.Db.DB()
+		_ = dbInstance.Close()
+	}()
+
+	repo := postgresql.NewClient()
+	service := media.NewService(postgresql.NewMediaRepository(repo), storage.NewBlobStore(cfg))
+
+	report, err := service.CollectOrphans(time.Now().UTC(), *gracePeriod, *dryRun)
+	if report != nil {
+		log.Printf("orphaned media (dry run: %t): %d media, %d deleted; scanned %d objects, %d orphans of %d bytes, %d deleted, %d recent; failed %d",
+			report.DryRun, report.OrphanMedia, report.DeletedMedia, report.ScannedObjects, report.OrphanObjects, report.OrphanBytes,
+			report.DeletedObjects, report.Recent, report.Failed)
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/infra/storage"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/service/media"
+)
+
+// privatemedia makes private the files uploaded public before the media of their domain were private,
+// the attachments of the reminders. The new uploads are private already, and the API returns signed URLs
+// for every media of the domain, so the command can run after the deploy. Run it until nothing fails.
+func main() {
+	domain := flag.String("domain", entity.MediaDomainReminders, "domain of the media to make private")
+	batchSize := flag.Int("batch", media.DefaultPrivacyBatchSize, "number of media read per batch")
+	dryRun := flag.Bool("dry-run", false, "report the files to make private without changing them")
+	flag.Parse()
+
+	if !media.IsPrivateDomain(*domain) {
+		log.Fatalf("the media of %q are public, making them private would break their URLs", *domain)
+	}
+
+	cfg := config.Get()
+	postgresql.Connect()
+	defer func() {
+		dbInstance, _ := postgresql.Db.DB()
+		_ = dbInstance.Close()
+	}()
+
+	repo := postgresql.NewClient()
+	service := media.NewService(postgresql.NewMediaRepository(repo), storage.NewBlobStore(cfg))
+
+	report, err := service.MakeDomainPrivate(*domain, *batchSize, *dryRun)
+	log.Printf("private media of %s (dry run: %t): scanned %d, made private %d, missing %d, failed %d",
+		report.Domain, *dryRun, report.Scanned, report.Updated, report.Missing, report.Failed)
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
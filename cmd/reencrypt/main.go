@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/infra/mailer"
+	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/pkg/fieldcrypt"
+	"github.com/emur-uy/backend/internal/pkg/service/user"
+)
+
+// reencrypt migrates the personal data of the users to the primary key of the keyring.
+// To rotate the key, add the new key to ENCRYPTION_KEYS, set it as ENCRYPTION_KEY_ID, deploy,
+// and run this command. The old key can be removed once a run reports nothing left to re-encrypt.
+// The first run also migrates the values written before the keyring, after it ENCRYPTION_LEGACY_DISABLED
+// can be set to stop decrypting them with ENCRYPTION_KEY.
+func main() {
+	batchSize := flag.Int("batch", user.DefaultReencryptionBatchSize, "number of users re-encrypted per batch")
+	dryRun := flag.Bool("dry-run", false, "report the users to re-encrypt without writing them")
+	flag.Parse()
+
+	cfg := config.Get()
+	keyring, err := fieldcrypt.FromConfig(cfg)
+	if err != nil {
+		log.Fatalf("invalid encryption keys: %s", err)
+	}
+
+	postgresql.Connect()
+	defer func() {
+		dbInstance, _ := postgresql.Db.DB()
+		_ = dbInstance.Close()
+	}()
+
+	repo := postgresql.NewClient()
+	service := user.NewService(repo, postgresql.NewTokenRepository(repo), postgresql.NewLoginAttemptRepository(repo), postgresql.NewRoleRepository(repo), mailer.NewMailer(cfg))
+
+	report, err := service.ReencryptUsers(keyring, *batchSize, *dryRun)
+	log.Printf("re-encryption with key %s (dry run: %t): scanned %d, re-encrypted %d, skipped %d, failed %d",
+		keyring.PrimaryKeyID(), *dryRun, report.Scanned, report.Reencrypted, report.Skipped, report.Failed)
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/infra/mailer"
+	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/pkg/service/user"
+)
+
+// reindex recomputes the blind indexes of the user names used by the admin search.
+// It must be run once after the indexes are introduced, and again if BLIND_INDEX_KEY changes.
+func main() {
+	batchSize := flag.Int("batch", user.DefaultReencryptionBatchSize, "number of users indexed per batch")
+	flag.Parse()
+
+	postgresql.Connect()
+	defer func() {
+		dbInstance, _ := postgresql.Db.DB()
+		_ = dbInstance.Close()
+	}()
+
+	repo := postgresql.NewClient()
+	service := user.NewService(repo, postgresql.NewTokenRepository(repo), postgresql.NewLoginAttemptRepository(repo), postgresql.NewRoleRepository(repo), mailer.NewMailer(config.Get()))
+
+	indexed, err := service.ReindexUsers(*batchSize)
+	log.Printf("indexed %d users", indexed)
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
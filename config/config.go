@@ -27,15 +27,87 @@ type Config struct {
 	SecretKey        string `mapstructure:"SECRET_KEY"`
 	JWTTokenKey      string `mapstructure:"JWT_TOKEN_KEY"`
 	JWTTokenExpired  int    `mapstructure:"JWT_TOKEN_EXPIRED"`
-	AwsBucketName    string `mapstructure:"AWS_BUCKET_NAME"`
-	AwsFolderName    string `mapstructure:"AWS_FOLDER_NAME"`
-	AwsRegionName    string `mapstructure:"AWS_REGION_NAME"`
-	AwsAccessKey     string `mapstructure:"AWS_ACCESS_KEY"`
-	AwsSecretKey     string `mapstructure:"AWS_SECRET_KEY"`
-	AwsEndpoint      string `mapstructure:"AWS_ENDPOINT"`
-	ForecastKey      string `mapstructure:"FORECAST_KEY"`
-	ForecastAPI      string `mapstructure:"FORECAST_API"`
-	EncryptionKey    string `mapstructure:"ENCRYPTION_KEY"`
+	// RefreshTokenExpired is the lifetime of the refresh tokens in hours.
+	RefreshTokenExpired int    `mapstructure:"REFRESH_TOKEN_EXPIRED"`
+	AwsBucketName       string `mapstructure:"AWS_BUCKET_NAME"`
+	AwsFolderName       string `mapstructure:"AWS_FOLDER_NAME"`
+	AwsRegionName       string `mapstructure:"AWS_REGION_NAME"`
+	AwsAccessKey        string `mapstructure:"AWS_ACCESS_KEY"`
+	AwsSecretKey        string `mapstructure:"AWS_SECRET_KEY"`
+	AwsEndpoint         string `mapstructure:"AWS_ENDPOINT"`
+	ForecastKey         string `mapstructure:"FORECAST_KEY"`
+	ForecastAPI         string `mapstructure:"FORECAST_API"`
+	// ForecastProvider selects the weather provider, "weatherapi" (default) or "file" to read fixtures from ForecastFixturesDir.
+	ForecastProvider    string `mapstructure:"FORECAST_PROVIDER"`
+	ForecastFixturesDir string `mapstructure:"FORECAST_FIXTURES_DIR"`
+	// ForecastTimeout is the timeout of the weather API requests in seconds.
+	ForecastTimeout int    `mapstructure:"FORECAST_TIMEOUT"`
+	EncryptionKey   string `mapstructure:"ENCRYPTION_KEY"`
+	// EncryptionKeys is the keyring of the personal data, as comma separated "id:base64key" entries, encrypting with EncryptionKeyID.
+	// Without it, the key of the keyring is derived from EncryptionKey, which is kept to read the values written before the keyring.
+	EncryptionKeys  string `mapstructure:"ENCRYPTION_KEYS"`
+	EncryptionKeyID string `mapstructure:"ENCRYPTION_KEY_ID"`
+	// EncryptionLegacyDisabled stops decrypting the values written before the keyring with EncryptionKey,
+	// to be set once cmd/reencrypt reports nothing left to re-encrypt.
+	EncryptionLegacyDisabled bool `mapstructure:"ENCRYPTION_LEGACY_DISABLED"`
+	// BlindIndexKey is the HMAC key of the search indexes of the encrypted fields.
+	BlindIndexKey string `mapstructure:"BLIND_INDEX_KEY"`
+
+	HeatAlertMaxTemperature int `mapstructure:"HEAT_ALERT_MAX_TEMPERATURE"`
+	HeatAlertUV             int `mapstructure:"HEAT_ALERT_UV"`
+	ForecastRetentionDays   int `mapstructure:"FORECAST_RETENTION_DAYS"`
+
+	// AppURL is the public URL of the API, used to build the links sent by email.
+	AppURL string `mapstructure:"APP_URL"`
+	// PasswordResetURL is the page of the app where users set a new password, the reset token is appended to it.
+	PasswordResetURL string `mapstructure:"PASSWORD_RESET_URL"`
+	// RequireEmailVerification blocks the login of the users that did not verify their email.
+	RequireEmailVerification bool `mapstructure:"REQUIRE_EMAIL_VERIFICATION"`
+	// MailerProvider selects how emails are sent, "smtp" or "file" (default), which writes them to MailerDir or to the log.
+	MailerProvider string `mapstructure:"MAILER_PROVIDER"`
+	MailerDir      string `mapstructure:"MAILER_DIR"`
+	MailFrom       string `mapstructure:"MAIL_FROM"`
+	SMTPHost       string `mapstructure:"SMTP_HOST"`
+	SMTPPort       int    `mapstructure:"SMTP_PORT"`
+	SMTPUser       string `mapstructure:"SMTP_USER"`
+	SMTPPassword   string `mapstructure:"SMTP_PASS"`
+
+	// LoginMaxFailures and LoginIPMaxFailures are the failed logins of an email or an IP that lock them for LoginLockoutMinutes.
+	LoginMaxFailures    int `mapstructure:"LOGIN_MAX_FAILURES"`
+	LoginIPMaxFailures  int `mapstructure:"LOGIN_IP_MAX_FAILURES"`
+	LoginLockoutMinutes int `mapstructure:"LOGIN_LOCKOUT_MINUTES"`
+	// TrustedProxies are the comma separated IPs or CIDRs of the proxies whose X-Forwarded-For header gives the
+	// client IP. Without any, the IP of the connection is used, so the header cannot be forged to get around the
+	// login throttling.
+	TrustedProxies string `mapstructure:"TRUSTED_PROXIES"`
+
+	// StorageProvider selects where the uploaded files are kept, "spaces" or "local" to write them to StorageDir and
+	// serve them from the API. It defaults to "spaces" when AwsBucketName is set, so it runs without cloud credentials.
+	StorageProvider string `mapstructure:"STORAGE_PROVIDER"`
+	StorageDir      string `mapstructure:"STORAGE_DIR"`
+	// MediaGCDryRun makes the job collecting the orphaned media only report them, without deleting them.
+	MediaGCDryRun bool `mapstructure:"MEDIA_GC_DRY_RUN"`
+}
+
+// DefaultMediaFolder is the folder of the uploaded files kept on disk when AwsFolderName is not set.
+const DefaultMediaFolder = "media"
+
+// LocalStorage reports whether the uploaded files are kept on disk: StorageProvider is "local", or it is not set
+// and no Spaces bucket is configured.
+func (c Config) LocalStorage() bool {
+	if c.StorageProvider == "" {
+		return c.AwsBucketName == ""
+	}
+	return c.StorageProvider != "spaces"
+}
+
+// MediaFolder returns the folder of the uploaded files, AwsFolderName. The files kept on disk default to
+// DefaultMediaFolder, as their keys cannot start with a slash.
+func (c Config) MediaFolder() string {
+	if c.AwsFolderName == "" && c.LocalStorage() {
+		return DefaultMediaFolder
+	}
+	return c.AwsFolderName
 }
 
 func Get() Config {
@@ -2,8 +2,8 @@ package answer
 
 import (
 	"github.com/emur-uy/backend/internal/infra/api/middlewares"
-	"github.com/emur-uy/backend/internal/infra/api/middlewares/constants"
 	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/service/answer"
 	"github.com/gin-gonic/gin"
 )
@@ -22,9 +22,6 @@ func RegisterRoutes(e *gin.Engine) {
 	// Group the answer routes together.
 	answerRoutes := e.Group("/api/v1/questions/:question_uuid/answer")
 
-	// Register route for getting all questions accessible to both admin and user roles.
-	allowedRoles := []string{constants.RoleAdmin, constants.RoleUser}
-
 	// Register route for answering a question using the answerHandler
-	answerRoutes.POST("", middlewares.Authenticate(), middlewares.Authorize(allowedRoles...), handler.CreateAnswer)
+	answerRoutes.POST("", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionAnswersWrite), handler.CreateAnswer)
 }
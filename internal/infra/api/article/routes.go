@@ -1,9 +1,11 @@
 package article
 
 import (
+	"github.com/emur-uy/backend/config"
 	"github.com/emur-uy/backend/internal/infra/api/middlewares"
-	"github.com/emur-uy/backend/internal/infra/api/middlewares/constants"
 	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/infra/storage"
+	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/service/article"
 	"github.com/emur-uy/backend/internal/pkg/service/media"
 	"github.com/gin-gonic/gin"
@@ -22,7 +24,7 @@ func RegisterRoutes(e *gin.Engine) {
 	articleMediaRepo := postgresql.NewArticleMediaRepository(client)
 
 	// Create new services
-	mediaService := media.NewService(mediaRepo)
+	mediaService := media.NewService(mediaRepo, storage.NewBlobStore(config.Get()))
 	articleMediaService := article.NewArticleMediaService(articleMediaRepo)
 	articleService := article.NewService(articleRepo, mediaService, articleMediaService)
 
@@ -32,14 +34,13 @@ func RegisterRoutes(e *gin.Engine) {
 	// Group the article routes together.
 	articleRoutes := e.Group("/api/v1/articles")
 
-	// Register admin routes requiring authentication and authorization for admin role.
-	adminRoutes := articleRoutes.Group("", middlewares.Authenticate(), middlewares.Authorize(constants.RoleAdmin))
+	// Register admin routes requiring authentication and the articles:write permission.
+	adminRoutes := articleRoutes.Group("", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionArticlesWrite))
 	adminRoutes.POST("", handler.CreateArticle)
 	adminRoutes.DELETE("/:uuid", handler.DeleteArticle)
 	adminRoutes.PUT("/:uuid", handler.UpdateArticle)
 	adminRoutes.POST("/:uuid/categories", handler.AddArticleToCategory)
 
-	// Register route for getting all articles accessible to both admin and user roles.
-	allowedRoles := []string{constants.RoleAdmin, constants.RoleUser}
-	articleRoutes.GET("", middlewares.Authenticate(), middlewares.Authorize(allowedRoles...), handler.GetAllArticles)
+	// Register route for getting all articles requiring the articles:read permission.
+	articleRoutes.GET("", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionArticlesRead), handler.GetAllArticles)
 }
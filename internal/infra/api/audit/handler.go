@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/gin-gonic/gin"
+)
+
+// auditHandler type contains an instance of AuditService.
+type auditHandler struct {
+	auditService ports.AuditService
+}
+
+// newHandler is a constructor function for initializing auditHandler with the given AuditService.
+// The return is a pointer to an auditHandler instance.
+func newHandler(auditService ports.AuditService) *auditHandler {
+	return &auditHandler{
+		auditService: auditService,
+	}
+}
+
+// GetAuditLogs handles the HTTP request for querying the audit log.
+// It binds the query parameters filtering by user, resource and date to the request struct.
+func (h *auditHandler) GetAuditLogs(ctx *gin.Context) {
+	request := &entity.RequestAuditLogs{}
+	if err := ctx.ShouldBindQuery(request); err != nil {
+		handleError(ctx, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	result, status, err := h.auditService.GetAuditLogs(request)
+	if err != nil {
+		message := "An error occurred while getting the audit log"
+		if status == http.StatusBadRequest {
+			message = err.Error()
+		}
+		handleError(ctx, status, message, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Audit log retrieved successfully",
+		"data":    result,
+	})
+}
+
+// handleError is a generic error handler that logs the error and responds with the corresponding status code and error message.
+func handleError(ctx *gin.Context, statusCode int, message string, err error) {
+	log.Printf("[AuditHandler]: %s, %v", message, err)
+
+	ctx.JSON(statusCode, gin.H{
+		"code":    statusCode,
+		"message": message,
+		"data":    nil,
+	})
+}
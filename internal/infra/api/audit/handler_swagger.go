@@ -0,0 +1,20 @@
+package audit
+
+// @Summary Query the audit log
+// @Description Get a page of the audit log of the health data, newest first. Requires the audit:read permission.
+// @Tags Audit
+// @Produce json
+// @Param user query string false "UUID of the user who performed the action"
+// @Param resource_type query string false "Type of the resource (medical_record, monitoring, treatment, reminder, patient, personal_data)"
+// @Param resource_uuid query string false "UUID of the resource"
+// @Param from query string false "First day, in the dd/mm/yyyy format"
+// @Param to query string false "Last day, inclusive, in the dd/mm/yyyy format"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of records per page, 50 by default and up to 200"
+// @Success 200 {object} entity.AuditLogResult "Audit log retrieved successfully"
+// @Failure 400 "Invalid user, resource or date"
+// @Router /api/v1/audit-logs [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
@@ -0,0 +1,29 @@
+package audit
+
+import (
+	"github.com/emur-uy/backend/internal/infra/api/middlewares"
+	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/service/audit"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes sets up the audit log routes on the given gin.Engine instance.
+// It initializes the necessary components, such as the repository, service, and handler,
+// to handle audit log operations in a hexagonal architecture.
+func RegisterRoutes(e *gin.Engine) {
+	// Initialize the repository by creating a new PostgreSQL client.
+	auditRepo := postgresql.NewAuditRepository(postgresql.NewClient())
+
+	// Create a new AuditService instance by injecting the repository.
+	service := audit.NewService(auditRepo)
+
+	// Create a new auditHandler instance by injecting the AuditService.
+	handler := newHandler(service)
+
+	// Group the audit log routes together, requiring the audit:read permission.
+	auditRoutes := e.Group("/api/v1/audit-logs")
+	middlewares.RegisterAuthMiddlewares(auditRoutes, entity.PermissionAuditRead)
+
+	auditRoutes.GET("", handler.GetAuditLogs)
+}
@@ -2,8 +2,8 @@ package category
 
 import (
 	"github.com/emur-uy/backend/internal/infra/api/middlewares"
-	"github.com/emur-uy/backend/internal/infra/api/middlewares/constants"
 	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/service/category"
 	"github.com/gin-gonic/gin"
 )
@@ -24,8 +24,8 @@ func RegisterRoutes(e *gin.Engine) {
 	// Group the category routes together.
 	categoryRoutes := e.Group("/api/v1/categories")
 
-	// Register admin routes requiring authentication and authorization for admin role.
-	adminRoutes := categoryRoutes.Group("", middlewares.Authenticate(), middlewares.Authorize(constants.RoleAdmin))
+	// Register admin routes requiring authentication and the categories:write permission.
+	adminRoutes := categoryRoutes.Group("", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionCategoriesWrite))
 	adminRoutes.POST("", handler.CreateCategory)
 	adminRoutes.DELETE("/:uuid", handler.DeleteCategory)
 	adminRoutes.PUT("/:uuid", handler.UpdateCategory)
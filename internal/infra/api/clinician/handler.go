@@ -0,0 +1,349 @@
+package clinician
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// clinicianHandler type contains an instance of ClinicianService.
+type clinicianHandler struct {
+	clinicianService ports.ClinicianService
+}
+
+// newHandler is a constructor function for initializing clinicianHandler with the given ClinicianService.
+// The return is a pointer to a clinicianHandler instance.
+func newHandler(clinicianService ports.ClinicianService) *clinicianHandler {
+	return &clinicianHandler{
+		clinicianService: clinicianService,
+	}
+}
+
+// LinkClinician handles the HTTP request for linking the user with the UUID of the path to a medical professional.
+// If the user is linked successfully, it returns a 201 Created status with the clinician.
+func (h *clinicianHandler) LinkClinician(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	linkReq := &entity.RequestLinkClinician{}
+	if err := c.ShouldBindJSON(linkReq); err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid input", err)
+		return
+	}
+
+	clinician, statusCode, err := h.clinicianService.LinkClinician(userUUID, linkReq)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while linking the clinician", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"code":    http.StatusCreated,
+		"message": "Clinician linked successfully",
+		"data":    clinician,
+	})
+}
+
+// GetClinicians handles the HTTP request for getting every clinician with their medical professional.
+func (h *clinicianHandler) GetClinicians(c *gin.Context) {
+	clinicians, err := h.clinicianService.GetClinicians()
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, "An error occurred while getting the clinicians", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Clinicians retrieved successfully",
+		"data":    clinicians,
+	})
+}
+
+// GrantConsent handles the HTTP request for the user sharing their data with a clinician.
+// If the consent is granted successfully, it returns a 201 Created status with the consent.
+func (h *clinicianHandler) GrantConsent(c *gin.Context) {
+	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	consentReq := &entity.RequestCreateConsent{}
+	if err := c.ShouldBindJSON(consentReq); err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid input", err)
+		return
+	}
+
+	consent, statusCode, err := h.clinicianService.GrantConsent(userUUID, consentReq)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while granting the consent", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"code":    http.StatusCreated,
+		"message": "Consent granted successfully",
+		"data":    consent,
+	})
+}
+
+// GetConsents handles the HTTP request for getting the consents granted by the user.
+func (h *clinicianHandler) GetConsents(c *gin.Context) {
+	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	consents, statusCode, err := h.clinicianService.GetConsents(userUUID)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while getting the consents", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Consents retrieved successfully",
+		"data":    consents,
+	})
+}
+
+// RevokeConsent handles the HTTP request for revoking a consent granted by the user.
+func (h *clinicianHandler) RevokeConsent(c *gin.Context) {
+	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	consentUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	statusCode, err := h.clinicianService.RevokeConsent(userUUID, consentUUID)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while revoking the consent", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Consent revoked successfully",
+		"data":    nil,
+	})
+}
+
+// GetAccessLog handles the HTTP request for getting the latest accesses of the clinicians to the data of the user.
+func (h *clinicianHandler) GetAccessLog(c *gin.Context) {
+	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	accessLogs, statusCode, err := h.clinicianService.GetAccessLog(userUUID)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while getting the access log", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Access log retrieved successfully",
+		"data":    accessLogs,
+	})
+}
+
+// GetPatients handles the HTTP request for getting the patients sharing their data with the clinician.
+func (h *clinicianHandler) GetPatients(c *gin.Context) {
+	clinicianUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	patients, statusCode, err := h.clinicianService.GetPatients(clinicianUUID, c.ClientIP())
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while getting the patients", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Patients retrieved successfully",
+		"data":    patients,
+	})
+}
+
+// GetPatientSymptomTrends handles the HTTP request for the symptom trends of a patient.
+// The symptoms are read from the optional "symptoms" query parameter, defaulting to the symptoms the patient follows,
+// and the range from the "from" and "to" query parameters (dd/mm/yyyy), which defaults to the last 30 days.
+func (h *clinicianHandler) GetPatientSymptomTrends(c *gin.Context) {
+	clinicianUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	patientUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	analyticsReq := &entity.RequestMonitoringAnalytics{}
+	if analyticsReq.SymptomUUIDs, err = parseSymptomUUIDs(c); err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid symptom UUID", err)
+		return
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if analyticsReq.From, analyticsReq.To, err = parseDateRange(c, today.AddDate(0, 0, -29), today); err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid date range", err)
+		return
+	}
+
+	if window := c.Query("window"); window != "" {
+		if analyticsReq.MovingAverageWindow, err = strconv.Atoi(window); err != nil {
+			handleError(c, http.StatusBadRequest, "Invalid moving average window", err)
+			return
+		}
+	}
+
+	analytics, statusCode, err := h.clinicianService.GetPatientSymptomTrends(c, clinicianUUID, patientUUID, analyticsReq, c.ClientIP())
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while getting the symptom trends", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Symptom trends retrieved successfully",
+		"data":    analytics,
+	})
+}
+
+// GetPatientTreatments handles the HTTP request for the treatments of a patient.
+func (h *clinicianHandler) GetPatientTreatments(c *gin.Context) {
+	clinicianUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	patientUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	treatments, statusCode, err := h.clinicianService.GetPatientTreatments(clinicianUUID, patientUUID, c.ClientIP())
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while getting the treatments", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Treatments retrieved successfully",
+		"data":    treatments,
+	})
+}
+
+// GetPatientAdherence handles the HTTP request for the weekly adherence of a patient to a treatment.
+// The range is read from the optional 'from' and 'to' query parameters (dd/mm/yyyy), which default to the last four weeks.
+func (h *clinicianHandler) GetPatientAdherence(c *gin.Context) {
+	clinicianUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	patientUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+	treatmentUUID, err := uuid.Parse(c.Param("treatment_uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from, to, err := parseDateRange(c, today.AddDate(0, 0, -27), today)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid date format", err)
+		return
+	}
+
+	report, statusCode, err := h.clinicianService.GetPatientAdherence(clinicianUUID, patientUUID, treatmentUUID, from, to, c.ClientIP())
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while computing the treatment adherence", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Treatment adherence retrieved successfully",
+		"data":    report,
+	})
+}
+
+// GetPatientMedicalRecord handles the HTTP request for the medical record of a patient.
+func (h *clinicianHandler) GetPatientMedicalRecord(c *gin.Context) {
+	clinicianUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	patientUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	medicalRecord, statusCode, err := h.clinicianService.GetPatientMedicalRecord(c, clinicianUUID, patientUUID, c.ClientIP())
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while getting the medical record", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Medical record retrieved successfully",
+		"data":    medicalRecord,
+	})
+}
+
+// parseSymptomUUIDs parses the "symptoms" query parameter, which can be comma separated or repeated.
+func parseSymptomUUIDs(c *gin.Context) ([]uuid.UUID, error) {
+	symptomUUIDs := []uuid.UUID{}
+	for _, param := range c.QueryArray("symptoms") {
+		for _, value := range strings.Split(param, ",") {
+			if strings.TrimSpace(value) == "" {
+				continue
+			}
+			symptomUUID, err := uuid.Parse(strings.TrimSpace(value))
+			if err != nil {
+				return nil, err
+			}
+			symptomUUIDs = append(symptomUUIDs, symptomUUID)
+		}
+	}
+	return symptomUUIDs, nil
+}
+
+// parseDateRange reads the 'from' and 'to' query parameters (format: dd/MM/yyyy), using the given days when absent.
+// The returned range covers both days completely.
+func parseDateRange(c *gin.Context, defaultFrom time.Time, defaultTo time.Time) (time.Time, time.Time, error) {
+	layout := "02/01/2006"
+	from, to := defaultFrom, defaultTo
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.Parse(layout, fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = parsed
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := time.Parse(layout, toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = parsed
+	}
+
+	return from, to.Add(24*time.Hour - time.Second), nil
+}
+
+// handleError handles errors by sending an appropriate response to the client.
+// It takes the gin.Context, status code, error message, and error as parameters.
+func handleError(c *gin.Context, status int, message string, err error) {
+	log.Printf("[ClinicianHandler]: %s, %v", message, err)
+	c.JSON(status, gin.H{
+		"code":    status,
+		"message": message,
+		"error":   err.Error(),
+	})
+}
@@ -0,0 +1,148 @@
+package clinician
+
+// @Summary Link clinician
+// @Description Link a user to a registered medical professional and grant them the clinician role. Requires the users:manage permission.
+// @Tags Clinicians
+// @Accept json
+// @Produce json
+// @Param uuid path string true "UUID of the user"
+// @Param body body entity.RequestLinkClinician true "Medical professional"
+// @Success 201 {object} entity.Clinician "Clinician linked successfully"
+// @Failure 404 "User or medical professional not found"
+// @Failure 409 "Already linked"
+// @Router /api/v1/clinicians/{uuid} [put]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Get all clinicians
+// @Description Get the clinicians a patient can share their data with.
+// @Tags Clinicians
+// @Produce json
+// @Success 200 {array} entity.Clinician "Clinicians retrieved successfully"
+// @Router /api/v1/clinicians [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Get consenting patients
+// @Description Get the patients with an active consent to the clinician. Requires the patients:read permission.
+// @Tags Clinicians
+// @Produce json
+// @Success 200 {array} entity.ConsentingPatient "Patients retrieved successfully"
+// @Failure 403 "The user is not linked to a medical professional"
+// @Router /api/v1/clinicians/patients [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Get patient symptom trends
+// @Description Get the daily, weekly and monthly aggregates of the symptoms of a patient. Requires the symptoms consent scope.
+// @Tags Clinicians
+// @Produce json
+// @Param uuid path string true "UUID of the patient"
+// @Param symptoms query string false "Comma separated symptom UUIDs, defaults to the symptoms the patient follows"
+// @Param from query string false "Start date (dd/mm/yyyy), defaults to 30 days ago"
+// @Param to query string false "End date (dd/mm/yyyy), defaults to today"
+// @Param window query int false "Moving average window in days"
+// @Success 200 {array} entity.SymptomAnalytics "Symptom trends retrieved successfully"
+// @Failure 403 "The patient has not consented to share this data"
+// @Router /api/v1/clinicians/patients/{uuid}/symptoms/trends [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Get patient treatments
+// @Description Get the treatments of a patient. Requires the treatments consent scope.
+// @Tags Clinicians
+// @Produce json
+// @Param uuid path string true "UUID of the patient"
+// @Success 200 {array} entity.Treatment "Treatments retrieved successfully"
+// @Failure 403 "The patient has not consented to share this data"
+// @Router /api/v1/clinicians/patients/{uuid}/treatments [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Get patient treatment adherence
+// @Description Get the weekly adherence of a patient to a treatment. Requires the adherence consent scope.
+// @Tags Clinicians
+// @Produce json
+// @Param uuid path string true "UUID of the patient"
+// @Param treatment_uuid path string true "UUID of the treatment"
+// @Param from query string false "Start date (dd/mm/yyyy), defaults to four weeks ago"
+// @Param to query string false "End date (dd/mm/yyyy), defaults to today"
+// @Success 200 {object} entity.AdherenceReport "Treatment adherence retrieved successfully"
+// @Failure 403 "The patient has not consented to share this data"
+// @Router /api/v1/clinicians/patients/{uuid}/treatments/{treatment_uuid}/adherence [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Get patient medical record
+// @Description Get the medical record of a patient. Requires the medical_record consent scope.
+// @Tags Clinicians
+// @Produce json
+// @Param uuid path string true "UUID of the patient"
+// @Success 200 {object} entity.MedicalRecord "Medical record retrieved successfully"
+// @Failure 403 "The patient has not consented to share this data"
+// @Router /api/v1/clinicians/patients/{uuid}/medical-record [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Grant consent
+// @Description Share the data of the user with a clinician until the consent expires, within one year. Replaces the active consent to the same clinician. Scopes: symptoms, treatments, adherence, medical_record.
+// @Tags Consents
+// @Accept json
+// @Produce json
+// @Param body body entity.RequestCreateConsent true "Consent"
+// @Success 201 {object} entity.PatientConsent "Consent granted successfully"
+// @Failure 400 "Invalid scope or expiration"
+// @Failure 404 "Clinician not found"
+// @Router /api/v1/consents [post]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Get consents
+// @Description Get the consents granted by the user, including the revoked and expired ones.
+// @Tags Consents
+// @Produce json
+// @Success 200 {array} entity.PatientConsent "Consents retrieved successfully"
+// @Router /api/v1/consents [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Revoke consent
+// @Description Revoke a consent granted by the user.
+// @Tags Consents
+// @Param uuid path string true "UUID of the consent"
+// @Success 200 "Consent revoked successfully"
+// @Failure 404 "Consent not found"
+// @Router /api/v1/consents/{uuid} [delete]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Get access log
+// @Description Get the latest accesses of the clinicians to the data of the user, including the denied ones.
+// @Tags Consents
+// @Produce json
+// @Success 200 {array} entity.PatientAccessLog "Access log retrieved successfully"
+// @Router /api/v1/consents/access-log [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
@@ -0,0 +1,51 @@
+package clinician
+
+import (
+	"github.com/emur-uy/backend/internal/infra/api/middlewares"
+	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/service/clinician"
+	"github.com/emur-uy/backend/internal/pkg/service/medicalrecord"
+	"github.com/emur-uy/backend/internal/pkg/service/monitoring"
+	"github.com/emur-uy/backend/internal/pkg/service/treatment"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes sets up the clinician and consent routes on the given gin.Engine instance.
+// It initializes the necessary components, such as the repository, service, and handler,
+// to handle clinician-related operations in a hexagonal architecture.
+func RegisterRoutes(e *gin.Engine) {
+	// Initialize the repositories by creating a new PostgreSQL client.
+	client := postgresql.NewClient()
+	clinicianRepo := postgresql.NewClinicianRepository(client)
+
+	// Create a new ClinicianService instance, reading the patient data through the domain services.
+	service := clinician.NewService(clinicianRepo, monitoring.NewService(client), treatment.NewService(client), medicalrecord.NewService(client))
+
+	// Create a new clinicianHandler instance by injecting the ClinicianService.
+	handler := newHandler(service)
+
+	clinicianRoutes := e.Group("/api/v1/clinicians", middlewares.Authenticate())
+
+	// Register route for linking a user to a medical professional, requiring the users:manage permission.
+	clinicianRoutes.PUT("/:uuid", middlewares.RequirePermission(entity.PermissionUsersManage), handler.LinkClinician)
+
+	// Register route for the patients choosing a clinician to share their data with.
+	clinicianRoutes.GET("", middlewares.RequirePermission(entity.PermissionPatientSelf), handler.GetClinicians)
+
+	// Register the read-only routes for the data of the consenting patients, requiring the patients:read permission,
+	// every request being recorded in the audit log.
+	patientRoutes := clinicianRoutes.Group("/patients", middlewares.Audit(entity.AuditResourcePatient), middlewares.RequirePermission(entity.PermissionPatientsRead))
+	patientRoutes.GET("", handler.GetPatients)
+	patientRoutes.GET("/:uuid/symptoms/trends", handler.GetPatientSymptomTrends)
+	patientRoutes.GET("/:uuid/treatments", handler.GetPatientTreatments)
+	patientRoutes.GET("/:uuid/treatments/:treatment_uuid/adherence", handler.GetPatientAdherence)
+	patientRoutes.GET("/:uuid/medical-record", handler.GetPatientMedicalRecord)
+
+	// Register the routes for the patients managing their consents, requiring the patient:self permission.
+	consentRoutes := e.Group("/api/v1/consents", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionPatientSelf))
+	consentRoutes.POST("", handler.GrantConsent)
+	consentRoutes.GET("", handler.GetConsents)
+	consentRoutes.DELETE("/:uuid", handler.RevokeConsent)
+	consentRoutes.GET("/access-log", handler.GetAccessLog)
+}
@@ -0,0 +1,108 @@
+package erasure
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// erasureHandler type contains an instance of ErasureService.
+type erasureHandler struct {
+	erasureService ports.ErasureService
+}
+
+// newHandler is a constructor function for initializing erasureHandler with the given ErasureService.
+// The return is a pointer to an erasureHandler instance.
+func newHandler(erasureService ports.ErasureService) *erasureHandler {
+	return &erasureHandler{
+		erasureService: erasureService,
+	}
+}
+
+// RequestErasure handles the HTTP request for the user deleting their account.
+// The account is erased at the end of the grace period, it returns a 202 Accepted status with the scheduled erasure.
+func (h *erasureHandler) RequestErasure(c *gin.Context) {
+	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	erasure, statusCode, err := h.erasureService.RequestErasure(userUUID)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while deleting the account", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"code":    http.StatusAccepted,
+		"message": "Account deletion scheduled successfully",
+		"data":    erasure,
+	})
+}
+
+// GetErasure handles the HTTP request for getting the scheduled deletion of the account of the user.
+func (h *erasureHandler) GetErasure(c *gin.Context) {
+	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	erasure, statusCode, err := h.erasureService.GetErasure(userUUID)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while getting the account deletion", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Account deletion retrieved successfully",
+		"data":    erasure,
+	})
+}
+
+// CancelErasure handles the HTTP request for cancelling the deletion of the account of the user during the grace period.
+func (h *erasureHandler) CancelErasure(c *gin.Context) {
+	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	statusCode, err := h.erasureService.CancelErasure(userUUID)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while cancelling the account deletion", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Account deletion cancelled successfully",
+		"data":    nil,
+	})
+}
+
+// GetUserErasures handles the HTTP request for getting the account deletions of a user with their reports.
+func (h *erasureHandler) GetUserErasures(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	erasures, statusCode, err := h.erasureService.GetUserErasures(userUUID)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while getting the account deletions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Account deletions retrieved successfully",
+		"data":    erasures,
+	})
+}
+
+// handleError handles errors by sending an appropriate response to the client.
+// It takes the gin.Context, status code, error message, and error as parameters.
+func handleError(c *gin.Context, status int, message string, err error) {
+	log.Printf("[ErasureHandler]: %s, %v", message, err)
+	c.JSON(status, gin.H{
+		"code":    status,
+		"message": message,
+		"error":   err.Error(),
+	})
+}
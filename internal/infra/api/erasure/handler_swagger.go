@@ -0,0 +1,51 @@
+package erasure
+
+// @Summary Delete account
+// @Description Schedule the deletion of the account of the authenticated user and sign out every session.
+// @Description During the 30 days grace period the user can sign in and cancel the deletion. Then the personal data are erased, the uploaded files are deleted and the account is anonymised.
+// @Tags Users
+// @Produce json
+// @Success 202 {object} entity.AccountErasure "Account deletion scheduled successfully"
+// @Failure 404 "User not found"
+// @Router /api/v1/users/me [delete]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Get account deletion
+// @Description Get the scheduled deletion of the account of the authenticated user.
+// @Tags Users
+// @Produce json
+// @Success 200 {object} entity.AccountErasure "Account deletion retrieved successfully"
+// @Failure 404 "No account deletion is scheduled"
+// @Router /api/v1/users/me/erasure [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Cancel account deletion
+// @Description Cancel the scheduled deletion of the account of the authenticated user during the grace period.
+// @Tags Users
+// @Produce json
+// @Success 200 "Account deletion cancelled successfully"
+// @Failure 404 "No account deletion is scheduled"
+// @Router /api/v1/users/me/erasure [delete]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Get account deletions of a user
+// @Description Get the account deletions requested by a user, with the report of what was erased. Requires the users:manage permission.
+// @Tags Users
+// @Produce json
+// @Param uuid path string true "UUID of the user"
+// @Success 200 {array} entity.AccountErasure "Account deletions retrieved successfully"
+// @Failure 404 "No account deletion was requested"
+// @Router /api/v1/users/erasures/{uuid} [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
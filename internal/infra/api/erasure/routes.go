@@ -0,0 +1,40 @@
+package erasure
+
+import (
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/infra/api/middlewares"
+	"github.com/emur-uy/backend/internal/infra/mailer"
+	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/infra/storage"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/service/erasure"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes sets up the account deletion routes on the given gin.Engine instance.
+// It initializes the necessary components, such as the repository, service, and handler,
+// to handle erasure-related operations in a hexagonal architecture.
+func RegisterRoutes(e *gin.Engine) {
+	// Initialize the repositories by creating a new PostgreSQL client.
+	client := postgresql.NewClient()
+	erasureRepo := postgresql.NewErasureRepository(client)
+	tokenRepo := postgresql.NewTokenRepository(client)
+
+	// Create a new ErasureService instance by injecting the repositories, the mailer and the blob store.
+	service := erasure.NewService(erasureRepo, tokenRepo, mailer.NewMailer(config.Get()), storage.NewBlobStore(config.Get()))
+
+	// Create a new erasureHandler instance by injecting the ErasureService.
+	handler := newHandler(service)
+
+	// Register the routes of the user deleting their account, requiring authentication.
+	// Any user can delete their account, the requests are recorded in the audit log.
+	userRoutes := e.Group("/api/v1/users/me", middlewares.Authenticate(), middlewares.Audit(entity.AuditResourcePersonalData))
+	userRoutes.DELETE("", handler.RequestErasure)
+	userRoutes.GET("/erasure", handler.GetErasure)
+	userRoutes.DELETE("/erasure", handler.CancelErasure)
+
+	// Register the admin routes for the erasure reports, requiring the users:manage permission.
+	adminRoutes := e.Group("/api/v1/users/erasures")
+	middlewares.RegisterAuthMiddlewares(adminRoutes, entity.PermissionUsersManage)
+	adminRoutes.GET("/:uuid", handler.GetUserErasures)
+}
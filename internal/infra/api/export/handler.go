@@ -0,0 +1,112 @@
+package export
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// archiveContentType is the content type of the export archives.
+const archiveContentType = "application/zip"
+
+// exportHandler type contains an instance of ExportService.
+type exportHandler struct {
+	exportService ports.ExportService
+}
+
+// newHandler is a constructor function for initializing exportHandler with the given ExportService.
+// The return is a pointer to an exportHandler instance.
+func newHandler(exportService ports.ExportService) *exportHandler {
+	return &exportHandler{
+		exportService: exportService,
+	}
+}
+
+// ExportPersonalData handles the HTTP request for exporting the personal data of the user.
+// Small accounts get the archive right away, while the larger ones get a 202 Accepted status
+// with the export job to poll.
+func (h *exportHandler) ExportPersonalData(c *gin.Context) {
+	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	export, statusCode, err := h.exportService.ExportPersonalData(userUUID)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while exporting the personal data", err)
+		return
+	}
+
+	if statusCode == http.StatusOK {
+		sendArchive(c, export)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"code":    http.StatusAccepted,
+		"message": "The export is being prepared",
+		"data":    export,
+	})
+}
+
+// GetExport handles the HTTP request for polling the status of an export of the user.
+func (h *exportHandler) GetExport(c *gin.Context) {
+	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	exportUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	export, statusCode, err := h.exportService.GetExport(userUUID, exportUUID)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while getting the export", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Export retrieved successfully",
+		"data":    export,
+	})
+}
+
+// DownloadExport handles the HTTP request for downloading the archive of a completed export of the user.
+func (h *exportHandler) DownloadExport(c *gin.Context) {
+	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	exportUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	export, statusCode, err := h.exportService.DownloadExport(userUUID, exportUUID)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while downloading the export", err)
+		return
+	}
+
+	sendArchive(c, export)
+}
+
+// sendArchive responds with the archive of the export as an attachment.
+func sendArchive(c *gin.Context, export *entity.DataExport) {
+	fileName := fmt.Sprintf("personal-data-%s.zip", export.CreatedAt.Format("2006-01-02"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Data(http.StatusOK, archiveContentType, export.Archive)
+}
+
+// handleError handles errors by sending an appropriate response to the client.
+// It takes the gin.Context, status code, error message, and error as parameters.
+func handleError(c *gin.Context, status int, message string, err error) {
+	log.Printf("[ExportHandler]: %s, %v", message, err)
+	c.JSON(status, gin.H{
+		"code":    status,
+		"message": message,
+		"error":   err.Error(),
+	})
+}
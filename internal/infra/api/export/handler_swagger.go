@@ -0,0 +1,45 @@
+package export
+
+// @Summary Export personal data
+// @Description Export every personal data of the authenticated user as a zip archive holding a JSON file and a CSV file per section.
+// @Description Small accounts get the archive right away. Larger accounts get a 202 Accepted status with an export job, whose status can be polled and whose archive can be downloaded for 7 days once completed.
+// @Tags Export
+// @Produce application/zip
+// @Produce json
+// @Success 200 {file} file "Personal data archive"
+// @Success 202 {object} entity.DataExport "The export is being prepared"
+// @Failure 404 "User not found"
+// @Router /api/v1/users/me/export [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Get export status
+// @Description Get the status of a personal data export of the authenticated user.
+// @Tags Export
+// @Produce json
+// @Param uuid path string true "UUID of the export"
+// @Success 200 {object} entity.DataExport "Export retrieved successfully"
+// @Failure 404 "Export not found"
+// @Failure 410 "The export expired"
+// @Router /api/v1/users/me/export/{uuid} [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Download export
+// @Description Download the archive of a completed personal data export of the authenticated user.
+// @Tags Export
+// @Produce application/zip
+// @Param uuid path string true "UUID of the export"
+// @Success 200 {file} file "Personal data archive"
+// @Failure 404 "Export not found"
+// @Failure 409 "The export is not completed yet"
+// @Failure 410 "The export expired"
+// @Router /api/v1/users/me/export/{uuid}/download [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
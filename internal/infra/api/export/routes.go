@@ -0,0 +1,31 @@
+package export
+
+import (
+	"github.com/emur-uy/backend/internal/infra/api/middlewares"
+	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/service/export"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes sets up the personal data export routes on the given gin.Engine instance.
+// It initializes the necessary components, such as the repository, service, and handler,
+// to handle export-related operations in a hexagonal architecture.
+func RegisterRoutes(e *gin.Engine) {
+	// Initialize the repository by creating a new PostgreSQL client.
+	exportRepo := postgresql.NewExportRepository(postgresql.NewClient())
+
+	// Create a new ExportService instance by injecting the repository.
+	service := export.NewService(exportRepo)
+
+	// Create a new exportHandler instance by injecting the ExportService.
+	handler := newHandler(service)
+
+	// Group the export routes together, requiring the patient:self permission and recorded in the audit log.
+	exportRoutes := e.Group("/api/v1/users/me/export")
+	exportRoutes.Use(middlewares.Authenticate(), middlewares.Audit(entity.AuditResourcePersonalData), middlewares.RequirePermission(entity.PermissionPatientSelf))
+
+	exportRoutes.GET("", handler.ExportPersonalData)
+	exportRoutes.GET("/:uuid", handler.GetExport)
+	exportRoutes.GET("/:uuid/download", handler.DownloadExport)
+}
@@ -0,0 +1,94 @@
+package files
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/emur-uy/backend/internal/infra/storage"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/gin-gonic/gin"
+)
+
+// filesHandler type contains an instance of LocalBlobStore.
+type filesHandler struct {
+	store ports.LocalBlobStore
+}
+
+// newHandler is a constructor function for initializing filesHandler with the given LocalBlobStore.
+// The return is a pointer to a filesHandler instance.
+func newHandler(store ports.LocalBlobStore) *filesHandler {
+	return &filesHandler{
+		store: store,
+	}
+}
+
+// GetFile handles the HTTP request for downloading an uploaded file.
+func (h *filesHandler) GetFile(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+
+	file, err := h.store.Open(key, ctx.Query("expires"), ctx.Query("signature"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ports.ErrBlobNotFound):
+			handleError(ctx, http.StatusNotFound, "File not found", err)
+		case errors.Is(err, ports.ErrInvalidSignature):
+			handleError(ctx, http.StatusForbidden, "Invalid or expired link", err)
+		default:
+			handleError(ctx, http.StatusBadRequest, "Invalid file path", err)
+		}
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		handleError(ctx, http.StatusInternalServerError, "An error occurred while reading the file", err)
+		return
+	}
+
+	// The signed links of the private files must not be kept by shared caches
+	if ctx.Query("signature") != "" {
+		ctx.Header("Cache-Control", "private, no-store")
+	}
+	http.ServeContent(ctx.Writer, ctx.Request, path.Base(key), info.ModTime(), file)
+}
+
+// PutFile handles the HTTP request uploading a file, or a part of a multipart upload, to its signed URL.
+// The ETag header of the response identifies the uploaded content, to be sent when confirming the upload.
+func (h *filesHandler) PutFile(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+
+	etag, err := h.store.Write(key, ctx.Query("upload_id"), ctx.Query("part_number"), ctx.Query("size"), ctx.Query("expires"), ctx.Query("signature"), ctx.Request.Body)
+	if err != nil {
+		switch {
+		case errors.Is(err, ports.ErrInvalidSignature):
+			handleError(ctx, http.StatusForbidden, "Invalid or expired link", err)
+		case errors.Is(err, ports.ErrBlobNotFound):
+			handleError(ctx, http.StatusNotFound, "Upload not found", err)
+		case errors.Is(err, ports.ErrBlobTooLarge):
+			handleError(ctx, http.StatusRequestEntityTooLarge, "File larger than the signed size", err)
+		case errors.Is(err, storage.ErrInvalidKey), errors.Is(err, storage.ErrInvalidUpload):
+			handleError(ctx, http.StatusBadRequest, "Invalid file path", err)
+		default:
+			handleError(ctx, http.StatusInternalServerError, "An error occurred while writing the file", err)
+		}
+		return
+	}
+
+	ctx.Header("ETag", etag)
+	ctx.Status(http.StatusOK)
+}
+
+// handleError is a generic error handler that logs the error and responds with the corresponding status code and error message.
+func handleError(ctx *gin.Context, statusCode int, message string, err error) {
+	log.Printf("[FilesHandler]: %s, %v", message, err)
+
+	ctx.JSON(statusCode, gin.H{
+		"code":    statusCode,
+		"message": message,
+		"data":    nil,
+	})
+}
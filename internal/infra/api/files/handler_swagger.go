@@ -0,0 +1,36 @@
+package files
+
+// @Summary Download an uploaded file
+// @Description Download a file uploaded to the local storage, used when no bucket is configured. The private files require the expiry and the signature of their signed URL.
+// @Tags Files
+// @Produce octet-stream
+// @Param key path string true "Key of the file, such as reminders/<uuid>.jpg"
+// @Param expires query int false "Expiry of the signed URL, as a Unix time"
+// @Param signature query string false "Signature of the signed URL"
+// @Success 200 "The content of the file"
+// @Failure 403 "Invalid or expired link"
+// @Failure 404 "File not found"
+// @Router /api/v1/files/{key} [get]
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Upload a file
+// @Description Upload a file, or a part of a multipart upload, to the signed URL of an upload session when no bucket is configured. The ETag header of the response identifies the uploaded content.
+// @Tags Files
+// @Accept octet-stream
+// @Param key path string true "Key of the file"
+// @Param upload_id query string false "ID of the multipart upload"
+// @Param part_number query int false "Number of the part of the multipart upload"
+// @Param size query int true "Largest size of the file or the part, in bytes"
+// @Param expires query int true "Expiry of the signed URL, as a Unix time"
+// @Param signature query string true "Signature of the signed URL"
+// @Success 200 "The file is stored, its ETag is in the ETag header"
+// @Failure 400 "Invalid file path"
+// @Failure 403 "Invalid or expired link"
+// @Failure 404 "Upload not found"
+// @Failure 413 "File larger than the signed size"
+// @Router /api/v1/files/{key} [put]
+func _() {
+	// Swagger annotations.
+}
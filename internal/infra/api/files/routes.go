@@ -0,0 +1,26 @@
+package files
+
+import (
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/infra/storage"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes sets up the route serving the uploaded files on the given gin.Engine instance,
+// when they are kept on the local disk. The files kept in a bucket are served by the bucket.
+func RegisterRoutes(e *gin.Engine) {
+	store, ok := storage.NewBlobStore(config.Get()).(ports.LocalBlobStore)
+	if !ok {
+		return
+	}
+
+	// Create a new filesHandler instance by injecting the local store.
+	handler := newHandler(store)
+
+	// The public files are served to anyone, the private files require the signature of their signed URL.
+	e.GET(storage.FilesRoute+"/*key", handler.GetFile)
+
+	// The files uploaded directly by the clients require the signature of their upload URL.
+	e.PUT(storage.FilesRoute+"/*key", handler.PutFile)
+}
@@ -0,0 +1,60 @@
+package forecast
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// forecastHandler type contains an instance of ForecastService
+type forecastHandler struct {
+	forecastService ports.ForecastService
+}
+
+// newHandler is a constructor function for initializing forecastHandler with the given ForecastService.
+// The return is a pointer to a forecastHandler instance.
+func newHandler(forecastService ports.ForecastService) *forecastHandler {
+	return &forecastHandler{
+		forecastService: forecastService,
+	}
+}
+
+// GetForecast handles the HTTP request for getting the forecast of the authenticated user's city.
+// If any error occurs during this process, it returns the corresponding status code and error message.
+// If the forecast is retrieved successfully, it returns a 200 OK status with the forecast and its heat alerts.
+func (h *forecastHandler) GetForecast(c *gin.Context) {
+	// Get user UUID from JWT token
+	userUUID, err := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid user UUID", err)
+		return
+	}
+
+	// Get the forecast for the user's city
+	forecast, statusCode, err := h.forecastService.GetUserForecast(userUUID)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while getting the forecast", err)
+		return
+	}
+
+	// Return the response with the forecast
+	c.JSON(http.StatusOK, gin.H{
+		"code":    statusCode,
+		"message": "Forecast retrieved successfully",
+		"data":    forecast,
+	})
+}
+
+// handleError handles errors by sending an appropriate response to the client.
+// It takes the gin.Context, status code, error message, and error as parameters.
+func handleError(c *gin.Context, status int, message string, err error) {
+	log.Printf("[ForecastHandler]: %s, %v", message, err)
+	c.JSON(status, gin.H{
+		"code":    status,
+		"message": err.Error(),
+	})
+}
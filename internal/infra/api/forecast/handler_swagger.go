@@ -0,0 +1,13 @@
+package forecast
+
+// @Summary Get forecast
+// @Description Get the 3-day forecast for the authenticated user's city, with heat alerts for the days passing the temperature or UV thresholds
+// @Tags Forecast
+// @Produce json
+// @Success 200 {object} entity.UserForecast "Forecast retrieved successfully"
+// @Failure 400 {object} entity.UserForecast "Invalid user UUID"
+// @Router /api/v1/forecast [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
@@ -0,0 +1,31 @@
+package forecast
+
+import (
+	"github.com/emur-uy/backend/internal/infra/api/middlewares"
+	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/service/forecast"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes sets up the forecast-related routes on the given gin.Engine instance.
+// It initializes the necessary components, such as the repository, service, and handler,
+// to handle forecast-related operations in a hexagonal architecture.
+func RegisterRoutes(e *gin.Engine) {
+	// Initialize the repository by creating a new PostgreSQL client.
+	client := postgresql.NewClient()
+	repo := postgresql.NewForecastRepository(client)
+
+	// Create a new ForecastService instance by injecting the repository.
+	service := forecast.NewService(repo)
+
+	// Create a new forecastHandler instance by injecting the ForecastService.
+	handler := newHandler(service)
+
+	// Group the forecast routes together.
+	forecastRoutes := e.Group("/api/v1/forecast")
+
+	// Register user routes requiring authentication and the patient:self permission.
+	userRoutes := forecastRoutes.Group("", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionPatientSelf))
+	userRoutes.GET("", handler.GetForecast)
+}
@@ -2,8 +2,8 @@ package healthservice
 
 import (
 	"github.com/emur-uy/backend/internal/infra/api/middlewares"
-	"github.com/emur-uy/backend/internal/infra/api/middlewares/constants"
 	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/service/healthservice"
 	"github.com/gin-gonic/gin"
 )
@@ -24,15 +24,14 @@ func RegisterRoutes(e *gin.Engine) {
 	// Group the healthservice routes together.
 	healthServiceRoutes := e.Group("/api/v1/healthservices")
 
-	// Register route for getting all health services accessible to both admin and user roles.
-	allowedRoles := []string{constants.RoleAdmin, constants.RoleUser}
-	healthServiceRoutes.GET("", middlewares.Authenticate(), middlewares.Authorize(allowedRoles...), handler.GetAllHealthServices)
+	// Register route for getting all health services requiring the healthservices:read permission.
+	healthServiceRoutes.GET("", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionHealthServicesRead), handler.GetAllHealthServices)
 
-	// Register route for creating a health service accessible only to the admin role.
-	adminRoutes := healthServiceRoutes.Group("", middlewares.Authenticate(), middlewares.Authorize(constants.RoleAdmin))
+	// Register route for creating a health service requiring the healthservices:write permission.
+	adminRoutes := healthServiceRoutes.Group("", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionHealthServicesWrite))
 	adminRoutes.POST("", handler.CreateHealthService)
 
-	// Register route for adding a rating to a health service accessible only to the user role.
-	userRoutes := healthServiceRoutes.Group("", middlewares.Authenticate(), middlewares.Authorize(constants.RoleUser))
+	// Register route for adding a rating to a health service requiring the healthservices:rate permission.
+	userRoutes := healthServiceRoutes.Group("", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionHealthServicesRate))
 	userRoutes.POST("/rating", handler.AddRatingToHealthService)
 }
@@ -2,8 +2,8 @@ package maps
 
 import (
 	"github.com/emur-uy/backend/internal/infra/api/middlewares"
-	"github.com/emur-uy/backend/internal/infra/api/middlewares/constants"
 	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/service/maps"
 	"github.com/gin-gonic/gin"
 )
@@ -24,13 +24,12 @@ func RegisterRoutes(e *gin.Engine) {
 	// Group the map routes together.
 	mapRoutes := e.Group("/api/v1/maps")
 
-	// Register admin routes requiring authentication and authorization for admin role.
-	adminRoutes := mapRoutes.Group("", middlewares.Authenticate(), middlewares.Authorize(constants.RoleAdmin))
+	// Register admin routes requiring authentication and the maps:write permission.
+	adminRoutes := mapRoutes.Group("", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionMapsWrite))
 	adminRoutes.POST("", handler.CreateMap)
 	adminRoutes.PUT("/:uuid", handler.UpdateMap)
 	adminRoutes.DELETE("/:uuid", handler.DeleteMap)
 
-	// Register route for getting all maps accessible to both admin and user roles.
-	allowedRoles := []string{constants.RoleAdmin, constants.RoleUser}
-	mapRoutes.GET("", middlewares.Authenticate(), middlewares.Authorize(allowedRoles...), handler.GetAllMaps)
+	// Register route for getting all maps requiring the maps:read permission.
+	mapRoutes.GET("", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionMapsRead), handler.GetAllMaps)
 }
@@ -2,8 +2,8 @@ package medical
 
 import (
 	"github.com/emur-uy/backend/internal/infra/api/middlewares"
-	"github.com/emur-uy/backend/internal/infra/api/middlewares/constants"
 	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/service/medical"
 	"github.com/gin-gonic/gin"
 )
@@ -24,15 +24,14 @@ func RegisterRoutes(e *gin.Engine) {
 	// Group the medical routes together.
 	medicalRoutes := e.Group("/api/v1/medical")
 
-	// Register route for getting all medical records accessible to both admin and user roles.
-	allowedRoles := []string{constants.RoleAdmin, constants.RoleUser}
-	medicalRoutes.GET("", middlewares.Authenticate(), middlewares.Authorize(allowedRoles...), handler.GetAllMedicalRecords)
+	// Register route for getting all medical records requiring the medical:read permission.
+	medicalRoutes.GET("", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionMedicalRead), handler.GetAllMedicalRecords)
 
-	// Register route for uploading a CSV file accessible only to admin role.
-	adminRoutes := medicalRoutes.Group("", middlewares.Authenticate(), middlewares.Authorize(constants.RoleAdmin))
+	// Register route for uploading a CSV file requiring the medical:import permission.
+	adminRoutes := medicalRoutes.Group("", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionMedicalImport))
 	adminRoutes.POST("", handler.UploadCSV)
 
-	// Register route for adding a rating to a medical record accessible only to user role.
-	userRoutes := medicalRoutes.Group("", middlewares.Authenticate(), middlewares.Authorize(constants.RoleUser))
+	// Register route for adding a rating to a medical record requiring the medical:rate permission.
+	userRoutes := medicalRoutes.Group("", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionMedicalRate))
 	userRoutes.POST("/rating", handler.AddRatingToMedical)
 }
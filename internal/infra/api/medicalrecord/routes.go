@@ -2,8 +2,8 @@ package medicalrecord
 
 import (
 	"github.com/emur-uy/backend/internal/infra/api/middlewares"
-	"github.com/emur-uy/backend/internal/infra/api/middlewares/constants"
 	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/service/medicalrecord"
 	"github.com/gin-gonic/gin"
 )
@@ -24,11 +24,11 @@ func RegisterRoutes(e *gin.Engine) {
 	// Group the medical record routes together.
 	medicalRecordRoutes := e.Group("/api/v1/medicalrecords")
 
-	// Register routes requiring authentication and authorization for user and admin roles.
-	medicalRecordRoutes.Use(middlewares.Authenticate(), middlewares.Authorize(constants.RoleUser, constants.RoleAdmin))
+	// Register routes requiring authentication, recorded in the audit log.
+	medicalRecordRoutes.Use(middlewares.Authenticate(), middlewares.Audit(entity.AuditResourceMedicalRecord))
 
-	// POST and PUT endpoints accessible only for user role.
-	userRoutes := medicalRecordRoutes.Group("", middlewares.Authorize(constants.RoleUser))
+	// Endpoints requiring the patient:self permission.
+	userRoutes := medicalRecordRoutes.Group("", middlewares.RequirePermission(entity.PermissionPatientSelf))
 	userRoutes.GET("/", handler.GetMedicalRecord)
 	userRoutes.PUT("/:uuid", handler.UpdateMedicalRecord)
 	userRoutes.POST("/", handler.CreateMedicalRecord)
@@ -0,0 +1,81 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/emur-uy/backend/internal/pkg/service/audit"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// auditor records the actions on the health data.
+var auditor ports.Auditor
+
+// SetAuditor sets the auditor used by the Audit middleware.
+// It must be called before registering the routes.
+func SetAuditor(a ports.Auditor) {
+	auditor = a
+}
+
+// Audit is a middleware recording every request on a health data resource of the given type in the audit log,
+// once it is handled. It must run after Authenticate and before RequirePermission, so the denied requests are
+// recorded too. The resource is the "uuid" path parameter, or the one named by the service with audit.SetResource.
+func Audit(resourceType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if auditor == nil {
+			return
+		}
+
+		auditLog := &entity.AuditLog{
+			Action:       auditAction(c.Request.Method),
+			ResourceType: resourceType,
+			ResourceUUID: audit.ResourceFromContext(c),
+			Method:       c.Request.Method,
+			Route:        c.FullPath(),
+			IP:           c.ClientIP(),
+			Outcome:      auditOutcome(c.Writer.Status()),
+			Status:       c.Writer.Status(),
+		}
+		if actorUUID, err := uuid.Parse(fmt.Sprintf("%v", c.Value("userUUID"))); err == nil {
+			auditLog.ActorUUID = &actorUUID
+		}
+		if auditLog.ResourceUUID == nil {
+			if resourceUUID, err := uuid.Parse(c.Param("uuid")); err == nil {
+				auditLog.ResourceUUID = &resourceUUID
+			}
+		}
+
+		auditor.Record(auditLog)
+	}
+}
+
+// auditAction returns the action of the HTTP method.
+func auditAction(method string) string {
+	switch method {
+	case http.MethodPost:
+		return entity.AuditActionCreate
+	case http.MethodPut, http.MethodPatch:
+		return entity.AuditActionUpdate
+	case http.MethodDelete:
+		return entity.AuditActionDelete
+	default:
+		return entity.AuditActionRead
+	}
+}
+
+// auditOutcome returns the outcome of the response status.
+func auditOutcome(status int) string {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return entity.AuditOutcomeDenied
+	case status >= http.StatusBadRequest:
+		return entity.AuditOutcomeFailure
+	default:
+		return entity.AuditOutcomeSuccess
+	}
+}
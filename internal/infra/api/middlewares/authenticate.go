@@ -4,17 +4,29 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/emur-uy/backend/internal/infra/api/middlewares/jwtutils"
+	"github.com/emur-uy/backend/internal/pkg/ports"
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
 )
 
 // RegisterAuthMiddlewares is a function that sets up the authentication and authorization middlewares
-// on the given gin.RouterGroup instance for the specified role.
-// It takes the role string and the gin.RouterGroup instance as parameters.
-func RegisterAuthMiddlewares(role string, r *gin.RouterGroup) {
+// on the given gin.RouterGroup instance for the specified permissions.
+// It takes the gin.RouterGroup instance and the permissions as parameters.
+func RegisterAuthMiddlewares(r *gin.RouterGroup, permissions ...string) {
 	r.Use(Authenticate())
-	r.Use(Authorize(role))
+	r.Use(RequirePermission(permissions...))
+}
+
+// revocationList holds the IDs of the access tokens revoked before they expire.
+var revocationList ports.RevocationList
+
+// SetRevocationList sets the revocation list checked by the Authenticate middleware.
+// It must be called before registering the routes.
+func SetRevocationList(list ports.RevocationList) {
+	revocationList = list
 }
 
 // Authenticate is a middleware to validate JWT tokens and extract claims for authenticated requests.
@@ -40,11 +52,53 @@ func Authenticate() gin.HandlerFunc {
 			return
 		}
 
-		// 3. Extract claims and set them in the Gin context.
+		// 3. Reject the tokens revoked on logout.
+		if jti := jwtutils.GetTokenID(jwtToken); jti != "" && revocationList != nil {
+			revoked, err := revocationList.IsRevoked(jti)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Error validating token"})
+				return
+			}
+			if revoked {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token revoked"})
+				return
+			}
+		}
+
+		// 4. Reject the tokens issued before the sessions of the user were ended by a password reset.
+		if revocationList != nil {
+			revoked, err := issuedBeforeCutoff(jwtToken)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Error validating token"})
+				return
+			}
+			if revoked {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token revoked"})
+				return
+			}
+		}
+
+		// 5. Extract claims and set them in the Gin context.
 		jwtutils.SetClaims(c, jwtToken)
 	}
 }
 
+// issuedBeforeCutoff reports whether the token was issued before the time its user's tokens are valid after.
+// The issue time has a precision of a second, the tokens issued during the second of the cutoff are kept.
+// The tokens without an issue time or a user are rejected once the user has a cutoff.
+func issuedBeforeCutoff(jwtToken *jwt.Token) (bool, error) {
+	userUUID, ok := jwtutils.GetUserUUID(jwtToken)
+	if !ok {
+		return false, nil
+	}
+	validAfter, err := revocationList.TokensValidAfter(userUUID)
+	if err != nil || validAfter == nil {
+		return false, err
+	}
+	issuedAt, ok := jwtutils.GetIssuedAt(jwtToken)
+	return !ok || issuedAt.Before(validAfter.Truncate(time.Second)), nil
+}
+
 // getJwt retrieves the JWT from the Authorization header.
 // It takes the gin.Context as a parameter and returns the JWT string and an error (if any).
 func getJwt(c *gin.Context) (string, error) {
@@ -55,28 +109,3 @@ func getJwt(c *gin.Context) (string, error) {
 	jwtToken := strings.ReplaceAll(authorizationHeader, "Bearer", "")
 	return strings.TrimSpace(jwtToken), nil
 }
-
-// Authorize is Authorization middleware to validate roles for API calls.
-// It takes a variadic parameter allowedRoles of type string and returns a gin.HandlerFunc.
-func Authorize(allowedRoles ...string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Check if the user making the request has any of the specified roles.
-		// If the user has any of the roles, call the next middleware/handler.
-		// If the user does not have any of the roles, return an error response.
-		userRole := c.GetString("role")
-
-		authorized := false
-		for _, role := range allowedRoles {
-			if userRole == role {
-				authorized = true
-				break
-			}
-		}
-
-		if !authorized {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to access this resource"})
-			return
-		}
-		c.Next()
-	}
-}
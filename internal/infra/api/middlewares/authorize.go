@@ -0,0 +1,100 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// permissionCacheTTL is how long the permissions of a user are reused before they are resolved again,
+// so a role change takes effect in the next requests without a new login.
+const permissionCacheTTL = 30 * time.Second
+
+// permissionResolver resolves the permissions granted by the roles of the users.
+var permissionResolver ports.PermissionResolver
+
+// permissionCache holds the permissions resolved for each user UUID.
+var permissionCache sync.Map
+
+type cachedPermissions struct {
+	permissions map[string]bool
+	expiresAt   time.Time
+}
+
+// SetPermissionResolver sets the resolver used by the RequirePermission middleware.
+// It must be called before registering the routes.
+func SetPermissionResolver(resolver ports.PermissionResolver) {
+	permissionResolver = resolver
+	permissionCache = sync.Map{}
+}
+
+// RequirePermission is Authorization middleware to validate permissions for API calls.
+// The request is allowed when the roles of the user grant any of the given permissions.
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted, err := userPermissions(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Error validating permissions"})
+			return
+		}
+
+		for _, permission := range permissions {
+			if granted[permission] {
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to access this resource"})
+	}
+}
+
+// HasPermission reports whether the roles of the authenticated user grant the permission.
+func HasPermission(c *gin.Context, permission string) bool {
+	granted, err := userPermissions(c)
+	if err != nil {
+		return false
+	}
+	return granted[permission]
+}
+
+// userPermissions returns the permissions of the authenticated user, resolving them at most once per
+// request and once per permissionCacheTTL.
+func userPermissions(c *gin.Context) (map[string]bool, error) {
+	if granted, ok := c.Get("permissions"); ok {
+		return granted.(map[string]bool), nil
+	}
+	if permissionResolver == nil {
+		return nil, fmt.Errorf("permission resolver not set")
+	}
+
+	userUUID, err := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if cached, ok := permissionCache.Load(userUUID); ok && now.Before(cached.(*cachedPermissions).expiresAt) {
+		granted := cached.(*cachedPermissions).permissions
+		c.Set("permissions", granted)
+		return granted, nil
+	}
+
+	permissions, err := permissionResolver.GetUserPermissions(userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	granted := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		granted[permission] = true
+	}
+	permissionCache.Store(userUUID, &cachedPermissions{permissions: granted, expiresAt: now.Add(permissionCacheTTL)})
+	c.Set("permissions", granted)
+	return granted, nil
+}
@@ -1,6 +0,0 @@
-package constants
-
-const (
-	RoleAdmin = "admin" // RoleAdmin represents the admin role.
-	RoleUser  = "user"  // RoleUser represents the user role.
-)
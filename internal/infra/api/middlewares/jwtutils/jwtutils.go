@@ -1,9 +1,12 @@
 package jwtutils
 
 import (
+	"time"
+
 	"github.com/emur-uy/backend/config"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 )
 
 // ParseToken parses and returns a JWT token, or an error if the token is invalid.
@@ -26,4 +29,41 @@ func SetClaims(c *gin.Context, jwtToken *jwt.Token) {
 	c.Set("email", claims["email"])
 	c.Set("userUUID", claims["user_uuid"])
 	c.Set("role", claims["role"])
+	c.Set("roles", claims["roles"])
+	c.Set("jti", claims["jti"])
+	c.Set("exp", claims["exp"])
+}
+
+// GetIssuedAt returns the time the JWT token was issued at, and false when the token has none.
+func GetIssuedAt(jwtToken *jwt.Token) (time.Time, bool) {
+	claims, ok := jwtToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return time.Time{}, false
+	}
+	iat, ok := claims["iat"].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(iat), 0), true
+}
+
+// GetUserUUID returns the UUID of the user the JWT token was issued to, and false when the token has none.
+func GetUserUUID(jwtToken *jwt.Token) (uuid.UUID, bool) {
+	claims, ok := jwtToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return uuid.Nil, false
+	}
+	value, _ := claims["user_uuid"].(string)
+	userUUID, err := uuid.Parse(value)
+	return userUUID, err == nil
+}
+
+// GetTokenID returns the ID of the JWT token, or an empty string when the token has none.
+func GetTokenID(jwtToken *jwt.Token) string {
+	claims, ok := jwtToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return ""
+	}
+	jti, _ := claims["jti"].(string)
+	return jti
 }
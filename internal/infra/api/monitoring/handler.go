@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/ports"
@@ -88,6 +91,145 @@ func (h *monitoringHandler) GetAllMonitorings(c *gin.Context) {
 	})
 }
 
+// GetMonitoringAnalytics handles the HTTP request for getting the monitoring analytics of the given symptoms.
+// The symptoms are read from the "symptoms" query parameter, either comma separated or repeated, and the
+// range from the "from" and "to" query parameters (dd/mm/yyyy), which defaults to the last 30 days.
+// If the analytics are computed successfully, it returns a 200 OK status with the daily, weekly and monthly aggregates.
+func (h *monitoringHandler) GetMonitoringAnalytics(c *gin.Context) {
+	// Get user UUID from JWT token
+	userUUID, err := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid user UUID", err)
+		return
+	}
+
+	analyticsReq := &entity.RequestMonitoringAnalytics{}
+
+	// Parse the symptom UUIDs
+	if analyticsReq.SymptomUUIDs, err = parseSymptomUUIDs(c); err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid symptom UUID", err)
+		return
+	}
+
+	// Parse the date range, defaulting to the last 30 days
+	if analyticsReq.From, analyticsReq.To, err = parseDateRange(c, 30); err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid date range", err)
+		return
+	}
+
+	// Parse the optional analytics parameters
+	if window := c.Query("window"); window != "" {
+		if analyticsReq.MovingAverageWindow, err = strconv.Atoi(window); err != nil {
+			handleError(c, http.StatusBadRequest, "Invalid moving average window", err)
+			return
+		}
+	}
+	if worseningDays := c.Query("worsening_days"); worseningDays != "" {
+		if analyticsReq.WorseningDays, err = strconv.Atoi(worseningDays); err != nil {
+			handleError(c, http.StatusBadRequest, "Invalid worsening days", err)
+			return
+		}
+	}
+
+	// Call the monitoring service to compute the analytics
+	analytics, statusCode, err := h.monitoringService.GetMonitoringAnalytics(c, userUUID, analyticsReq)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while getting the monitoring analytics", err)
+		return
+	}
+
+	// Return the response with the analytics
+	c.JSON(http.StatusOK, gin.H{
+		"code":    statusCode,
+		"message": "Monitoring analytics retrieved successfully",
+		"data":    analytics,
+	})
+}
+
+// GetWeatherCorrelation handles the HTTP request for correlating the monitorings of the user with the weather.
+// The symptoms are read from the optional "symptoms" query parameter, and the range from the "from" and "to"
+// query parameters (dd/mm/yyyy), which defaults to the last 90 days.
+// If the correlations are computed successfully, it returns a 200 OK status with the coefficients and scatter data.
+func (h *monitoringHandler) GetWeatherCorrelation(c *gin.Context) {
+	// Get user UUID from JWT token
+	userUUID, err := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid user UUID", err)
+		return
+	}
+
+	correlationReq := &entity.RequestWeatherCorrelation{}
+
+	// Parse the symptom UUIDs
+	if correlationReq.SymptomUUIDs, err = parseSymptomUUIDs(c); err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid symptom UUID", err)
+		return
+	}
+
+	// Parse the date range, defaulting to the last 90 days
+	if correlationReq.From, correlationReq.To, err = parseDateRange(c, 90); err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid date range", err)
+		return
+	}
+
+	// Call the monitoring service to compute the correlations
+	correlations, statusCode, err := h.monitoringService.GetWeatherCorrelation(c, userUUID, correlationReq)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while correlating the monitorings with the weather", err)
+		return
+	}
+
+	// Return the response with the correlations
+	c.JSON(http.StatusOK, gin.H{
+		"code":    statusCode,
+		"message": "Weather correlation retrieved successfully",
+		"data":    correlations,
+	})
+}
+
+// parseSymptomUUIDs parses the "symptoms" query parameter, which can be comma separated or repeated.
+func parseSymptomUUIDs(c *gin.Context) ([]uuid.UUID, error) {
+	symptomUUIDs := []uuid.UUID{}
+	for _, param := range c.QueryArray("symptoms") {
+		for _, value := range strings.Split(param, ",") {
+			if strings.TrimSpace(value) == "" {
+				continue
+			}
+			symptomUUID, err := uuid.Parse(strings.TrimSpace(value))
+			if err != nil {
+				return nil, err
+			}
+			symptomUUIDs = append(symptomUUIDs, symptomUUID)
+		}
+	}
+	return symptomUUIDs, nil
+}
+
+// parseDateRange parses the "from" and "to" query parameters (dd/mm/yyyy), the 'to' date being inclusive.
+// When they are missing, the range covers the given number of days up to today.
+func parseDateRange(c *gin.Context, defaultDays int) (time.Time, time.Time, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from := today.AddDate(0, 0, -(defaultDays - 1))
+	to := today.Add(24*time.Hour - time.Nanosecond)
+
+	if fromParam := c.Query("from"); fromParam != "" {
+		parsedFrom, err := time.Parse("02/01/2006", fromParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = parsedFrom
+	}
+	if toParam := c.Query("to"); toParam != "" {
+		parsedTo, err := time.Parse("02/01/2006", toParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = parsedTo.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	return from, to, nil
+}
+
 // handleError handles errors by sending an appropriate response to the client.
 // It takes the gin.Context, status code, error message, and error as parameters.
 func handleError(c *gin.Context, status int, message string, err error) {
@@ -1,7 +1,7 @@
 package monitoring
 
 // @Summary Create monitoring
-// @Description Create a new monitoring
+// @Description Create a new monitoring, or update the one recorded for the same symptom on the same day in the user's timezone
 // @Tags Monitoring
 // @Accept json
 // @Produce json
@@ -25,3 +25,35 @@ func _() {
 func _() {
 	// Swagger annotations.
 }
+
+// @Summary Get monitoring analytics
+// @Description Get daily, weekly and monthly aggregates, a moving average and a worsening flag for the given symptoms
+// @Tags Monitoring
+// @Produce json
+// @Param symptoms query string true "Comma separated symptom UUIDs"
+// @Param from query string false "Start date (dd/mm/yyyy), defaults to 30 days ago"
+// @Param to query string false "End date (dd/mm/yyyy), defaults to today"
+// @Param window query int false "Moving average window in days" default(7)
+// @Param worsening_days query int false "Consecutive increasing days to flag a worsening symptom" default(3)
+// @Success 200 {array} entity.SymptomAnalytics "Monitoring analytics retrieved successfully"
+// @Failure 400 {object} entity.SymptomAnalytics "Invalid input"
+// @Router /api/v1/monitorings/analytics [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Get weather correlation
+// @Description Correlate the daily symptom scale with the temperature, humidity and UV forecast for the user's city
+// @Tags Monitoring
+// @Produce json
+// @Param symptoms query string false "Comma separated symptom UUIDs, defaults to every recorded symptom"
+// @Param from query string false "Start date (dd/mm/yyyy), defaults to 90 days ago"
+// @Param to query string false "End date (dd/mm/yyyy), defaults to today"
+// @Success 200 {array} entity.WeatherCorrelation "Weather correlation retrieved successfully"
+// @Failure 400 {object} entity.WeatherCorrelation "Invalid input"
+// @Router /api/v1/monitorings/weather-correlation [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
@@ -2,8 +2,8 @@ package monitoring
 
 import (
 	"github.com/emur-uy/backend/internal/infra/api/middlewares"
-	"github.com/emur-uy/backend/internal/infra/api/middlewares/constants"
 	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/service/monitoring"
 	"github.com/gin-gonic/gin"
 )
@@ -24,9 +24,11 @@ func RegisterRoutes(e *gin.Engine) {
 	// Group the monitoring routes together.
 	monitoringRoutes := e.Group("/api/v1/monitorings")
 
-	// Register user routes requiring authentication and authorization for user role.
-	userRoutes := monitoringRoutes.Group("", middlewares.Authenticate(), middlewares.Authorize(constants.RoleUser))
+	// Register user routes requiring authentication and the patient:self permission, recorded in the audit log.
+	userRoutes := monitoringRoutes.Group("", middlewares.Authenticate(), middlewares.Audit(entity.AuditResourceMonitoring), middlewares.RequirePermission(entity.PermissionPatientSelf))
 	userRoutes.POST("/", handler.CreateMonitoring)
 	userRoutes.GET("/", handler.GetAllMonitorings)
+	userRoutes.GET("/analytics", handler.GetMonitoringAnalytics)
+	userRoutes.GET("/weather-correlation", handler.GetWeatherCorrelation)
 
 }
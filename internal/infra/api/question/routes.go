@@ -2,8 +2,8 @@ package question
 
 import (
 	"github.com/emur-uy/backend/internal/infra/api/middlewares"
-	"github.com/emur-uy/backend/internal/infra/api/middlewares/constants"
 	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/service/question"
 	"github.com/gin-gonic/gin"
 )
@@ -24,9 +24,8 @@ func RegisterRoutes(e *gin.Engine) {
 	// Group the question routes together.
 	questionRoutes := e.Group("/api/v1/questions")
 
-	// Register route for getting all questions accessible to both admin and user roles.
-	allowedRoles := []string{constants.RoleAdmin, constants.RoleUser}
-	questionRoutes.GET("", middlewares.Authenticate(), middlewares.Authorize(allowedRoles...), handler.GetAllQuestions)
-	questionRoutes.POST("", middlewares.Authenticate(), middlewares.Authorize(allowedRoles...), handler.CreateQuestion)
-	questionRoutes.GET("/:uuid", middlewares.Authenticate(), middlewares.Authorize(allowedRoles...), handler.GetAllQuestionsAndAnswers)
+	// Register question routes requiring the questions:read and questions:write permissions.
+	questionRoutes.GET("", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionQuestionsRead), handler.GetAllQuestions)
+	questionRoutes.POST("", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionQuestionsWrite), handler.CreateQuestion)
+	questionRoutes.GET("/:uuid", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionQuestionsRead), handler.GetAllQuestionsAndAnswers)
 }
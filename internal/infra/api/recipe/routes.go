@@ -1,9 +1,11 @@
 package recipe
 
 import (
+	"github.com/emur-uy/backend/config"
 	"github.com/emur-uy/backend/internal/infra/api/middlewares"
-	"github.com/emur-uy/backend/internal/infra/api/middlewares/constants"
 	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/infra/storage"
+	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/service/media"
 	"github.com/emur-uy/backend/internal/pkg/service/recipe"
 	"github.com/gin-gonic/gin"
@@ -22,7 +24,7 @@ func RegisterRoutes(e *gin.Engine) {
 	recipeMediaRepo := postgresql.NewRecipeMediaRepository(client)
 
 	// Create new services
-	mediaService := media.NewService(mediaRepo)
+	mediaService := media.NewService(mediaRepo, storage.NewBlobStore(config.Get()))
 	recipeMediaService := recipe.NewRecipeMediaService(recipeMediaRepo)
 	recipeService := recipe.NewService(recipeRepo, mediaService, recipeMediaService)
 
@@ -32,14 +34,13 @@ func RegisterRoutes(e *gin.Engine) {
 	// Group the recipe routes together.
 	recipeRoutes := e.Group("/api/v1/recipes")
 
-	// Register admin routes requiring authentication and authorization for admin role.
-	adminRoutes := recipeRoutes.Group("", middlewares.Authenticate(), middlewares.Authorize(constants.RoleAdmin))
+	// Register admin routes requiring authentication and the recipes:write permission.
+	adminRoutes := recipeRoutes.Group("", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionRecipesWrite))
 	adminRoutes.POST("", handler.CreateRecipe)
 	adminRoutes.DELETE("/:uuid", handler.DeleteRecipe)
 	adminRoutes.PUT("/:uuid", handler.UpdateRecipe)
 
-	// Register route for getting all recipes accessible to both admin and user roles.
-	allowedRoles := []string{constants.RoleAdmin, constants.RoleUser}
-	recipeRoutes.GET("", middlewares.Authenticate(), middlewares.Authorize(allowedRoles...), handler.GetAllRecipes)
-	recipeRoutes.POST("/:uuid/vote", middlewares.Authenticate(), middlewares.Authorize(allowedRoles...), handler.VoteRecipe)
+	// Register route for getting all recipes and voting requiring the recipes:read and recipes:vote permissions.
+	recipeRoutes.GET("", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionRecipesRead), handler.GetAllRecipes)
+	recipeRoutes.POST("/:uuid/vote", middlewares.Authenticate(), middlewares.RequirePermission(entity.PermissionRecipesVote), handler.VoteRecipe)
 }
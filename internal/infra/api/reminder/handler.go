@@ -115,6 +115,37 @@ func (r *reminderHandler) GetAllReminders(c *gin.Context) {
 	})
 }
 
+// GetReminderMedia handles the HTTP request for getting the media of a reminder.
+// The media are private, it returns new signed URLs to read them, valid for a short time.
+func (r *reminderHandler) GetReminderMedia(c *gin.Context) {
+	// Get user UUID from context
+	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	// Parse the reminder UUID from the URL parameter.
+	reminderUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	media, status, err := r.reminderService.GetReminderMedia(c, userUUID, reminderUUID)
+	if err != nil {
+		message := "An error occurred while fetching the reminder media"
+		if status == http.StatusNotFound {
+			message = "Reminder not found"
+		}
+		handleError(c, status, message, err)
+		return
+	}
+
+	// Return a successful response.
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Reminder media fetched successfully",
+		"data":    media,
+	})
+}
+
 // UpdateReminder handles the HTTP request for updating a reminder.
 // It parses the reminder UUID from the URL parameter, binds the incoming form-data fields to the reqUpdate struct,
 // and calls the reminder service to update the reminder in the database.
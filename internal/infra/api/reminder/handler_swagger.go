@@ -32,6 +32,21 @@ func _() {
 	// Swagger annotations.
 }
 
+// @Summary Get reminder media
+// @Description Get the media of a reminder of the user with new signed URLs. The media of the reminders are private, the URLs expire after 15 minutes.
+// @Tags Reminder
+// @Produce json
+// @Param uuid path string true "Reminder UUID"
+// @Success 200 {array} entity.GetReminderMediaResponse "Reminder media fetched successfully"
+// @Failure 400 "Invalid UUID format"
+// @Failure 404 "Reminder not found"
+// @Router /api/v1/reminders/{uuid}/media [get]
+// @Security Bearer
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
 // @Summary Update reminder
 // @Description Update an existing reminder
 // @Tags Reminder
@@ -1,9 +1,11 @@
 package reminder
 
 import (
+	"github.com/emur-uy/backend/config"
 	"github.com/emur-uy/backend/internal/infra/api/middlewares"
-	"github.com/emur-uy/backend/internal/infra/api/middlewares/constants"
 	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/infra/storage"
+	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/service/media"
 	"github.com/emur-uy/backend/internal/pkg/service/reminder"
 
@@ -23,20 +25,21 @@ func RegisterRoutes(e *gin.Engine) {
 	reminderMediaRepo := postgresql.NewReminderMediaRepository(client)
 
 	// Create new services
-	mediaService := media.NewService(mediaRepo)
+	mediaService := media.NewService(mediaRepo, storage.NewBlobStore(config.Get()))
 	reminderMediaService := reminder.NewReminderMediaService(reminderMediaRepo)
 	reminderService := reminder.NewService(reminderRepo, mediaService, reminderMediaService)
 
 	// Create a new reminderHandler instance by injecting the ReminderService.
 	handler := newHandler(reminderService)
 
-	// Group the reminder routes together.
+	// Group the reminder routes together, recorded in the audit log.
 	reminderRoutes := e.Group("/api/v1/reminders")
-	reminderRoutes.Use(middlewares.Authenticate(), middlewares.Authorize(constants.RoleUser))
+	reminderRoutes.Use(middlewares.Authenticate(), middlewares.Audit(entity.AuditResourceReminder), middlewares.RequirePermission(entity.PermissionPatientSelf))
 
-	// Register user routes requiring authentication and authorization for user role.
+	// Register user routes requiring authentication and the patient:self permission.
 	reminderRoutes.POST("", handler.CreateReminder)
 	reminderRoutes.GET("", handler.GetAllReminders)
 	reminderRoutes.PUT("", handler.UpdateReminder)
 	reminderRoutes.DELETE("", handler.DeleteReminder)
+	reminderRoutes.GET("/:uuid/media", handler.GetReminderMedia)
 }
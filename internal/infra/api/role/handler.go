@@ -0,0 +1,169 @@
+package role
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// roleHandler type contains an instance of RoleService.
+type roleHandler struct {
+	roleService ports.RoleService
+}
+
+// newHandler is a constructor function for initializing roleHandler with the given RoleService.
+// The return is a pointer to a roleHandler instance.
+func newHandler(roleService ports.RoleService) *roleHandler {
+	return &roleHandler{
+		roleService: roleService,
+	}
+}
+
+// GetRoles handles the HTTP request for getting every role with its permissions.
+func (h *roleHandler) GetRoles(ctx *gin.Context) {
+	roles, err := h.roleService.GetRoles()
+	if err != nil {
+		handleError(ctx, http.StatusInternalServerError, "An error occurred while getting the roles", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Roles retrieved successfully",
+		"data":    roles,
+	})
+}
+
+// GetPermissions handles the HTTP request for getting the permissions that can be granted to a role.
+func (h *roleHandler) GetPermissions(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Permissions retrieved successfully",
+		"data":    h.roleService.GetPermissions(),
+	})
+}
+
+// CreateRole handles the HTTP request for creating a role.
+// It binds the incoming JSON payload to the reqCreate struct.
+// If the role is created successfully, it will return a 201 Created status with the created role.
+func (h *roleHandler) CreateRole(ctx *gin.Context) {
+	reqCreate := &entity.RequestCreateRole{}
+	if err := ctx.ShouldBindJSON(reqCreate); err != nil {
+		handleError(ctx, http.StatusBadRequest, "Invalid input", err)
+		return
+	}
+
+	createdRole, status, err := h.roleService.CreateRole(reqCreate)
+	if err != nil {
+		handleServiceError(ctx, status, "An error occurred while creating the role", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"code":    http.StatusCreated,
+		"message": "Role created successfully",
+		"data":    createdRole,
+	})
+}
+
+// UpdateRole handles the HTTP request for replacing the description and the permissions of a role.
+func (h *roleHandler) UpdateRole(ctx *gin.Context) {
+	reqUpdate := &entity.RequestUpdateRole{}
+	if err := ctx.ShouldBindJSON(reqUpdate); err != nil {
+		handleError(ctx, http.StatusBadRequest, "Invalid input", err)
+		return
+	}
+
+	updatedRole, status, err := h.roleService.UpdateRole(ctx.Param("role"), reqUpdate)
+	if err != nil {
+		handleServiceError(ctx, status, "An error occurred while updating the role", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Role updated successfully",
+		"data":    updatedRole,
+	})
+}
+
+// DeleteRole handles the HTTP request for deleting a role.
+func (h *roleHandler) DeleteRole(ctx *gin.Context) {
+	status, err := h.roleService.DeleteRole(ctx.Param("role"))
+	if err != nil {
+		handleServiceError(ctx, status, "An error occurred while deleting the role", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Role deleted successfully",
+		"data":    nil,
+	})
+}
+
+// AssignRole handles the HTTP request for granting a role to a user.
+func (h *roleHandler) AssignRole(ctx *gin.Context) {
+	userUUID, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		handleError(ctx, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	status, err := h.roleService.AssignRole(ctx.Param("role"), userUUID)
+	if err != nil {
+		handleServiceError(ctx, status, "An error occurred while assigning the role", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Role assigned successfully",
+		"data":    nil,
+	})
+}
+
+// UnassignRole handles the HTTP request for revoking a role from a user.
+func (h *roleHandler) UnassignRole(ctx *gin.Context) {
+	userUUID, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		handleError(ctx, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	status, err := h.roleService.UnassignRole(ctx.Param("role"), userUUID)
+	if err != nil {
+		handleServiceError(ctx, status, "An error occurred while unassigning the role", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Role unassigned successfully",
+		"data":    nil,
+	})
+}
+
+// handleServiceError responds with the service error message for the client errors, and with the
+// generic message otherwise.
+func handleServiceError(ctx *gin.Context, statusCode int, message string, err error) {
+	if statusCode >= http.StatusBadRequest && statusCode < http.StatusInternalServerError {
+		message = err.Error()
+	}
+	handleError(ctx, statusCode, message, err)
+}
+
+// handleError is a generic error handler that logs the error and responds with the corresponding status code and error message.
+func handleError(ctx *gin.Context, statusCode int, message string, err error) {
+	log.Printf("[RoleHandler]: %s, %v", message, err)
+
+	ctx.JSON(statusCode, gin.H{
+		"code":    statusCode,
+		"message": message,
+		"data":    nil,
+	})
+}
@@ -0,0 +1,93 @@
+package role
+
+// @Summary Get all roles
+// @Description Get every role with its permissions. Requires the roles:manage permission.
+// @Tags Roles
+// @Produce json
+// @Success 200 {array} entity.Role "Roles retrieved successfully"
+// @Router /api/v1/roles [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Get all permissions
+// @Description Get the permissions that can be granted to a role. Requires the roles:manage permission.
+// @Tags Roles
+// @Produce json
+// @Success 200 {array} string "Permissions retrieved successfully"
+// @Router /api/v1/roles/permissions [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Create role
+// @Description Create a new role with a set of permissions. Requires the roles:manage permission.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param body body entity.RequestCreateRole true "Role object"
+// @Success 201 {object} entity.Role "Role created successfully"
+// @Failure 400 "Invalid role name or permission"
+// @Failure 409 "Role already exists"
+// @Router /api/v1/roles [post]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Update role
+// @Description Replace the description and the permissions of a role. Requires the roles:manage permission.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param role path string true "Name of the role"
+// @Param body body entity.RequestUpdateRole true "Role permissions"
+// @Success 200 {object} entity.Role "Role updated successfully"
+// @Failure 400 "Invalid permission"
+// @Failure 404 "Role not found"
+// @Router /api/v1/roles/{role} [put]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Delete role
+// @Description Delete a role and revoke it from its users. The admin and user roles cannot be deleted. Requires the roles:manage permission.
+// @Tags Roles
+// @Param role path string true "Name of the role"
+// @Success 200 "Role deleted successfully"
+// @Failure 400 "Built-in role"
+// @Failure 404 "Role not found"
+// @Router /api/v1/roles/{role} [delete]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Assign role
+// @Description Grant a role to a user. Requires the roles:manage permission.
+// @Tags Roles
+// @Param role path string true "Name of the role"
+// @Param uuid path string true "UUID of the user"
+// @Success 200 "Role assigned successfully"
+// @Failure 404 "Role or user not found"
+// @Router /api/v1/roles/{role}/users/{uuid} [put]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Unassign role
+// @Description Revoke a role from a user. Requires the roles:manage permission.
+// @Tags Roles
+// @Param role path string true "Name of the role"
+// @Param uuid path string true "UUID of the user"
+// @Success 200 "Role unassigned successfully"
+// @Failure 404 "Role or user not found"
+// @Router /api/v1/roles/{role}/users/{uuid} [delete]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
@@ -0,0 +1,38 @@
+package role
+
+import (
+	"github.com/emur-uy/backend/internal/infra/api/middlewares"
+	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/service/role"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes sets up the role-related routes on the given gin.Engine instance.
+// It initializes the necessary components, such as the repository, service, and handler,
+// to handle role-related operations in a hexagonal architecture.
+func RegisterRoutes(e *gin.Engine) {
+	// Initialize the repositories by creating a new PostgreSQL client.
+	client := postgresql.NewClient()
+	roleRepo := postgresql.NewRoleRepository(client)
+
+	// Create a new RoleService instance by injecting the repositories.
+	service := role.NewService(roleRepo, client)
+
+	// Create a new roleHandler instance by injecting the RoleService.
+	handler := newHandler(service)
+
+	// Group the role routes together, requiring the roles:manage permission.
+	roleRoutes := e.Group("/api/v1/roles")
+	middlewares.RegisterAuthMiddlewares(roleRoutes, entity.PermissionRolesManage)
+
+	roleRoutes.GET("", handler.GetRoles)
+	roleRoutes.GET("/permissions", handler.GetPermissions)
+	roleRoutes.POST("", handler.CreateRole)
+	roleRoutes.PUT("/:role", handler.UpdateRole)
+	roleRoutes.DELETE("/:role", handler.DeleteRole)
+
+	// Register routes for assigning the roles to the users.
+	roleRoutes.PUT("/:role/users/:uuid", handler.AssignRole)
+	roleRoutes.DELETE("/:role/users/:uuid", handler.UnassignRole)
+}
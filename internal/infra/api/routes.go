@@ -5,18 +5,30 @@ import (
 	"github.com/emur-uy/backend/docs"
 	"github.com/emur-uy/backend/internal/infra/api/answer"
 	"github.com/emur-uy/backend/internal/infra/api/article"
+	"github.com/emur-uy/backend/internal/infra/api/audit"
 	"github.com/emur-uy/backend/internal/infra/api/category"
+	"github.com/emur-uy/backend/internal/infra/api/clinician"
+	"github.com/emur-uy/backend/internal/infra/api/erasure"
+	"github.com/emur-uy/backend/internal/infra/api/export"
+	"github.com/emur-uy/backend/internal/infra/api/files"
+	"github.com/emur-uy/backend/internal/infra/api/forecast"
 	"github.com/emur-uy/backend/internal/infra/api/healthservice"
 	"github.com/emur-uy/backend/internal/infra/api/maps"
 	"github.com/emur-uy/backend/internal/infra/api/medical"
 	"github.com/emur-uy/backend/internal/infra/api/medicalrecord"
+	"github.com/emur-uy/backend/internal/infra/api/middlewares"
 	"github.com/emur-uy/backend/internal/infra/api/monitoring"
 	"github.com/emur-uy/backend/internal/infra/api/question"
 	"github.com/emur-uy/backend/internal/infra/api/recipe"
 	"github.com/emur-uy/backend/internal/infra/api/reminder"
+	"github.com/emur-uy/backend/internal/infra/api/role"
 	"github.com/emur-uy/backend/internal/infra/api/symptom"
+	"github.com/emur-uy/backend/internal/infra/api/trash"
 	"github.com/emur-uy/backend/internal/infra/api/treatment"
+	"github.com/emur-uy/backend/internal/infra/api/upload"
 	"github.com/emur-uy/backend/internal/infra/api/user"
+	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	auditService "github.com/emur-uy/backend/internal/pkg/service/audit"
 	"github.com/gin-gonic/gin" // Importing gin package for http web framework
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -31,6 +43,15 @@ func RegisterRoutes(e *gin.Engine) {
 	// It is recommended to use a dedicated health check package for this purpose
 	e.GET("/health", healthcheck.Default())
 
+	// Check the revoked access tokens on every authenticated request
+	middlewares.SetRevocationList(postgresql.NewTokenRepository(postgresql.NewClient()))
+
+	// Resolve the permissions granted by the roles of the users
+	middlewares.SetPermissionResolver(postgresql.NewRoleRepository(postgresql.NewClient()))
+
+	// Record the accesses to the health data in the audit log
+	middlewares.SetAuditor(auditService.NewService(postgresql.NewAuditRepository(postgresql.NewClient())))
+
 	// Register user routes
 	user.RegisterRoutes(e)
 	article.RegisterRoutes(e)
@@ -46,6 +67,15 @@ func RegisterRoutes(e *gin.Engine) {
 	monitoring.RegisterRoutes(e)
 	medicalrecord.RegisterRoutes(e)
 	maps.RegisterRoutes(e)
+	forecast.RegisterRoutes(e)
+	role.RegisterRoutes(e)
+	clinician.RegisterRoutes(e)
+	audit.RegisterRoutes(e)
+	export.RegisterRoutes(e)
+	erasure.RegisterRoutes(e)
+	trash.RegisterRoutes(e)
+	files.RegisterRoutes(e)
+	upload.RegisterRoutes(e)
 
 	// use ginSwagger middleware to serve the API docs
 	e.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
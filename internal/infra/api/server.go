@@ -2,9 +2,10 @@ package api
 
 import (
 	"log"
+	"strings"
 
+	"github.com/emur-uy/backend/config"
 	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
-	aws "github.com/emur-uy/backend/internal/infra/repositories/spaces"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,26 +18,36 @@ func RunServer(address string) {
 	// Create a Gin server instance with default middlewares
 	server := gin.Default()
 
+	// Only trust the client IP forwarded by the configured proxies
+	if err := server.SetTrustedProxies(trustedProxies(config.Get().TrustedProxies)); err != nil {
+		log.Fatalf("Error setting the trusted proxies: %v", err)
+	}
+
 	// Set CORS configuration as default for all routes
 	server.Use(CORS())
 
-	//start aws services
-	err := aws.Init()
-	if err != nil {
-		log.Fatalf("error initializing aws services: %s", err)
-	}
-
 	// Register all API routes
 	RegisterRoutes(server)
 
 	// Start running the server on the specified address
 
-	err = server.Run(":" + address)
+	err := server.Run(":" + address)
 	if err != nil {
 		log.Fatalf("Error starting server: %v", err)
 	}
 }
 
+// trustedProxies parses the comma separated proxies, returning nil to trust none.
+func trustedProxies(value string) []string {
+	var proxies []string
+	for _, proxy := range strings.Split(value, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
 // CORS returns a Gin middleware function with CORS configurations to allow requests from frontend.
 func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -2,8 +2,8 @@ package symptom
 
 import (
 	"github.com/emur-uy/backend/internal/infra/api/middlewares"
-	"github.com/emur-uy/backend/internal/infra/api/middlewares/constants"
 	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/service/symptom"
 	"github.com/gin-gonic/gin"
 )
@@ -24,12 +24,12 @@ func RegisterRoutes(e *gin.Engine) {
 	// Group the symptom routes together.
 	symptomRoutes := e.Group("/api/v1/symptoms", middlewares.Authenticate())
 
-	// Register routes for admin role.
-	symptomRoutes.POST("", middlewares.Authorize(constants.RoleAdmin), handler.CreateSymptom)
-	symptomRoutes.GET("", middlewares.Authorize(constants.RoleAdmin, constants.RoleUser), handler.GetAllSymptoms)
+	// Register routes for managing the symptom catalog.
+	symptomRoutes.POST("", middlewares.RequirePermission(entity.PermissionSymptomsWrite), handler.CreateSymptom)
+	symptomRoutes.GET("", middlewares.RequirePermission(entity.PermissionSymptomsRead), handler.GetAllSymptoms)
 
-	// Register routes for user role.
-	symptomRoutes.POST("/add", middlewares.Authorize(constants.RoleUser), handler.AddUserToSymptom)
-	symptomRoutes.POST("/remove", middlewares.Authorize(constants.RoleUser), handler.RemoveUserFromSymptom)
-	symptomRoutes.GET("/all", middlewares.Authorize(constants.RoleUser), handler.GetSymptomsByUser)
+	// Register routes for the symptoms of the user, requiring the patient:self permission.
+	symptomRoutes.POST("/add", middlewares.RequirePermission(entity.PermissionPatientSelf), handler.AddUserToSymptom)
+	symptomRoutes.POST("/remove", middlewares.RequirePermission(entity.PermissionPatientSelf), handler.RemoveUserFromSymptom)
+	symptomRoutes.GET("/all", middlewares.RequirePermission(entity.PermissionPatientSelf), handler.GetSymptomsByUser)
 }
@@ -0,0 +1,85 @@
+package trash
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// trashHandler type contains an instance of TrashService.
+type trashHandler struct {
+	trashService ports.TrashService
+}
+
+// newHandler is a constructor function for initializing trashHandler with the given TrashService.
+// The return is a pointer to a trashHandler instance.
+func newHandler(trashService ports.TrashService) *trashHandler {
+	return &trashHandler{
+		trashService: trashService,
+	}
+}
+
+// GetTrash handles the HTTP request for listing the deleted records of a resource.
+func (h *trashHandler) GetTrash(ctx *gin.Context) {
+	request := &entity.RequestTrash{}
+	if err := ctx.ShouldBindQuery(request); err != nil {
+		handleError(ctx, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	result, status, err := h.trashService.GetTrash(ctx.Param("resource"), request)
+	if err != nil {
+		message := "An error occurred while getting the trash"
+		if status == http.StatusBadRequest {
+			message = err.Error()
+		}
+		handleError(ctx, status, message, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Trash retrieved successfully",
+		"data":    result,
+	})
+}
+
+// RestoreItem handles the HTTP request for restoring a deleted record of a resource.
+func (h *trashHandler) RestoreItem(ctx *gin.Context) {
+	itemUUID, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		handleError(ctx, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	status, err := h.trashService.RestoreItem(ctx.Param("resource"), itemUUID)
+	if err != nil {
+		message := "An error occurred while restoring the deleted record"
+		if status == http.StatusBadRequest || status == http.StatusNotFound {
+			message = err.Error()
+		}
+		handleError(ctx, status, message, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Record restored successfully",
+		"data":    nil,
+	})
+}
+
+// handleError is a generic error handler that logs the error and responds with the corresponding status code and error message.
+func handleError(ctx *gin.Context, statusCode int, message string, err error) {
+	log.Printf("[TrashHandler]: %s, %v", message, err)
+
+	ctx.JSON(statusCode, gin.H{
+		"code":    statusCode,
+		"message": message,
+		"data":    nil,
+	})
+}
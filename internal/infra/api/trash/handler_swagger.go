@@ -0,0 +1,31 @@
+package trash
+
+// @Summary List the trash
+// @Description Get a page of the deleted records of a resource, last deleted first, with the date they will be purged. Requires the trash:manage permission.
+// @Tags Trash
+// @Produce json
+// @Param resource path string true "Resource (reminders, articles or recipes)"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of records per page, 50 by default and up to 200"
+// @Success 200 {object} entity.TrashResult "Trash retrieved successfully"
+// @Failure 400 "Unknown resource"
+// @Router /api/v1/trash/{resource} [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Restore a deleted record
+// @Description Restore a deleted reminder, article or recipe with its media. Requires the trash:manage permission.
+// @Tags Trash
+// @Produce json
+// @Param resource path string true "Resource (reminders, articles or recipes)"
+// @Param uuid path string true "UUID of the deleted record"
+// @Success 200 "Record restored successfully"
+// @Failure 400 "Unknown resource or invalid UUID"
+// @Failure 404 "Deleted record not found"
+// @Router /api/v1/trash/{resource}/{uuid}/restore [put]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
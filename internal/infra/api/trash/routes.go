@@ -0,0 +1,32 @@
+package trash
+
+import (
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/infra/api/middlewares"
+	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/infra/storage"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/service/trash"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes sets up the trash routes on the given gin.Engine instance.
+// It initializes the necessary components, such as the repository, service, and handler,
+// to handle trash operations in a hexagonal architecture.
+func RegisterRoutes(e *gin.Engine) {
+	// Initialize the repository by creating a new PostgreSQL client.
+	trashRepo := postgresql.NewTrashRepository(postgresql.NewClient())
+
+	// Create a new TrashService instance by injecting the repository and the blob store.
+	service := trash.NewService(trashRepo, storage.NewBlobStore(config.Get()))
+
+	// Create a new trashHandler instance by injecting the TrashService.
+	handler := newHandler(service)
+
+	// Group the trash routes together, requiring the trash:manage permission.
+	trashRoutes := e.Group("/api/v1/trash")
+	middlewares.RegisterAuthMiddlewares(trashRoutes, entity.PermissionTrashManage)
+
+	trashRoutes.GET("/:resource", handler.GetTrash)
+	trashRoutes.PUT("/:resource/:uuid/restore", handler.RestoreItem)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/ports"
@@ -134,3 +135,134 @@ func (t *treatmentHandler) UpdateTreatment(c *gin.Context) {
 		"message": "Treatment updated successfully",
 	})
 }
+
+// GetDoses handles the HTTP request for listing the dose occurrences of a treatment.
+// It parses the treatment UUID from the path parameter and the optional 'from' and 'to' query parameters (format: dd/MM/yyyy),
+// which default to the current week, and calls the treatment service to expand the schedule.
+// If any error occurs during this process, it returns the corresponding status code and error message.
+// If the doses are retrieved successfully, it returns a 200 OK status with the dose occurrences.
+func (t *treatmentHandler) GetDoses(c *gin.Context) {
+	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	// Parse the treatment UUID from the path parameter.
+	treatmentUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	// Parse the date range, by default the 7 days starting today.
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from, to, err := parseDateRange(c, today, today.AddDate(0, 0, 6))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid date format", err)
+		return
+	}
+
+	doses, statusCode, err := t.treatmentService.GetDoses(userUUID, treatmentUUID, from, to)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while retrieving the treatment doses", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Treatment doses retrieved successfully",
+		"data":    doses,
+	})
+}
+
+// RecordDose handles the HTTP request for marking a dose of a treatment as taken, late or skipped.
+// It parses the treatment UUID from the path parameter and binds the incoming JSON payload to the recordReq struct.
+// If any error occurs during this process, it returns the corresponding status code and error message.
+// If the dose is recorded successfully, it returns a 200 OK status with the recorded dose.
+func (t *treatmentHandler) RecordDose(c *gin.Context) {
+	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	// Parse the treatment UUID from the path parameter.
+	treatmentUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	// Bind the incoming JSON to a struct.
+	recordReq := &entity.RequestRecordDose{}
+	if err := c.ShouldBindJSON(recordReq); err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid input", err)
+		return
+	}
+
+	dose, statusCode, err := t.treatmentService.RecordDose(userUUID, treatmentUUID, recordReq)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while recording the treatment dose", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Treatment dose recorded successfully",
+		"data":    dose,
+	})
+}
+
+// GetAdherence handles the HTTP request for the weekly adherence report of a treatment.
+// It parses the treatment UUID from the path parameter and the optional 'from' and 'to' query parameters (format: dd/MM/yyyy),
+// which default to the last four weeks.
+// If any error occurs during this process, it returns the corresponding status code and error message.
+// If the report is computed successfully, it returns a 200 OK status with the adherence report.
+func (t *treatmentHandler) GetAdherence(c *gin.Context) {
+	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	// Parse the treatment UUID from the path parameter.
+	treatmentUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	// Parse the date range, by default the last 4 weeks including today.
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from, to, err := parseDateRange(c, today.AddDate(0, 0, -27), today)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid date format", err)
+		return
+	}
+
+	report, statusCode, err := t.treatmentService.GetAdherence(userUUID, treatmentUUID, from, to)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while computing the treatment adherence", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Treatment adherence retrieved successfully",
+		"data":    report,
+	})
+}
+
+// parseDateRange reads the 'from' and 'to' query parameters (format: dd/MM/yyyy), using the given days when absent.
+// The returned range covers both days completely.
+func parseDateRange(c *gin.Context, defaultFrom time.Time, defaultTo time.Time) (time.Time, time.Time, error) {
+	layout := "02/01/2006"
+	from, to := defaultFrom, defaultTo
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.Parse(layout, fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = parsed
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := time.Parse(layout, toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = parsed
+	}
+
+	return from, to.Add(24*time.Hour - time.Second), nil
+}
@@ -55,3 +55,51 @@ func _() {
 func _() {
 	// Swagger annotations.
 }
+
+// @Summary Get treatment doses
+// @Description Expand a treatment into its dose occurrences with the status recorded for each one
+// @Tags Treatment
+// @Produce json
+// @Param uuid path string true "UUID of the treatment"
+// @Param from query string false "First day of the range (format: dd/MM/yyyy), defaults to today"
+// @Param to query string false "Last day of the range (format: dd/MM/yyyy), defaults to 6 days after today"
+// @Success 200 {array} entity.DoseOccurrence "Treatment doses retrieved successfully"
+// @Failure 400 {object} entity.DoseOccurrence "Invalid UUID or date format"
+// @Failure 403 {object} entity.DoseOccurrence "User is not authorized to access the treatment doses"
+// @Router /api/v1/treatments/{uuid}/doses [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Record treatment dose
+// @Description Mark a dose of a treatment as taken, late or skipped
+// @Tags Treatment
+// @Accept json
+// @Produce json
+// @Param uuid path string true "UUID of the treatment"
+// @Param body body entity.RequestRecordDose true "Scheduled time and status of the dose"
+// @Success 200 {object} entity.TreatmentDose "Treatment dose recorded successfully"
+// @Failure 400 {object} entity.TreatmentDose "Invalid input or dose not scheduled"
+// @Failure 403 {object} entity.TreatmentDose "User is not authorized to access the treatment doses"
+// @Router /api/v1/treatments/{uuid}/doses [put]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Get treatment adherence
+// @Description Get the weekly percentage of doses taken for a treatment
+// @Tags Treatment
+// @Produce json
+// @Param uuid path string true "UUID of the treatment"
+// @Param from query string false "First day of the range (format: dd/MM/yyyy), defaults to 27 days before today"
+// @Param to query string false "Last day of the range (format: dd/MM/yyyy), defaults to today"
+// @Success 200 {object} entity.AdherenceReport "Treatment adherence retrieved successfully"
+// @Failure 400 {object} entity.AdherenceReport "Invalid UUID or date format"
+// @Failure 403 {object} entity.AdherenceReport "User is not authorized to access the treatment doses"
+// @Router /api/v1/treatments/{uuid}/doses/adherence [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
@@ -2,8 +2,8 @@ package treatment
 
 import (
 	"github.com/emur-uy/backend/internal/infra/api/middlewares"
-	"github.com/emur-uy/backend/internal/infra/api/middlewares/constants"
 	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/service/treatment"
 	"github.com/gin-gonic/gin"
 )
@@ -24,10 +24,13 @@ func RegisterRoutes(e *gin.Engine) {
 	// Group the treatment routes together.
 	treatmentRoutes := e.Group("/api/v1/treatments")
 
-	// Register routes requiring authentication and authorization for user role.
-	userRoutes := treatmentRoutes.Group("", middlewares.Authenticate(), middlewares.Authorize(constants.RoleUser))
+	// Register routes requiring authentication and the patient:self permission, recorded in the audit log.
+	userRoutes := treatmentRoutes.Group("", middlewares.Authenticate(), middlewares.Audit(entity.AuditResourceTreatment), middlewares.RequirePermission(entity.PermissionPatientSelf))
 	userRoutes.POST("", handler.CreateTreatment)
 	userRoutes.DELETE("/:uuid", handler.DeleteTreatment)
 	userRoutes.PUT("/:uuid", handler.UpdateTreatment)
 	userRoutes.GET("", handler.GetAllTreatments)
+	userRoutes.GET("/:uuid/doses", handler.GetDoses)
+	userRoutes.PUT("/:uuid/doses", handler.RecordDose)
+	userRoutes.GET("/:uuid/doses/adherence", handler.GetAdherence)
 }
@@ -0,0 +1,152 @@
+package upload
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/emur-uy/backend/internal/infra/api/middlewares"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// uploadPermissions are the permissions required to upload the files of each domain.
+var uploadPermissions = map[string]string{
+	entity.MediaDomainReminders: entity.PermissionPatientSelf,
+	entity.MediaDomainArticles:  entity.PermissionArticlesWrite,
+	entity.MediaDomainRecipes:   entity.PermissionRecipesWrite,
+}
+
+// uploadHandler type contains an instance of UploadService.
+type uploadHandler struct {
+	uploadService ports.UploadService
+}
+
+// newHandler is a constructor function for initializing uploadHandler with the given UploadService.
+// The return is a pointer to an uploadHandler instance.
+func newHandler(uploadService ports.UploadService) *uploadHandler {
+	return &uploadHandler{
+		uploadService: uploadService,
+	}
+}
+
+// CreateUpload handles the HTTP request for opening an upload session, once the user is checked to have the
+// permission to edit the records of the domain. The file is then uploaded to the returned URLs.
+func (h *uploadHandler) CreateUpload(c *gin.Context) {
+	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	request := &entity.RequestCreateUpload{}
+	if err := c.ShouldBindJSON(request); err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if permission, ok := uploadPermissions[request.Domain]; ok && !middlewares.HasPermission(c, permission) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to access this resource"})
+		return
+	}
+
+	upload, statusCode, err := h.uploadService.CreateUpload(userUUID, request)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while creating the upload", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"code":    http.StatusCreated,
+		"message": "Upload created successfully",
+		"data":    upload,
+	})
+}
+
+// GetUpload handles the HTTP request for getting a pending upload session with new upload URLs,
+// to resume an interrupted upload.
+func (h *uploadHandler) GetUpload(c *gin.Context) {
+	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	uploadUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	upload, statusCode, err := h.uploadService.GetUpload(userUUID, uploadUUID)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while getting the upload", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Upload retrieved successfully",
+		"data":    upload,
+	})
+}
+
+// ConfirmUpload handles the HTTP request for confirming an upload, with the ETags of the parts of a multipart
+// upload. The uploaded file is checked and attached as a media of the record.
+func (h *uploadHandler) ConfirmUpload(c *gin.Context) {
+	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	uploadUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	request := &entity.RequestConfirmUpload{}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(request); err != nil {
+			handleError(c, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+	}
+
+	media, statusCode, err := h.uploadService.ConfirmUpload(userUUID, uploadUUID, request)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while confirming the upload", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Upload confirmed successfully",
+		"data":    media,
+	})
+}
+
+// AbortUpload handles the HTTP request for cancelling a pending upload session.
+func (h *uploadHandler) AbortUpload(c *gin.Context) {
+	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+
+	uploadUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	statusCode, err := h.uploadService.AbortUpload(userUUID, uploadUUID)
+	if err != nil {
+		handleError(c, statusCode, "An error occurred while cancelling the upload", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Upload cancelled successfully",
+		"data":    nil,
+	})
+}
+
+// handleError handles errors by sending an appropriate response to the client.
+// It takes the gin.Context, status code, error message, and error as parameters.
+func handleError(c *gin.Context, status int, message string, err error) {
+	log.Printf("[UploadHandler]: %s, %v", message, err)
+	c.JSON(status, gin.H{
+		"code":    status,
+		"message": message,
+		"error":   err.Error(),
+	})
+}
@@ -0,0 +1,66 @@
+package upload
+
+// @Summary Create an upload
+// @Description Open a session uploading a file straight to the storage, to be attached to a reminder, an article or a recipe. The images are limited to 10 MB, the PDFs and the videos to 500 MB.
+// @Description The files up to 8 MB are uploaded with a PUT request to the returned URL, sending the content type. The larger files are uploaded in parts of part_size bytes, with a PUT request to the URL of each part, keeping the ETag header of each response.
+// @Description The URLs expire after an hour and the session after 24 hours. The reminders require the patient:self permission, the articles and the recipes the articles:write and recipes:write permissions.
+// @Tags Uploads
+// @Accept json
+// @Produce json
+// @Param request body entity.RequestCreateUpload true "Domain, record, content type and size of the file"
+// @Success 201 {object} entity.UploadSessionResponse "Upload created successfully"
+// @Failure 400 "Invalid domain, file type or size"
+// @Failure 401 "You are not authorized to access this resource"
+// @Failure 404 "Record not found"
+// @Router /api/v1/uploads [post]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Get an upload
+// @Description Get a pending upload session of the authenticated user with new upload URLs, to resume an interrupted upload.
+// @Tags Uploads
+// @Produce json
+// @Param uuid path string true "UUID of the upload"
+// @Success 200 {object} entity.UploadSessionResponse "Upload retrieved successfully"
+// @Failure 404 "Upload not found"
+// @Failure 409 "The upload is already completed or cancelled"
+// @Failure 410 "The upload expired"
+// @Router /api/v1/uploads/{uuid} [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Confirm an upload
+// @Description Confirm that the file is uploaded, with the ETag of every part of a multipart upload in order. The size and the type of the file are checked, then the file is attached as a media of the record. A file that does not match the upload is deleted.
+// @Tags Uploads
+// @Accept json
+// @Produce json
+// @Param uuid path string true "UUID of the upload"
+// @Param request body entity.RequestConfirmUpload false "Uploaded parts of a multipart upload"
+// @Success 200 {object} entity.Media "Upload confirmed successfully"
+// @Failure 400 "The file is not uploaded completely or does not match the upload"
+// @Failure 404 "Upload not found"
+// @Failure 409 "The upload is already completed or cancelled"
+// @Failure 410 "The upload expired"
+// @Router /api/v1/uploads/{uuid}/confirm [post]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Cancel an upload
+// @Description Cancel a pending upload session of the authenticated user, discarding what was uploaded.
+// @Tags Uploads
+// @Produce json
+// @Param uuid path string true "UUID of the upload"
+// @Success 200 "Upload cancelled successfully"
+// @Failure 404 "Upload not found"
+// @Failure 409 "The upload is already completed or cancelled"
+// @Router /api/v1/uploads/{uuid} [delete]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
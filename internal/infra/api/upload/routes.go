@@ -0,0 +1,34 @@
+package upload
+
+import (
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/infra/api/middlewares"
+	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/infra/storage"
+	"github.com/emur-uy/backend/internal/pkg/service/media"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes sets up the upload session routes on the given gin.Engine instance.
+// It initializes the necessary components, such as the repository, service, and handler,
+// to handle the files uploaded directly to the storage in a hexagonal architecture.
+func RegisterRoutes(e *gin.Engine) {
+	// Initialize the repository by creating a new PostgreSQL client.
+	uploadRepo := postgresql.NewUploadRepository(postgresql.NewClient())
+
+	// Create a new UploadService instance by injecting the repository and the storage.
+	service := media.NewUploadService(uploadRepo, storage.NewBlobStore(config.Get()))
+
+	// Create a new uploadHandler instance by injecting the UploadService.
+	handler := newHandler(service)
+
+	// Group the upload routes together, requiring authentication. The permission on the domain of the
+	// upload is checked when the session is created, the sessions being only reachable by their user.
+	uploadRoutes := e.Group("/api/v1/uploads")
+	uploadRoutes.Use(middlewares.Authenticate())
+
+	uploadRoutes.POST("", handler.CreateUpload)
+	uploadRoutes.GET("/:uuid", handler.GetUpload)
+	uploadRoutes.POST("/:uuid/confirm", handler.ConfirmUpload)
+	uploadRoutes.DELETE("/:uuid", handler.AbortUpload)
+}
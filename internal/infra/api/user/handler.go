@@ -6,7 +6,9 @@ import (
 	"log"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/emur-uy/backend/internal/infra/api/middlewares"
 	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/ports"
 	"github.com/getsentry/sentry-go"
@@ -40,17 +42,167 @@ func (u *userHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// 2. Authenticate the user and generate a JWT token.
-	token, err := u.userService.Login(credentials)
+	// 2. Authenticate the user and generate the JWT and refresh tokens.
+	tokens, status, err := u.userService.Login(credentials, c.ClientIP())
 	if err != nil {
-		handleError(c, http.StatusBadRequest, "failed to generate token", err)
+		if status == http.StatusInternalServerError {
+			handleError(c, status, "failed to generate token", err)
+			return
+		}
+		handleError(c, status, err.Error(), err)
+		return
+	}
+
+	// 3. Return the generated tokens in the response.
+	c.JSON(http.StatusOK, gin.H{
+		"code":          http.StatusOK,
+		"token":         tokens.Token,
+		"refresh_token": tokens.RefreshToken,
+		"expires_at":    tokens.ExpiresAt,
+	})
+}
+
+// RefreshToken handles the rotation of a refresh token into a new pair of tokens.
+func (u *userHandler) RefreshToken(c *gin.Context) {
+	request := &entity.RequestRefreshToken{}
+
+	if err := c.ShouldBindJSON(request); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid input", err)
+		return
+	}
+
+	tokens, status, err := u.userService.RefreshToken(request.RefreshToken)
+	if err != nil {
+		handleError(c, status, err.Error(), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":          http.StatusOK,
+		"token":         tokens.Token,
+		"refresh_token": tokens.RefreshToken,
+		"expires_at":    tokens.ExpiresAt,
+	})
+}
+
+// VerifyEmail handles the link of the verification email.
+func (u *userHandler) VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		handleError(c, http.StatusBadRequest, "missing token", errors.New("missing token"))
+		return
+	}
+
+	status, err := u.userService.VerifyEmail(token)
+	if err != nil {
+		handleError(c, status, err.Error(), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "email verified",
+	})
+}
+
+// ResendVerificationEmail handles the request of a new verification email.
+func (u *userHandler) ResendVerificationEmail(c *gin.Context) {
+	request := &entity.RequestForgotPassword{}
+
+	if err := c.ShouldBindJSON(request); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid input", err)
+		return
+	}
+
+	status, err := u.userService.ResendVerificationEmail(request.Email)
+	if err != nil {
+		handleError(c, status, err.Error(), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "if the email is registered and not verified, a verification email was sent",
+	})
+}
+
+// ForgotPassword handles the request of a password reset email.
+func (u *userHandler) ForgotPassword(c *gin.Context) {
+	request := &entity.RequestForgotPassword{}
+
+	if err := c.ShouldBindJSON(request); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid input", err)
+		return
+	}
+
+	status, err := u.userService.ForgotPassword(request.Email)
+	if err != nil {
+		handleError(c, status, err.Error(), err)
+		return
+	}
+
+	// The same response is returned whether the email is registered or not.
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "if the email is registered, a password reset email was sent",
+	})
+}
+
+// ResetPassword handles setting a new password with a password reset token.
+func (u *userHandler) ResetPassword(c *gin.Context) {
+	request := &entity.RequestResetPassword{}
+
+	if err := c.ShouldBindJSON(request); err != nil {
+		handleError(c, http.StatusBadRequest, "invalid input", err)
+		return
+	}
+
+	status, err := u.userService.ResetPassword(request)
+	if err != nil {
+		handleError(c, status, err.Error(), err)
 		return
 	}
 
-	// 3. Return the generated token in the response.
 	c.JSON(http.StatusOK, gin.H{
-		"code":  http.StatusOK,
-		"token": token,
+		"code":    http.StatusOK,
+		"message": "password updated",
+	})
+}
+
+// Logout revokes the access token of the request and the session of the given refresh token.
+func (u *userHandler) Logout(c *gin.Context) {
+	userUUID, err := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, "invalid user uuid", err)
+		return
+	}
+
+	// The refresh token is optional, without it only the access token is revoked.
+	request := &entity.RequestLogout{}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(request); err != nil {
+			handleError(c, http.StatusBadRequest, "invalid input", err)
+			return
+		}
+	}
+
+	jti := c.GetString("jti")
+	var expiresAt time.Time
+	if exp, ok := c.Get("exp"); ok {
+		if expUnix, ok := exp.(float64); ok {
+			expiresAt = time.Unix(int64(expUnix), 0)
+		}
+	}
+
+	status, err := u.userService.Logout(userUUID, jti, expiresAt, request.RefreshToken)
+	if err != nil {
+		handleError(c, status, err.Error(), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "logged out",
 	})
 }
 
@@ -130,7 +282,13 @@ func (u *userHandler) UpdateUser(c *gin.Context) {
 }
 
 // GetUser handles the HTTP request for getting user information.
+// Users with the users:manage permission searching users get the search results instead.
 func (u *userHandler) GetUser(c *gin.Context) {
+	if isUserSearch(c) && middlewares.HasPermission(c, entity.PermissionUsersManage) {
+		u.SearchUsers(c)
+		return
+	}
+
 	//  1. Get user uuid from context
 	userUUID, _ := uuid.Parse(fmt.Sprintf("%v", c.MustGet("userUUID")))
 
@@ -156,6 +314,78 @@ func (u *userHandler) GetUser(c *gin.Context) {
 	})
 }
 
+// SearchUsers handles the HTTP request for searching users by name or email.
+func (u *userHandler) SearchUsers(c *gin.Context) {
+	request := &entity.RequestUserSearch{}
+	if err := c.ShouldBindQuery(request); err != nil {
+		handleUserError(c, http.StatusBadRequest, "invalid query parameters", err)
+		return
+	}
+
+	result, statusCode, err := u.userService.SearchUsers(request)
+	if err != nil {
+		handleUserError(c, statusCode, err.Error(), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "users found",
+		"data":    result,
+	})
+}
+
+// isUserSearch reports whether the request has any of the user search parameters.
+func isUserSearch(c *gin.Context) bool {
+	for _, param := range []string{"name", "email", "match", "page", "page_size"} {
+		if _, ok := c.GetQuery(param); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// UnlockUser handles the HTTP request for clearing the failed logins and the lockout of a user.
+func (u *userHandler) UnlockUser(c *gin.Context) {
+	uuidObj, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleUserError(c, http.StatusBadRequest, "invalid user UUID", err)
+		return
+	}
+
+	statusCode, err := u.userService.UnlockUser(uuidObj)
+	if err != nil {
+		handleUserError(c, statusCode, "an error occurred while unlocking the user", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "user unlocked",
+	})
+}
+
+// GetLoginAttempts handles the HTTP request for the audit of the login attempts of a user.
+func (u *userHandler) GetLoginAttempts(c *gin.Context) {
+	uuidObj, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleUserError(c, http.StatusBadRequest, "invalid user UUID", err)
+		return
+	}
+
+	attempts, statusCode, err := u.userService.GetLoginAttempts(uuidObj)
+	if err != nil {
+		handleUserError(c, statusCode, "an error occurred while getting the login attempts", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "login attempts",
+		"data":    attempts,
+	})
+}
+
 // SetActiveStatus handles the HTTP request for updating the user's is_active status
 func (u *userHandler) SetActiveStatus(c *gin.Context) {
 	// Get user UUID from the URL parameter
@@ -263,7 +493,7 @@ func handleSignUpError(c *gin.Context, statusCode int, message string, err error
 // handleError is a utility function to handle errors, log them, and return an appropriate HTTP response.
 func handleErrorLogin(c *gin.Context, httpCode int, errMsg string, err error) {
 	// Check if the error message should be replaced with the actual error.
-	if strings.Contains(err.Error(), "user not found") || strings.Contains(err.Error(), "incorrect/mismatch password") {
+	if errors.Is(err, ports.ErrInvalidCredentials) {
 		errMsg = err.Error()
 	}
 
@@ -1,5 +1,7 @@
 package user
 
+import "time"
+
 // @Summary User login
 // @Description Authenticate user and generate JWT token
 // @Tags Users
@@ -8,6 +10,8 @@ package user
 // @Param body body entity.DefaultCredentials true "User credentials"
 // @Success 200 {object} TokenResponse "Token generated successfully"
 // @Failure 400 {object} ErrorResponse "Invalid input"
+// @Failure 403 {object} ErrorResponse "Email not verified"
+// @Failure 429 {object} ErrorResponse "Too many failed attempts, the email or IP is throttled or locked"
 // @Router /api/v1/users/login [post]
 func _() {
 	// Swagger annotations.
@@ -41,11 +45,18 @@ func _() {
 }
 
 // @Summary Get user information
-// @Description Get user information
+// @Description Get user information. Admins sending any search parameter get a page of the users matching the name and email instead (entity.UserSearchResult).
+// @Description Every word of the name must match the start (match=prefix, default) or the whole (match=exact) of a word of the first or last name.
 // @Tags Users
 // @Accept json
 // @Produce json
+// @Param name query string false "Name to search, admin only, words of at least 3 characters"
+// @Param email query string false "Email to search, admin only"
+// @Param match query string false "prefix (default) or exact"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Users per page, 20 by default and 100 at most"
 // @Success 200 {object} entity.User "User information retrieved successfully"
+// @Failure 400 {object} ErrorResponse "Invalid search parameters"
 // @Failure 404 {object} ErrorResponse "User not found"
 // @Failure 500 {object} ErrorResponse "An error occurred while getting the user"
 // @Router /api/v1/users [get]
@@ -86,9 +97,122 @@ func _() {
 	// Swagger annotations.
 }
 
-// TokenResponse represents the response structure for the login endpoint.
+// @Summary Unlock user
+// @Description Clear the failed logins and the lockout of a user's email
+// @Tags Users
+// @Produce json
+// @Param uuid path string true "User UUID"
+// @Success 200 {object} ErrorResponse "User unlocked"
+// @Failure 400 {object} ErrorResponse "Invalid user UUID"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 500 {object} ErrorResponse "An error occurred while unlocking the user"
+// @Router /api/v1/users/unlock/{uuid} [put]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Get login attempts
+// @Description Get the latest login attempts of a user, newest first
+// @Tags Users
+// @Produce json
+// @Param uuid path string true "User UUID"
+// @Success 200 {array} entity.LoginAttempt "Login attempts"
+// @Failure 400 {object} ErrorResponse "Invalid user UUID"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 500 {object} ErrorResponse "An error occurred while getting the login attempts"
+// @Router /api/v1/users/login-attempts/{uuid} [get]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Refresh token
+// @Description Rotate a refresh token into a new access token and refresh token. Reusing a rotated refresh token revokes the session.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param body body entity.RequestRefreshToken true "Refresh token"
+// @Success 200 {object} TokenResponse "Token refreshed successfully"
+// @Failure 400 {object} ErrorResponse "Invalid input"
+// @Failure 401 {object} ErrorResponse "Invalid, expired or reused refresh token"
+// @Router /api/v1/users/token/refresh [post]
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary User logout
+// @Description Revoke the access token and, when given, the session of the refresh token
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param body body entity.RequestLogout false "Refresh token of the session"
+// @Success 200 {object} ErrorResponse "Logged out"
+// @Failure 400 {object} ErrorResponse "Invalid refresh token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/users/logout [post]
+// @Param Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Verify email
+// @Description Verify the email of a user with the token of the verification email. Tokens can be used once.
+// @Tags Users
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} ErrorResponse "Email verified"
+// @Failure 400 {object} ErrorResponse "Invalid, used or expired token"
+// @Router /api/v1/users/verify [get]
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Resend verification email
+// @Description Send a new verification email. The response is the same whether the email is registered or not.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param body body entity.RequestForgotPassword true "User email"
+// @Success 200 {object} ErrorResponse "Verification email sent"
+// @Failure 400 {object} ErrorResponse "Invalid input"
+// @Router /api/v1/users/verify/resend [post]
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Forgot password
+// @Description Send a password reset email. The response is the same whether the email is registered or not.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param body body entity.RequestForgotPassword true "User email"
+// @Success 200 {object} ErrorResponse "Password reset email sent"
+// @Failure 400 {object} ErrorResponse "Invalid input"
+// @Router /api/v1/users/password/forgot [post]
+func _() {
+	// Swagger annotations.
+}
+
+// @Summary Reset password
+// @Description Set a new password with the token of the password reset email. Every session of the user is revoked.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param body body entity.RequestResetPassword true "Reset token and new password"
+// @Success 200 {object} ErrorResponse "Password updated"
+// @Failure 400 {object} ErrorResponse "Invalid, used or expired token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/users/password/reset [post]
+func _() {
+	// Swagger annotations.
+}
+
+// TokenResponse represents the response structure for the login and refresh endpoints.
 type TokenResponse struct {
-	Token string `json:"token"`
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
 }
 
 // ErrorResponse represents the response structure for error responses.
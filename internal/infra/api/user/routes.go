@@ -1,9 +1,11 @@
 package user
 
 import (
+	"github.com/emur-uy/backend/config"
 	"github.com/emur-uy/backend/internal/infra/api/middlewares"
-	"github.com/emur-uy/backend/internal/infra/api/middlewares/constants"
+	"github.com/emur-uy/backend/internal/infra/mailer"
 	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/service/user"
 	"github.com/gin-gonic/gin"
 )
@@ -14,9 +16,12 @@ import (
 func RegisterRoutes(e *gin.Engine) {
 	// Initialize the repository by creating a new PostgreSQL client.
 	repo := postgresql.NewClient()
+	tokenRepo := postgresql.NewTokenRepository(repo)
+	attemptRepo := postgresql.NewLoginAttemptRepository(repo)
+	roleRepo := postgresql.NewRoleRepository(repo)
 
-	// Create a new UserService instance by injecting the repository.
-	service := user.NewService(repo)
+	// Create a new UserService instance by injecting the repositories and the mailer.
+	service := user.NewService(repo, tokenRepo, attemptRepo, roleRepo, mailer.NewMailer(config.Get()))
 
 	// Create a new userHandler instance by injecting the UserService.
 	handler := newHandler(service)
@@ -24,6 +29,11 @@ func RegisterRoutes(e *gin.Engine) {
 	// Register the SignUp and Login routes with the handler.
 	e.POST("/api/v1/users/login", handler.Login)
 	e.POST("/api/v1/users/signup", handler.SignUp)
+	e.POST("/api/v1/users/token/refresh", handler.RefreshToken)
+	e.POST("/api/v1/users/password/forgot", handler.ForgotPassword)
+	e.POST("/api/v1/users/password/reset", handler.ResetPassword)
+	e.GET("/api/v1/users/verify", handler.VerifyEmail)
+	e.POST("/api/v1/users/verify/resend", handler.ResendVerificationEmail)
 
 	// Group the user routes together.
 	userRoutes := e.Group("/api/v1/users")
@@ -32,10 +42,13 @@ func RegisterRoutes(e *gin.Engine) {
 	// Register the GET route for both admin and user.
 	userRoutes.GET("", handler.GetUser)
 	userRoutes.PUT("", handler.UpdateUser)
+	userRoutes.POST("/logout", handler.Logout)
 
-	// Register admin routes requiring authorization for admin role.
+	// Register admin routes requiring the users:manage permission.
 	adminRoutes := userRoutes.Group("")
-	adminRoutes.Use(middlewares.Authorize(constants.RoleAdmin))
+	adminRoutes.Use(middlewares.RequirePermission(entity.PermissionUsersManage))
 	adminRoutes.PUT("/active/:uuid", handler.SetActiveStatus)
 	adminRoutes.PUT("/banned/:uuid", handler.SetBannedStatus)
+	adminRoutes.PUT("/unlock/:uuid", handler.UnlockUser)
+	adminRoutes.GET("/login-attempts/:uuid", handler.GetLoginAttempts)
 }
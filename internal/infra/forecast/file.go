@@ -0,0 +1,76 @@
+package forecast
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+const (
+	// defaultFixture is the forecast fixture returned for the coordinates without their own fixture.
+	defaultFixture = "default.json"
+	// locationsFixture is the fixture listing the locations known by the geocoder.
+	locationsFixture = "locations.json"
+)
+
+// fileProvider is a ForecastProvider that reads weather API responses from JSON fixtures.
+// It is used to run the worker and the tests offline.
+type fileProvider struct {
+	dir string
+}
+
+// NewFileProvider returns a ForecastProvider reading the fixtures from dir. Locations are geocoded from the
+// "locations.json" list, and the forecast of each coordinates is read from "<latitude>_<longitude>.json",
+// with two decimals, falling back to "default.json" when the coordinates have no fixture.
+func NewFileProvider(dir string) ports.ForecastProvider {
+	return &fileProvider{dir: dir}
+}
+
+// Geocode looks up the city and country, ignoring case, in the locations fixture.
+func (p *fileProvider) Geocode(ctx context.Context, city string, country string) (*entity.Location, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(p.dir, locationsFixture))
+	if err != nil {
+		return nil, fmt.Errorf("[Geocode]: cannot read fixture, %w", err)
+	}
+
+	var locations []entity.ForecastLocation
+	if err := json.Unmarshal(data, &locations); err != nil {
+		return nil, fmt.Errorf("[Geocode]: cannot decode JSON, %w", err)
+	}
+
+	for _, location := range locations {
+		if strings.EqualFold(strings.TrimSpace(city), location.Name) && strings.EqualFold(strings.TrimSpace(country), location.Country) {
+			return locationFromResponse(location)
+		}
+	}
+
+	return nil, fmt.Errorf("[Geocode]: location %s, %s not found", city, country)
+}
+
+// GetForecast reads the forecast fixture of the coordinates.
+func (p *fileProvider) GetForecast(ctx context.Context, lang string, latitude float64, longitude float64) (entity.RequestForecast, error) {
+	if err := ctx.Err(); err != nil {
+		return entity.RequestForecast{}, err
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(p.dir, fmt.Sprintf("%.2f_%.2f.json", latitude, longitude)))
+	if os.IsNotExist(err) {
+		data, err = ioutil.ReadFile(filepath.Join(p.dir, defaultFixture))
+	}
+	if err != nil {
+		return entity.RequestForecast{}, fmt.Errorf("[GetForecast]: cannot read fixture, %w", err)
+	}
+
+	return decodeForecast(data)
+}
@@ -0,0 +1,24 @@
+package forecast
+
+import (
+	"time"
+
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+// Provider names accepted by the FORECAST_PROVIDER configuration.
+const (
+	ProviderWeatherAPI = "weatherapi"
+	ProviderFile       = "file"
+)
+
+// NewProvider returns the ForecastProvider selected by the configuration,
+// defaulting to the weather API.
+func NewProvider(cfg config.Config) ports.ForecastProvider {
+	if cfg.ForecastProvider == ProviderFile {
+		return NewFileProvider(cfg.ForecastFixturesDir)
+	}
+
+	return NewWeatherAPIProvider(cfg.ForecastAPI, cfg.ForecastKey, time.Duration(cfg.ForecastTimeout)*time.Second)
+}
@@ -0,0 +1,141 @@
+package forecast
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/getsentry/sentry-go"
+)
+
+// DefaultTimeout is the timeout of the requests to the weather API when none is configured.
+const DefaultTimeout = 10 * time.Second
+
+// forecastDays is the number of forecast days requested to the weather API.
+const forecastDays = 3
+
+// weatherAPIProvider is a ForecastProvider backed by the weatherapi.com forecast endpoint.
+type weatherAPIProvider struct {
+	client *http.Client
+	apiURL string
+	apiKey string
+}
+
+// NewWeatherAPIProvider returns a ForecastProvider that fetches the forecast from the weather API at apiURL,
+// giving up on requests that take longer than timeout.
+func NewWeatherAPIProvider(apiURL string, apiKey string, timeout time.Duration) ports.ForecastProvider {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &weatherAPIProvider{
+		client: &http.Client{Timeout: timeout},
+		apiURL: apiURL,
+		apiKey: apiKey,
+	}
+}
+
+// Geocode resolves the city and country with the weather API, which returns the location matched by the query.
+// It can return an error if the HTTP request fails or is cancelled, the HTTP status is not OK,
+// or the response has no location.
+func (p *weatherAPIProvider) Geocode(ctx context.Context, city string, country string) (*entity.Location, error) {
+	data, err := p.request(ctx, "en", fmt.Sprintf("%s,%s", url.PathEscape(city), url.PathEscape(country)), 1)
+	if err != nil {
+		return nil, err
+	}
+
+	var forecast entity.RequestForecast
+	if err := json.Unmarshal(data, &forecast); err != nil {
+		sentry.CaptureException(err)
+		return nil, fmt.Errorf("[Geocode]: cannot decode JSON, %w", err)
+	}
+
+	return locationFromResponse(forecast.Location)
+}
+
+// GetForecast fetches the forecast for the provided coordinates
+// and returns the forecast data as an entity.RequestForecast object.
+// It can return an error if the HTTP request fails or is cancelled, the HTTP status is not OK,
+// or the response data cannot be unmarshaled to an entity.RequestForecast with forecast days.
+func (p *weatherAPIProvider) GetForecast(ctx context.Context, lang string, latitude float64, longitude float64) (entity.RequestForecast, error) {
+	data, err := p.request(ctx, lang, fmt.Sprintf("%.4f,%.4f", latitude, longitude), forecastDays)
+	if err != nil {
+		return entity.RequestForecast{}, err
+	}
+
+	return decodeForecast(data)
+}
+
+// request queries the forecast endpoint of the weather API and returns the response body.
+func (p *weatherAPIProvider) request(ctx context.Context, lang string, query string, days int) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("%slang=%s&key=%s&q=%s&days=%d", p.apiURL, lang, p.apiKey, query, days), nil)
+	if err != nil {
+		return nil, fmt.Errorf("[GetForecast]: cannot build request, %w", err)
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		sentry.CaptureException(err)
+		return nil, fmt.Errorf("[GetForecast]: cannot fetch URL, %w", err)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		errMsg := fmt.Sprintf("[GetForecast]: unexpected http GET status, %s", res.Status)
+		sentry.CaptureMessage(errMsg)
+		return nil, errors.New(errMsg)
+	}
+
+	resBodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		sentry.CaptureException(err)
+		return nil, fmt.Errorf("[GetForecast]: error reading response body, %w", err)
+	}
+
+	return resBodyBytes, nil
+}
+
+// locationFromResponse converts the location returned by the weather API, failing when it is empty.
+func locationFromResponse(location entity.ForecastLocation) (*entity.Location, error) {
+	if location.Name == "" {
+		return nil, errors.New("[Geocode]: the response has no location")
+	}
+
+	timezone := location.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	return &entity.Location{
+		City:      location.Name,
+		Country:   location.Country,
+		Latitude:  location.Lat,
+		Longitude: location.Lon,
+		Timezone:  timezone,
+	}, nil
+}
+
+// decodeForecast unmarshals a weather API response, failing when it holds no forecast days.
+func decodeForecast(data []byte) (entity.RequestForecast, error) {
+	var forecast entity.RequestForecast
+	err := json.Unmarshal(data, &forecast)
+	if err != nil {
+		sentry.CaptureException(err)
+		return entity.RequestForecast{}, fmt.Errorf("[GetForecast]: cannot decode JSON, %w", err)
+	}
+
+	if len(forecast.ForecastInfo.ForecastDay) == 0 {
+		return entity.RequestForecast{}, errors.New("[GetForecast]: the response has no forecast days")
+	}
+
+	return forecast, nil
+}
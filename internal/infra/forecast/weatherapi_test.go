@@ -0,0 +1,64 @@
+package forecast
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeatherAPIProviderGetForecast(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("q") {
+		case "-34.9000,-56.1900", "Montevideo,Uruguay":
+			w.Write([]byte(`{"location":{"name":"Montevideo","country":"Uruguay","lat":-34.9,"lon":-56.19,"tz_id":"America/Montevideo"},` +
+				`"forecast":{"forecastday":[{"date":"2023-01-10","day":{"maxtemp_c":31.2,"uv":9}}]}}`))
+		case "-1.0000,-1.0000", "Empty,Uruguay":
+			w.Write([]byte(`{}`))
+		case "-2.0000,-2.0000":
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewWeatherAPIProvider(server.URL+"/forecast.json?", "key", 50*time.Millisecond)
+
+	forecast, err := provider.GetForecast(context.Background(), "es", -34.9, -56.19)
+	require.NoError(t, err)
+	require.Len(t, forecast.ForecastInfo.ForecastDay, 1)
+	assert.Equal(t, float32(31.2), forecast.ForecastInfo.ForecastDay[0].Day.MaxTempC)
+
+	// A response without forecast days is an error
+	_, err = provider.GetForecast(context.Background(), "es", -1, -1)
+	assert.Error(t, err)
+
+	_, err = provider.GetForecast(context.Background(), "es", 10, 10)
+	assert.Error(t, err)
+
+	// Slow responses time out
+	_, err = provider.GetForecast(context.Background(), "es", -2, -2)
+	assert.Error(t, err)
+
+	// Cancelled contexts abort the request
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = provider.GetForecast(ctx, "es", -34.9, -56.19)
+	assert.Error(t, err)
+
+	// Geocoding returns the location matched by the weather API
+	location, err := provider.Geocode(context.Background(), "Montevideo", "Uruguay")
+	require.NoError(t, err)
+	assert.Equal(t, "Montevideo", location.City)
+	assert.Equal(t, -56.19, location.Longitude)
+	assert.Equal(t, "America/Montevideo", location.Timezone)
+
+	_, err = provider.Geocode(context.Background(), "Empty", "Uruguay")
+	assert.Error(t, err)
+}
@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/google/uuid"
+)
+
+// fileMailer is a Mailer that writes every email to a directory, or to the standard logger
+// when no directory is configured. It is meant for local environments and testing.
+type fileMailer struct {
+	dir string
+}
+
+// NewFileMailer returns a Mailer that writes the emails as .eml files in the given directory,
+// or logs them when the directory is empty.
+func NewFileMailer(dir string) ports.Mailer {
+	return &fileMailer{dir: dir}
+}
+
+// Send writes the email to the directory or the log.
+func (m *fileMailer) Send(email *entity.Email) error {
+	message := buildMessage("no-reply@localhost", email)
+
+	if m.dir == "" {
+		log.Printf("[Mailer]: email to %s\n%s", email.To, message)
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("error creating the mail directory: %s", err)
+	}
+
+	name := fmt.Sprintf("%s_%s.eml", time.Now().UTC().Format("20060102T150405"), uuid.NewString())
+	if err := os.WriteFile(filepath.Join(m.dir, name), message, 0o644); err != nil {
+		return fmt.Errorf("error writing email to %s: %s", email.To, err)
+	}
+	return nil
+}
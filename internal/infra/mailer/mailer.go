@@ -0,0 +1,22 @@
+package mailer
+
+import (
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+// Provider names accepted by the MAILER_PROVIDER configuration.
+const (
+	ProviderSMTP = "smtp"
+	ProviderFile = "file"
+)
+
+// NewMailer returns the Mailer selected by the configuration, defaulting to the file mailer
+// so local environments do not need an SMTP server.
+func NewMailer(cfg config.Config) ports.Mailer {
+	if cfg.MailerProvider == ProviderSMTP {
+		return NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPassword, cfg.MailFrom)
+	}
+
+	return NewFileMailer(cfg.MailerDir)
+}
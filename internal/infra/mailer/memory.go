@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"sync"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+)
+
+// MemoryMailer is a Mailer that keeps every email in memory.
+// It is meant for tests that need to inspect what would have been sent.
+type MemoryMailer struct {
+	mu   sync.Mutex
+	Sent []*entity.Email
+	// Err, when set, is returned by Send instead of recording the email.
+	Err error
+}
+
+// NewMemoryMailer returns an empty MemoryMailer.
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Send records the email, or returns Err if it is set.
+func (m *MemoryMailer) Send(email *entity.Email) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Sent = append(m.Sent, email)
+	return nil
+}
+
+// Last returns the last email recorded, or nil if none was sent.
+func (m *MemoryMailer) Last() *entity.Email {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.Sent) == 0 {
+		return nil
+	}
+	return m.Sent[len(m.Sent)-1]
+}
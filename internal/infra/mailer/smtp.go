@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+// smtpMailer is a Mailer that sends the emails through an SMTP server.
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPMailer returns a Mailer that sends the emails through the given SMTP server,
+// authenticating with PLAIN auth when a username is given.
+func NewSMTPMailer(host string, port int, username, password, from string) ports.Mailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &smtpMailer{
+		addr: fmt.Sprintf("%s:%d", host, port),
+		auth: auth,
+		from: from,
+	}
+}
+
+// Send delivers the email to the SMTP server.
+func (m *smtpMailer) Send(email *entity.Email) error {
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{email.To}, buildMessage(m.from, email)); err != nil {
+		return fmt.Errorf("error sending email to %s: %s", email.To, err)
+	}
+	return nil
+}
+
+// buildMessage returns the email formatted as a plain text RFC 822 message.
+func buildMessage(from string, email *entity.Email) []byte {
+	var message strings.Builder
+	message.WriteString("From: " + from + "\r\n")
+	message.WriteString("To: " + email.To + "\r\n")
+	message.WriteString("Subject: " + email.Subject + "\r\n")
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.ReplaceAll(email.Body, "\n", "\r\n"))
+	return []byte(message.String())
+}
@@ -0,0 +1,25 @@
+package notifier
+
+import (
+	"log"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+// logNotifier is a Notifier that writes every notification to the standard logger.
+// It is used by the worker until a push provider is configured.
+type logNotifier struct{}
+
+// NewLogNotifier returns a Notifier that logs notifications instead of delivering them.
+func NewLogNotifier() ports.Notifier {
+	return &logNotifier{}
+}
+
+// Notify logs the reminder notification.
+func (n *logNotifier) Notify(reminder *entity.Reminder, notification *entity.ReminderNotification) error {
+	log.Printf("[Notifier]: reminder %s (%s) for user %d due at %s, notifying %d %s before",
+		reminder.UUID, reminder.Name, reminder.UserID, reminder.Date.Format("02/01/2006 15:04"),
+		notification.HoursBefore, notification.DaysOrHours)
+	return nil
+}
@@ -0,0 +1,41 @@
+package notifier
+
+import (
+	"sync"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+)
+
+// MemoryNotifier is a Notifier that keeps every notification in memory.
+// It is meant for tests that need to inspect what would have been delivered.
+type MemoryNotifier struct {
+	mu   sync.Mutex
+	Sent []*entity.ReminderNotification
+	// Err, when set, is returned by Notify instead of recording the notification.
+	Err error
+}
+
+// NewMemoryNotifier returns an empty MemoryNotifier.
+func NewMemoryNotifier() *MemoryNotifier {
+	return &MemoryNotifier{}
+}
+
+// Notify records the notification, or returns Err if it is set.
+func (n *MemoryNotifier) Notify(reminder *entity.Reminder, notification *entity.ReminderNotification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.Err != nil {
+		return n.Err
+	}
+	n.Sent = append(n.Sent, notification)
+	return nil
+}
+
+// Count returns the number of notifications recorded so far.
+func (n *MemoryNotifier) Count() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return len(n.Sent)
+}
@@ -0,0 +1,50 @@
+package postgresql
+
+import (
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+type auditRepository struct {
+	client *Client
+}
+
+// NewAuditRepository creates a new instance of a PostgreSQL audit repository.
+func NewAuditRepository(client *Client) ports.AuditRepository {
+	return &auditRepository{client: client}
+}
+
+// CreateAuditLog appends the record to the audit log.
+func (r *auditRepository) CreateAuditLog(auditLog *entity.AuditLog) error {
+	return r.client.db.Create(auditLog).Error
+}
+
+// FindAuditLogs retrieves a page of the audit log matching the filter.
+func (r *auditRepository) FindAuditLogs(filter *entity.AuditLogFilter) ([]entity.AuditLog, int64, error) {
+	query := r.client.db.Model(&entity.AuditLog{})
+
+	if filter.ActorUUID != nil {
+		query = query.Where("actor_uuid = ?", *filter.ActorUUID)
+	}
+	if filter.ResourceType != "" {
+		query = query.Where("resource_type = ?", filter.ResourceType)
+	}
+	if filter.ResourceUUID != nil {
+		query = query.Where("resource_uuid = ?", *filter.ResourceUUID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var auditLogs []entity.AuditLog
+	err := query.Order("created_at DESC, id DESC").Limit(filter.Limit).Offset(filter.Offset).Find(&auditLogs).Error
+	return auditLogs, total, err
+}
@@ -0,0 +1,119 @@
+package postgresql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type clinicianRepository struct {
+	client *Client
+}
+
+// NewClinicianRepository creates a new instance of a PostgreSQL clinician repository.
+func NewClinicianRepository(client *Client) ports.ClinicianRepository {
+	return &clinicianRepository{client: client}
+}
+
+// FindByUUID retrieves a record by its UUID.
+func (r *clinicianRepository) FindByUUID(uuid uuid.UUID, out interface{}) (interface{}, error) {
+	return r.client.FindByUUID(uuid, out)
+}
+
+// CreateWithOmit creates a new record in the database, omitting the given columns.
+func (r *clinicianRepository) CreateWithOmit(omit string, value interface{}) error {
+	return r.client.CreateWithOmit(omit, value)
+}
+
+// First retrieves the first record matching the given conditions.
+func (r *clinicianRepository) First(out interface{}, conditions ...interface{}) error {
+	return r.client.First(out, conditions...)
+}
+
+// Find retrieves the records matching the given conditions.
+func (r *clinicianRepository) Find(out interface{}, conditions ...interface{}) error {
+	return r.client.Find(out, conditions...)
+}
+
+// CreateClinician creates the clinician and grants its user the role in a transaction.
+func (r *clinicianRepository) CreateClinician(clinician *entity.Clinician, roleName string) error {
+	return r.client.db.Transaction(func(tx *gorm.DB) error {
+		role := &entity.Role{}
+		if err := tx.Where("role = ?", roleName).First(role).Error; err != nil {
+			return fmt.Errorf("error finding the %s role: %w", roleName, err)
+		}
+		if err := tx.Omit("uuid", clause.Associations).Create(clinician).Error; err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&entity.UserRole{UserID: clinician.UserID, RoleID: role.ID}).Error
+	})
+}
+
+// FindClinicians retrieves every clinician with their medical professional.
+func (r *clinicianRepository) FindClinicians() ([]entity.Clinician, error) {
+	var clinicians []entity.Clinician
+	err := r.client.db.Preload("Medical").Order("id").Find(&clinicians).Error
+	return clinicians, err
+}
+
+// FindActiveConsents retrieves the consents of the clinician neither revoked nor expired.
+func (r *clinicianRepository) FindActiveConsents(clinicianID int, now time.Time) ([]entity.PatientConsent, error) {
+	var consents []entity.PatientConsent
+	err := r.client.db.
+		Where("clinician_id = ? AND revoked_at IS NULL AND expires_at > ?", clinicianID, now).
+		Order("id").
+		Find(&consents).Error
+	return consents, err
+}
+
+// FindPatientConsents retrieves every consent of the patient with its clinician.
+func (r *clinicianRepository) FindPatientConsents(patientID int) ([]entity.PatientConsent, error) {
+	var consents []entity.PatientConsent
+	err := r.client.db.Preload("Clinician.Medical").
+		Where("patient_id = ?", patientID).
+		Order("created_at DESC").
+		Find(&consents).Error
+	return consents, err
+}
+
+// RevokeConsents revokes the active consents of the patient matching the conditions.
+func (r *clinicianRepository) RevokeConsents(patientID int, revokedAt time.Time, conditions map[string]interface{}) (int64, error) {
+	result := r.client.db.Model(&entity.PatientConsent{}).
+		Where("patient_id = ? AND revoked_at IS NULL", patientID).
+		Where(conditions).
+		Update("revoked_at", revokedAt)
+	return result.RowsAffected, result.Error
+}
+
+// CreateAccessLog appends an access to the patient data.
+func (r *clinicianRepository) CreateAccessLog(accessLog *entity.PatientAccessLog) error {
+	return r.client.db.Omit("Clinician").Create(accessLog).Error
+}
+
+// FindAccessLogs retrieves the latest accesses to the data of the patient.
+func (r *clinicianRepository) FindAccessLogs(patientID int, limit int) ([]entity.PatientAccessLog, error) {
+	var accessLogs []entity.PatientAccessLog
+	err := r.client.db.Preload("Clinician.Medical").
+		Where("patient_id = ?", patientID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&accessLogs).Error
+	return accessLogs, err
+}
+
+// FindUserSymptomUUIDs retrieves the UUIDs of the symptoms followed by the user.
+func (r *clinicianRepository) FindUserSymptomUUIDs(userID int) ([]uuid.UUID, error) {
+	var symptomUUIDs []uuid.UUID
+	err := r.client.db.Model(&entity.Symptom{}).
+		Joins("JOIN symptom_user ON symptom_user.symptom_id = symptoms.id").
+		Where("symptom_user.user_id = ?", userID).
+		Order("symptoms.id").
+		Pluck("symptoms.uuid", &symptomUUIDs).Error
+	return symptomUUIDs, err
+}
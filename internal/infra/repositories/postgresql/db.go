@@ -3,6 +3,7 @@ package postgresql
 import (
 	"errors" // Importing errors package for error handling
 	"fmt"
+	"strings"
 
 	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/google/uuid"
@@ -133,3 +134,69 @@ func (c *Client) GetDistinctCountryAndCityUsers(users *[]entity.User) error {
 	}
 	return nil
 }
+
+// FindUsersAfterID returns up to limit users with an ID greater than afterID, ordered by ID.
+// It is used to go through every user in batches, the erased users excluded.
+func (c *Client) FindUsersAfterID(afterID, limit int) ([]entity.User, error) {
+	var users []entity.User
+	err := c.db.Where("id > ? AND deleted_at IS NULL", afterID).Order("id").Limit(limit).Find(&users).Error
+	return users, err
+}
+
+// UpdateColumnsWhere updates the given columns of the records of the model that match the conditions.
+// Returns the number of updated records and an error if the operation fails.
+func (c *Client) UpdateColumnsWhere(model interface{}, conditions map[string]interface{}, columns map[string]interface{}) (int64, error) {
+	result := c.db.Model(model).Where(conditions).Updates(columns)
+	return result.RowsAffected, result.Error
+}
+
+// ReplaceSearchIndexes replaces the blind indexes of the user with the given ones.
+func (c *Client) ReplaceSearchIndexes(userID int, indexes []entity.UserSearchIndex) error {
+	return c.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.UserSearchIndex{}).Error; err != nil {
+			return err
+		}
+		if len(indexes) == 0 {
+			return nil
+		}
+		return tx.Create(&indexes).Error
+	})
+}
+
+// SearchUsers returns a page of the users matching the filter, ordered by ID, and the total of matching users.
+// The erased users are excluded.
+func (c *Client) SearchUsers(filter *entity.UserSearchFilter) ([]entity.User, int64, error) {
+	query := c.db.Model(&entity.User{}).Where("deleted_at IS NULL")
+
+	if len(filter.Hashes) > 0 {
+		matching := c.db.Model(&entity.UserSearchIndex{}).
+			Select("user_id").
+			Where("kind = ? AND hash IN ?", filter.Kind, filter.Hashes).
+			Group("user_id").
+			Having("COUNT(DISTINCT hash) = ?", len(filter.Hashes))
+		query = query.Where("id IN (?)", matching)
+	}
+
+	if filter.Email != "" {
+		email := strings.ToLower(filter.Email)
+		if filter.EmailExact {
+			query = query.Where("LOWER(email) = ?", email)
+		} else {
+			query = query.Where("LOWER(email) LIKE ? ESCAPE '\\'", escapeLike(email)+"%")
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []entity.User
+	err := query.Order("id").Limit(filter.Limit).Offset(filter.Offset).Find(&users).Error
+	return users, total, err
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern.
+func escapeLike(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(value)
+}
@@ -0,0 +1,205 @@
+package postgresql
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Subqueries of the records of a user referenced by the erased tables.
+const (
+	userRemindersQuery  = "SELECT id FROM reminders WHERE user_id = ?"
+	userTreatmentsQuery = "SELECT id FROM treatments WHERE user_id = ?"
+)
+
+// erasureStep deletes the records of a table matching a condition on the ID of the user.
+type erasureStep struct {
+	table     string
+	condition string
+}
+
+// erasureSteps are the deletions of the personal data of a user, in an order respecting the foreign keys.
+// The media of the reminders are deleted separately, as they are referenced through reminder_media.
+var erasureSteps = []erasureStep{
+	{"medical_ratings", "reminder_id IN (" + userRemindersQuery + ")"},
+	{"health_services_ratings", "reminder_id IN (" + userRemindersQuery + ")"},
+	{"reminder_notifications", "reminder_id IN (" + userRemindersQuery + ")"},
+	{"reminder_media", "reminder_id IN (" + userRemindersQuery + ")"},
+	{"reminders", "user_id = ?"},
+	{"treatment_doses", "treatment_id IN (" + userTreatmentsQuery + ")"},
+	{"treatments", "user_id = ?"},
+	{"monitorings", "user_id = ?"},
+	{"medical_records", "user_id = ?"},
+	{"answers", "user_id = ?"},
+	{"rating_recipes", "user_id = ?"},
+	{"symptom_user", "user_id = ?"},
+	{"activity_users", "user_id = ?"},
+	{"patient_consents", "patient_id = ?"},
+	{"data_exports", "user_id = ?"},
+	{"upload_sessions", "user_id = ?"},
+	{"refresh_tokens", "user_id = ?"},
+	{"user_action_tokens", "user_id = ?"},
+	{"user_search_indexes", "user_id = ?"},
+	{"login_attempts", "user_id = ?"},
+	{"role_user", "user_id = ?"},
+}
+
+type erasureRepository struct {
+	client *Client
+}
+
+// NewErasureRepository creates a new instance of a PostgreSQL erasure repository.
+func NewErasureRepository(client *Client) ports.ErasureRepository {
+	return &erasureRepository{client: client}
+}
+
+// FindByUUID retrieves a record by its UUID.
+func (r *erasureRepository) FindByUUID(uuid uuid.UUID, out interface{}) (interface{}, error) {
+	return r.client.FindByUUID(uuid, out)
+}
+
+// First retrieves the first record matching the given conditions.
+func (r *erasureRepository) First(out interface{}, conditions ...interface{}) error {
+	return r.client.First(out, conditions...)
+}
+
+// CreateErasure creates a new erasure request.
+func (r *erasureRepository) CreateErasure(erasure *entity.AccountErasure) error {
+	return r.client.db.Omit("uuid").Create(erasure).Error
+}
+
+// FindScheduledErasure retrieves the erasure of the user waiting for the end of the grace period.
+func (r *erasureRepository) FindScheduledErasure(userID int) (*entity.AccountErasure, error) {
+	erasure := &entity.AccountErasure{}
+	err := r.client.db.Where("user_id = ? AND status = ?", userID, entity.ErasureStatusScheduled).First(erasure).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return erasure, nil
+}
+
+// FindErasures retrieves every erasure request of the user with the given UUID, newest first.
+func (r *erasureRepository) FindErasures(userUUID uuid.UUID) ([]entity.AccountErasure, error) {
+	var erasures []entity.AccountErasure
+	err := r.client.db.Where("user_uuid = ?", userUUID).Order("requested_at DESC").Find(&erasures).Error
+	return erasures, err
+}
+
+// FindDueErasures retrieves up to limit scheduled erasures whose grace period ended before now.
+func (r *erasureRepository) FindDueErasures(now time.Time, limit int) ([]entity.AccountErasure, error) {
+	var erasures []entity.AccountErasure
+	err := r.client.db.
+		Where("status = ? AND scheduled_at <= ?", entity.ErasureStatusScheduled, now).
+		Order("scheduled_at").
+		Limit(limit).
+		Find(&erasures).Error
+	return erasures, err
+}
+
+// UpdateErasure saves the status, the error, the report and the dates of the erasure.
+func (r *erasureRepository) UpdateErasure(erasure *entity.AccountErasure) error {
+	return r.client.db.Model(erasure).
+		Select("status", "error", "report", "cancelled_at", "completed_at").
+		Updates(erasure).Error
+}
+
+// FindUserMediaURLs retrieves the URLs of the media and their variants uploaded with the reminders of the user.
+func (r *erasureRepository) FindUserMediaURLs(userID int) ([]string, error) {
+	var media []entity.Media
+	err := r.client.db.
+		Joins("JOIN reminder_media ON reminder_media.media_id = media.id").
+		Where("reminder_media.reminder_id IN ("+userRemindersQuery+")", userID).
+		Find(&media).Error
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, 0, len(media))
+	for _, m := range media {
+		urls = append(urls, m.URLs()...)
+	}
+	return urls, nil
+}
+
+// EraseUserData deletes the personal data of the user and anonymises the user, in a single transaction.
+// The questions are kept for the answers of the other users, attributed to the anonymised user.
+func (r *erasureRepository) EraseUserData(userID int, anonymousEmail string, erasedAt time.Time) (*entity.ErasureReport, error) {
+	report := &entity.ErasureReport{Deleted: map[string]int64{}, Anonymised: map[string]int64{}}
+
+	err := r.client.db.Transaction(func(tx *gorm.DB) error {
+		user := &entity.User{}
+		if err := tx.First(user, "id = ?", userID).Error; err != nil {
+			return err
+		}
+
+		var mediaIDs []int
+		err := tx.Table("reminder_media").Where("reminder_id IN ("+userRemindersQuery+")", userID).Pluck("media_id", &mediaIDs).Error
+		if err != nil {
+			return err
+		}
+
+		for _, step := range erasureSteps {
+			result := tx.Exec("DELETE FROM "+step.table+" WHERE "+step.condition, userID)
+			if result.Error != nil {
+				return result.Error
+			}
+			report.Deleted[step.table] = result.RowsAffected
+		}
+
+		if len(mediaIDs) > 0 {
+			result := tx.Exec("DELETE FROM media WHERE id IN ?", mediaIDs)
+			if result.Error != nil {
+				return result.Error
+			}
+			report.Deleted["media"] = result.RowsAffected
+		}
+
+		// The failed logins are counted by email, see the login throttle of the user service
+		result := tx.Exec("DELETE FROM login_throttles WHERE key = ?", "email:"+strings.ToLower(strings.TrimSpace(user.Email)))
+		if result.Error != nil {
+			return result.Error
+		}
+		report.Deleted["login_throttles"] = result.RowsAffected
+
+		var questions int64
+		if err := tx.Model(&entity.Question{}).Where("user_id = ?", userID).Count(&questions).Error; err != nil {
+			return err
+		}
+		report.Anonymised["questions"] = questions
+
+		result = tx.Model(&entity.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"email":             anonymousEmail,
+			"first_name":        "",
+			"last_name":         "",
+			"profile_image":     "",
+			"password":          "",
+			"date_of_birth":     nil,
+			"sex":               "",
+			"user_type":         "",
+			"city":              "",
+			"country":           "",
+			"location_id":       nil,
+			"is_active":         false,
+			"email_verified_at": nil,
+			"updated_at":        erasedAt,
+			"deleted_at":        erasedAt,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		report.Anonymised["users"] = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,262 @@
+package postgresql
+
+import (
+	"errors"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// countPersonalDataQuery counts the records holding personal data of a user, leaving out those in the trash.
+const countPersonalDataQuery = `SELECT
+	(SELECT COUNT(*) FROM treatments WHERE user_id = @user) +
+	(SELECT COUNT(*) FROM treatment_doses JOIN treatments ON treatments.id = treatment_doses.treatment_id WHERE treatments.user_id = @user) +
+	(SELECT COUNT(*) FROM reminders WHERE user_id = @user AND deleted_at IS NULL) +
+	(SELECT COUNT(*) FROM monitorings WHERE user_id = @user) +
+	(SELECT COUNT(*) FROM symptom_user WHERE user_id = @user) +
+	(SELECT COUNT(*) FROM questions WHERE user_id = @user) +
+	(SELECT COUNT(*) FROM answers WHERE user_id = @user) +
+	(SELECT COUNT(*) FROM rating_recipes JOIN recipes ON recipes.id = rating_recipes.recipe_id AND recipes.deleted_at IS NULL WHERE rating_recipes.user_id = @user)`
+
+type exportRepository struct {
+	client *Client
+}
+
+// reminderMediaRow is a media of a reminder.
+type reminderMediaRow struct {
+	ReminderID int
+	entity.Media
+}
+
+// NewExportRepository creates a new instance of a PostgreSQL export repository.
+func NewExportRepository(client *Client) ports.ExportRepository {
+	return &exportRepository{client: client}
+}
+
+// FindByUUID retrieves a record by its UUID.
+func (r *exportRepository) FindByUUID(uuid uuid.UUID, out interface{}) (interface{}, error) {
+	return r.client.FindByUUID(uuid, out)
+}
+
+// CreateExport creates a new export job.
+func (r *exportRepository) CreateExport(export *entity.DataExport) error {
+	return r.client.db.Omit("uuid").Create(export).Error
+}
+
+// FindExport retrieves the export of the user with the given UUID, with its archive if withArchive is set.
+func (r *exportRepository) FindExport(userID int, exportUUID uuid.UUID, withArchive bool) (*entity.DataExport, error) {
+	query := r.client.db
+	if !withArchive {
+		query = query.Omit("archive")
+	}
+
+	export := &entity.DataExport{}
+	if err := query.Where("user_id = ? AND uuid = ?", userID, exportUUID).First(export).Error; err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// FindActiveExport retrieves the latest export of the user that is pending, processing or completed and not expired.
+func (r *exportRepository) FindActiveExport(userID int, now time.Time) (*entity.DataExport, error) {
+	export := &entity.DataExport{}
+	err := r.client.db.Omit("archive").
+		Where("user_id = ?", userID).
+		Where("status IN ? OR (status = ? AND expires_at > ?)",
+			[]string{entity.ExportStatusPending, entity.ExportStatusProcessing}, entity.ExportStatusCompleted, now).
+		Order("created_at DESC").
+		First(export).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// FindPendingExports retrieves up to limit exports waiting for the worker, including the ones processing since before staleBefore.
+func (r *exportRepository) FindPendingExports(staleBefore time.Time, limit int) ([]entity.DataExport, error) {
+	var exports []entity.DataExport
+	err := r.client.db.Omit("archive").
+		Where("status = ? OR (status = ? AND started_at < ?)", entity.ExportStatusPending, entity.ExportStatusProcessing, staleBefore).
+		Order("created_at").
+		Limit(limit).
+		Find(&exports).Error
+	return exports, err
+}
+
+// ClaimExport marks the export as processing unless another worker claimed it since it was retrieved.
+func (r *exportRepository) ClaimExport(export *entity.DataExport, staleBefore time.Time) (bool, error) {
+	result := r.client.db.Model(&entity.DataExport{}).
+		Where("id = ?", export.ID).
+		Where("status = ? OR (status = ? AND started_at < ?)", entity.ExportStatusPending, entity.ExportStatusProcessing, staleBefore).
+		Updates(map[string]interface{}{"status": entity.ExportStatusProcessing, "started_at": export.StartedAt})
+	return result.RowsAffected == 1, result.Error
+}
+
+// UpdateExport saves the status, the error, the archive and the dates of the export.
+func (r *exportRepository) UpdateExport(export *entity.DataExport) error {
+	return r.client.db.Model(export).
+		Select("status", "error", "archive", "size", "started_at", "completed_at", "expires_at").
+		Updates(export).Error
+}
+
+// DeleteExpiredExports deletes the exports that expired before now.
+func (r *exportRepository) DeleteExpiredExports(now time.Time) (int64, error) {
+	result := r.client.db.Where("expires_at < ?", now).Delete(&entity.DataExport{})
+	return result.RowsAffected, result.Error
+}
+
+// CountPersonalData counts the records holding personal data of the user.
+func (r *exportRepository) CountPersonalData(userID int) (int64, error) {
+	var count int64
+	err := r.client.db.Raw(countPersonalDataQuery, map[string]interface{}{"user": userID}).Scan(&count).Error
+	return count, err
+}
+
+// FindPersonalData retrieves every personal data of the user, the profile being still encrypted. The reminders
+// and the recipes in the trash are left out, with their ratings.
+func (r *exportRepository) FindPersonalData(userID int) (*entity.User, *entity.PersonalData, error) {
+	db := r.client.db
+
+	user := &entity.User{}
+	if err := db.First(user, "id = ?", userID).Error; err != nil {
+		return nil, nil, err
+	}
+
+	data := &entity.PersonalData{}
+
+	medicalRecord := &entity.MedicalRecord{}
+	err := db.First(medicalRecord, "user_id = ?", userID).Error
+	if err == nil {
+		data.MedicalRecord = medicalRecord
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, err
+	}
+
+	if err := db.Where("user_id = ?", userID).Order("id").Find(&data.Treatments).Error; err != nil {
+		return nil, nil, err
+	}
+
+	err = db.Table("treatment_doses").
+		Select("treatment_doses.uuid, treatments.uuid AS treatment_uuid, treatment_doses.scheduled_at, treatment_doses.status, treatment_doses.updated_at").
+		Joins("JOIN treatments ON treatments.id = treatment_doses.treatment_id").
+		Where("treatments.user_id = ?", userID).
+		Order("treatment_doses.scheduled_at").
+		Scan(&data.TreatmentDoses).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := r.findReminders(userID, data); err != nil {
+		return nil, nil, err
+	}
+
+	err = db.Table("monitorings").
+		Select("symptoms.name AS symptom, monitorings.scale, monitorings.date, monitorings.local_date").
+		Joins("JOIN symptoms ON symptoms.id = monitorings.symptom_id").
+		Where("monitorings.user_id = ?", userID).
+		Order("monitorings.date").
+		Scan(&data.Monitorings).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = db.Table("symptom_user").
+		Select("symptoms.name AS symptom, symptom_user.created_at").
+		Joins("JOIN symptoms ON symptoms.id = symptom_user.symptom_id").
+		Where("symptom_user.user_id = ?", userID).
+		Order("symptom_user.id").
+		Scan(&data.SymptomSubscriptions).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := db.Where("user_id = ?", userID).Order("id").Find(&data.Questions).Error; err != nil {
+		return nil, nil, err
+	}
+
+	err = db.Table("answers").
+		Select("answers.uuid, questions.uuid AS question_uuid, answers.text, answers.is_public, answers.created_at").
+		Joins("JOIN questions ON questions.id = answers.question_id").
+		Where("answers.user_id = ?", userID).
+		Order("answers.id").
+		Scan(&data.Answers).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = db.Table("medical_ratings").
+		Select("reminders.uuid AS reminder_uuid, CONCAT(medicals.first_name, ' ', medicals.last_name) AS name, medical_ratings.rating, medical_ratings.created_at").
+		Joins("JOIN reminders ON reminders.id = medical_ratings.reminder_id AND reminders.deleted_at IS NULL").
+		Joins("JOIN medicals ON medicals.id = medical_ratings.medical_id").
+		Where("reminders.user_id = ?", userID).
+		Order("medical_ratings.id").
+		Scan(&data.MedicalRatings).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = db.Table("health_services_ratings").
+		Select("reminders.uuid AS reminder_uuid, health_services.name, health_services_ratings.rating, health_services_ratings.created_at").
+		Joins("JOIN reminders ON reminders.id = health_services_ratings.reminder_id AND reminders.deleted_at IS NULL").
+		Joins("JOIN health_services ON health_services.id = health_services_ratings.health_service_id").
+		Where("reminders.user_id = ?", userID).
+		Order("health_services_ratings.id").
+		Scan(&data.HealthServiceRatings).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = db.Table("rating_recipes").
+		Select("recipes.uuid AS recipe_uuid, recipes.name AS recipe, rating_recipes.level").
+		Joins("JOIN recipes ON recipes.id = rating_recipes.recipe_id AND recipes.deleted_at IS NULL").
+		Where("rating_recipes.user_id = ?", userID).
+		Order("rating_recipes.id").
+		Scan(&data.RecipeRatings).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, data, nil
+}
+
+// findReminders retrieves the reminders of the user with their media.
+func (r *exportRepository) findReminders(userID int, data *entity.PersonalData) error {
+	var reminders []entity.Reminder
+	if err := r.client.db.Where("user_id = ?", userID).Order("id").Find(&reminders).Error; err != nil {
+		return err
+	}
+	if len(reminders) == 0 {
+		return nil
+	}
+
+	reminderIDs := make([]int, 0, len(reminders))
+	for _, reminder := range reminders {
+		reminderIDs = append(reminderIDs, reminder.ID)
+	}
+
+	var rows []reminderMediaRow
+	err := r.client.db.Table("media").
+		Select("media.*, reminder_media.reminder_id").
+		Joins("JOIN reminder_media ON reminder_media.media_id = media.id").
+		Where("reminder_media.reminder_id IN ?", reminderIDs).
+		Order("media.id").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	media := make(map[int][]entity.Media)
+	for _, row := range rows {
+		media[row.ReminderID] = append(media[row.ReminderID], row.Media)
+	}
+	for _, reminder := range reminders {
+		data.Reminders = append(data.Reminders, entity.ExportReminder{Reminder: reminder, Media: media[reminder.ID]})
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package postgresql
+
+import (
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type forecastRepository struct {
+	client *Client
+}
+
+// NewForecastRepository creates a new instance of a PostgreSQL forecast repository.
+func NewForecastRepository(client *Client) ports.ForecastRepository {
+	return &forecastRepository{client: client}
+}
+
+// Create creates a new forecast in the database.
+func (r *forecastRepository) Create(value interface{}) error {
+	return r.client.Create(value)
+}
+
+// GetDistinctCountryAndCityUsers retrieves the distinct locations of the users.
+func (r *forecastRepository) GetDistinctCountryAndCityUsers(users *[]entity.User) error {
+	return r.client.GetDistinctCountryAndCityUsers(users)
+}
+
+// FindByUUID finds a record by its UUID.
+func (r *forecastRepository) FindByUUID(uuid uuid.UUID, out interface{}) (interface{}, error) {
+	return r.client.FindByUUID(uuid, out)
+}
+
+// Find return records that match given conditions.
+func (r *forecastRepository) Find(dest interface{}, conditions ...interface{}) error {
+	return r.client.Find(dest, conditions...)
+}
+
+// UpsertForecast creates the forecast, or updates the weather of the existing forecast of the same location and date.
+func (r *forecastRepository) UpsertForecast(forecast *entity.Forecast) error {
+	return r.client.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "location_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"country", "state", "avg_temperature", "max_temperature", "min_temperature", "description",
+			"humidity", "code", "wind", "uv", "updated_at",
+		}),
+	}).Create(forecast).Error
+}
+
+// DeleteForecastsBefore deletes the forecasts dated before the given date.
+func (r *forecastRepository) DeleteForecastsBefore(date string) (int64, error) {
+	result := r.client.db.Where("date < ?", date).Delete(&entity.Forecast{})
+	return result.RowsAffected, result.Error
+}
+
+// UpsertForecastFetch creates the fetch state of a location, or updates the existing one.
+func (r *forecastRepository) UpsertForecastFetch(fetch *entity.ForecastFetch) error {
+	return r.client.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "location_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"country", "state", "last_success_at", "last_attempt_at", "next_attempt_at", "failures", "last_error"}),
+	}).Create(fetch).Error
+}
+
+// FindOrCreateLocation fills the location with the stored location of the same coordinates, creating it if missing.
+func (r *forecastRepository) FindOrCreateLocation(location *entity.Location) error {
+	err := r.client.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "latitude"}, {Name: "longitude"}},
+		DoNothing: true,
+	}).Create(location).Error
+	if err != nil {
+		return err
+	}
+	if location.ID != 0 {
+		return nil
+	}
+	return r.client.db.First(location, "latitude = ? AND longitude = ?", location.Latitude, location.Longitude).Error
+}
+
+// AssignUserLocation links the users of the city and country without a location to the location.
+// The timezone of the location replaces the default timezone, the timezone chosen by the user is kept.
+func (r *forecastRepository) AssignUserLocation(city string, country string, location *entity.Location) error {
+	return r.client.db.Model(&entity.User{}).
+		Where("location_id IS NULL AND city = ? AND country = ?", city, country).
+		Updates(map[string]interface{}{
+			"location_id": location.ID,
+			"timezone":    gorm.Expr("CASE WHEN timezone_set_by_user THEN timezone ELSE ? END", location.Timezone),
+		}).Error
+}
+
+// GetUnresolvedUserLocations retrieves the distinct cities and countries of the users without a location.
+func (r *forecastRepository) GetUnresolvedUserLocations(users *[]entity.User) error {
+	return r.client.db.Model(&entity.User{}).
+		Select("DISTINCT country, city").
+		Where("location_id IS NULL AND city <> '' AND country <> ''").
+		Find(users).Error
+}
+
+// GetUserLocations retrieves the locations of at least one user.
+func (r *forecastRepository) GetUserLocations(locations *[]*entity.Location) error {
+	return r.client.db.
+		Where("id IN (?)", r.client.db.Model(&entity.User{}).Select("location_id").Where("location_id IS NOT NULL")).
+		Find(locations).Error
+}
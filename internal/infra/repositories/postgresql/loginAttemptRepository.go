@@ -0,0 +1,62 @@
+package postgresql
+
+import (
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type loginAttemptRepository struct {
+	client *Client
+}
+
+// NewLoginAttemptRepository creates a new instance of a PostgreSQL login attempt repository.
+func NewLoginAttemptRepository(client *Client) ports.LoginAttemptRepository {
+	return &loginAttemptRepository{client: client}
+}
+
+// CreateAttempt creates a new login attempt in the database.
+func (r *loginAttemptRepository) CreateAttempt(attempt *entity.LoginAttempt) error {
+	return r.client.Create(attempt)
+}
+
+// FindAttempts retrieves the latest login attempts of the user.
+func (r *loginAttemptRepository) FindAttempts(userID int, limit int) ([]entity.LoginAttempt, error) {
+	var attempts []entity.LoginAttempt
+	err := r.client.db.Where("user_id = ?", userID).Order("created_at DESC").Limit(limit).Find(&attempts).Error
+	return attempts, err
+}
+
+// FindThrottles retrieves the failure counters of the keys.
+func (r *loginAttemptRepository) FindThrottles(keys []string) ([]entity.LoginThrottle, error) {
+	var throttles []entity.LoginThrottle
+	err := r.client.db.Where("key IN ?", keys).Find(&throttles).Error
+	return throttles, err
+}
+
+// IncrementThrottle adds a failure to the counter of the key in a single statement, so concurrent
+// failures are all counted.
+func (r *loginAttemptRepository) IncrementThrottle(key string, failedAt time.Time, windowStart time.Time) (*entity.LoginThrottle, error) {
+	throttle := &entity.LoginThrottle{Key: key, Failures: 1, LastFailureAt: failedAt}
+	err := r.client.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures":        gorm.Expr("CASE WHEN login_throttles.last_failure_at < ? THEN 1 ELSE login_throttles.failures + 1 END", windowStart),
+			"last_failure_at": failedAt,
+		}),
+	}, clause.Returning{}).Create(throttle).Error
+	return throttle, err
+}
+
+// LockThrottle locks the key until the given time.
+func (r *loginAttemptRepository) LockThrottle(key string, lockedUntil time.Time) error {
+	return r.client.db.Model(&entity.LoginThrottle{}).Where("key = ?", key).Update("locked_until", lockedUntil).Error
+}
+
+// DeleteThrottle removes the counter and the lock of the key.
+func (r *loginAttemptRepository) DeleteThrottle(key string) error {
+	return r.client.db.Where("key = ?", key).Delete(&entity.LoginThrottle{}).Error
+}
@@ -2,6 +2,11 @@ package postgresql
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/ports"
 )
 
@@ -30,3 +35,70 @@ func NewMediaRepository(client *Client) ports.MediaRepository {
 func (r *mediaRepository) CreateWithOmit(omit string, value interface{}) error {
 	return r.client.CreateWithOmit(omit, value)
 }
+
+// mediaTables are the join tables between the records of each domain and their media.
+var mediaTables = map[string]string{
+	entity.MediaDomainReminders: "reminder_media",
+	entity.MediaDomainArticles:  "article_media",
+	entity.MediaDomainRecipes:   "recipe_media",
+}
+
+// FindDomainMedia retrieves a batch of the media joined to the records of the domain, after the given ID.
+func (r *mediaRepository) FindDomainMedia(domain string, afterID int, limit int) ([]entity.Media, error) {
+	table, ok := mediaTables[domain]
+	if !ok {
+		return nil, fmt.Errorf("unknown media domain %q", domain)
+	}
+
+	var media []entity.Media
+	err := r.client.db.
+		Where("id > ? AND id IN (SELECT media_id FROM "+table+")", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&media).Error
+	return media, err
+}
+
+// FindMedia retrieves a batch of the media, after the given ID.
+func (r *mediaRepository) FindMedia(afterID int, limit int) ([]entity.Media, error) {
+	var media []entity.Media
+	err := r.client.db.
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&media).Error
+	return media, err
+}
+
+// orphanCondition matches the media joined to no record of any domain.
+func orphanCondition() string {
+	conditions := make([]string, 0, len(entity.MediaDomains))
+	for _, domain := range entity.MediaDomains {
+		conditions = append(conditions, "NOT EXISTS (SELECT 1 FROM "+mediaTables[domain]+" WHERE media_id = media.id)")
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// FindOrphanMedia retrieves a batch of the media created before the given time and joined to no record, after the given ID.
+func (r *mediaRepository) FindOrphanMedia(before time.Time, afterID int, limit int) ([]entity.Media, error) {
+	var media []entity.Media
+	err := r.client.db.
+		Where("id > ? AND created_at < ?", afterID, before).
+		Where(orphanCondition()).
+		Order("id").
+		Limit(limit).
+		Find(&media).Error
+	return media, err
+}
+
+// DeleteOrphanMedia deletes the media unless a record was joined to it since it was read.
+func (r *mediaRepository) DeleteOrphanMedia(id int) (bool, error) {
+	result := r.client.db.
+		Where("id = ?", id).
+		Where(orphanCondition()).
+		Delete(&entity.Media{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete media %d: %s", id, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,50 @@
+package postgresql
+
+import (
+	"errors"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+type reminderNotificationRepository struct {
+	client *Client
+}
+
+// NewReminderNotificationRepository creates a new instance of a PostgreSQL reminderNotification repository.
+func NewReminderNotificationRepository(client *Client) ports.ReminderNotificationRepository {
+	return &reminderNotificationRepository{client: client}
+}
+
+// Create creates a new reminderNotification in the database.
+func (r *reminderNotificationRepository) Create(value interface{}) error {
+	return r.client.Create(value)
+}
+
+// Find return records that match given conditions.
+func (r *reminderNotificationRepository) Find(model interface{}, dest interface{}, conditions ...interface{}) error {
+	return r.client.db.Model(model).Find(dest, conditions...).Error
+}
+
+// UpdateColumns updates a specific column of a reminderNotification in the database.
+func (r *reminderNotificationRepository) UpdateColumns(value interface{}, column string, updateValue interface{}) error {
+	return r.client.UpdateColumns(value, column, updateValue)
+}
+
+// Delete deletes a reminderNotification from the database.
+func (r *reminderNotificationRepository) Delete(value interface{}) error {
+	err := r.client.db.Delete(value).Error
+	if err != nil {
+		return errors.New("failed to delete record: " + err.Error())
+	}
+	return nil
+}
+
+// Reclaim takes over the claim of an unsent notification unless another worker claimed it since it was retrieved.
+func (r *reminderNotificationRepository) Reclaim(notification *entity.ReminderNotification, staleBefore time.Time) (bool, error) {
+	result := r.client.db.Model(&entity.ReminderNotification{}).
+		Where("id = ? AND sent_at IS NULL AND claimed_at < ?", notification.ID, staleBefore).
+		Update("claimed_at", notification.ClaimedAt)
+	return result.RowsAffected == 1, result.Error
+}
@@ -0,0 +1,145 @@
+package postgresql
+
+import (
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type roleRepository struct {
+	client *Client
+}
+
+// NewRoleRepository creates a new instance of a PostgreSQL role repository.
+func NewRoleRepository(client *Client) ports.RoleRepository {
+	return &roleRepository{client: client}
+}
+
+// FindRoles retrieves every role with its permissions.
+func (r *roleRepository) FindRoles() ([]entity.Role, error) {
+	var roles []entity.Role
+	err := r.client.db.Preload("Permissions").Order("role").Find(&roles).Error
+	return roles, err
+}
+
+// FindRoleByName retrieves the role with the given name and its permissions.
+func (r *roleRepository) FindRoleByName(name string) (*entity.Role, error) {
+	role := &entity.Role{}
+	if err := r.client.db.Preload("Permissions").Where("role = ?", name).First(role).Error; err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// CreateRole creates the role and its permissions in a transaction.
+func (r *roleRepository) CreateRole(role *entity.Role) error {
+	return r.client.db.Transaction(func(tx *gorm.DB) error {
+		permissions := role.Permissions
+		if err := tx.Omit(clause.Associations).Create(role).Error; err != nil {
+			return err
+		}
+		return createRolePermissions(tx, role.ID, permissions)
+	})
+}
+
+// UpdateRole replaces the description and the permissions of the role in a transaction.
+func (r *roleRepository) UpdateRole(role *entity.Role) error {
+	return r.client.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Role{}).Where("id = ?", role.ID).Update("description", role.Description).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", role.ID).Delete(&entity.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return createRolePermissions(tx, role.ID, role.Permissions)
+	})
+}
+
+// createRolePermissions stores the permissions of the role.
+func createRolePermissions(tx *gorm.DB, roleID int, permissions []entity.RolePermission) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+	for i := range permissions {
+		permissions[i].RoleID = roleID
+	}
+	return tx.Create(&permissions).Error
+}
+
+// DeleteRole removes the role, its permissions and its assignments in a transaction.
+func (r *roleRepository) DeleteRole(roleID int) error {
+	return r.client.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", roleID).Delete(&entity.UserRole{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", roleID).Delete(&entity.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Role{}, roleID).Error
+	})
+}
+
+// AssignRole grants the role to the user, ignoring the roles the user already holds.
+func (r *roleRepository) AssignRole(userID int, roleID int) error {
+	return r.client.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.UserRole{UserID: userID, RoleID: roleID}).Error
+}
+
+// UnassignRole revokes the role from the user.
+func (r *roleRepository) UnassignRole(userID int, roleID int) error {
+	return r.client.db.Where("user_id = ? AND role_id = ?", userID, roleID).Delete(&entity.UserRole{}).Error
+}
+
+// UnassignRoleKeepingHolder revokes the role from the user in a transaction, unless no other user holds it.
+func (r *roleRepository) UnassignRoleKeepingHolder(userID int, roleID int) error {
+	return r.client.db.Transaction(func(tx *gorm.DB) error {
+		// The role is locked, so the concurrent revocations of the role run one after the other
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&entity.Role{}, roleID).Error; err != nil {
+			return err
+		}
+
+		var others int64
+		err := tx.Model(&entity.UserRole{}).
+			Joins("JOIN users ON users.id = role_user.user_id").
+			Where("role_user.role_id = ? AND role_user.user_id <> ? AND users.deleted_at IS NULL", roleID, userID).
+			Count(&others).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Where("user_id = ? AND role_id = ?", userID, roleID).Delete(&entity.UserRole{})
+		if result.Error != nil {
+			return result.Error
+		}
+		// The error rolls the revocation back
+		if others == 0 && result.RowsAffected > 0 {
+			return ports.ErrLastRoleHolder
+		}
+		return nil
+	})
+}
+
+// GetUserRoleNames retrieves the names of the roles held by the user.
+func (r *roleRepository) GetUserRoleNames(userID int) ([]string, error) {
+	var names []string
+	err := r.client.db.Model(&entity.Role{}).
+		Joins("JOIN role_user ON role_user.role_id = roles.id").
+		Where("role_user.user_id = ?", userID).
+		Order("role_user.id").
+		Pluck("roles.role", &names).Error
+	return names, err
+}
+
+// GetUserPermissions retrieves the permissions granted by every role of the user.
+func (r *roleRepository) GetUserPermissions(userUUID uuid.UUID) ([]string, error) {
+	var permissions []string
+	err := r.client.db.Model(&entity.RolePermission{}).
+		Distinct("role_permissions.permission").
+		Joins("JOIN role_user ON role_user.role_id = role_permissions.role_id").
+		Joins("JOIN users ON users.id = role_user.user_id").
+		Where("users.uuid = ?", userUUID).
+		Pluck("role_permissions.permission", &permissions).Error
+	return permissions, err
+}
@@ -0,0 +1,97 @@
+package postgresql
+
+import (
+	"errors"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type tokenRepository struct {
+	client *Client
+}
+
+// NewTokenRepository creates a new instance of a PostgreSQL token repository.
+func NewTokenRepository(client *Client) ports.TokenRepository {
+	return &tokenRepository{client: client}
+}
+
+// Create creates a new token in the database.
+func (r *tokenRepository) Create(value interface{}) error {
+	return r.client.Create(value)
+}
+
+// First retrieves the first token matching the given conditions.
+func (r *tokenRepository) First(out interface{}, conditions ...interface{}) error {
+	return r.client.First(out, conditions...)
+}
+
+// RevokeRefreshToken revokes the refresh token if it is not revoked yet, in a single statement so
+// two concurrent refreshes with the same token cannot both succeed.
+func (r *tokenRepository) RevokeRefreshToken(id int, revokedAt time.Time) (bool, error) {
+	result := r.client.db.Model(&entity.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt)
+	return result.RowsAffected > 0, result.Error
+}
+
+// RevokeRefreshTokenFamily revokes every refresh token of the family.
+func (r *tokenRepository) RevokeRefreshTokenFamily(familyID uuid.UUID, revokedAt time.Time) error {
+	return r.client.db.Model(&entity.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", revokedAt).Error
+}
+
+// RevokeUserRefreshTokens revokes every refresh token of the user.
+func (r *tokenRepository) RevokeUserRefreshTokens(userID int, revokedAt time.Time) error {
+	return r.client.db.Model(&entity.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", revokedAt).Error
+}
+
+// SetTokensValidAfter rejects the access tokens of the user issued before validAfter.
+func (r *tokenRepository) SetTokensValidAfter(userID int, validAfter time.Time) error {
+	return r.client.db.Model(&entity.User{}).Where("id = ?", userID).Update("tokens_valid_after", validAfter).Error
+}
+
+// UseActionToken marks the action token as used if it was not used yet, in a single statement so
+// the token cannot be used twice by concurrent requests.
+func (r *tokenRepository) UseActionToken(id int, usedAt time.Time) (bool, error) {
+	result := r.client.db.Model(&entity.UserActionToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", usedAt)
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteExpiredTokens deletes the refresh tokens, revoked tokens and action tokens expired before the given time.
+func (r *tokenRepository) DeleteExpiredTokens(before time.Time) (int64, error) {
+	var deleted int64
+	for _, model := range []interface{}{&entity.RefreshToken{}, &entity.RevokedToken{}, &entity.UserActionToken{}} {
+		result := r.client.db.Where("expires_at < ?", before).Delete(model)
+		if result.Error != nil {
+			return deleted, result.Error
+		}
+		deleted += result.RowsAffected
+	}
+	return deleted, nil
+}
+
+// IsRevoked reports whether the access token with the given JWT ID was revoked.
+func (r *tokenRepository) IsRevoked(jti string) (bool, error) {
+	var count int64
+	err := r.client.db.Model(&entity.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
+
+// TokensValidAfter retrieves the time before which the access tokens of the user are rejected.
+func (r *tokenRepository) TokensValidAfter(userUUID uuid.UUID) (*time.Time, error) {
+	user := &entity.User{}
+	err := r.client.db.Select("tokens_valid_after").Where("uuid = ?", userUUID).Take(user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return user.TokensValidAfter, err
+}
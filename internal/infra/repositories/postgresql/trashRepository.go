@@ -0,0 +1,155 @@
+package postgresql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// trashTable describes how the deleted records of a resource are listed and purged.
+type trashTable struct {
+	// nameColumn is the column describing the record in the trash listing.
+	nameColumn string
+	// mediaTable is the join table between the records and their media.
+	mediaTable string
+	// foreignKey is the column referencing the records in the mediaTable and the dependents.
+	foreignKey string
+	// dependents are the tables referencing the records, deleted before them.
+	dependents []string
+}
+
+// trashTables are the resources kept in the trash, by table name.
+var trashTables = map[string]trashTable{
+	entity.TrashResourceReminders: {
+		nameColumn: "name",
+		mediaTable: "reminder_media",
+		foreignKey: "reminder_id",
+		dependents: []string{"medical_ratings", "health_services_ratings", "reminder_notifications"},
+	},
+	entity.TrashResourceArticles: {
+		nameColumn: "title",
+		mediaTable: "article_media",
+		foreignKey: "article_id",
+		dependents: []string{"article_category"},
+	},
+	entity.TrashResourceRecipes: {
+		nameColumn: "name",
+		mediaTable: "recipe_media",
+		foreignKey: "recipe_id",
+		dependents: []string{"rating_recipes"},
+	},
+}
+
+type trashRepository struct {
+	client *Client
+}
+
+// NewTrashRepository creates a new instance of a PostgreSQL trash repository.
+func NewTrashRepository(client *Client) ports.TrashRepository {
+	return &trashRepository{client: client}
+}
+
+// FindDeleted retrieves a page of the deleted records of the resource, last deleted first.
+func (r *trashRepository) FindDeleted(resource string, limit, offset int) ([]entity.TrashItem, int64, error) {
+	table, err := findTrashTable(resource)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := r.client.db.Table(resource).Where("deleted_at IS NOT NULL")
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []entity.TrashItem
+	err = query.
+		Select("uuid, " + table.nameColumn + " AS name, deleted_at").
+		Order("deleted_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&items).Error
+	return items, total, err
+}
+
+// Restore restores the deleted record of the resource with the given UUID.
+func (r *trashRepository) Restore(resource string, uuid uuid.UUID) (bool, error) {
+	if _, err := findTrashTable(resource); err != nil {
+		return false, err
+	}
+
+	result := r.client.db.Table(resource).
+		Where("uuid = ? AND deleted_at IS NOT NULL", uuid).
+		Update("deleted_at", nil)
+	return result.RowsAffected > 0, result.Error
+}
+
+// PurgeDeleted hard-deletes up to limit records of the resource deleted before the given time, with the
+// records referencing them and their media, in a single transaction.
+func (r *trashRepository) PurgeDeleted(resource string, before time.Time, limit int) (*entity.PurgedTrash, error) {
+	table, err := findTrashTable(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	purged := &entity.PurgedTrash{Resource: resource}
+	err = r.client.db.Transaction(func(tx *gorm.DB) error {
+		var ids []int
+		err := tx.Table(resource).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Order("deleted_at").
+			Limit(limit).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		var media []entity.Media
+		err = tx.Joins("JOIN "+table.mediaTable+" ON "+table.mediaTable+".media_id = media.id").
+			Where(table.mediaTable+"."+table.foreignKey+" IN ?", ids).
+			Find(&media).Error
+		if err != nil {
+			return err
+		}
+
+		for _, dependent := range append(table.dependents, table.mediaTable) {
+			if err := tx.Exec("DELETE FROM "+dependent+" WHERE "+table.foreignKey+" IN ?", ids).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(media) > 0 {
+			mediaIDs := make([]int, 0, len(media))
+			for _, m := range media {
+				mediaIDs = append(mediaIDs, m.ID)
+				purged.MediaURLs = append(purged.MediaURLs, m.URLs()...)
+			}
+			if err := tx.Exec("DELETE FROM media WHERE id IN ?", mediaIDs).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Exec("DELETE FROM "+resource+" WHERE id IN ?", ids)
+		purged.Purged = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return purged, nil
+}
+
+// findTrashTable returns the description of the resource kept in the trash.
+// The resource is checked against the known tables, as it is used to build the queries.
+func findTrashTable(resource string) (trashTable, error) {
+	table, ok := trashTables[resource]
+	if !ok {
+		return trashTable{}, fmt.Errorf("resource %q is not kept in the trash", resource)
+	}
+	return table, nil
+}
@@ -0,0 +1,135 @@
+package postgresql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// uploadTable describes how the media uploaded for the records of a domain are attached.
+type uploadTable struct {
+	// mediaTable is the join table between the records and their media.
+	mediaTable string
+	// foreignKey is the column referencing the records in the mediaTable.
+	foreignKey string
+	// ownerColumn is the column referencing the user owning the record, empty for the shared records.
+	ownerColumn string
+}
+
+// uploadTables are the domains whose records the files are uploaded for, by table name.
+var uploadTables = map[string]uploadTable{
+	entity.MediaDomainReminders: {mediaTable: "reminder_media", foreignKey: "reminder_id", ownerColumn: "user_id"},
+	entity.MediaDomainArticles:  {mediaTable: "article_media", foreignKey: "article_id"},
+	entity.MediaDomainRecipes:   {mediaTable: "recipe_media", foreignKey: "recipe_id"},
+}
+
+type uploadRepository struct {
+	client *Client
+}
+
+// NewUploadRepository creates a new instance of a PostgreSQL upload repository.
+func NewUploadRepository(client *Client) ports.UploadRepository {
+	return &uploadRepository{client: client}
+}
+
+// FindByUUID retrieves a record by its UUID.
+func (r *uploadRepository) FindByUUID(uuid uuid.UUID, out interface{}) (interface{}, error) {
+	return r.client.FindByUUID(uuid, out)
+}
+
+// FindRecord retrieves the ID and the owner of the record of the domain, unless it is deleted.
+func (r *uploadRepository) FindRecord(domain string, recordUUID uuid.UUID) (*entity.UploadRecord, error) {
+	table, err := findUploadTable(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := "id"
+	if table.ownerColumn != "" {
+		columns += ", " + table.ownerColumn + " AS user_id"
+	}
+
+	record := &entity.UploadRecord{}
+	result := r.client.db.Table(domain).
+		Select(columns).
+		Where("uuid = ? AND deleted_at IS NULL", recordUUID).
+		Limit(1).
+		Scan(record)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return record, nil
+}
+
+// CreateUpload creates a new upload session.
+func (r *uploadRepository) CreateUpload(upload *entity.UploadSession) error {
+	return r.client.db.Create(upload).Error
+}
+
+// FindUpload retrieves the upload session of the user with the given UUID.
+func (r *uploadRepository) FindUpload(userID int, uploadUUID uuid.UUID) (*entity.UploadSession, error) {
+	upload := &entity.UploadSession{}
+	if err := r.client.db.Where("user_id = ? AND uuid = ?", userID, uploadUUID).First(upload).Error; err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
+
+// UpdateUpload saves the status, the error and the completion date of the upload session.
+func (r *uploadRepository) UpdateUpload(upload *entity.UploadSession) error {
+	return r.client.db.Model(upload).
+		Select("status", "error", "completed_at").
+		Updates(upload).Error
+}
+
+// AttachMedia creates the media, joins it to the record of the upload session and completes the session.
+func (r *uploadRepository) AttachMedia(upload *entity.UploadSession, media *entity.Media) error {
+	table, err := findUploadTable(upload.Domain)
+	if err != nil {
+		return err
+	}
+
+	return r.client.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(media).Error; err != nil {
+			return err
+		}
+
+		err := tx.Exec("INSERT INTO "+table.mediaTable+" ("+table.foreignKey+", media_id) VALUES (?, ?)", upload.RecordID, media.ID).Error
+		if err != nil {
+			return err
+		}
+
+		upload.MediaID = &media.ID
+		return tx.Model(upload).
+			Select("status", "error", "media_id", "completed_at").
+			Updates(upload).Error
+	})
+}
+
+// FindExpiredUploads retrieves up to limit pending upload sessions that expired before now, oldest first.
+func (r *uploadRepository) FindExpiredUploads(now time.Time, limit int) ([]entity.UploadSession, error) {
+	var uploads []entity.UploadSession
+	err := r.client.db.
+		Where("status = ? AND expires_at < ?", entity.UploadStatusPending, now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&uploads).Error
+	return uploads, err
+}
+
+// findUploadTable returns the description of the domain the files are uploaded for.
+// The domain is checked against the known tables, as it is used to build the queries.
+func findUploadTable(domain string) (uploadTable, error) {
+	table, ok := uploadTables[domain]
+	if !ok {
+		return uploadTable{}, fmt.Errorf("unknown media domain %q", domain)
+	}
+	return table, nil
+}
@@ -4,235 +4,281 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
+	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
-	configs "github.com/emur-uy/backend/config"
-
-	"github.com/aws/aws-sdk-go/aws/credentials"
-
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
 )
 
-// Digital Ocean Spaces Configs
-var endpoint = configs.Get().AwsEndpoint
-var region = configs.Get().AwsRegionName
-var accessKey = configs.Get().AwsAccessKey
-var secretKey = configs.Get().AwsSecretKey
-var bucketName = configs.Get().AwsBucketName
-
-var sess *session.Session
+// spacesStore is a BlobStore keeping the objects in a Digital Ocean Spaces bucket, through the S3 API.
+type spacesStore struct {
+	client   *s3.S3
+	bucket   string
+	endpoint string
+}
 
-// Init initializes the AWS package
-func Init() (err error) {
-	sess, err = session.NewSession(&aws.Config{
+// NewBlobStore returns a BlobStore keeping the objects in the given Spaces bucket.
+func NewBlobStore(endpoint, region, accessKey, secretKey, bucket string) (ports.BlobStore, error) {
+	sess, err := session.NewSession(&aws.Config{
 		Region:      aws.String(region),
 		Credentials: credentials.NewStaticCredentials(accessKey, secretKey, ""),
-		Endpoint:    &endpoint,
-	},
-	)
-	return
-}
-
-// GetHeaderType determines the file header type based on the file name
-func GetHeaderType(fileName string) string {
-	if strings.Contains(fileName, "jpeg") || strings.Contains(fileName, "jpg") {
-		return "image/jpeg"
-	}
-
-	if strings.Contains(fileName, "png") {
-		return "image/png"
-	}
-
-	return "application/octet-stream"
-}
-
-// UploadFileToS3 uploads a file (fileName) to a specified bucket path (uploadPath)
-func UploadFileToS3(fileName, uploadPath string) (string, error) {
-
-	contentType := GetHeaderType(fileName)
-	file, err := os.Open(fileName)
-	if err != nil {
-		return "", fmt.Errorf("file open failed for %v, err %v", fileName, err.Error())
-	}
-	defer file.Close()
-	uploader := s3manager.NewUploader(sess)
-	_, err = uploader.Upload(
-		&s3manager.UploadInput{
-			ACL:         aws.String("public-read"),
-			Bucket:      aws.String(bucketName),
-			Key:         aws.String(uploadPath),
-			Body:        file,
-			ContentType: &contentType,
-			// Digital Ocean Spaces encrypts data at rest automatically, no additional settings needed
-		})
+		Endpoint:    aws.String(endpoint),
+	})
 	if err != nil {
-		return "", fmt.Errorf("unable to upload %q to %q, err %v", fileName, bucketName, err)
+		return nil, err
 	}
-	return fmt.Sprintf("https://%s.%s/%s", bucketName, endpoint, uploadPath), nil
-}
 
-// UploadFileToS3Stream uploads a file to S3 using a stream.
-func UploadFileToS3Stream(file io.Reader, uploadPath string, isPublic bool) (string, error) {
-	// Create a new S3 service client
-	s3Client := s3.New(sess)
+	return &spacesStore{
+		client:   s3.New(sess),
+		bucket:   bucket,
+		endpoint: endpoint,
+	}, nil
+}
 
+// Put uploads the content to the bucket, with a public-read ACL when public is true.
+func (s *spacesStore) Put(key string, body io.Reader, contentType string, public bool) (string, error) {
 	// Read the entire file into a buffer (this assumes the file is not too large to fit in memory)
 	buffer := new(bytes.Buffer)
-	if _, err := io.Copy(buffer, file); err != nil {
+	if _, err := io.Copy(buffer, body); err != nil {
 		return "", fmt.Errorf("failed to read file into buffer: %v", err)
 	}
-
-	// Define the ACL based on the isPublic parameter
-	acl := "private"
-	if isPublic {
-		acl = "public-read"
+	if contentType == "" {
+		contentType = http.DetectContentType(buffer.Bytes())
 	}
 
-	// Upload the file to S3
-	input := &s3.PutObjectInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(uploadPath),
-		Body:   bytes.NewReader(buffer.Bytes()),
-		ACL:    aws.String(acl),
+	acl := s3.ObjectCannedACLPrivate
+	if public {
+		acl = s3.ObjectCannedACLPublicRead
 	}
 
-	_, err := s3Client.PutObject(input)
+	// Digital Ocean Spaces encrypts data at rest automatically, no additional settings needed
+	_, err := s.client.PutObject(&s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(buffer.Bytes()),
+		ACL:         aws.String(acl),
+		ContentType: aws.String(contentType),
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to upload file to S3: %v", err)
 	}
 
-	// Generate the URL of the uploaded file
-	fileURL := fmt.Sprintf("https://%s.%s/%s", bucketName, endpoint, uploadPath)
+	return s.url(key), nil
+}
 
-	return fileURL, nil
+// Get downloads the object from the bucket.
+func (s *spacesStore) Get(key string) (io.ReadCloser, error) {
+	output, err := s.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, ports.ErrBlobNotFound
+		}
+		return nil, fmt.Errorf("unable to get %q from %q, err %v", key, s.bucket, err)
+	}
+	return output.Body, nil
 }
 
-// GetFileLinkUsingKey generates a pre-signed URL for the specified bucket path (uploadPath) with an expiration time
-func GetFileLinkUsingKey(uploadPath string, expirySeconds int) (string, error) {
+// SetPublic replaces the ACL of the object, public-read when public is true and private otherwise.
+func (s *spacesStore) SetPublic(key string, public bool) error {
+	acl := s3.ObjectCannedACLPrivate
+	if public {
+		acl = s3.ObjectCannedACLPublicRead
+	}
 
-	svc := s3.New(sess)
-	req, _ := svc.GetObjectRequest(&s3.GetObjectInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(uploadPath),
+	_, err := s.client.PutObjectAcl(&s3.PutObjectAclInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		ACL:    aws.String(acl),
 	})
-	urlStr, err := req.Presign(time.Duration(expirySeconds) * time.Second)
-
 	if err != nil {
-		return "", fmt.Errorf("unable to get presigned link for %q, err %v", uploadPath, err)
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return ports.ErrBlobNotFound
+		}
+		return fmt.Errorf("unable to set the ACL of %q, err %v", key, err)
 	}
-	return urlStr, err
+	return nil
 }
 
-// GetFileFromS3 downloads a file (fileName) from the bucket and saves it to the specified local path (outPath)
-func GetFileFromS3(fileName, outPath string) (string, error) {
-	downloader := s3manager.NewDownloader(sess)
-	outFile := filepath.Join(outPath, fileName)
-	file, err := os.Create(outFile)
+// Delete deletes the object from the bucket.
+func (s *spacesStore) Delete(key string) error {
+	_, err := s.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+// List retrieves every object of the bucket under the prefix, page after page.
+func (s *spacesStore) List(prefix string) ([]entity.BlobObject, error) {
+	var objects []entity.BlobObject
+	err := s.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			objects = append(objects, entity.BlobObject{
+				Key:          aws.StringValue(object.Key),
+				Size:         aws.Int64Value(object.Size),
+				LastModified: aws.TimeValue(object.LastModified),
+			})
+		}
+		return true
+	})
 	if err != nil {
-		return "", fmt.Errorf("not able to create file, err : %v", err)
+		return nil, fmt.Errorf("unable to get list from %q, err %v", prefix, err)
 	}
-	defer file.Close()
-	_, err = downloader.Download(file,
-		&s3.GetObjectInput{
-			Bucket: aws.String(bucketName),
-			Key:    aws.String(fileName),
-		})
+	return objects, nil
+}
+
+// SignedURL generates a pre-signed URL of the object.
+func (s *spacesStore) SignedURL(key string, expiry time.Duration) (string, error) {
+	req, _ := s.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	urlStr, err := req.Presign(expiry)
 	if err != nil {
-		return "", fmt.Errorf("unable to get %q from %q, err %v", fileName, bucketName, err)
+		return "", fmt.Errorf("unable to get presigned link for %q, err %v", key, err)
 	}
-	return outFile, nil
+	return urlStr, nil
 }
 
-// GetFileFromS3WithFileName downloads a file (fileName) from the bucket and saves it to the specified local path (outPath) with a custom name
-func GetFileFromS3WithFileName(fileName, outPath, customFileName string) (string, error) {
-	downloader := s3manager.NewDownloader(sess)
-	outFile := filepath.Join(outPath, customFileName)
-	file, err := os.Create(outFile)
+// Key returns the key of the object from its URL, a value that is not a URL is returned as is.
+func (s *spacesStore) Key(fileURL string) string {
+	return ObjectKey(fileURL)
+}
+
+// URL returns the URL of the object in the bucket.
+func (s *spacesStore) URL(key string) string {
+	return s.url(key)
+}
+
+// Stat retrieves the size and the last modification of the object, without downloading it.
+func (s *spacesStore) Stat(key string) (*entity.BlobObject, error) {
+	output, err := s.client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
 	if err != nil {
-		return "", fmt.Errorf("not able to create file, err : %v", err)
+		// HEAD responses have no body, a missing object is reported with the NotFound code
+		if aerr, ok := err.(awserr.Error); ok && (aerr.Code() == "NotFound" || aerr.Code() == s3.ErrCodeNoSuchKey) {
+			return nil, ports.ErrBlobNotFound
+		}
+		return nil, fmt.Errorf("unable to get %q from %q, err %v", key, s.bucket, err)
 	}
-	defer file.Close()
-	_, err = downloader.Download(file,
-		&s3.GetObjectInput{
-			Bucket: aws.String(bucketName),
-			Key:    aws.String(fileName),
-		})
+	return &entity.BlobObject{
+		Key:          key,
+		Size:         aws.Int64Value(output.ContentLength),
+		LastModified: aws.TimeValue(output.LastModified),
+	}, nil
+}
+
+// SignedPutURL generates a pre-signed URL uploading the object. The bucket keeps the objects private by default.
+// The content length is signed, so the body must be of the given size.
+func (s *spacesStore) SignedPutURL(key string, contentType string, size int64, expiry time.Duration) (string, error) {
+	req, _ := s.client.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	})
+	urlStr, err := req.Presign(expiry)
 	if err != nil {
-		return "", fmt.Errorf("unable to get %q from %q, err %v", fileName, bucketName, err)
+		return "", fmt.Errorf("unable to get presigned upload link for %q, err %v", key, err)
 	}
-	return outFile, nil
+	return urlStr, nil
 }
 
-// FileExistsS3 checks if the file (fileName) exists in the bucket
-func FileExistsS3(fileName string) (bool, error) {
-	svc := s3.New(sess)
-	_, err := svc.HeadObject(&s3.HeadObjectInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(fileName),
+// CreateMultipartUpload starts a multipart upload of a private object.
+func (s *spacesStore) CreateMultipartUpload(key string, contentType string) (string, error) {
+	output, err := s.client.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ACL:         aws.String(s3.ObjectCannedACLPrivate),
+		ContentType: aws.String(contentType),
 	})
 	if err != nil {
-		if aerr, ok := err.(awserr.Error); ok {
-			switch aerr.Code() {
-			case "NotFound": // s3.ErrCodeNoSuchKey does not work, AWS is missing this error code so we hardwire a string
-				return false, nil
-			default:
-				return false, err
-			}
-		}
-		return false, err
+		return "", fmt.Errorf("unable to create multipart upload for %q, err %v", key, err)
 	}
-	return true, nil
+	return aws.StringValue(output.UploadId), nil
 }
 
-// GetFileBytesFromS3 retrieves the bytes of a file (fileName) from the bucket
-func GetFileBytesFromS3(fileName string) ([]byte, error) {
-	downloader := s3manager.NewDownloader(sess)
-
-	buffer := &aws.WriteAtBuffer{}
-	_, err := downloader.Download(buffer,
-		&s3.GetObjectInput{
-			Bucket: aws.String(bucketName),
-			Key:    aws.String(fileName),
-		})
+// SignedPartURL generates a pre-signed URL uploading a part of the multipart upload, of the signed content length.
+func (s *spacesStore) SignedPartURL(key string, uploadID string, partNumber int, size int64, expiry time.Duration) (string, error) {
+	req, _ := s.client.UploadPartRequest(&s3.UploadPartInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		UploadId:      aws.String(uploadID),
+		PartNumber:    aws.Int64(int64(partNumber)),
+		ContentLength: aws.Int64(size),
+	})
+	urlStr, err := req.Presign(expiry)
 	if err != nil {
-		return nil, fmt.Errorf("Unable to get %q from %q, err %v", fileName, bucketName, err)
+		return "", fmt.Errorf("unable to get presigned part link for %q, err %v", key, err)
 	}
-	return buffer.Bytes(), nil
+	return urlStr, nil
 }
 
-// GetListFromS3 retrieves the list of files from the specified subfolder in the bucket
-func GetListFromS3(subFolder string) ([]string, error) {
-	svc := s3.New(sess)
-	params := &s3.ListObjectsInput{
-		Bucket: aws.String(bucketName),
-		Prefix: aws.String(subFolder),
+// CompleteMultipartUpload assembles the parts of the multipart upload into the object.
+func (s *spacesStore) CompleteMultipartUpload(key string, uploadID string, parts []entity.UploadPart) error {
+	completed := make([]*s3.CompletedPart, 0, len(parts))
+	for _, part := range parts {
+		completed = append(completed, &s3.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int64(int64(part.PartNumber)),
+		})
 	}
-	fmt.Println(params)
-	resp, err := svc.ListObjects(params)
+
+	_, err := s.client.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: completed},
+	})
 	if err != nil {
-		return nil, fmt.Errorf("Unable to get list from %q, err %v", subFolder, err)
+		return fmt.Errorf("unable to complete multipart upload of %q, err %v", key, err)
 	}
-	var result []string
-	for _, key := range resp.Contents {
-		result = append(result, *key.Key)
+	return nil
+}
+
+// AbortMultipartUpload aborts the multipart upload, the bucket discarding its parts.
+func (s *spacesStore) AbortMultipartUpload(key string, uploadID string) error {
+	_, err := s.client.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchUpload {
+			return nil
+		}
+		return fmt.Errorf("unable to abort multipart upload of %q, err %v", key, err)
 	}
-	return result, nil
+	return nil
+}
+
+// url returns the public URL of the object.
+func (s *spacesStore) url(key string) string {
+	return fmt.Sprintf("https://%s.%s/%s", s.bucket, s.endpoint, key)
 }
 
-// DeleteObjectFromS3 deletes a file from the bucket at the specified path
-func DeleteObjectFromS3(filePath string) error {
-	svc := s3.New(sess)
-	params := &s3.DeleteObjectInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(filePath),
+// ObjectKey returns the key in the bucket of a file from the URL returned when it was uploaded.
+// A value that is not a URL is returned as is.
+func ObjectKey(fileURL string) string {
+	parsed, err := url.Parse(fileURL)
+	if err != nil || parsed.Host == "" {
+		return fileURL
 	}
-	_, err := svc.DeleteObject(params)
-	return err
+	return strings.TrimPrefix(parsed.Path, "/")
 }
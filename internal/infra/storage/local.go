@@ -0,0 +1,460 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+// FilesRoute is the route of the API serving the objects of the local store.
+const FilesRoute = "/api/v1/files"
+
+// Directories of the local store keeping the public and the private objects, and the parts of the
+// multipart uploads.
+const (
+	publicDir  = "public"
+	privateDir = "private"
+	uploadsDir = "uploads"
+)
+
+// maxPartNumber is the largest part number of a multipart upload.
+const maxPartNumber = 10000
+
+// tempPrefix prefixes the files being written, ignored by List.
+const tempPrefix = ".upload-"
+
+var (
+	// ErrInvalidKey is returned when a key is empty or escapes the directory of the store.
+	ErrInvalidKey = errors.New("invalid object key")
+	// ErrInvalidUpload is returned when the ID or the part number of a multipart upload is not valid.
+	ErrInvalidUpload = errors.New("invalid multipart upload")
+)
+
+// timeNow returns the current time, replaced in the tests.
+var timeNow = time.Now
+
+// localStore is a BlobStore keeping the objects on the local disk, served by the API under FilesRoute.
+// The public objects are kept under "public/" and served to anyone, the private objects under "private/"
+// and served with a signed URL only.
+type localStore struct {
+	dir     string
+	baseURL string
+	secret  []byte
+}
+
+// NewLocalStore returns a BlobStore keeping the objects in the given directory. The URLs of the objects
+// start with baseURL, the public URL of the API, and the private ones are signed with the secret.
+func NewLocalStore(dir, baseURL string, secret []byte) ports.LocalBlobStore {
+	return &localStore{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		secret:  secret,
+	}
+}
+
+// Put writes the content to a temporary file, renamed once complete so a partial object is never served.
+// The content type is not kept, the files being served with the type sniffed from their content.
+func (s *localStore) Put(key string, body io.Reader, contentType string, public bool) (string, error) {
+	target, other, err := s.paths(key, public)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", fmt.Errorf("error creating the storage directory: %s", err)
+	}
+	if err := writeFile(target, body); err != nil {
+		return "", fmt.Errorf("error writing the file of %s: %w", key, err)
+	}
+
+	// The object changing of visibility is removed from the other directory
+	if err := os.Remove(other); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	return s.url(key), nil
+}
+
+// Get opens the file of the object, public or private.
+func (s *localStore) Get(key string) (io.ReadCloser, error) {
+	return s.open(key, true)
+}
+
+// SetPublic moves the file of the object to the directory of the visibility.
+func (s *localStore) SetPublic(key string, public bool) error {
+	target, other, err := s.paths(key, public)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(target); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("error creating the storage directory: %s", err)
+	}
+
+	err = os.Rename(other, target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ports.ErrBlobNotFound
+	}
+	return err
+}
+
+// Delete removes the file of the object.
+func (s *localStore) Delete(key string) error {
+	public, private, err := s.paths(key, true)
+	if err != nil {
+		return err
+	}
+	for _, name := range []string{public, private} {
+		if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// List walks the public and private directories for the objects under the prefix.
+func (s *localStore) List(prefix string) ([]entity.BlobObject, error) {
+	var objects []entity.BlobObject
+	for _, visibility := range []string{publicDir, privateDir} {
+		root := filepath.Join(s.dir, visibility)
+		err := filepath.WalkDir(root, func(name string, d fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) && name == root {
+				return filepath.SkipDir
+			}
+			if err != nil || d.IsDir() || strings.HasPrefix(d.Name(), tempPrefix) {
+				return err
+			}
+
+			rel, err := filepath.Rel(root, name)
+			if err != nil {
+				return err
+			}
+			key := filepath.ToSlash(rel)
+			if !strings.HasPrefix(key, prefix) {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			objects = append(objects, entity.BlobObject{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to get list from %q, err %v", prefix, err)
+		}
+	}
+	return objects, nil
+}
+
+// SignedURL returns the URL of the object with its expiry and the HMAC signature of both.
+func (s *localStore) SignedURL(key string, expiry time.Duration) (string, error) {
+	if _, _, err := s.paths(key, true); err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(timeNow().Add(expiry).Unix(), 10)
+	query := url.Values{"expires": {expires}, "signature": {s.sign(key, expires)}}
+	return s.url(key) + "?" + query.Encode(), nil
+}
+
+// URL returns the URL of the object served by the API.
+func (s *localStore) URL(key string) string {
+	return s.url(key)
+}
+
+// Stat retrieves the size and the modification time of the file of the object.
+func (s *localStore) Stat(key string) (*entity.BlobObject, error) {
+	file, err := s.open(key, true)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return &entity.BlobObject{Key: key, Size: info.Size(), LastModified: info.ModTime()}, nil
+}
+
+// SignedPutURL returns the URL of the object with its size, its expiry and the HMAC signature of the upload.
+// The content type is not kept, as for Put.
+func (s *localStore) SignedPutURL(key string, contentType string, size int64, expiry time.Duration) (string, error) {
+	if _, _, err := s.paths(key, false); err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(timeNow().Add(expiry).Unix(), 10)
+	sizeValue := strconv.FormatInt(size, 10)
+	query := url.Values{"size": {sizeValue}, "expires": {expires}, "signature": {s.sign(http.MethodPut, key, sizeValue, expires)}}
+	return s.url(key) + "?" + query.Encode(), nil
+}
+
+// CreateMultipartUpload creates the directory keeping the parts of the upload, named after a random ID.
+func (s *localStore) CreateMultipartUpload(key string, contentType string) (string, error) {
+	if _, _, err := s.paths(key, false); err != nil {
+		return "", err
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	uploadID := hex.EncodeToString(id)
+	if err := os.MkdirAll(filepath.Join(s.dir, uploadsDir, uploadID), 0o755); err != nil {
+		return "", fmt.Errorf("error creating the upload directory: %s", err)
+	}
+	return uploadID, nil
+}
+
+// SignedPartURL returns the URL of the object with the upload ID, the part number, the size, the expiry and
+// the HMAC signature of all of them.
+func (s *localStore) SignedPartURL(key string, uploadID string, partNumber int, size int64, expiry time.Duration) (string, error) {
+	if _, _, err := s.paths(key, false); err != nil {
+		return "", err
+	}
+	part := strconv.Itoa(partNumber)
+	if _, err := s.partPath(uploadID, part); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(timeNow().Add(expiry).Unix(), 10)
+	sizeValue := strconv.FormatInt(size, 10)
+	query := url.Values{
+		"upload_id":   {uploadID},
+		"part_number": {part},
+		"size":        {sizeValue},
+		"expires":     {expires},
+		"signature":   {s.sign(http.MethodPut, key, uploadID, part, sizeValue, expires)},
+	}
+	return s.url(key) + "?" + query.Encode(), nil
+}
+
+// Write checks the signature of the upload and stores the body as a private object, or as a part of the
+// multipart upload. The body is read up to the signed size, nothing is stored when it is larger.
+// The ETag is the MD5 of the content, as returned by S3.
+func (s *localStore) Write(key string, uploadID string, partNumber string, size string, expires string, signature string, body io.Reader) (string, error) {
+	expected := s.sign(http.MethodPut, key, size, expires)
+	if uploadID != "" {
+		expected = s.sign(http.MethodPut, key, uploadID, partNumber, size, expires)
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || timeNow().Unix() > expiresAt || !hmac.Equal([]byte(signature), []byte(expected)) {
+		return "", ports.ErrInvalidSignature
+	}
+	limit, err := strconv.ParseInt(size, 10, 64)
+	if err != nil || limit < 0 {
+		return "", ports.ErrInvalidSignature
+	}
+
+	hash := md5.New()
+	body = io.TeeReader(&limitedReader{reader: body, remaining: limit}, hash)
+	if uploadID == "" {
+		if _, err := s.Put(key, body, "", false); err != nil {
+			return "", err
+		}
+		return etag(hash.Sum(nil)), nil
+	}
+
+	name, err := s.partPath(uploadID, partNumber)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Dir(name)); err != nil {
+		return "", ports.ErrBlobNotFound
+	}
+	if err := writeFile(name, body); err != nil {
+		return "", fmt.Errorf("error writing the part %s of %s: %w", partNumber, key, err)
+	}
+	return etag(hash.Sum(nil)), nil
+}
+
+// CompleteMultipartUpload checks the ETag of every part and writes their content, in order, to the object.
+// The parts are deleted once the object is written.
+func (s *localStore) CompleteMultipartUpload(key string, uploadID string, parts []entity.UploadPart) error {
+	if len(parts) == 0 {
+		return ErrInvalidUpload
+	}
+
+	readers := make([]io.Reader, 0, len(parts))
+	for _, part := range parts {
+		name, err := s.partPath(uploadID, strconv.Itoa(part.PartNumber))
+		if err != nil {
+			return err
+		}
+		file, err := os.Open(name)
+		if err != nil {
+			return fmt.Errorf("part %d of %s was not uploaded", part.PartNumber, key)
+		}
+		defer file.Close()
+
+		hash := md5.New()
+		if _, err := io.Copy(hash, file); err != nil {
+			return err
+		}
+		if strings.Trim(part.ETag, `"`) != hex.EncodeToString(hash.Sum(nil)) {
+			return fmt.Errorf("the ETag of the part %d of %s does not match", part.PartNumber, key)
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		readers = append(readers, file)
+	}
+
+	if _, err := s.Put(key, io.MultiReader(readers...), "", false); err != nil {
+		return err
+	}
+	return s.AbortMultipartUpload(key, uploadID)
+}
+
+// AbortMultipartUpload deletes the directory of the parts of the upload.
+func (s *localStore) AbortMultipartUpload(key string, uploadID string) error {
+	name, err := s.partPath(uploadID, "1")
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(filepath.Dir(name))
+}
+
+// Key returns the key of the object from its URL, a value that is not a URL of the store is returned as is.
+func (s *localStore) Key(fileURL string) string {
+	if parsed, err := url.Parse(fileURL); err == nil && strings.HasPrefix(parsed.Path, FilesRoute+"/") {
+		return strings.TrimPrefix(parsed.Path, FilesRoute+"/")
+	}
+	return fileURL
+}
+
+// Open opens the public object, or the private object when the signature is valid and not expired.
+func (s *localStore) Open(key string, expires string, signature string) (*os.File, error) {
+	file, err := s.open(key, false)
+	if !errors.Is(err, ports.ErrBlobNotFound) {
+		return file, err
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || timeNow().Unix() > expiresAt || !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+		return nil, ports.ErrInvalidSignature
+	}
+	return s.open(key, true)
+}
+
+// open opens the file of the public object, or of the private object when private is true.
+func (s *localStore) open(key string, private bool) (*os.File, error) {
+	public, privateName, err := s.paths(key, true)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{public}
+	if private {
+		names = append(names, privateName)
+	}
+	for _, name := range names {
+		file, err := os.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return file, err
+	}
+	return nil, ports.ErrBlobNotFound
+}
+
+// paths returns the path of the object in the directory of its visibility, and in the other directory.
+// The key is checked not to escape the directory of the store.
+func (s *localStore) paths(key string, public bool) (string, string, error) {
+	if key == "" || path.IsAbs(key) || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return "", "", ErrInvalidKey
+	}
+	publicName := filepath.Join(s.dir, publicDir, filepath.FromSlash(key))
+	privateName := filepath.Join(s.dir, privateDir, filepath.FromSlash(key))
+	if public {
+		return publicName, privateName, nil
+	}
+	return privateName, publicName, nil
+}
+
+// partPath returns the path of a part of the multipart upload.
+// The upload ID is checked to be a generated one and the part number to be in range.
+func (s *localStore) partPath(uploadID string, partNumber string) (string, error) {
+	if id, err := hex.DecodeString(uploadID); err != nil || len(id) != 16 {
+		return "", ErrInvalidUpload
+	}
+	part, err := strconv.Atoi(partNumber)
+	if err != nil || part < 1 || part > maxPartNumber {
+		return "", ErrInvalidUpload
+	}
+	return filepath.Join(s.dir, uploadsDir, uploadID, strconv.Itoa(part)), nil
+}
+
+// writeFile writes the content to a temporary file of the directory, renamed to name once complete.
+func writeFile(name string, body io.Reader) error {
+	file, err := os.CreateTemp(filepath.Dir(name), tempPrefix)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), name)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}
+
+// limitedReader reads the body up to the remaining bytes, failing with ErrBlobTooLarge when it is larger.
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	// One byte past the limit is read to tell a body of the exact size from a larger one
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.reader.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, ports.ErrBlobTooLarge
+	}
+	return n, err
+}
+
+// etag returns the quoted hexadecimal MD5 of a content, as the ETag returned by S3.
+func etag(sum []byte) string {
+	return `"` + hex.EncodeToString(sum) + `"`
+}
+
+// url returns the URL of the object served by the API.
+func (s *localStore) url(key string) string {
+	return s.baseURL + FilesRoute + "/" + (&url.URL{Path: key}).EscapedPath()
+}
+
+// sign returns the HMAC of the values of a signed URL: the key and the expiry of a download, preceded by
+// the method and followed by the upload ID, the part number and the size of an upload.
+func (s *localStore) sign(values ...string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(strings.Join(values, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
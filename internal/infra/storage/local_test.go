@@ -0,0 +1,317 @@
+package storage
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2023, 8, 6, 10, 0, 0, 0, time.UTC)
+
+func newTestLocalStore(t *testing.T) ports.LocalBlobStore {
+	original := timeNow
+	timeNow = func() time.Time { return testNow }
+	t.Cleanup(func() { timeNow = original })
+
+	return NewLocalStore(t.TempDir(), "http://localhost:8080/", []byte("secret"))
+}
+
+func readObject(t *testing.T, store ports.BlobStore, key string) string {
+	body, err := store.Get(key)
+	require.NoError(t, err)
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestLocalStorePutGetDelete(t *testing.T) {
+	store := newTestLocalStore(t)
+
+	fileURL, err := store.Put("reminders/a.png", strings.NewReader("image"), "image/png", true)
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/api/v1/files/reminders/a.png", fileURL)
+	assert.Equal(t, "reminders/a.png", store.Key(fileURL))
+	assert.Equal(t, "image", readObject(t, store, "reminders/a.png"))
+
+	// Storing the object again replaces it, moving it to the private files
+	_, err = store.Put("reminders/a.png", strings.NewReader("private image"), "image/png", false)
+	require.NoError(t, err)
+	assert.Equal(t, "private image", readObject(t, store, "reminders/a.png"))
+	objects, err := store.List("reminders/")
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.Equal(t, int64(len("private image")), objects[0].Size)
+
+	require.NoError(t, store.Delete("reminders/a.png"))
+	require.NoError(t, store.Delete("reminders/a.png"))
+	_, err = store.Get("reminders/a.png")
+	assert.ErrorIs(t, err, ports.ErrBlobNotFound)
+}
+
+func TestLocalStoreList(t *testing.T) {
+	store := newTestLocalStore(t)
+
+	objects, err := store.List("")
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+
+	for _, key := range []string{"reminders/a.png", "reminders/b.png", "recipes/c.png"} {
+		_, err := store.Put(key, strings.NewReader(key), "", key != "reminders/b.png")
+		require.NoError(t, err)
+	}
+
+	objects, err = store.List("reminders/")
+	require.NoError(t, err)
+	keys := []string{}
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+	assert.ElementsMatch(t, []string{"reminders/a.png", "reminders/b.png"}, keys)
+}
+
+func TestLocalStoreOpen(t *testing.T) {
+	store := newTestLocalStore(t)
+	_, err := store.Put("reminders/public.png", strings.NewReader("public"), "", true)
+	require.NoError(t, err)
+	_, err = store.Put("reminders/private.png", strings.NewReader("private"), "", false)
+	require.NoError(t, err)
+
+	file, err := store.Open("reminders/public.png", "", "")
+	require.NoError(t, err)
+	file.Close()
+
+	_, err = store.Open("reminders/private.png", "", "")
+	assert.ErrorIs(t, err, ports.ErrInvalidSignature)
+
+	signedURL, err := store.SignedURL("reminders/private.png", 5*time.Minute)
+	require.NoError(t, err)
+	parsed, err := url.Parse(signedURL)
+	require.NoError(t, err)
+	assert.Equal(t, "reminders/private.png", store.Key(signedURL))
+	expires, signature := parsed.Query().Get("expires"), parsed.Query().Get("signature")
+
+	file, err = store.Open("reminders/private.png", expires, signature)
+	require.NoError(t, err)
+	file.Close()
+
+	_, err = store.Open("reminders/public.png", expires, signature)
+	assert.NoError(t, err)
+	_, err = store.Open("reminders/other.png", expires, signature)
+	assert.ErrorIs(t, err, ports.ErrInvalidSignature)
+
+	timeNow = func() time.Time { return testNow.Add(6 * time.Minute) }
+	_, err = store.Open("reminders/private.png", expires, signature)
+	assert.ErrorIs(t, err, ports.ErrInvalidSignature)
+}
+
+func TestLocalStoreInvalidKey(t *testing.T) {
+	store := newTestLocalStore(t)
+
+	for _, key := range []string{"", "../secret", "/etc/passwd", "reminders/../../secret", "reminders//a.png"} {
+		_, err := store.Put(key, strings.NewReader("data"), "", true)
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+		_, err = store.Open(key, "", "")
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+}
+
+func TestNewBlobStore(t *testing.T) {
+	store := NewBlobStore(config.Config{StorageDir: t.TempDir()})
+	_, ok := store.(ports.LocalBlobStore)
+	assert.True(t, ok)
+
+	store = NewBlobStore(config.Config{AwsBucketName: "emur", AwsEndpoint: "nyc3.digitaloceanspaces.com", AwsRegionName: "us-east-1"})
+	_, ok = store.(ports.LocalBlobStore)
+	assert.False(t, ok)
+
+	store = NewBlobStore(config.Config{AwsBucketName: "emur", StorageProvider: ProviderLocal, StorageDir: t.TempDir()})
+	_, ok = store.(ports.LocalBlobStore)
+	assert.True(t, ok)
+}
+
+func TestLocalStoreWithoutFolderName(t *testing.T) {
+	store := newTestLocalStore(t)
+	cfg := config.Config{StorageProvider: ProviderLocal}
+
+	// The keys of the media are "<folder>/<name>", an empty folder would make them absolute
+	_, err := store.Put("/"+uuid.New().String(), strings.NewReader("data"), "image/png", true)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	key := cfg.MediaFolder() + "/" + uuid.New().String()
+	_, err = store.Put(key, strings.NewReader("data"), "image/png", true)
+	require.NoError(t, err)
+	assert.Equal(t, "data", readObject(t, store, key))
+
+	assert.Equal(t, config.DefaultMediaFolder, config.Config{}.MediaFolder())
+	assert.Equal(t, "emur", config.Config{StorageProvider: ProviderLocal, AwsFolderName: "emur"}.MediaFolder())
+	assert.Empty(t, config.Config{AwsBucketName: "emur"}.MediaFolder())
+}
+
+func TestLocalStoreSetPublic(t *testing.T) {
+	store := newTestLocalStore(t)
+	_, err := store.Put("reminders/a.png", strings.NewReader("image"), "", true)
+	require.NoError(t, err)
+
+	require.NoError(t, store.SetPublic("reminders/a.png", false))
+	require.NoError(t, store.SetPublic("reminders/a.png", false))
+	_, err = store.Open("reminders/a.png", "", "")
+	assert.ErrorIs(t, err, ports.ErrInvalidSignature)
+	assert.Equal(t, "image", readObject(t, store, "reminders/a.png"))
+
+	require.NoError(t, store.SetPublic("reminders/a.png", true))
+	file, err := store.Open("reminders/a.png", "", "")
+	require.NoError(t, err)
+	file.Close()
+
+	assert.ErrorIs(t, store.SetPublic("reminders/missing.png", false), ports.ErrBlobNotFound)
+}
+
+// md5ETag returns the ETag of a content, its quoted MD5.
+func md5ETag(data string) string {
+	sum := md5.Sum([]byte(data))
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// signedQuery returns the query of a signed URL of the store.
+func signedQuery(t *testing.T, signedURL string) url.Values {
+	parsed, err := url.Parse(signedURL)
+	require.NoError(t, err)
+	return parsed.Query()
+}
+
+func TestLocalStoreDirectUpload(t *testing.T) {
+	store := newTestLocalStore(t)
+
+	putURL, err := store.SignedPutURL("reminders/a.pdf", "application/pdf", 4, 5*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "reminders/a.pdf", store.Key(putURL))
+	query := signedQuery(t, putURL)
+
+	// The signature of the upload only allows to upload the object
+	_, err = store.Write("reminders/b.pdf", "", "", query.Get("size"), query.Get("expires"), query.Get("signature"), strings.NewReader("%PDF"))
+	assert.ErrorIs(t, err, ports.ErrInvalidSignature)
+	_, err = store.Open("reminders/a.pdf", query.Get("expires"), query.Get("signature"))
+	assert.ErrorIs(t, err, ports.ErrInvalidSignature)
+
+	etag, err := store.Write("reminders/a.pdf", "", "", query.Get("size"), query.Get("expires"), query.Get("signature"), strings.NewReader("%PDF"))
+	require.NoError(t, err)
+	assert.Equal(t, md5ETag("%PDF"), etag)
+	assert.Equal(t, "%PDF", readObject(t, store, "reminders/a.pdf"))
+
+	object, err := store.Stat("reminders/a.pdf")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), object.Size)
+
+	// The uploaded object is private
+	_, err = store.Open("reminders/a.pdf", "", "")
+	assert.ErrorIs(t, err, ports.ErrInvalidSignature)
+
+	timeNow = func() time.Time { return testNow.Add(6 * time.Minute) }
+	_, err = store.Write("reminders/a.pdf", "", "", query.Get("size"), query.Get("expires"), query.Get("signature"), strings.NewReader("%PDF"))
+	assert.ErrorIs(t, err, ports.ErrInvalidSignature)
+
+	_, err = store.Stat("reminders/missing.pdf")
+	assert.ErrorIs(t, err, ports.ErrBlobNotFound)
+}
+
+func TestLocalStoreUploadSizeLimit(t *testing.T) {
+	store := newTestLocalStore(t)
+
+	putURL, err := store.SignedPutURL("reminders/a.pdf", "application/pdf", 4, 5*time.Minute)
+	require.NoError(t, err)
+	query := signedQuery(t, putURL)
+
+	// The size is signed
+	_, err = store.Write("reminders/a.pdf", "", "", "1000", query.Get("expires"), query.Get("signature"), strings.NewReader("%PDF-1.7"))
+	assert.ErrorIs(t, err, ports.ErrInvalidSignature)
+
+	// Nothing is stored from a larger body
+	_, err = store.Write("reminders/a.pdf", "", "", query.Get("size"), query.Get("expires"), query.Get("signature"), strings.NewReader("%PDF-1.7"))
+	assert.ErrorIs(t, err, ports.ErrBlobTooLarge)
+	_, err = store.Stat("reminders/a.pdf")
+	assert.ErrorIs(t, err, ports.ErrBlobNotFound)
+	objects, err := store.List("")
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+
+	uploadID, err := store.CreateMultipartUpload("reminders/a.mp4", "video/mp4")
+	require.NoError(t, err)
+	partURL, err := store.SignedPartURL("reminders/a.mp4", uploadID, 1, 4, 5*time.Minute)
+	require.NoError(t, err)
+	query = signedQuery(t, partURL)
+	_, err = store.Write("reminders/a.mp4", uploadID, "1", query.Get("size"), query.Get("expires"), query.Get("signature"), strings.NewReader("first part"))
+	assert.ErrorIs(t, err, ports.ErrBlobTooLarge)
+}
+
+func TestLocalStoreMultipartUpload(t *testing.T) {
+	store := newTestLocalStore(t)
+
+	uploadID, err := store.CreateMultipartUpload("reminders/a.mp4", "video/mp4")
+	require.NoError(t, err)
+
+	parts := []entity.UploadPart{}
+	for number, data := range []string{"first ", "second"} {
+		partURL, err := store.SignedPartURL("reminders/a.mp4", uploadID, number+1, int64(len(data)), 5*time.Minute)
+		require.NoError(t, err)
+		query := signedQuery(t, partURL)
+		assert.Equal(t, uploadID, query.Get("upload_id"))
+
+		// The signature is bound to the part
+		_, err = store.Write("reminders/a.mp4", uploadID, "3", query.Get("size"), query.Get("expires"), query.Get("signature"), strings.NewReader(data))
+		assert.ErrorIs(t, err, ports.ErrInvalidSignature)
+
+		etag, err := store.Write("reminders/a.mp4", uploadID, query.Get("part_number"), query.Get("size"), query.Get("expires"), query.Get("signature"), strings.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, md5ETag(data), etag)
+		parts = append(parts, entity.UploadPart{PartNumber: number + 1, ETag: etag})
+	}
+
+	// The parts are not listed as objects
+	objects, err := store.List("")
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+
+	assert.Error(t, store.CompleteMultipartUpload("reminders/a.mp4", uploadID, []entity.UploadPart{{PartNumber: 1, ETag: parts[1].ETag}}))
+	assert.Error(t, store.CompleteMultipartUpload("reminders/a.mp4", uploadID, append(parts, entity.UploadPart{PartNumber: 3, ETag: parts[0].ETag})))
+
+	require.NoError(t, store.CompleteMultipartUpload("reminders/a.mp4", uploadID, parts))
+	assert.Equal(t, "first second", readObject(t, store, "reminders/a.mp4"))
+	_, err = store.Open("reminders/a.mp4", "", "")
+	assert.ErrorIs(t, err, ports.ErrInvalidSignature)
+
+	// The parts are deleted once assembled
+	assert.Error(t, store.CompleteMultipartUpload("reminders/a.mp4", uploadID, parts))
+
+	_, err = store.SignedPartURL("reminders/a.mp4", "../../secret", 1, 4, time.Minute)
+	assert.ErrorIs(t, err, ErrInvalidUpload)
+	_, err = store.SignedPartURL("reminders/a.mp4", uploadID, maxPartNumber+1, 4, time.Minute)
+	assert.ErrorIs(t, err, ErrInvalidUpload)
+}
+
+func TestLocalStoreAbortMultipartUpload(t *testing.T) {
+	store := newTestLocalStore(t)
+
+	uploadID, err := store.CreateMultipartUpload("reminders/a.mp4", "video/mp4")
+	require.NoError(t, err)
+	partURL, err := store.SignedPartURL("reminders/a.mp4", uploadID, 1, 4, 5*time.Minute)
+	require.NoError(t, err)
+	query := signedQuery(t, partURL)
+
+	require.NoError(t, store.AbortMultipartUpload("reminders/a.mp4", uploadID))
+	require.NoError(t, store.AbortMultipartUpload("reminders/a.mp4", uploadID))
+
+	_, err = store.Write("reminders/a.mp4", uploadID, "1", query.Get("size"), query.Get("expires"), query.Get("signature"), strings.NewReader("data"))
+	assert.ErrorIs(t, err, ports.ErrBlobNotFound)
+}
@@ -0,0 +1,264 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+// memoryURL is the base of the URLs of the objects of the MemoryStore.
+const memoryURL = "https://storage.test/"
+
+// MemoryObject is an object kept by the MemoryStore.
+type MemoryObject struct {
+	Data         []byte
+	ContentType  string
+	Public       bool
+	LastModified time.Time
+}
+
+// MemoryUpload is a multipart upload of the MemoryStore, with its uploaded parts by part number.
+type MemoryUpload struct {
+	Key         string
+	ContentType string
+	Parts       map[int][]byte
+}
+
+// MemoryStore is a BlobStore that keeps every object in memory.
+// It is meant for tests that need to inspect what would have been stored.
+type MemoryStore struct {
+	mu      sync.Mutex
+	Objects map[string]*MemoryObject
+	// Uploads are the multipart uploads in progress, by upload ID.
+	Uploads map[string]*MemoryUpload
+	// Deleted are the keys of the deleted objects, in order.
+	Deleted []string
+	// Errors, when set for a key, are returned by the operations on the object instead of performing them.
+	Errors map[string]error
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{Objects: map[string]*MemoryObject{}, Uploads: map[string]*MemoryUpload{}, Errors: map[string]error{}}
+}
+
+// Put records the object, or returns the error of the key if it is set.
+func (m *MemoryStore) Put(key string, body io.Reader, contentType string, public bool) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.Errors[key]; err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	m.Objects[key] = &MemoryObject{Data: data, ContentType: contentType, Public: public, LastModified: timeNow()}
+	return memoryURL + key, nil
+}
+
+// Get returns the content of the object.
+func (m *MemoryStore) Get(key string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.Errors[key]; err != nil {
+		return nil, err
+	}
+	object, ok := m.Objects[key]
+	if !ok {
+		return nil, ports.ErrBlobNotFound
+	}
+	return io.NopCloser(bytes.NewReader(object.Data)), nil
+}
+
+// SetPublic changes the visibility of the object.
+func (m *MemoryStore) SetPublic(key string, public bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.Errors[key]; err != nil {
+		return err
+	}
+	object, ok := m.Objects[key]
+	if !ok {
+		return ports.ErrBlobNotFound
+	}
+	object.Public = public
+	return nil
+}
+
+// Delete removes the object and records its key.
+func (m *MemoryStore) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.Errors[key]; err != nil {
+		return err
+	}
+	delete(m.Objects, key)
+	m.Deleted = append(m.Deleted, key)
+	return nil
+}
+
+// List returns the objects under the prefix, sorted by key.
+func (m *MemoryStore) List(prefix string) ([]entity.BlobObject, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var objects []entity.BlobObject
+	for key, object := range m.Objects {
+		if strings.HasPrefix(key, prefix) {
+			objects = append(objects, entity.BlobObject{Key: key, Size: int64(len(object.Data)), LastModified: object.LastModified})
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
+
+// SignedURL returns the URL of the object with its expiry.
+func (m *MemoryStore) SignedURL(key string, expiry time.Duration) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.Errors[key]; err != nil {
+		return "", err
+	}
+	return memoryURL + key + "?expires=" + url.QueryEscape(timeNow().Add(expiry).UTC().Format(time.RFC3339)), nil
+}
+
+// Key returns the path of the URL, a value that is not a URL is returned as is.
+func (m *MemoryStore) Key(fileURL string) string {
+	parsed, err := url.Parse(fileURL)
+	if err != nil || parsed.Host == "" {
+		return fileURL
+	}
+	return strings.TrimPrefix(parsed.Path, "/")
+}
+
+// URL returns the URL of the object.
+func (m *MemoryStore) URL(key string) string {
+	return memoryURL + key
+}
+
+// Stat returns the size and the last modification of the object.
+func (m *MemoryStore) Stat(key string) (*entity.BlobObject, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.Errors[key]; err != nil {
+		return nil, err
+	}
+	object, ok := m.Objects[key]
+	if !ok {
+		return nil, ports.ErrBlobNotFound
+	}
+	return &entity.BlobObject{Key: key, Size: int64(len(object.Data)), LastModified: object.LastModified}, nil
+}
+
+// SignedPutURL returns the URL of the object with the upload method and its expiry.
+func (m *MemoryStore) SignedPutURL(key string, contentType string, size int64, expiry time.Duration) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.Errors[key]; err != nil {
+		return "", err
+	}
+	return memoryURL + key + "?method=PUT&size=" + strconv.FormatInt(size, 10) + "&expires=" + url.QueryEscape(timeNow().Add(expiry).UTC().Format(time.RFC3339)), nil
+}
+
+// CreateMultipartUpload records a new multipart upload of the object.
+func (m *MemoryStore) CreateMultipartUpload(key string, contentType string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.Errors[key]; err != nil {
+		return "", err
+	}
+	uploadID := fmt.Sprintf("upload-%d", len(m.Uploads)+1)
+	m.Uploads[uploadID] = &MemoryUpload{Key: key, ContentType: contentType, Parts: map[int][]byte{}}
+	return uploadID, nil
+}
+
+// SignedPartURL returns the URL of the object with the upload ID and the part number.
+func (m *MemoryStore) SignedPartURL(key string, uploadID string, partNumber int, size int64, expiry time.Duration) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.Errors[key]; err != nil {
+		return "", err
+	}
+	if _, ok := m.Uploads[uploadID]; !ok {
+		return "", ports.ErrBlobNotFound
+	}
+	return memoryURL + key + "?uploadId=" + uploadID + "&partNumber=" + strconv.Itoa(partNumber) + "&size=" + strconv.FormatInt(size, 10), nil
+}
+
+// UploadPart records a part of the multipart upload, as a client sending it to its signed URL would.
+// It returns the ETag of the part.
+func (m *MemoryStore) UploadPart(uploadID string, partNumber int, data []byte) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	upload, ok := m.Uploads[uploadID]
+	if !ok {
+		return "", ports.ErrBlobNotFound
+	}
+	upload.Parts[partNumber] = data
+	return memoryETag(data), nil
+}
+
+// CompleteMultipartUpload stores the parts, in order, as a private object once their ETag are checked.
+func (m *MemoryStore) CompleteMultipartUpload(key string, uploadID string, parts []entity.UploadPart) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.Errors[key]; err != nil {
+		return err
+	}
+	upload, ok := m.Uploads[uploadID]
+	if !ok || upload.Key != key || len(parts) == 0 {
+		return fmt.Errorf("no multipart upload %s of %s", uploadID, key)
+	}
+
+	var data []byte
+	for _, part := range parts {
+		content, ok := upload.Parts[part.PartNumber]
+		if !ok || memoryETag(content) != part.ETag {
+			return fmt.Errorf("invalid part %d of %s", part.PartNumber, key)
+		}
+		data = append(data, content...)
+	}
+	m.Objects[key] = &MemoryObject{Data: data, ContentType: upload.ContentType, LastModified: timeNow()}
+	delete(m.Uploads, uploadID)
+	return nil
+}
+
+// AbortMultipartUpload discards the multipart upload.
+func (m *MemoryStore) AbortMultipartUpload(key string, uploadID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.Errors[key]; err != nil {
+		return err
+	}
+	delete(m.Uploads, uploadID)
+	return nil
+}
+
+// memoryETag returns the ETag of a part, as returned by the local store.
+func memoryETag(data []byte) string {
+	sum := md5.Sum(data)
+	return etag(sum[:])
+}
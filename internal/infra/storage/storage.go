@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"log"
+
+	"github.com/emur-uy/backend/config"
+	aws "github.com/emur-uy/backend/internal/infra/repositories/spaces"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+// Provider names accepted by the STORAGE_PROVIDER configuration.
+const (
+	ProviderSpaces = "spaces"
+	ProviderLocal  = "local"
+)
+
+// defaultDir is the directory of the local store when STORAGE_DIR is not set.
+const defaultDir = "storage"
+
+// urlKeyLabel derives the key signing the URLs of the local store from SECRET_KEY.
+const urlKeyLabel = "local-store-url-signing-key"
+
+// NewBlobStore returns the BlobStore selected by the configuration. It defaults to the Spaces bucket when
+// one is configured, and to the local store otherwise so local environments do not need cloud credentials.
+func NewBlobStore(cfg config.Config) ports.BlobStore {
+	if !cfg.LocalStorage() {
+		store, err := aws.NewBlobStore(cfg.AwsEndpoint, cfg.AwsRegionName, cfg.AwsAccessKey, cfg.AwsSecretKey, cfg.AwsBucketName)
+		if err != nil {
+			log.Fatalf("error initializing aws services: %s", err)
+		}
+		return store
+	}
+
+	dir := cfg.StorageDir
+	if dir == "" {
+		dir = defaultDir
+	}
+	return NewLocalStore(dir, cfg.AppURL, urlSigningKey(cfg.SecretKey))
+}
+
+// urlSigningKey derives the key of the signed URLs of the local store from the secret, which also signs the
+// action tokens, so a signature of one can never be valid for the other.
+func urlSigningKey(secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(urlKeyLabel))
+	return mac.Sum(nil)
+}
@@ -3,8 +3,19 @@ package worker
 import (
 	"time"
 
+	"github.com/emur-uy/backend/config"
+	forecastProvider "github.com/emur-uy/backend/internal/infra/forecast"
+	"github.com/emur-uy/backend/internal/infra/mailer"
+	"github.com/emur-uy/backend/internal/infra/notifier"
 	"github.com/emur-uy/backend/internal/infra/repositories/postgresql"
+	"github.com/emur-uy/backend/internal/infra/storage"
+	"github.com/emur-uy/backend/internal/pkg/service/erasure"
+	"github.com/emur-uy/backend/internal/pkg/service/export"
 	"github.com/emur-uy/backend/internal/pkg/service/forecast"
+	"github.com/emur-uy/backend/internal/pkg/service/media"
+	"github.com/emur-uy/backend/internal/pkg/service/reminder"
+	"github.com/emur-uy/backend/internal/pkg/service/trash"
+	"github.com/emur-uy/backend/internal/pkg/service/user"
 	"github.com/go-co-op/gocron"
 )
 
@@ -12,11 +23,38 @@ func Start() {
 	postgresql.Connect()
 
 	repo := postgresql.NewClient()
-	forecastService := forecast.NewService(repo)
-	forecastWorker := forecast.NewWorker(forecastService)
+	forecastRepo := postgresql.NewForecastRepository(repo)
+	forecastService := forecast.NewService(forecastRepo)
+	forecastWorker := forecast.NewWorker(forecastService, forecastProvider.NewProvider(config.Get()))
+
+	reminderRepo := postgresql.NewReminderRepository(repo)
+	reminderNotificationRepo := postgresql.NewReminderNotificationRepository(repo)
+	reminderNotificationService := reminder.NewReminderNotificationService(reminderRepo, reminderNotificationRepo, notifier.NewLogNotifier())
+	reminderWorker := reminder.NewWorker(reminderNotificationService)
+
+	tokenRepo := postgresql.NewTokenRepository(repo)
+	userWorker := user.NewWorker(user.NewService(repo, tokenRepo, postgresql.NewLoginAttemptRepository(repo), postgresql.NewRoleRepository(repo), mailer.NewMailer(config.Get())))
+
+	store := storage.NewBlobStore(config.Get())
+	exportWorker := export.NewWorker(export.NewService(postgresql.NewExportRepository(repo)))
+	erasureWorker := erasure.NewWorker(erasure.NewService(postgresql.NewErasureRepository(repo), tokenRepo, mailer.NewMailer(config.Get()), store))
+	trashWorker := trash.NewWorker(trash.NewService(postgresql.NewTrashRepository(repo), store))
+	mediaWorker := media.NewWorker(
+		media.NewUploadService(postgresql.NewUploadRepository(repo), store),
+		media.NewService(postgresql.NewMediaRepository(repo), store),
+		config.Get().MediaGCDryRun,
+	)
 
 	s := gocron.NewScheduler(time.UTC)
-	s.Every(1).Hour().Do(forecastWorker.CheckForecast)
+	s.Every(5).Minutes().Do(forecastWorker.CheckForecast)
+	s.Every(5).Minutes().Do(reminderWorker.CheckNotifications)
+	s.Every(1).Day().At("03:00").Do(userWorker.PurgeExpiredTokens)
+	s.Every(1).Minute().Do(exportWorker.ProcessExports)
+	s.Every(1).Day().At("03:30").Do(exportWorker.PurgeExpiredExports)
+	s.Every(1).Hour().Do(erasureWorker.EraseAccounts)
+	s.Every(1).Day().At("04:00").Do(trashWorker.PurgeTrash)
+	s.Every(1).Hour().Do(mediaWorker.ExpireUploads)
+	s.Every(1).Day().At("04:30").Do(mediaWorker.CollectOrphans)
 
 	s.StartBlocking()
 }
@@ -0,0 +1,56 @@
+// Package dates provides the calendar helpers shared by the services: the timezone of the users,
+// and the start of the days, weeks and months they see in it.
+package dates
+
+import (
+	"errors"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+)
+
+var ErrInvalidTimezone = errors.New("invalid timezone")
+
+// ParseTimezone returns the timezone with the given IANA name. "Local", the timezone of the server,
+// and the empty name are not timezones a user can be in.
+func ParseTimezone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return location, nil
+}
+
+// UserLocation returns the timezone of the user, defaulting to UTC when none is set.
+func UserLocation(user *entity.User) (*time.Location, error) {
+	if user.Timezone == "" {
+		return time.UTC, nil
+	}
+	return ParseTimezone(user.Timezone)
+}
+
+// LocalDay returns the calendar day of t in the given location as a UTC midnight date.
+func LocalDay(t time.Time, location *time.Location) time.Time {
+	local := t.In(location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// StartOfDay returns the midnight of the day of t, in the location of t.
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek returns the Monday at midnight of the week of t, in the location of t.
+func StartOfWeek(t time.Time) time.Time {
+	day := StartOfDay(t)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// StartOfMonth returns the first day at midnight of the month of t, in the location of t.
+func StartOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,59 @@
+package dates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimezone(t *testing.T) {
+	location, err := ParseTimezone("America/Montevideo")
+	require.NoError(t, err)
+	assert.Equal(t, "America/Montevideo", location.String())
+
+	for _, name := range []string{"", "Local", "Nowhere/Nothing"} {
+		_, err := ParseTimezone(name)
+		assert.ErrorIs(t, err, ErrInvalidTimezone, name)
+	}
+}
+
+func TestUserLocation(t *testing.T) {
+	location, err := UserLocation(&entity.User{})
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, location)
+
+	location, err = UserLocation(&entity.User{Timezone: "America/Montevideo"})
+	require.NoError(t, err)
+	assert.Equal(t, "America/Montevideo", location.String())
+
+	_, err = UserLocation(&entity.User{Timezone: "Nowhere/Nothing"})
+	assert.Error(t, err)
+}
+
+func TestLocalDay(t *testing.T) {
+	montevideo, err := time.LoadLocation("America/Montevideo")
+	require.NoError(t, err)
+
+	// 01:30 UTC on June 6th is still June 5th in Montevideo (UTC-3)
+	date := time.Date(2023, time.June, 6, 1, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2023, time.June, 5, 0, 0, 0, 0, time.UTC), LocalDay(date, montevideo))
+	assert.Equal(t, time.Date(2023, time.June, 6, 0, 0, 0, 0, time.UTC), LocalDay(date, time.UTC))
+}
+
+func TestStartOfWeek(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+
+	// Sunday 26/03 belongs to the week of Monday 20/03, which starts at midnight before the change to summer time
+	date := time.Date(2023, time.March, 26, 10, 0, 0, 0, madrid)
+	assert.Equal(t, time.Date(2023, time.March, 26, 0, 0, 0, 0, madrid), StartOfDay(date))
+	assert.Equal(t, time.Date(2023, time.March, 20, 0, 0, 0, 0, madrid), StartOfWeek(date))
+}
+
+func TestStartOfMonth(t *testing.T) {
+	date := time.Date(2023, time.June, 30, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC), StartOfMonth(date))
+}
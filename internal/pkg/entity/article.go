@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // TableName returns the name of the table corresponding to the Role entity in the database.
@@ -14,12 +15,13 @@ func (*Article) TableName() string {
 
 // Article represents a struct for articles
 type Article struct {
-	ID          int       `gorm:"Column:id;PRIMARY_KEY" json:"-"`
-	UUID        uuid.UUID `gorm:"Column:uuid" json:"uuid"`
-	Title       string    `gorm:"Column:title" binding:"required" json:"title"`
-	Content     string    `gorm:"Column:content" binding:"required" json:"content"`
-	IsPublished bool      `gorm:"Column:is_published" sql:"DEFAULT:0" json:"is_published"`
-	CreatedAt   time.Time `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"created_at"`
+	ID          int            `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	UUID        uuid.UUID      `gorm:"Column:uuid" json:"uuid"`
+	Title       string         `gorm:"Column:title" binding:"required" json:"title"`
+	Content     string         `gorm:"Column:content" binding:"required" json:"content"`
+	IsPublished bool           `gorm:"Column:is_published" sql:"DEFAULT:0" json:"is_published"`
+	CreatedAt   time.Time      `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"created_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"Column:deleted_at" json:"-"`
 }
 
 // RequestCreateArticle represents a struct for creating articles
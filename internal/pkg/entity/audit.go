@@ -0,0 +1,84 @@
+// Package entity defines the domain entities (models) for the application.
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actions recorded in the audit log, derived from the HTTP method of the request.
+const (
+	AuditActionCreate = "create"
+	AuditActionRead   = "read"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// Outcomes recorded in the audit log, derived from the status of the response.
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeDenied  = "denied"
+	AuditOutcomeFailure = "failure"
+)
+
+// Types of the health data resources recorded in the audit log.
+const (
+	AuditResourceMedicalRecord = "medical_record"
+	AuditResourceMonitoring    = "monitoring"
+	AuditResourceTreatment     = "treatment"
+	AuditResourceReminder      = "reminder"
+	AuditResourcePatient       = "patient"
+	AuditResourcePersonalData  = "personal_data"
+)
+
+// TableName returns the name of the table corresponding to the AuditLog entity in the database.
+func (*AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// AuditLog represents a struct for an append-only record of an action of a user on a health data resource.
+type AuditLog struct {
+	ID           int64      `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	ActorUUID    *uuid.UUID `gorm:"Column:actor_uuid" json:"actor_uuid"`
+	Action       string     `gorm:"Column:action" json:"action"`
+	ResourceType string     `gorm:"Column:resource_type" json:"resource_type"`
+	ResourceUUID *uuid.UUID `gorm:"Column:resource_uuid" json:"resource_uuid"`
+	Method       string     `gorm:"Column:method" json:"method"`
+	Route        string     `gorm:"Column:route" json:"route"`
+	IP           string     `gorm:"Column:ip" json:"ip"`
+	Outcome      string     `gorm:"Column:outcome" json:"outcome"`
+	Status       int        `gorm:"Column:status" json:"status"`
+	CreatedAt    time.Time  `gorm:"Column:created_at" json:"created_at"`
+}
+
+// RequestAuditLogs represents a struct for the query parameters of the audit log query.
+// The dates use the dd/mm/yyyy format, the 'to' date being inclusive.
+type RequestAuditLogs struct {
+	User         string `form:"user"`
+	ResourceType string `form:"resource_type"`
+	ResourceUUID string `form:"resource_uuid"`
+	From         string `form:"from"`
+	To           string `form:"to"`
+	Page         int    `form:"page"`
+	PageSize     int    `form:"page_size"`
+}
+
+// AuditLogFilter represents a struct for the audit log query as run by the repository.
+type AuditLogFilter struct {
+	ActorUUID    *uuid.UUID
+	ResourceType string
+	ResourceUUID *uuid.UUID
+	From         *time.Time
+	To           *time.Time
+	Limit        int
+	Offset       int
+}
+
+// AuditLogResult represents a struct for a page of the audit log, newest first.
+type AuditLogResult struct {
+	Logs     []AuditLog `json:"logs"`
+	Page     int        `json:"page"`
+	PageSize int        `json:"page_size"`
+	Total    int64      `json:"total"`
+}
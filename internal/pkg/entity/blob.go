@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// BlobObject represents a file kept in the blob store.
+type BlobObject struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// UploadPart represents a part of a multipart upload, identified by the ETag returned when it was uploaded.
+type UploadPart struct {
+	PartNumber int    `json:"part_number" binding:"required"`
+	ETag       string `json:"etag" binding:"required"`
+}
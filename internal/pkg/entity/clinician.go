@@ -0,0 +1,143 @@
+// Package entity defines the domain entities (models) for the application.
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scopes of the patient data a consent shares with a clinician.
+const (
+	ConsentScopeSymptoms      = "symptoms"
+	ConsentScopeTreatments    = "treatments"
+	ConsentScopeAdherence     = "adherence"
+	ConsentScopeMedicalRecord = "medical_record"
+)
+
+// ConsentScopeList is the catalog of the scopes a patient can grant.
+var ConsentScopeList = []string{ConsentScopeSymptoms, ConsentScopeTreatments, ConsentScopeAdherence, ConsentScopeMedicalRecord}
+
+// Resources of the patient data recorded in the access log.
+const (
+	AccessResourcePatient       = "patient"
+	AccessResourceSymptomTrends = "symptom_trends"
+	AccessResourceTreatments    = "treatments"
+	AccessResourceAdherence     = "adherence"
+	AccessResourceMedicalRecord = "medical_record"
+)
+
+// TableName returns the name of the table corresponding to the Clinician entity in the database.
+func (*Clinician) TableName() string {
+	return "clinicians"
+}
+
+// Clinician represents a struct for a user account linked to a registered medical professional
+type Clinician struct {
+	ID        int       `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	UUID      uuid.UUID `gorm:"Column:uuid;default:gen_random_uuid()" json:"uuid"`
+	UserID    int       `gorm:"Column:user_id" json:"-"`
+	MedicalID int64     `gorm:"Column:medical_id" json:"-"`
+	Medical   *Medical  `gorm:"foreignKey:MedicalID" json:"medical,omitempty"`
+	CreatedAt time.Time `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"created_at"`
+}
+
+// ConsentScopes represents a wrapper type for the scopes of a consent, stored as JSON.
+type ConsentScopes []string
+
+// Value returns the database value for the ConsentScopes type.
+func (s ConsentScopes) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan scans the database value and assigns it to the ConsentScopes type.
+func (s *ConsentScopes) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	jsonBytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to scan ConsentScopes: unexpected value type")
+	}
+
+	return json.Unmarshal(jsonBytes, s)
+}
+
+// Has reports whether the scopes include the given scope.
+func (s ConsentScopes) Has(scope string) bool {
+	for _, granted := range s {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// TableName returns the name of the table corresponding to the PatientConsent entity in the database.
+func (*PatientConsent) TableName() string {
+	return "patient_consents"
+}
+
+// PatientConsent represents a struct for the consent of a patient to share their data with a clinician
+// until it expires or is revoked.
+type PatientConsent struct {
+	ID          int           `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	UUID        uuid.UUID     `gorm:"Column:uuid;default:gen_random_uuid()" json:"uuid"`
+	PatientID   int           `gorm:"Column:patient_id" json:"-"`
+	ClinicianID int           `gorm:"Column:clinician_id" json:"-"`
+	Clinician   *Clinician    `gorm:"foreignKey:ClinicianID" json:"clinician,omitempty"`
+	Scopes      ConsentScopes `gorm:"Column:scopes;type:jsonb" json:"scopes"`
+	ExpiresAt   time.Time     `gorm:"Column:expires_at" json:"expires_at"`
+	RevokedAt   *time.Time    `gorm:"Column:revoked_at" json:"revoked_at"`
+	CreatedAt   time.Time     `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"created_at"`
+}
+
+// IsActive reports whether the consent is neither revoked nor expired at the given time.
+func (c *PatientConsent) IsActive(now time.Time) bool {
+	return c.RevokedAt == nil && now.Before(c.ExpiresAt)
+}
+
+// TableName returns the name of the table corresponding to the PatientAccessLog entity in the database.
+func (*PatientAccessLog) TableName() string {
+	return "patient_access_logs"
+}
+
+// PatientAccessLog represents a struct for an append-only record of a clinician accessing, or trying to
+// access, the data of a patient.
+type PatientAccessLog struct {
+	ID           int        `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	ClinicianID  int        `gorm:"Column:clinician_id" json:"-"`
+	Clinician    *Clinician `gorm:"foreignKey:ClinicianID" json:"clinician,omitempty"`
+	PatientID    int        `gorm:"Column:patient_id" json:"-"`
+	ConsentID    *int       `gorm:"Column:consent_id" json:"-"`
+	Resource     string     `gorm:"Column:resource" json:"resource"`
+	ResourceUUID *uuid.UUID `gorm:"Column:resource_uuid" json:"resource_uuid,omitempty"`
+	Granted      bool       `gorm:"Column:granted" json:"granted"`
+	IP           string     `gorm:"Column:ip" json:"ip"`
+	CreatedAt    time.Time  `gorm:"Column:created_at" json:"created_at"`
+}
+
+// RequestLinkClinician represents a struct for linking a user account to a registered medical professional.
+type RequestLinkClinician struct {
+	MedicalID int64 `json:"medical_id" binding:"required"`
+}
+
+// RequestCreateConsent represents a struct for a patient granting a clinician access to their data.
+type RequestCreateConsent struct {
+	ClinicianUUID uuid.UUID `json:"clinician_uuid" binding:"required"`
+	Scopes        []string  `json:"scopes" binding:"required"`
+	ExpiresAt     time.Time `json:"expires_at" binding:"required"`
+}
+
+// ConsentingPatient represents a struct for a patient sharing their data with the clinician.
+type ConsentingPatient struct {
+	UUID      uuid.UUID     `json:"uuid"`
+	FirstName string        `json:"first_name"`
+	LastName  string        `json:"last_name"`
+	Scopes    ConsentScopes `json:"scopes"`
+	ExpiresAt time.Time     `json:"expires_at"`
+}
@@ -0,0 +1,8 @@
+package entity
+
+// Email represents a plain text email sent to a user.
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
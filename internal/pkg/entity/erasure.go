@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// ErasureStatusScheduled is the status of an erasure waiting for the end of the grace period.
+	ErasureStatusScheduled = "scheduled"
+	// ErasureStatusCancelled is the status of an erasure cancelled by the user during the grace period.
+	ErasureStatusCancelled = "cancelled"
+	// ErasureStatusCompleted is the status of an erasure carried out by the worker.
+	ErasureStatusCompleted = "completed"
+)
+
+// TableName returns the name of the table corresponding to the AccountErasure entity in the database.
+func (*AccountErasure) TableName() string {
+	return "account_erasures"
+}
+
+// AccountErasure represents a struct for the request of a user to delete their account.
+// The user is kept as a UUID, so the erasure and its report outlive the personal data.
+type AccountErasure struct {
+	ID          int64          `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	UUID        uuid.UUID      `gorm:"Column:uuid;default:gen_random_uuid()" json:"uuid"`
+	UserID      int            `gorm:"Column:user_id" json:"-"`
+	UserUUID    uuid.UUID      `gorm:"Column:user_uuid" json:"user_uuid"`
+	Status      string         `gorm:"Column:status" json:"status"`
+	Error       string         `gorm:"Column:error" json:"error,omitempty"`
+	Report      *ErasureReport `gorm:"Column:report;type:jsonb" json:"report,omitempty"`
+	RequestedAt time.Time      `gorm:"Column:requested_at" json:"requested_at"`
+	ScheduledAt time.Time      `gorm:"Column:scheduled_at" json:"scheduled_at"`
+	CancelledAt *time.Time     `gorm:"Column:cancelled_at" json:"cancelled_at,omitempty"`
+	CompletedAt *time.Time     `gorm:"Column:completed_at" json:"completed_at,omitempty"`
+}
+
+// ErasureReport represents a struct for what was erased from the account, by table.
+type ErasureReport struct {
+	Deleted     map[string]int64 `json:"deleted"`
+	Anonymised  map[string]int64 `json:"anonymised"`
+	MediaFiles  int              `json:"media_files"`
+	MediaFailed []string         `json:"media_failed,omitempty"`
+}
+
+// Value converts the ErasureReport to a database value.
+func (r ErasureReport) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+// Scan scans the database value and assigns it to the ErasureReport type.
+func (r *ErasureReport) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	jsonBytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to scan ErasureReport: unexpected value type")
+	}
+
+	return json.Unmarshal(jsonBytes, r)
+}
@@ -0,0 +1,127 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// ExportStatusPending is the status of an export waiting for the worker.
+	ExportStatusPending = "pending"
+	// ExportStatusProcessing is the status of an export being built by the worker.
+	ExportStatusProcessing = "processing"
+	// ExportStatusCompleted is the status of an export whose archive can be downloaded.
+	ExportStatusCompleted = "completed"
+	// ExportStatusFailed is the status of an export the worker could not build.
+	ExportStatusFailed = "failed"
+)
+
+// TableName returns the name of the table corresponding to the DataExport entity in the database.
+func (*DataExport) TableName() string {
+	return "data_exports"
+}
+
+// DataExport represents a struct for the asynchronous exports of the personal data of a user.
+// The archive is only loaded to be downloaded, and it is stored encrypted with the keyring of the personal data.
+type DataExport struct {
+	ID          int64      `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	UUID        uuid.UUID  `gorm:"Column:uuid;default:gen_random_uuid()" json:"uuid"`
+	UserID      int        `gorm:"Column:user_id" json:"-"`
+	Status      string     `gorm:"Column:status" json:"status"`
+	Error       string     `gorm:"Column:error" json:"error,omitempty"`
+	Archive     []byte     `gorm:"Column:archive" json:"-"`
+	Size        int64      `gorm:"Column:size" json:"size"`
+	CreatedAt   time.Time  `gorm:"Column:created_at" json:"created_at"`
+	StartedAt   *time.Time `gorm:"Column:started_at" json:"started_at"`
+	CompletedAt *time.Time `gorm:"Column:completed_at" json:"completed_at"`
+	ExpiresAt   *time.Time `gorm:"Column:expires_at" json:"expires_at"`
+}
+
+// PersonalData represents a struct for every personal data of a user, as written in the export archive.
+type PersonalData struct {
+	ExportedAt           time.Time                   `json:"exported_at"`
+	Profile              ExportProfile               `json:"profile"`
+	MedicalRecord        *MedicalRecord              `json:"medical_record"`
+	Treatments           []Treatment                 `json:"treatments"`
+	TreatmentDoses       []ExportTreatmentDose       `json:"treatment_doses"`
+	Reminders            []ExportReminder            `json:"reminders"`
+	Monitorings          []ExportMonitoring          `json:"monitorings"`
+	SymptomSubscriptions []ExportSymptomSubscription `json:"symptom_subscriptions"`
+	Questions            []Question                  `json:"questions"`
+	Answers              []ExportAnswer              `json:"answers"`
+	MedicalRatings       []ExportRating              `json:"medical_ratings"`
+	HealthServiceRatings []ExportRating              `json:"health_service_ratings"`
+	RecipeRatings        []ExportRecipeRating        `json:"recipe_ratings"`
+}
+
+// ExportProfile represents a struct for the decrypted profile of the user.
+type ExportProfile struct {
+	UUID            uuid.UUID  `json:"uuid"`
+	Email           string     `json:"email"`
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	ProfileImage    string     `json:"profile_image"`
+	DateOfBirth     time.Time  `json:"date_of_birth"`
+	Sex             string     `json:"sex"`
+	UserType        string     `json:"user_type"`
+	City            string     `json:"city"`
+	Country         string     `json:"country"`
+	Timezone        string     `json:"timezone"`
+	IsActive        bool       `json:"is_active"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// ExportTreatmentDose represents a struct for a dose of a treatment of the user.
+type ExportTreatmentDose struct {
+	UUID          uuid.UUID `json:"uuid"`
+	TreatmentUUID uuid.UUID `json:"treatment_uuid"`
+	ScheduledAt   time.Time `json:"scheduled_at"`
+	Status        string    `json:"status"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ExportReminder represents a struct for a reminder of the user with its media.
+type ExportReminder struct {
+	Reminder
+	Media []Media `json:"media"`
+}
+
+// ExportMonitoring represents a struct for a symptom recorded by the user.
+type ExportMonitoring struct {
+	Symptom   string    `json:"symptom"`
+	Scale     int       `json:"scale"`
+	Date      time.Time `json:"date"`
+	LocalDate time.Time `json:"local_date"`
+}
+
+// ExportSymptomSubscription represents a struct for a symptom followed by the user.
+type ExportSymptomSubscription struct {
+	Symptom   string    `json:"symptom"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExportAnswer represents a struct for an answer of the user with the question it answers.
+type ExportAnswer struct {
+	UUID         uuid.UUID `json:"uuid"`
+	QuestionUUID uuid.UUID `json:"question_uuid"`
+	Text         string    `json:"text"`
+	IsPublic     bool      `json:"is_public"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ExportRating represents a struct for a rating of a medical professional or a health service given in a reminder.
+type ExportRating struct {
+	ReminderUUID uuid.UUID `json:"reminder_uuid"`
+	Name         string    `json:"name"`
+	Rating       int       `json:"rating"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ExportRecipeRating represents a struct for a vote of the user on a recipe.
+type ExportRecipeRating struct {
+	RecipeUUID uuid.UUID `json:"recipe_uuid"`
+	Recipe     string    `json:"recipe"`
+	Level      int       `json:"level"`
+}
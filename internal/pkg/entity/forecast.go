@@ -1,28 +1,61 @@
 package entity
 
+import "time"
+
 // TableName - returns name of the table
 func (*Forecast) TableName() string {
 	return "forecasts"
 }
 
 type Forecast struct {
-	ID             int     `gorm:"Column:id;PRIMARY_KEY" json:"-"`
-	Country        string  `gorm:"Column:country" json:"country"`
-	State          string  `gorm:"Column:state" json:"state"`
-	AvgTemperature int     `gorm:"Column:avg_temperature" json:"avg_temperature"`
-	MaxTemperature int     `gorm:"Column:max_temperature" json:"max_temperature"`
-	MinTemperature int     `gorm:"Column:min_temperature" json:"min_temperature"`
-	Description    string  `gorm:"Column:description" json:"description"`
-	Humidity       float32 `gorm:"Column:humidity"  json:"humidity"`
-	Code           int     `gorm:"Column:code"  json:"code"`
-	Wind           float32 `gorm:"Column:wind" json:"wind"`
-	UV             int     `gorm:"Column:uv"  json:"uv"`
-	Date           string  `gorm:"Column:date" json:"date"`
+	ID             int       `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	LocationID     *int      `gorm:"Column:location_id" json:"-"`
+	Country        string    `gorm:"Column:country" json:"country"`
+	State          string    `gorm:"Column:state" json:"state"`
+	AvgTemperature int       `gorm:"Column:avg_temperature" json:"avg_temperature"`
+	MaxTemperature int       `gorm:"Column:max_temperature" json:"max_temperature"`
+	MinTemperature int       `gorm:"Column:min_temperature" json:"min_temperature"`
+	Description    string    `gorm:"Column:description" json:"description"`
+	Humidity       float32   `gorm:"Column:humidity"  json:"humidity"`
+	Code           int       `gorm:"Column:code"  json:"code"`
+	Wind           float32   `gorm:"Column:wind" json:"wind"`
+	UV             int       `gorm:"Column:uv"  json:"uv"`
+	Date           string    `gorm:"Column:date" json:"date"`
+	UpdatedAt      time.Time `gorm:"Column:updated_at" json:"-"`
+}
+
+// TableName returns the name of the table corresponding to the ForecastFetch entity in the database.
+func (*ForecastFetch) TableName() string {
+	return "forecast_fetches"
+}
+
+// ForecastFetch represents the state of the forecast ingestion of a location, keeping
+// the last successful fetch and the backoff applied after consecutive failures.
+type ForecastFetch struct {
+	ID            int        `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	LocationID    int        `gorm:"Column:location_id" json:"-"`
+	Country       string     `gorm:"Column:country" json:"country"`
+	State         string     `gorm:"Column:state" json:"state"`
+	LastSuccessAt *time.Time `gorm:"Column:last_success_at" json:"last_success_at"`
+	LastAttemptAt *time.Time `gorm:"Column:last_attempt_at" json:"last_attempt_at"`
+	NextAttemptAt *time.Time `gorm:"Column:next_attempt_at" json:"next_attempt_at"`
+	Failures      int        `gorm:"Column:failures" json:"failures"`
+	LastError     string     `gorm:"Column:last_error" json:"last_error"`
 }
 
 type RequestForecast struct {
-	Current      Current      `json:"current"`
-	ForecastInfo ForecastInfo `json:"forecast"`
+	Location     ForecastLocation `json:"location"`
+	Current      Current          `json:"current"`
+	ForecastInfo ForecastInfo     `json:"forecast"`
+}
+
+// ForecastLocation represents the location resolved by the weather provider for a query
+type ForecastLocation struct {
+	Name     string  `json:"name"`
+	Country  string  `json:"country"`
+	Lat      float64 `json:"lat"`
+	Lon      float64 `json:"lon"`
+	Timezone string  `json:"tz_id"`
 }
 
 type Current struct {
@@ -59,3 +92,26 @@ type Condition struct {
 	Code int    `json:"code"`
 	Icon string `json:"icon"`
 }
+
+// Heat alert types
+const (
+	HeatAlertTemperature = "temperature"
+	HeatAlertUV          = "uv"
+)
+
+// HeatAlert represents a warning for a forecast day whose maximum temperature or UV index passes the configured threshold
+type HeatAlert struct {
+	Date      string `json:"date"`
+	Type      string `json:"type"`
+	Value     int    `json:"value"`
+	Threshold int    `json:"threshold"`
+	Message   string `json:"message"`
+}
+
+// UserForecast represents the upcoming forecast for the city of a user along with its heat alerts
+type UserForecast struct {
+	City      string       `json:"city"`
+	Country   string       `json:"country"`
+	Forecasts []*Forecast  `json:"forecasts"`
+	Alerts    []*HeatAlert `json:"alerts"`
+}
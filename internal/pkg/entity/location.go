@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// TableName returns the name of the table corresponding to the Location entity in the database.
+func (*Location) TableName() string {
+	return "locations"
+}
+
+// Location represents a geocoded city shared by the users who live in it.
+// Forecasts are fetched by its coordinates, so users writing the same city differently share one forecast.
+type Location struct {
+	ID        int       `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	City      string    `gorm:"Column:city" json:"city"`
+	Country   string    `gorm:"Column:country" json:"country"`
+	Latitude  float64   `gorm:"Column:latitude" json:"latitude"`
+	Longitude float64   `gorm:"Column:longitude" json:"longitude"`
+	Timezone  string    `gorm:"Column:timezone" json:"timezone"`
+	CreatedAt time.Time `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"-"`
+}
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// Reasons of the LoginAttempt.
+const (
+	LoginReasonSuccess       = "success"
+	LoginReasonUnknownEmail  = "unknown_email"
+	LoginReasonWrongPassword = "wrong_password"
+	LoginReasonNotVerified   = "not_verified"
+	LoginReasonThrottled     = "throttled"
+	LoginReasonLocked        = "locked"
+)
+
+// TableName returns the name of the table corresponding to the LoginAttempt entity in the database.
+func (*LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+// LoginAttempt represents a struct for the audit of the login attempts.
+type LoginAttempt struct {
+	ID        int       `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	UserID    *int      `gorm:"Column:user_id" json:"-"`
+	Email     string    `gorm:"Column:email" json:"email"`
+	IP        string    `gorm:"Column:ip" json:"ip"`
+	Success   bool      `gorm:"Column:success" json:"success"`
+	Reason    string    `gorm:"Column:reason" json:"reason"`
+	CreatedAt time.Time `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"created_at"`
+}
+
+// TableName returns the name of the table corresponding to the LoginThrottle entity in the database.
+func (*LoginThrottle) TableName() string {
+	return "login_throttles"
+}
+
+// LoginThrottle represents a struct for the consecutive failed logins of an email or an IP.
+type LoginThrottle struct {
+	Key           string     `gorm:"Column:key;PRIMARY_KEY" json:"key"`
+	Failures      int        `gorm:"Column:failures" json:"failures"`
+	LastFailureAt time.Time  `gorm:"Column:last_failure_at" json:"last_failure_at"`
+	LockedUntil   *time.Time `gorm:"Column:locked_until" json:"locked_until"`
+}
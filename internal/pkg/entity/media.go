@@ -11,11 +11,88 @@ func (*Media) TableName() string {
 	return "media"
 }
 
-// Media represents a struct for media
+// Media represents a struct for media.
+// MediaThumb and MediaWebP are the resized thumbnail and the WebP variant generated on upload.
 type Media struct {
-	ID         int       `gorm:"Column:id;PRIMARY_KEY" json:"-"`
-	UUID       uuid.UUID `gorm:"Column:uuid" json:"uuid"`
-	MediaURL   string    `gorm:"Column:media_url" binding:"required" json:"media_url"`
-	MediaThumb string    `gorm:"Column:media_thumb" binding:"required" json:"media_thumb"`
-	CreatedAt  time.Time `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"created_at"`
+	ID          int       `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	UUID        uuid.UUID `gorm:"Column:uuid" json:"uuid"`
+	MediaURL    string    `gorm:"Column:media_url" binding:"required" json:"media_url"`
+	MediaThumb  string    `gorm:"Column:media_thumb" binding:"required" json:"media_thumb"`
+	MediaWebP   string    `gorm:"Column:media_webp" json:"media_webp"`
+	ContentType string    `gorm:"Column:content_type" json:"content_type"`
+	Width       int       `gorm:"Column:width" json:"width"`
+	Height      int       `gorm:"Column:height" json:"height"`
+	CreatedAt   time.Time `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"created_at"`
+}
+
+// URLs returns the URLs of the uploaded files of the media: the original and its variants.
+func (m *Media) URLs() []string {
+	urls := []string{m.MediaURL}
+	for _, url := range []string{m.MediaThumb, m.MediaWebP} {
+		if url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// Domains of the media, the records they are uploaded with.
+const (
+	MediaDomainReminders = "reminders"
+	MediaDomainArticles  = "articles"
+	MediaDomainRecipes   = "recipes"
+)
+
+// MediaDomains is the list of the domains of the media.
+var MediaDomains = []string{MediaDomainReminders, MediaDomainArticles, MediaDomainRecipes}
+
+// IsMediaDomain reports whether the domain is a domain of the media.
+func IsMediaDomain(domain string) bool {
+	for _, d := range MediaDomains {
+		if d == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// SignedMedia represents the URLs of a private media, readable until ExpiresAt.
+type SignedMedia struct {
+	UUID       uuid.UUID `json:"uuid"`
+	MediaURL   string    `json:"media_url"`
+	MediaThumb string    `json:"media_thumb"`
+	MediaWebP  string    `json:"media_webp"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// MediaPrivacyReport represents a struct for the result of making the uploaded files of a domain private.
+type MediaPrivacyReport struct {
+	Domain string `json:"domain"`
+	// Scanned is the number of media of the domain.
+	Scanned int `json:"scanned"`
+	// Updated is the number of files made private (or to make private, in a dry run).
+	Updated int `json:"updated"`
+	// Missing is the number of files no longer in the store.
+	Missing int `json:"missing"`
+	Failed  int `json:"failed"`
+}
+
+// MediaGCReport represents a struct for the result of collecting the orphaned media: the media rows joined to
+// no record and the stored objects of no media, older than the grace period.
+type MediaGCReport struct {
+	DryRun bool `json:"dry_run"`
+	// OrphanMedia is the number of media rows joined to no record.
+	OrphanMedia int `json:"orphan_media"`
+	// DeletedMedia is the number of orphan media rows deleted with their files.
+	DeletedMedia int `json:"deleted_media"`
+	// ScannedObjects is the number of objects in the store.
+	ScannedObjects int `json:"scanned_objects"`
+	// OrphanObjects is the number of objects of no media, and OrphanBytes their size.
+	OrphanObjects int   `json:"orphan_objects"`
+	OrphanBytes   int64 `json:"orphan_bytes"`
+	// DeletedObjects is the number of orphan objects deleted.
+	DeletedObjects int `json:"deleted_objects"`
+	// Recent is the number of objects of no media kept because they are within the grace period.
+	Recent int `json:"recent"`
+	Failed int `json:"failed"`
 }
@@ -14,7 +14,7 @@ func (MedicalRecord) TableName() string {
 
 type MedicalRecord struct {
 	ID                      int       `gorm:"column:id;primary_key" json:"-"`
-	UUID                    uuid.UUID `gorm:"column:uuid" json:"uuid"`
+	UUID                    uuid.UUID `gorm:"column:uuid;default:gen_random_uuid()" json:"uuid"`
 	UserID                  int       `gorm:"column:user_id" json:"-"`
 	HealthCareProvider      string    `gorm:"column:health_care_provider" json:"health_care_provider"`
 	EmergencyMedicalService string    `gorm:"column:emergency_medical_service" json:"emergency_medical_service"`
@@ -18,9 +18,13 @@ type Monitoring struct {
 	SymptomID int       `gorm:"column:symptom_id" json:"symptom"`
 	Scale     int       `gorm:"Column:scale" json:"scale"`
 	Date      time.Time `gorm:"column:date;default:current_timestamp" json:"date"`
+	LocalDate time.Time `gorm:"column:local_date;type:date" json:"local_date"`
 }
 
+// RequestCreateMonitoring represents the payload for recording a symptom.
+// Date is optional and allows backdating a record, it defaults to the current time.
 type RequestCreateMonitoring struct {
-	SymptomUUID uuid.UUID `json:"symptom"`
-	Scale       int       `json:"scale"`
+	SymptomUUID uuid.UUID  `json:"symptom"`
+	Scale       int        `json:"scale"`
+	Date        *time.Time `json:"date"`
 }
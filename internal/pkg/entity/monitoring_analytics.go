@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RequestMonitoringAnalytics represents a struct for requesting the monitoring analytics of a user
+type RequestMonitoringAnalytics struct {
+	SymptomUUIDs        []uuid.UUID
+	From                time.Time
+	To                  time.Time
+	MovingAverageWindow int
+	WorseningDays       int
+}
+
+// MonitoringAggregate represents the aggregated scale values of a symptom over a day, week or month
+type MonitoringAggregate struct {
+	Start         time.Time `json:"start"`
+	Count         int       `json:"count"`
+	Mean          float64   `json:"mean"`
+	Min           int       `json:"min"`
+	Max           int       `json:"max"`
+	MovingAverage *float64  `json:"moving_average,omitempty"`
+}
+
+// SymptomAnalytics represents the daily, weekly and monthly aggregates of a symptom
+type SymptomAnalytics struct {
+	SymptomUUID    uuid.UUID              `json:"symptom_uuid"`
+	Name           string                 `json:"name"`
+	Daily          []*MonitoringAggregate `json:"daily"`
+	Weekly         []*MonitoringAggregate `json:"weekly"`
+	Monthly        []*MonitoringAggregate `json:"monthly"`
+	Worsening      bool                   `json:"worsening"`
+	WorseningSince *time.Time             `json:"worsening_since,omitempty"`
+}
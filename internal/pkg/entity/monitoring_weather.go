@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RequestWeatherCorrelation represents a struct for requesting the correlation between the monitorings of a user and the weather.
+// When no symptom is given, every symptom the user recorded in the range is correlated.
+type RequestWeatherCorrelation struct {
+	SymptomUUIDs []uuid.UUID
+	From         time.Time
+	To           time.Time
+}
+
+// WeatherPoint represents the daily mean scale of a symptom paired with the forecast of the same day
+type WeatherPoint struct {
+	Date        time.Time `json:"date"`
+	Scale       float64   `json:"scale"`
+	Temperature int       `json:"temperature"`
+	Humidity    float32   `json:"humidity"`
+	UV          int       `json:"uv"`
+}
+
+// WeatherCorrelation represents the Pearson correlation coefficients between a symptom and the weather.
+// The temperature is the maximum of the day. A coefficient is omitted when there is not enough data to compute it.
+type WeatherCorrelation struct {
+	SymptomUUID uuid.UUID       `json:"symptom_uuid"`
+	Name        string          `json:"name"`
+	Samples     int             `json:"samples"`
+	Temperature *float64        `json:"temperature,omitempty"`
+	Humidity    *float64        `json:"humidity,omitempty"`
+	UV          *float64        `json:"uv,omitempty"`
+	Points      []*WeatherPoint `json:"points"`
+}
@@ -0,0 +1,87 @@
+package entity
+
+import "time"
+
+// Permissions granted by the roles. Routes require permissions instead of roles, so new roles
+// can be created from the existing permissions without code changes.
+const (
+	PermissionArticlesRead        = "articles:read"
+	PermissionArticlesWrite       = "articles:write"
+	PermissionCategoriesWrite     = "categories:write"
+	PermissionRecipesRead         = "recipes:read"
+	PermissionRecipesWrite        = "recipes:write"
+	PermissionRecipesVote         = "recipes:vote"
+	PermissionMapsRead            = "maps:read"
+	PermissionMapsWrite           = "maps:write"
+	PermissionMedicalRead         = "medical:read"
+	PermissionMedicalImport       = "medical:import"
+	PermissionMedicalRate         = "medical:rate"
+	PermissionHealthServicesRead  = "healthservices:read"
+	PermissionHealthServicesWrite = "healthservices:write"
+	PermissionHealthServicesRate  = "healthservices:rate"
+	PermissionQuestionsRead       = "questions:read"
+	PermissionQuestionsWrite      = "questions:write"
+	PermissionAnswersWrite        = "answers:write"
+	PermissionSymptomsRead        = "symptoms:read"
+	PermissionSymptomsWrite       = "symptoms:write"
+	// PermissionPatientSelf grants access to the user's own health data: symptoms, monitorings,
+	// treatments, reminders, medical records and forecast.
+	PermissionPatientSelf = "patient:self"
+	PermissionUsersManage = "users:manage"
+	PermissionRolesManage = "roles:manage"
+
+	// PermissionPatientsRead grants a clinician read access to the data their patients consented to share.
+	PermissionPatientsRead = "patients:read"
+	// PermissionAuditRead grants access to the audit log of the health data.
+	PermissionAuditRead = "audit:read"
+	// PermissionTrashManage grants access to the deleted reminders, articles and recipes, to restore them.
+	PermissionTrashManage = "trash:manage"
+)
+
+// Permissions is the catalog of the permissions that can be granted to a role.
+var Permissions = []string{
+	PermissionArticlesRead, PermissionArticlesWrite, PermissionCategoriesWrite,
+	PermissionRecipesRead, PermissionRecipesWrite, PermissionRecipesVote,
+	PermissionMapsRead, PermissionMapsWrite,
+	PermissionMedicalRead, PermissionMedicalImport, PermissionMedicalRate,
+	PermissionHealthServicesRead, PermissionHealthServicesWrite, PermissionHealthServicesRate,
+	PermissionQuestionsRead, PermissionQuestionsWrite, PermissionAnswersWrite,
+	PermissionSymptomsRead, PermissionSymptomsWrite,
+	PermissionPatientSelf, PermissionPatientsRead, PermissionUsersManage, PermissionRolesManage,
+	PermissionAuditRead, PermissionTrashManage,
+}
+
+// IsPermission reports whether the permission is in the catalog.
+func IsPermission(permission string) bool {
+	for _, p := range Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// TableName returns the name of the table corresponding to the RolePermission entity in the database.
+func (*RolePermission) TableName() string {
+	return "role_permissions"
+}
+
+// RolePermission represents a struct for a permission granted to a role.
+type RolePermission struct {
+	RoleID     int       `gorm:"Column:role_id;PRIMARY_KEY" json:"-"`
+	Permission string    `gorm:"Column:permission;PRIMARY_KEY" json:"permission"`
+	CreatedAt  time.Time `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"-"`
+}
+
+// RequestCreateRole represents a struct for creating a role.
+type RequestCreateRole struct {
+	Role        string   `json:"role" binding:"required"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// RequestUpdateRole represents a struct for updating the description and permissions of a role.
+type RequestUpdateRole struct {
+	Description *string  `json:"description"`
+	Permissions []string `json:"permissions" binding:"required"`
+}
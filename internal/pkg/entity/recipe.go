@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // TableName returns the name of the table corresponding to the Role entity in the database.
@@ -14,15 +15,16 @@ func (*Recipe) TableName() string {
 
 // Recipe represents a struct for articles
 type Recipe struct {
-	ID          int       `gorm:"Column:id;PRIMARY_KEY" json:"-"`
-	UUID        uuid.UUID `gorm:"Column:uuid" json:"uuid"`
-	Name        string    `gorm:"Column:name" binding:"required" json:"name"`
-	Ingredients string    `gorm:"Column:ingredients" binding:"required" json:"ingredients"`
-	Elaboration string    `gorm:"Column:elaboration" binding:"required" json:"elaboration"`
-	Category    int       `gorm:"Column:category_id" binding:"required" json:"category"`
-	Time        int       `gorm:"Column:time" binding:"required" json:"time"`
-	IsPublished bool      `gorm:"Column:is_published" sql:"DEFAULT:0" json:"is_published"`
-	CreatedAt   time.Time `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"created_at"`
+	ID          int            `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	UUID        uuid.UUID      `gorm:"Column:uuid" json:"uuid"`
+	Name        string         `gorm:"Column:name" binding:"required" json:"name"`
+	Ingredients string         `gorm:"Column:ingredients" binding:"required" json:"ingredients"`
+	Elaboration string         `gorm:"Column:elaboration" binding:"required" json:"elaboration"`
+	Category    int            `gorm:"Column:category_id" binding:"required" json:"category"`
+	Time        int            `gorm:"Column:time" binding:"required" json:"time"`
+	IsPublished bool           `gorm:"Column:is_published" sql:"DEFAULT:0" json:"is_published"`
+	CreatedAt   time.Time      `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"created_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"Column:deleted_at" json:"-"`
 }
 
 // RequestCreateRecipe represents a struct for creating articles
@@ -0,0 +1,13 @@
+package entity
+
+// ReencryptionReport represents a struct for the result of re-encrypting the personal data with the primary key.
+type ReencryptionReport struct {
+	// Scanned is the number of users read.
+	Scanned int `json:"scanned"`
+	// Reencrypted is the number of users with at least one field re-encrypted (or to re-encrypt, in a dry run).
+	Reencrypted int `json:"reencrypted"`
+	// Skipped is the number of users modified while they were being re-encrypted, they are left for a next run.
+	Skipped int `json:"skipped"`
+	// Failed is the number of users with a field that could not be decrypted.
+	Failed int `json:"failed"`
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // TableName returns the name of the table corresponding to the Reminder entity in the database.
@@ -84,7 +85,8 @@ func (t *TaskSlice) Scan(value interface{}) error {
 	return nil
 }
 
-// Reminder represents a struct for reminders
+// Reminder represents a struct for reminders.
+// The deleted reminders are kept in the trash until purged, the queries skip them.
 type Reminder struct {
 	ID           int               `gorm:"Column:id;PRIMARY_KEY" json:"-"`
 	UserID       int               `gorm:"Column:user_id" json:"-"`
@@ -98,6 +100,7 @@ type Reminder struct {
 	Medical      int               `gorm:"Column:medical_id" json:"medical_id"`
 	IsActive     bool              `gorm:"Column:is_active" sql:"DEFAULT:1" json:"is_active"`
 	CreatedAt    time.Time         `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"created_at"`
+	DeletedAt    gorm.DeletedAt    `gorm:"Column:deleted_at" json:"-"`
 }
 
 // Notification represents the struct for notifications
@@ -137,10 +140,14 @@ type GetReminderResponse struct {
 	Media        []GetReminderMediaResponse `json:"media"`
 }
 
-// GetReminderMediaResponse represents a struct for GetReminderMediaResponse
+// GetReminderMediaResponse represents a struct for GetReminderMediaResponse.
+// The media of the reminders are private, the URLs are signed and readable until ExpiresAt.
 type GetReminderMediaResponse struct {
-	MediaURL   string `json:"media_url"`
-	MediaThumb string `json:"media_thumb"`
+	UUID       uuid.UUID `json:"uuid"`
+	MediaURL   string    `json:"media_url"`
+	MediaThumb string    `json:"media_thumb"`
+	MediaWebP  string    `json:"media_webp"`
+	ExpiresAt  time.Time `json:"expires_at"`
 }
 
 // RequestUpdateReminder represents a struct for RequestUpdateReminder
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+)
+
+// TableName returns the name of the table corresponding to the ReminderNotification entity in the database.
+func (*ReminderNotification) TableName() string {
+	return "reminder_notifications"
+}
+
+// ReminderNotification represents a struct for the reminder_notifications delivery ledger.
+// Each row records a single notification of a reminder that has been claimed for delivery at ClaimedAt.
+type ReminderNotification struct {
+	ID          int        `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	ReminderID  int        `gorm:"Column:reminder_id" json:"-"`
+	NotifyAt    time.Time  `gorm:"Column:notify_at" json:"notify_at"`
+	DaysOrHours string     `gorm:"Column:days_or_hours" json:"days_or_hours"`
+	HoursBefore int        `gorm:"Column:hours_before" json:"hours_before"`
+	SentAt      *time.Time `gorm:"Column:sent_at" json:"sent_at"`
+	ClaimedAt   time.Time  `gorm:"Column:claimed_at" json:"-"`
+	CreatedAt   time.Time  `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"-"`
+}
@@ -10,9 +10,11 @@ func (*Role) TableName() string {
 	return "roles"
 }
 
-// Role represents a struct for roles
+// Role represents a struct for roles, a named set of permissions
 type Role struct {
-	ID        int       `gorm:"Column:id;PRIMARY_KEY" json:"-"`
-	Role      string    `gorm:"Column:role" json:"role"`
-	CreatedAt time.Time `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"-"`
+	ID          int              `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	Role        string           `gorm:"Column:role" json:"role"`
+	Description string           `gorm:"Column:description" json:"description"`
+	Permissions []RolePermission `gorm:"foreignKey:RoleID" json:"permissions"`
+	CreatedAt   time.Time        `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"-"`
 }
@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TableName returns the name of the table corresponding to the RefreshToken entity in the database.
+func (*RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// RefreshToken represents a struct for the refresh tokens issued to the users. Only the SHA-256 hash of the
+// token is stored, and every token rotated from the same login shares the family ID, so a reused token revokes them all.
+type RefreshToken struct {
+	ID        int        `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	UserID    int        `gorm:"Column:user_id" json:"-"`
+	FamilyID  uuid.UUID  `gorm:"Column:family_id" json:"-"`
+	TokenHash string     `gorm:"Column:token_hash" json:"-"`
+	ExpiresAt time.Time  `gorm:"Column:expires_at" json:"-"`
+	RevokedAt *time.Time `gorm:"Column:revoked_at" json:"-"`
+	CreatedAt time.Time  `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"-"`
+}
+
+// TableName returns the name of the table corresponding to the RevokedToken entity in the database.
+func (*RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
+
+// RevokedToken represents a struct for the access tokens revoked before their expiration, identified by their JWT ID.
+type RevokedToken struct {
+	ID        int       `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	JTI       string    `gorm:"Column:jti" json:"-"`
+	ExpiresAt time.Time `gorm:"Column:expires_at" json:"-"`
+	CreatedAt time.Time `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"-"`
+}
+
+// AuthTokens represents a struct for the tokens returned when logging in or refreshing a session.
+type AuthTokens struct {
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// RequestRefreshToken represents a struct for refreshing a session.
+type RequestRefreshToken struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// RequestLogout represents a struct for logging out, the refresh token of the session is revoked when given.
+type RequestLogout struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Purposes of the UserActionToken.
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+)
+
+// TableName returns the name of the table corresponding to the UserActionToken entity in the database.
+func (*UserActionToken) TableName() string {
+	return "user_action_tokens"
+}
+
+// UserActionToken represents a struct for the single-use tokens sent by email to verify the address
+// or reset the password. Only the SHA-256 hash of the token is stored.
+type UserActionToken struct {
+	ID        int        `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	UserID    int        `gorm:"Column:user_id" json:"-"`
+	Purpose   string     `gorm:"Column:purpose" json:"-"`
+	TokenHash string     `gorm:"Column:token_hash" json:"-"`
+	ExpiresAt time.Time  `gorm:"Column:expires_at" json:"-"`
+	UsedAt    *time.Time `gorm:"Column:used_at" json:"-"`
+	CreatedAt time.Time  `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"-"`
+}
+
+// RequestForgotPassword represents a struct for requesting a password reset or verification email.
+type RequestForgotPassword struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// RequestResetPassword represents a struct for setting a new password with a password reset token.
+type RequestResetPassword struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
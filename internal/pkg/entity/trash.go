@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Resources kept in the trash when deleted.
+const (
+	TrashResourceReminders = "reminders"
+	TrashResourceArticles  = "articles"
+	TrashResourceRecipes   = "recipes"
+)
+
+// TrashResources is the list of the resources kept in the trash, in the order they are purged.
+var TrashResources = []string{TrashResourceReminders, TrashResourceArticles, TrashResourceRecipes}
+
+// IsTrashResource reports whether the resource is kept in the trash when deleted.
+func IsTrashResource(resource string) bool {
+	for _, r := range TrashResources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// TrashItem represents a struct for a deleted record waiting in the trash to be restored or purged.
+type TrashItem struct {
+	UUID      uuid.UUID `json:"uuid"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `gorm:"-" json:"purge_at"`
+}
+
+// RequestTrash represents a struct for the query parameters of the trash listing.
+type RequestTrash struct {
+	Page     int `form:"page"`
+	PageSize int `form:"page_size"`
+}
+
+// TrashResult represents a struct for a page of the trash of a resource, last deleted first.
+type TrashResult struct {
+	Resource string      `json:"resource"`
+	Items    []TrashItem `json:"items"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"page_size"`
+	Total    int64       `json:"total"`
+}
+
+// PurgedTrash represents a struct for the records hard-deleted from the trash of a resource.
+// MediaURLs are the uploaded files of the purged records, to be deleted from the bucket.
+type PurgedTrash struct {
+	Resource  string
+	Purged    int64
+	MediaURLs []string
+}
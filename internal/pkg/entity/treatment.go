@@ -78,7 +78,7 @@ func (s *ShotsSlice) Scan(value interface{}) error {
 type Treatment struct {
 	ID        int            `gorm:"Column:id;PRIMARY_KEY" json:"-"`
 	UserID    int            `gorm:"Column:user_id" json:"-"`
-	UUID      uuid.UUID      `gorm:"Column:uuid" json:"-"`
+	UUID      uuid.UUID      `gorm:"Column:uuid;default:gen_random_uuid()" json:"uuid"`
 	Name      string         `gorm:"Column:name" binding:"required" json:"name"`
 	Type      string         `gorm:"Column:type" binding:"required" json:"type"`
 	Frequency FrequencySlice `gorm:"Column:frequency;type:json" json:"frequency"`
@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DoseStatusTaken marks a dose taken on time.
+	DoseStatusTaken = "taken"
+	// DoseStatusLate marks a dose taken after its scheduled time.
+	DoseStatusLate = "late"
+	// DoseStatusSkipped marks a dose the patient decided not to take.
+	DoseStatusSkipped = "skipped"
+	// DoseStatusMissed marks a past dose that was never recorded.
+	DoseStatusMissed = "missed"
+	// DoseStatusPending marks a future dose that was not recorded yet.
+	DoseStatusPending = "pending"
+)
+
+// TableName returns the name of the table corresponding to the TreatmentDose entity in the database.
+func (*TreatmentDose) TableName() string {
+	return "treatment_doses"
+}
+
+// TreatmentDose represents a struct for the recorded outcome of a scheduled treatment dose
+type TreatmentDose struct {
+	ID          int       `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	UUID        uuid.UUID `gorm:"Column:uuid" json:"uuid"`
+	TreatmentID int       `gorm:"Column:treatment_id" json:"-"`
+	ScheduledAt time.Time `gorm:"Column:scheduled_at" json:"scheduled_at"`
+	Status      string    `gorm:"Column:status" json:"status"`
+	CreatedAt   time.Time `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"Column:updated_at" sql:"DEFAULT:current_timestamp" json:"updated_at"`
+}
+
+// DoseOccurrence represents a concrete dose of a treatment expanded from its frequency
+type DoseOccurrence struct {
+	ScheduledAt time.Time  `json:"scheduled_at"`
+	Status      string     `json:"status"`
+	Shots       ShotsSlice `json:"shots"`
+}
+
+// RequestRecordDose represents a struct for marking a dose as taken, late or skipped
+type RequestRecordDose struct {
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required"`
+	Status      string    `json:"status" binding:"required"`
+}
+
+// WeeklyAdherence represents the adherence of a treatment over one week starting on Monday
+type WeeklyAdherence struct {
+	WeekStart       time.Time `json:"week_start"`
+	Scheduled       int       `json:"scheduled"`
+	Taken           int       `json:"taken"`
+	Late            int       `json:"late"`
+	Skipped         int       `json:"skipped"`
+	Missed          int       `json:"missed"`
+	TakenPercentage float64   `json:"taken_percentage"`
+}
+
+// AdherenceReport represents the adherence of a treatment over a date range
+type AdherenceReport struct {
+	TreatmentUUID   uuid.UUID          `json:"treatment_uuid"`
+	From            time.Time          `json:"from"`
+	To              time.Time          `json:"to"`
+	TakenPercentage float64            `json:"taken_percentage"`
+	Weeks           []*WeeklyAdherence `json:"weeks"`
+}
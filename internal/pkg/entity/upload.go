@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// UploadStatusPending is the status of an upload session waiting for the file.
+	UploadStatusPending = "pending"
+	// UploadStatusCompleted is the status of an upload session whose file was attached as a media.
+	UploadStatusCompleted = "completed"
+	// UploadStatusFailed is the status of an upload session whose file did not match the session.
+	UploadStatusFailed = "failed"
+	// UploadStatusAborted is the status of an upload session cancelled by the user.
+	UploadStatusAborted = "aborted"
+	// UploadStatusExpired is the status of an upload session not confirmed in time.
+	UploadStatusExpired = "expired"
+)
+
+// TableName returns the name of the table corresponding to the UploadSession entity in the database.
+func (*UploadSession) TableName() string {
+	return "upload_sessions"
+}
+
+// UploadSession represents a struct for a file uploaded by the client straight to the blob store,
+// to be attached as a media of a reminder, an article or a recipe once confirmed.
+// UploadID is the ID of the multipart upload, empty for the files uploaded with a single request.
+type UploadSession struct {
+	ID          int64      `gorm:"Column:id;PRIMARY_KEY" json:"-"`
+	UUID        uuid.UUID  `gorm:"Column:uuid" json:"uuid"`
+	UserID      int        `gorm:"Column:user_id" json:"-"`
+	Domain      string     `gorm:"Column:domain" json:"domain"`
+	RecordID    int        `gorm:"Column:record_id" json:"-"`
+	Key         string     `gorm:"Column:key" json:"-"`
+	ContentType string     `gorm:"Column:content_type" json:"content_type"`
+	Size        int64      `gorm:"Column:size" json:"size"`
+	UploadID    string     `gorm:"Column:upload_id" json:"-"`
+	PartSize    int64      `gorm:"Column:part_size" json:"part_size"`
+	Status      string     `gorm:"Column:status" json:"status"`
+	Error       string     `gorm:"Column:error" json:"error,omitempty"`
+	MediaID     *int       `gorm:"Column:media_id" json:"-"`
+	CreatedAt   time.Time  `gorm:"Column:created_at" json:"created_at"`
+	ExpiresAt   time.Time  `gorm:"Column:expires_at" json:"expires_at"`
+	CompletedAt *time.Time `gorm:"Column:completed_at" json:"completed_at"`
+}
+
+// UploadRecord represents the record a file is uploaded for. UserID is the owner of the reminders,
+// zero for the articles and recipes.
+type UploadRecord struct {
+	ID     int
+	UserID int
+}
+
+// RequestCreateUpload represents a struct for requesting an upload session.
+type RequestCreateUpload struct {
+	Domain      string    `json:"domain" binding:"required"`
+	RecordUUID  uuid.UUID `json:"record_uuid" binding:"required"`
+	ContentType string    `json:"content_type" binding:"required"`
+	Size        int64     `json:"size" binding:"required"`
+}
+
+// RequestConfirmUpload represents a struct for confirming an upload, with the uploaded parts of a multipart upload.
+type RequestConfirmUpload struct {
+	Parts []UploadPart `json:"parts"`
+}
+
+// UploadPartURL represents a struct for the URL uploading a part of a multipart upload.
+type UploadPartURL struct {
+	PartNumber int    `json:"part_number"`
+	URL        string `json:"url"`
+}
+
+// UploadSessionResponse represents a struct for an upload session with the URLs uploading the file.
+// A file uploaded with a single request has a URL, a file uploaded in parts has the URL of each part,
+// PartSize bytes long except the last one. The URLs are signed again when the session is retrieved,
+// so an interrupted upload can be resumed until the session expires.
+type UploadSessionResponse struct {
+	UUID         uuid.UUID       `json:"uuid"`
+	Status       string          `json:"status"`
+	Method       string          `json:"method"`
+	URL          string          `json:"url,omitempty"`
+	Parts        []UploadPartURL `json:"parts,omitempty"`
+	PartSize     int64           `json:"part_size,omitempty"`
+	ContentType  string          `json:"content_type"`
+	Size         int64           `json:"size"`
+	URLsExpireAt time.Time       `json:"urls_expire_at"`
+	ExpiresAt    time.Time       `json:"expires_at"`
+}
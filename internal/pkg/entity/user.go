@@ -29,9 +29,20 @@ type User struct {
 	IsBanned     bool       `gorm:"Column:is_banned" json:"is_banned"`
 	City         string     `gorm:"Column:city" json:"city" binding:"required"`
 	Country      string     `gorm:"Column:country" json:"country" binding:"required"`
+	Timezone     string     `gorm:"Column:timezone;default:America/Montevideo" json:"timezone"`
+	LocationID   *int       `gorm:"Column:location_id" json:"-"`
 	CreatedAt    time.Time  `gorm:"Column:created_at" sql:"DEFAULT:current_timestamp" json:"-"`
 	UpdatedAt    time.Time  `gorm:"Column:updated_at" sql:"DEFAULT:current_timestamp" json:"-"`
 	DeletedAt    *time.Time `gorm:"Column:deleted_at" sql:"DEFAULT:NULL" json:"-"`
+
+	// TimezoneSetByUser is set once the user chooses a timezone, which then no longer follows the location.
+	TimezoneSetByUser bool `gorm:"Column:timezone_set_by_user" json:"-"`
+
+	// EmailVerifiedAt is set once the user follows the link of the verification email.
+	EmailVerifiedAt *time.Time `gorm:"Column:email_verified_at" json:"email_verified_at"`
+
+	// TokensValidAfter is the time before which the access tokens of the user are rejected.
+	TokensValidAfter *time.Time `gorm:"Column:tokens_valid_after" json:"-"`
 }
 
 // SignUp represents a struct for user registration, including only the necessary fields for registration.
@@ -50,6 +61,7 @@ type UpdateUser struct {
 	UserType    *string `gorm:"Column:user_type" json:"user_type"`
 	City        *string `gorm:"Column:city" json:"city"`
 	Country     *string `gorm:"Column:country" json:"country"`
+	Timezone    *string `gorm:"Column:timezone" json:"timezone"`
 }
 
 type UpdateUserIsActive struct {
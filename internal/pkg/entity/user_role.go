@@ -10,6 +10,8 @@ const (
 	RoleUser = "user"
 	// RoleAdmin represents the admin role.
 	RoleAdmin = "admin"
+	// RoleClinician represents the role of the users linked to a medical professional.
+	RoleClinician = "clinician"
 )
 
 // TableName returns the name of the table corresponding to the UserRole entity in the database.
@@ -0,0 +1,55 @@
+package entity
+
+// Kinds of the UserSearchIndex.
+const (
+	SearchIndexNameWord   = "name_word"
+	SearchIndexNamePrefix = "name_prefix"
+)
+
+// Match modes of the user search.
+const (
+	SearchMatchPrefix = "prefix"
+	SearchMatchExact  = "exact"
+)
+
+// TableName returns the name of the table corresponding to the UserSearchIndex entity in the database.
+func (*UserSearchIndex) TableName() string {
+	return "user_search_indexes"
+}
+
+// UserSearchIndex represents a struct for a blind index of the encrypted name of a user.
+type UserSearchIndex struct {
+	UserID int    `gorm:"Column:user_id;PRIMARY_KEY" json:"-"`
+	Kind   string `gorm:"Column:kind;PRIMARY_KEY" json:"-"`
+	Hash   string `gorm:"Column:hash;PRIMARY_KEY" json:"-"`
+}
+
+// RequestUserSearch represents a struct for the query parameters of the admin user search.
+// Every word of the name must match a word of the first or last name of the user.
+type RequestUserSearch struct {
+	Name     string `form:"name"`
+	Email    string `form:"email"`
+	Match    string `form:"match"`
+	Page     int    `form:"page"`
+	PageSize int    `form:"page_size"`
+}
+
+// UserSearchFilter represents a struct for the user search as run by the repository.
+type UserSearchFilter struct {
+	// Kind and Hashes are the blind indexes the user must have, all of them.
+	Kind   string
+	Hashes []string
+	// Email is matched case insensitively, as a prefix unless EmailExact is set.
+	Email      string
+	EmailExact bool
+	Limit      int
+	Offset     int
+}
+
+// UserSearchResult represents a struct for a page of the user search.
+type UserSearchResult struct {
+	Users    []User `json:"users"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+	Total    int64  `json:"total"`
+}
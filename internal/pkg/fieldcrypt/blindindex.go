@@ -0,0 +1,44 @@
+package fieldcrypt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/emur-uy/backend/config"
+)
+
+// blindIndexSize is the number of bytes of the HMAC kept in a blind index. Truncating it makes
+// collisions between different values possible, which leaks less about repeated values.
+const blindIndexSize = 16
+
+// BlindIndexer computes blind indexes: keyed hashes of normalized values stored next to their ciphertext,
+// so the values can be looked up by equality without decrypting them.
+type BlindIndexer struct {
+	key []byte
+}
+
+// NewBlindIndexer returns a BlindIndexer with the given HMAC key.
+func NewBlindIndexer(key []byte) *BlindIndexer {
+	return &BlindIndexer{key: key}
+}
+
+// BlindIndexerFromConfig returns the BlindIndexer of BLIND_INDEX_KEY. Without it, the key is derived from
+// ENCRYPTION_KEY, so the indexes never use the encryption key itself.
+func BlindIndexerFromConfig(cfg config.Config) *BlindIndexer {
+	if cfg.BlindIndexKey != "" {
+		return NewBlindIndexer([]byte(cfg.BlindIndexKey))
+	}
+
+	mac := hmac.New(sha256.New, []byte(cfg.EncryptionKey))
+	mac.Write([]byte("blind-index"))
+	return NewBlindIndexer(mac.Sum(nil))
+}
+
+// Index returns the hex encoded blind index of the value. The kind separates the indexes of different
+// attributes, so equal values of different kinds get different indexes.
+func (b *BlindIndexer) Index(kind, value string) string {
+	mac := hmac.New(sha256.New, b.key)
+	mac.Write([]byte(kind + ":" + value))
+	return hex.EncodeToString(mac.Sum(nil)[:blindIndexSize])
+}
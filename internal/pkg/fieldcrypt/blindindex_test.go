@@ -0,0 +1,29 @@
+package fieldcrypt_test
+
+import (
+	"testing"
+
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/pkg/fieldcrypt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlindIndex(t *testing.T) {
+	indexer := fieldcrypt.NewBlindIndexer([]byte("index key"))
+
+	index := indexer.Index("name_word", "lucia")
+	assert.Len(t, index, 32)
+	assert.Equal(t, index, indexer.Index("name_word", "lucia"), "indexes are deterministic")
+	assert.NotEqual(t, index, indexer.Index("name_prefix", "lucia"))
+	assert.NotEqual(t, index, indexer.Index("name_word", "lucas"))
+	assert.NotEqual(t, index, fieldcrypt.NewBlindIndexer([]byte("other key")).Index("name_word", "lucia"))
+}
+
+func TestBlindIndexerFromConfig(t *testing.T) {
+	derived := fieldcrypt.BlindIndexerFromConfig(config.Config{EncryptionKey: string(legacyKey)})
+	configured := fieldcrypt.BlindIndexerFromConfig(config.Config{EncryptionKey: string(legacyKey), BlindIndexKey: "index key"})
+
+	assert.Equal(t, fieldcrypt.NewBlindIndexer([]byte("index key")).Index("name_word", "ana"), configured.Index("name_word", "ana"))
+	assert.NotEqual(t, fieldcrypt.NewBlindIndexer(legacyKey).Index("name_word", "ana"), derived.Index("name_word", "ana"),
+		"the encryption key is not used directly")
+}
@@ -0,0 +1,328 @@
+// Package fieldcrypt encrypts the personal data stored in the database with AES-GCM.
+//
+// Ciphertexts are versioned and carry the ID of the key that encrypted them ("v1:<key id>:<data>"),
+// so a Keyring holding several keys can decrypt values written with older keys while encrypting
+// with the primary one. The table, column and record of the value are authenticated with it, so a
+// ciphertext moved to another field does not decrypt. Values written before the versioned format
+// (AES-CFB, without authentication) are still decrypted with the legacy key until they are re-encrypted.
+package fieldcrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/emur-uy/backend/config"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Version is the prefix of the ciphertexts written by this package.
+const Version = "v1"
+
+// DefaultKeyID is the ID of the key derived from ENCRYPTION_KEY when no keyring is configured.
+const DefaultKeyID = "default"
+
+// derivationInfo binds the key derived from ENCRYPTION_KEY to its use, so it never equals the legacy key.
+const derivationInfo = "emur fieldcrypt v1 aes-gcm"
+
+var (
+	ErrUnknownKey        = errors.New("fieldcrypt: unknown key id")
+	ErrMalformed         = errors.New("fieldcrypt: malformed ciphertext")
+	ErrTampered          = errors.New("fieldcrypt: ciphertext authentication failed")
+	ErrNoLegacyKey       = errors.New("fieldcrypt: legacy ciphertext and no legacy key configured")
+	ErrInvalidKeyID      = errors.New("fieldcrypt: key ids cannot be empty nor contain ':'")
+	ErrNoPrimaryKey      = errors.New("fieldcrypt: primary key not in the keyring")
+	ErrInvalidKeyringDef = errors.New("fieldcrypt: invalid keyring definition, expected id:base64key[,id:base64key]")
+	ErrLegacyKeyReused   = errors.New("fieldcrypt: the keyring cannot hold the legacy key")
+	ErrEmptyKey          = errors.New("fieldcrypt: empty encryption key")
+)
+
+// Field identifies where a value is stored: the table, the column and the record it belongs to.
+type Field struct {
+	Table  string
+	Column string
+	Record string
+}
+
+// UserField returns the Field of a column of the users table for the user with the given UUID.
+func UserField(column string, userUUID uuid.UUID) Field {
+	return Field{Table: "users", Column: column, Record: userUUID.String()}
+}
+
+// additionalData returns the data authenticated with the ciphertexts of the field written under the header.
+func (f Field) additionalData(header string) []byte {
+	return []byte(strings.Join([]string{header, f.Table, f.Column, f.Record}, "\x00"))
+}
+
+// Keyring encrypts with its primary key and decrypts with any of its keys.
+type Keyring struct {
+	primaryID string
+	keys      map[string]cipher.AEAD
+	raw       [][]byte
+	legacy    cipher.Block
+}
+
+// NewKeyring returns a Keyring of the given AES keys (16, 24 or 32 bytes) indexed by ID,
+// encrypting with the key of primaryID.
+func NewKeyring(primaryID string, keys map[string][]byte) (*Keyring, error) {
+	keyring := &Keyring{primaryID: primaryID, keys: make(map[string]cipher.AEAD, len(keys))}
+
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, ErrInvalidKeyID
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("fieldcrypt: key %s: %s", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("fieldcrypt: key %s: %s", id, err)
+		}
+		keyring.keys[id] = aead
+		keyring.raw = append(keyring.raw, key)
+	}
+
+	if _, ok := keyring.keys[primaryID]; !ok {
+		return nil, ErrNoPrimaryKey
+	}
+
+	return keyring, nil
+}
+
+// ParseKeyring returns a Keyring from a definition of comma separated "id:base64key" entries,
+// the keys being standard base64 encoded.
+func ParseKeyring(primaryID, definition string) (*Keyring, error) {
+	keys := map[string][]byte{}
+	for _, entry := range strings.Split(definition, ",") {
+		id, encodedKey, found := strings.Cut(strings.TrimSpace(entry), ":")
+		if !found {
+			return nil, ErrInvalidKeyringDef
+		}
+		key, err := base64.StdEncoding.DecodeString(encodedKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %s: %s", ErrInvalidKeyringDef, id, err)
+		}
+		keys[id] = key
+	}
+	return NewKeyring(primaryID, keys)
+}
+
+// FromConfig returns the Keyring of ENCRYPTION_KEYS with ENCRYPTION_KEY_ID as primary key.
+// Without ENCRYPTION_KEYS the keyring only holds a key derived from ENCRYPTION_KEY, with DefaultKeyID as ID,
+// so the same key is never used by both ciphers. ENCRYPTION_KEY is the legacy key, so the values written
+// before the keyring can be read, until ENCRYPTION_LEGACY_DISABLED is set once they are re-encrypted.
+func FromConfig(cfg config.Config) (*Keyring, error) {
+	var keyring *Keyring
+	var err error
+	if cfg.EncryptionKeys == "" {
+		key, err := deriveKey([]byte(cfg.EncryptionKey))
+		if err != nil {
+			return nil, err
+		}
+		keyring, err = NewKeyring(DefaultKeyID, map[string][]byte{DefaultKeyID: key})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		keyring, err = ParseKeyring(cfg.EncryptionKeyID, cfg.EncryptionKeys)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.EncryptionKey != "" && keyring.holds([]byte(cfg.EncryptionKey)) {
+			return nil, ErrLegacyKeyReused
+		}
+	}
+
+	if cfg.EncryptionKey != "" && !cfg.EncryptionLegacyDisabled {
+		if err := keyring.SetLegacyKey([]byte(cfg.EncryptionKey)); err != nil {
+			return nil, err
+		}
+	}
+	return keyring, nil
+}
+
+// deriveKey derives the AES-256 key of the keyring from the legacy key.
+func deriveKey(legacyKey []byte) ([]byte, error) {
+	if len(legacyKey) == 0 {
+		return nil, ErrEmptyKey
+	}
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, legacyKey, nil, []byte(derivationInfo)), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// holds reports whether the key is one of the keys of the keyring.
+func (k *Keyring) holds(key []byte) bool {
+	for _, raw := range k.raw {
+		if bytes.Equal(raw, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetLegacyKey sets the key of the unversioned AES-CFB ciphertexts.
+func (k *Keyring) SetLegacyKey(key []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return fmt.Errorf("fieldcrypt: legacy key: %s", err)
+	}
+	k.legacy = block
+	return nil
+}
+
+// PrimaryKeyID returns the ID of the key used to encrypt.
+func (k *Keyring) PrimaryKeyID() string {
+	return k.primaryID
+}
+
+// Encrypt encrypts the plaintext of the field with the primary key.
+func (k *Keyring) Encrypt(field Field, plaintext string) (string, error) {
+	header, sealed, err := k.seal(field, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return header + ":" + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// EncryptBytes encrypts the binary plaintext of the field with the primary key. The ciphertext has
+// the same header as the ones of Encrypt, followed by the sealed data without encoding.
+func (k *Keyring) EncryptBytes(field Field, plaintext []byte) ([]byte, error) {
+	header, sealed, err := k.seal(field, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(header+":"), sealed...), nil
+}
+
+// seal encrypts the plaintext with the primary key, returning the header of the ciphertext and
+// the nonce followed by the sealed data.
+func (k *Keyring) seal(field Field, plaintext []byte) (string, []byte, error) {
+	aead := k.keys[k.primaryID]
+	header := Version + ":" + k.primaryID
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+
+	// The header and the field are authenticated, so a ciphertext cannot be relabeled with another key
+	// or version, nor copied to another column or user
+	return header, aead.Seal(nonce, nonce, plaintext, field.additionalData(header)), nil
+}
+
+// Decrypt decrypts a ciphertext of the field written with any key of the keyring, or with the legacy key.
+// An empty value, as left by the erasure of the accounts, is an empty plaintext.
+// Returns ErrTampered when the ciphertext was modified or written for another field.
+func (k *Keyring) Decrypt(field Field, ciphertext string) (string, error) {
+	if ciphertext == "" {
+		return "", nil
+	}
+	version, keyID, data, err := parse(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	if version == "" {
+		return k.decryptLegacy(ciphertext)
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil {
+		return "", ErrMalformed
+	}
+	plaintext, err := k.open(field, keyID, sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// DecryptBytes decrypts a binary ciphertext of the field written by EncryptBytes with any key of the keyring.
+// Returns ErrTampered when the ciphertext was modified or written for another field.
+func (k *Keyring) DecryptBytes(field Field, ciphertext []byte) ([]byte, error) {
+	parts := bytes.SplitN(ciphertext, []byte(":"), 3)
+	if len(parts) != 3 || string(parts[0]) != Version {
+		return nil, ErrMalformed
+	}
+	return k.open(field, string(parts[1]), parts[2])
+}
+
+// open decrypts the nonce followed by the sealed data, written with the key of keyID.
+func (k *Keyring) open(field Field, keyID string, sealed []byte) ([]byte, error) {
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, field.additionalData(Version+":"+keyID))
+	if err != nil {
+		return nil, ErrTampered
+	}
+	return plaintext, nil
+}
+
+// NeedsReencryption reports whether the ciphertext was not written with the current version and primary key.
+// An empty value has nothing to re-encrypt.
+func (k *Keyring) NeedsReencryption(ciphertext string) bool {
+	if ciphertext == "" {
+		return false
+	}
+	version, keyID, _, err := parse(ciphertext)
+	return err != nil || version != Version || keyID != k.primaryID
+}
+
+// Reencrypt decrypts the ciphertext of the field and encrypts it again with the primary key.
+func (k *Keyring) Reencrypt(field Field, ciphertext string) (string, error) {
+	plaintext, err := k.Decrypt(field, ciphertext)
+	if err != nil {
+		return "", err
+	}
+	return k.Encrypt(field, plaintext)
+}
+
+// decryptLegacy decrypts the unversioned AES-CFB ciphertexts.
+func (k *Keyring) decryptLegacy(ciphertext string) (string, error) {
+	if k.legacy == nil {
+		return "", ErrNoLegacyKey
+	}
+
+	data, err := base64.URLEncoding.DecodeString(ciphertext)
+	if err != nil || len(data) < aes.BlockSize {
+		return "", ErrMalformed
+	}
+
+	iv := data[:aes.BlockSize]
+	stream := cipher.NewCFBDecrypter(k.legacy, iv)
+	stream.XORKeyStream(data[aes.BlockSize:], data[aes.BlockSize:])
+
+	return string(data[aes.BlockSize:]), nil
+}
+
+// parse splits a versioned ciphertext, returning an empty version for the legacy ones.
+// Legacy ciphertexts are base64 and never contain ':'.
+func parse(ciphertext string) (version, keyID, data string, err error) {
+	if !strings.Contains(ciphertext, ":") {
+		return "", "", ciphertext, nil
+	}
+
+	parts := strings.SplitN(ciphertext, ":", 3)
+	if len(parts) != 3 || parts[0] != Version {
+		return "", "", "", ErrMalformed
+	}
+	return parts[0], parts[1], parts[2], nil
+}
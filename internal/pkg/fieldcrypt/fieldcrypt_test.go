@@ -0,0 +1,237 @@
+package fieldcrypt_test
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/pkg/fieldcrypt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	oldKey    = []byte("0123456789abcdef0123456789abcdef")
+	newKey    = []byte("fedcba9876543210fedcba9876543210")
+	legacyKey = []byte("!mGL^CiiDhVVLaR7dM%FCeymgGuq^RUQ")
+
+	userUUID = uuid.MustParse("24df3f36-ca63-11ed-afa1-0242ac120002")
+	field    = fieldcrypt.UserField("first_name", userUUID)
+)
+
+// legacyEncrypt encrypts like the AES-CFB cipher used before the keyring.
+func legacyEncrypt(t *testing.T, key []byte, text string) string {
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+	ciphertext := make([]byte, aes.BlockSize+len(text))
+	cipher.NewCFBEncrypter(block, ciphertext[:aes.BlockSize]).XORKeyStream(ciphertext[aes.BlockSize:], []byte(text))
+	return base64.URLEncoding.EncodeToString(ciphertext)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	keyring, err := fieldcrypt.NewKeyring("k1", map[string][]byte{"k1": oldKey})
+	require.NoError(t, err)
+
+	ciphertext, err := keyring.Encrypt(field, "María")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(ciphertext, "v1:k1:"))
+	assert.NotContains(t, ciphertext, "María")
+
+	other, err := keyring.Encrypt(field, "María")
+	require.NoError(t, err)
+	assert.NotEqual(t, ciphertext, other, "every encryption uses a new nonce")
+
+	plaintext, err := keyring.Decrypt(field, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "María", plaintext)
+	assert.False(t, keyring.NeedsReencryption(ciphertext))
+}
+
+func TestEmptyValue(t *testing.T) {
+	keyring, err := fieldcrypt.NewKeyring("k1", map[string][]byte{"k1": oldKey})
+	require.NoError(t, err)
+
+	// The fields of the erased accounts are empty
+	plaintext, err := keyring.Decrypt(field, "")
+	require.NoError(t, err)
+	assert.Empty(t, plaintext)
+	assert.False(t, keyring.NeedsReencryption(""))
+}
+
+func TestDecryptDetectsTampering(t *testing.T) {
+	keyring, err := fieldcrypt.NewKeyring("k1", map[string][]byte{"k1": oldKey, "k2": newKey})
+	require.NoError(t, err)
+
+	ciphertext, err := keyring.Encrypt(field, "María")
+	require.NoError(t, err)
+
+	data := []byte(ciphertext)
+	position := len("v1:k1:") + 20
+	if data[position] == 'A' {
+		data[position] = 'B'
+	} else {
+		data[position] = 'A'
+	}
+	_, err = keyring.Decrypt(field, string(data))
+	assert.ErrorIs(t, err, fieldcrypt.ErrTampered)
+
+	// Relabeling the ciphertext with another key of the keyring is detected too
+	_, err = keyring.Decrypt(field, strings.Replace(ciphertext, "v1:k1:", "v1:k2:", 1))
+	assert.ErrorIs(t, err, fieldcrypt.ErrTampered)
+
+	_, err = keyring.Decrypt(field, strings.Replace(ciphertext, "v1:k1:", "v1:k3:", 1))
+	assert.ErrorIs(t, err, fieldcrypt.ErrUnknownKey)
+
+	_, err = keyring.Decrypt(field, "v2:k1:abc")
+	assert.ErrorIs(t, err, fieldcrypt.ErrMalformed)
+}
+
+func TestDecryptRejectsOtherFields(t *testing.T) {
+	keyring, err := fieldcrypt.NewKeyring("k1", map[string][]byte{"k1": oldKey})
+	require.NoError(t, err)
+
+	ciphertext, err := keyring.Encrypt(field, "María")
+	require.NoError(t, err)
+
+	// A ciphertext copied to another column or to another user does not decrypt
+	_, err = keyring.Decrypt(fieldcrypt.UserField("last_name", userUUID), ciphertext)
+	assert.ErrorIs(t, err, fieldcrypt.ErrTampered)
+
+	_, err = keyring.Decrypt(fieldcrypt.UserField("first_name", uuid.New()), ciphertext)
+	assert.ErrorIs(t, err, fieldcrypt.ErrTampered)
+
+	_, err = keyring.Decrypt(fieldcrypt.Field{Table: "clinicians", Column: "first_name", Record: userUUID.String()}, ciphertext)
+	assert.ErrorIs(t, err, fieldcrypt.ErrTampered)
+}
+
+func TestEncryptDecryptBytes(t *testing.T) {
+	keyring, err := fieldcrypt.NewKeyring("k1", map[string][]byte{"k1": oldKey, "k2": newKey})
+	require.NoError(t, err)
+	archive := fieldcrypt.Field{Table: "data_exports", Column: "archive", Record: userUUID.String()}
+
+	ciphertext, err := keyring.EncryptBytes(archive, []byte{0, 1, 2, ':'})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(ciphertext), "v1:k1:"))
+
+	plaintext, err := keyring.DecryptBytes(archive, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 1, 2, ':'}, plaintext)
+
+	_, err = keyring.DecryptBytes(field, ciphertext)
+	assert.ErrorIs(t, err, fieldcrypt.ErrTampered)
+
+	_, err = keyring.DecryptBytes(archive, []byte(strings.Replace(string(ciphertext), "v1:k1:", "v1:k2:", 1)))
+	assert.ErrorIs(t, err, fieldcrypt.ErrTampered)
+
+	_, err = keyring.DecryptBytes(archive, []byte("not encrypted"))
+	assert.ErrorIs(t, err, fieldcrypt.ErrMalformed)
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKeyring, err := fieldcrypt.NewKeyring("k1", map[string][]byte{"k1": oldKey})
+	require.NoError(t, err)
+	ciphertext, err := oldKeyring.Encrypt(field, "Pérez")
+	require.NoError(t, err)
+
+	keyring, err := fieldcrypt.NewKeyring("k2", map[string][]byte{"k1": oldKey, "k2": newKey})
+	require.NoError(t, err)
+	assert.True(t, keyring.NeedsReencryption(ciphertext))
+
+	reencrypted, err := keyring.Reencrypt(field, ciphertext)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(reencrypted, "v1:k2:"))
+	assert.False(t, keyring.NeedsReencryption(reencrypted))
+
+	plaintext, err := keyring.Decrypt(field, reencrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "Pérez", plaintext)
+}
+
+func TestLegacyCiphertexts(t *testing.T) {
+	keyring, err := fieldcrypt.NewKeyring("k1", map[string][]byte{"k1": oldKey})
+	require.NoError(t, err)
+	legacy := legacyEncrypt(t, legacyKey, "Ana")
+
+	_, err = keyring.Decrypt(field, legacy)
+	assert.ErrorIs(t, err, fieldcrypt.ErrNoLegacyKey)
+
+	require.NoError(t, keyring.SetLegacyKey(legacyKey))
+	plaintext, err := keyring.Decrypt(field, legacy)
+	require.NoError(t, err)
+	assert.Equal(t, "Ana", plaintext)
+	assert.True(t, keyring.NeedsReencryption(legacy))
+}
+
+func TestParseKeyring(t *testing.T) {
+	definition := "k1:" + base64.StdEncoding.EncodeToString(oldKey) + ", k2:" + base64.StdEncoding.EncodeToString(newKey)
+	keyring, err := fieldcrypt.ParseKeyring("k2", definition)
+	require.NoError(t, err)
+	assert.Equal(t, "k2", keyring.PrimaryKeyID())
+
+	_, err = fieldcrypt.ParseKeyring("k3", definition)
+	assert.ErrorIs(t, err, fieldcrypt.ErrNoPrimaryKey)
+
+	_, err = fieldcrypt.ParseKeyring("k1", "k1")
+	assert.ErrorIs(t, err, fieldcrypt.ErrInvalidKeyringDef)
+
+	_, err = fieldcrypt.ParseKeyring("k1", "k1:"+base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.Error(t, err)
+}
+
+func TestFromConfig(t *testing.T) {
+	keyring, err := fieldcrypt.FromConfig(config.Config{EncryptionKey: string(legacyKey)})
+	require.NoError(t, err)
+	assert.Equal(t, fieldcrypt.DefaultKeyID, keyring.PrimaryKeyID())
+
+	plaintext, err := keyring.Decrypt(field, legacyEncrypt(t, legacyKey, "Ana"))
+	require.NoError(t, err)
+	assert.Equal(t, "Ana", plaintext)
+
+	keyring, err = fieldcrypt.FromConfig(config.Config{
+		EncryptionKey:   string(legacyKey),
+		EncryptionKeys:  "2023-07:" + base64.StdEncoding.EncodeToString(newKey),
+		EncryptionKeyID: "2023-07",
+	})
+	require.NoError(t, err)
+	ciphertext, err := keyring.Encrypt(field, "Ana")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(ciphertext, "v1:2023-07:"))
+}
+
+func TestFromConfigDoesNotReuseTheLegacyKey(t *testing.T) {
+	keyring, err := fieldcrypt.FromConfig(config.Config{EncryptionKey: string(legacyKey)})
+	require.NoError(t, err)
+	ciphertext, err := keyring.Encrypt(field, "Ana")
+	require.NoError(t, err)
+
+	// The key of the keyring is derived from ENCRYPTION_KEY, the legacy key itself does not decrypt
+	legacyKeyring, err := fieldcrypt.NewKeyring(fieldcrypt.DefaultKeyID, map[string][]byte{fieldcrypt.DefaultKeyID: legacyKey})
+	require.NoError(t, err)
+	_, err = legacyKeyring.Decrypt(field, ciphertext)
+	assert.ErrorIs(t, err, fieldcrypt.ErrTampered)
+
+	_, err = fieldcrypt.FromConfig(config.Config{
+		EncryptionKey:   string(legacyKey),
+		EncryptionKeys:  "2023-07:" + base64.StdEncoding.EncodeToString(legacyKey),
+		EncryptionKeyID: "2023-07",
+	})
+	assert.ErrorIs(t, err, fieldcrypt.ErrLegacyKeyReused)
+}
+
+func TestFromConfigLegacyDisabled(t *testing.T) {
+	keyring, err := fieldcrypt.FromConfig(config.Config{EncryptionKey: string(legacyKey), EncryptionLegacyDisabled: true})
+	require.NoError(t, err)
+
+	_, err = keyring.Decrypt(field, legacyEncrypt(t, legacyKey, "Ana"))
+	assert.ErrorIs(t, err, fieldcrypt.ErrNoLegacyKey)
+
+	ciphertext, err := keyring.Encrypt(field, "Ana")
+	require.NoError(t, err)
+	plaintext, err := keyring.Decrypt(field, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "Ana", plaintext)
+}
@@ -0,0 +1,29 @@
+package ports
+
+import "github.com/emur-uy/backend/internal/pkg/entity"
+
+// AuditRepository is an interface that represents the contract for the append-only audit log.
+type AuditRepository interface {
+	// CreateAuditLog appends a new AuditLog record to the data store.
+	// Returns an error if the operation fails.
+	CreateAuditLog(auditLog *entity.AuditLog) error
+
+	// FindAuditLogs retrieves a page of the audit log matching the filter, newest first.
+	// Returns the page, the total of matching records and an error if the operation fails.
+	FindAuditLogs(filter *entity.AuditLogFilter) ([]entity.AuditLog, int64, error)
+}
+
+// Auditor is an interface for recording the actions on the health data in the audit log.
+type Auditor interface {
+	// Record appends the action to the audit log. A failure is logged and does not fail the action.
+	Record(auditLog *entity.AuditLog)
+}
+
+// AuditService is the interface that defines the methods for recording and querying the audit log.
+type AuditService interface {
+	Auditor
+
+	// GetAuditLogs retrieves a page of the audit log filtered by user, resource and date.
+	// Returns the page, a status code, and an error if the operation fails.
+	GetAuditLogs(request *entity.RequestAuditLogs) (*entity.AuditLogResult, int, error)
+}
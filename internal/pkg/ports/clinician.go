@@ -0,0 +1,110 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ClinicianRepository is an interface that represents the contract for the clinicians, the consents of
+// their patients and the log of their accesses to the patient data.
+type ClinicianRepository interface {
+	// FindByUUID retrieves a record from the data store using its UUID.
+	// Returns the found record and an error if the operation fails.
+	FindByUUID(uuid uuid.UUID, out interface{}) (interface{}, error)
+
+	// CreateWithOmit inserts a new record into the data store while ignoring specific fields.
+	// Returns an error if the operation fails.
+	CreateWithOmit(omit string, value interface{}) error
+
+	// First retrieves the first record that matches the given conditions from the data store.
+	// Returns an error if the operation fails or no record is found.
+	First(out interface{}, conditions ...interface{}) error
+
+	// Find retrieves the records that match the given conditions from the data store.
+	// Returns an error if the operation fails.
+	Find(out interface{}, conditions ...interface{}) error
+
+	// CreateClinician inserts the new Clinician record and grants its user the role with the given name, in a transaction.
+	// Returns an error if the role does not exist or the operation fails.
+	CreateClinician(clinician *entity.Clinician, roleName string) error
+
+	// FindClinicians retrieves every clinician with their medical professional.
+	// Returns an error if the operation fails.
+	FindClinicians() ([]entity.Clinician, error)
+
+	// FindActiveConsents retrieves the consents granted to the clinician that are neither revoked nor expired at the given time.
+	// Returns an error if the operation fails.
+	FindActiveConsents(clinicianID int, now time.Time) ([]entity.PatientConsent, error)
+
+	// FindPatientConsents retrieves every consent granted by the patient with its clinician, newest first.
+	// Returns an error if the operation fails.
+	FindPatientConsents(patientID int) ([]entity.PatientConsent, error)
+
+	// RevokeConsents revokes the active consents of the patient matching the given conditions.
+	// Returns the number of revoked consents and an error if the operation fails.
+	RevokeConsents(patientID int, revokedAt time.Time, conditions map[string]interface{}) (int64, error)
+
+	// CreateAccessLog appends a new PatientAccessLog record to the data store.
+	// Returns an error if the operation fails.
+	CreateAccessLog(accessLog *entity.PatientAccessLog) error
+
+	// FindAccessLogs retrieves the latest accesses to the data of the patient with their clinician, newest first.
+	// Returns an error if the operation fails.
+	FindAccessLogs(patientID int, limit int) ([]entity.PatientAccessLog, error)
+
+	// FindUserSymptomUUIDs retrieves the UUIDs of the symptoms followed by the user.
+	// Returns an error if the operation fails.
+	FindUserSymptomUUIDs(userID int) ([]uuid.UUID, error)
+}
+
+// ClinicianService is the interface that defines the methods for linking clinicians to medical professionals,
+// managing the consents of the patients and reading the data shared with the clinicians.
+type ClinicianService interface {
+	// LinkClinician links the user to the medical professional and grants them the clinician role.
+	// Returns the created clinician, a status code, and an error if the operation fails.
+	LinkClinician(userUUID uuid.UUID, linkReq *entity.RequestLinkClinician) (*entity.Clinician, int, error)
+
+	// GetClinicians retrieves every clinician with their medical professional.
+	// Returns the list of clinicians and an error if the operation fails.
+	GetClinicians() ([]entity.Clinician, error)
+
+	// GrantConsent shares the requested scopes of the data of the patient with the clinician until the consent expires,
+	// replacing the active consent of the patient to the same clinician.
+	// Returns the created consent, a status code, and an error if the operation fails.
+	GrantConsent(patientUUID uuid.UUID, consentReq *entity.RequestCreateConsent) (*entity.PatientConsent, int, error)
+
+	// GetConsents retrieves every consent granted by the patient.
+	// Returns the list of consents, a status code, and an error if the operation fails.
+	GetConsents(patientUUID uuid.UUID) ([]entity.PatientConsent, int, error)
+
+	// RevokeConsent revokes a consent granted by the patient.
+	// Returns a status code and an error if the operation fails.
+	RevokeConsent(patientUUID uuid.UUID, consentUUID uuid.UUID) (int, error)
+
+	// GetAccessLog retrieves the latest accesses of the clinicians to the data of the patient.
+	// Returns the list of accesses, a status code, and an error if the operation fails.
+	GetAccessLog(patientUUID uuid.UUID) ([]entity.PatientAccessLog, int, error)
+
+	// GetPatients retrieves the patients with an active consent to the clinician.
+	// Returns the list of patients, a status code, and an error if the operation fails.
+	GetPatients(clinicianUUID uuid.UUID, ip string) ([]*entity.ConsentingPatient, int, error)
+
+	// GetPatientSymptomTrends aggregates the monitorings of a consenting patient, defaulting to the symptoms they follow.
+	// Returns the analytics of each symptom, a status code, and an error if the operation fails.
+	GetPatientSymptomTrends(c *gin.Context, clinicianUUID uuid.UUID, patientUUID uuid.UUID, analyticsReq *entity.RequestMonitoringAnalytics, ip string) ([]*entity.SymptomAnalytics, int, error)
+
+	// GetPatientTreatments retrieves the treatments of a consenting patient.
+	// Returns the list of treatments, a status code, and an error if the operation fails.
+	GetPatientTreatments(clinicianUUID uuid.UUID, patientUUID uuid.UUID, ip string) ([]*entity.Treatment, int, error)
+
+	// GetPatientAdherence computes the weekly adherence of a consenting patient to a treatment between the given dates.
+	// Returns the adherence report, a status code, and an error if the operation fails.
+	GetPatientAdherence(clinicianUUID uuid.UUID, patientUUID uuid.UUID, treatmentUUID uuid.UUID, from time.Time, to time.Time, ip string) (*entity.AdherenceReport, int, error)
+
+	// GetPatientMedicalRecord retrieves the medical record of a consenting patient.
+	// Returns the medical record, a status code, and an error if the operation fails.
+	GetPatientMedicalRecord(c *gin.Context, clinicianUUID uuid.UUID, patientUUID uuid.UUID, ip string) (*entity.MedicalRecord, int, error)
+}
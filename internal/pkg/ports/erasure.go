@@ -0,0 +1,71 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/google/uuid"
+)
+
+// ErasureRepository is an interface that represents the contract that any data access
+// implementation must satisfy in order to erase the accounts of the users.
+type ErasureRepository interface {
+	FindByUUID(uuid uuid.UUID, out interface{}) (interface{}, error)
+
+	// First retrieves the first record that matches the given conditions from the database
+	// Returns an error if the operation fails.
+	First(out interface{}, conditions ...interface{}) error
+
+	// CreateErasure creates a new erasure request.
+	// Returns an error if the operation fails.
+	CreateErasure(erasure *entity.AccountErasure) error
+
+	// FindScheduledErasure retrieves the erasure of the user waiting for the end of the grace period.
+	// Returns nil if there is none, and an error if the operation fails.
+	FindScheduledErasure(userID int) (*entity.AccountErasure, error)
+
+	// FindErasures retrieves every erasure request of the user with the given UUID, newest first.
+	// Returns an error if the operation fails.
+	FindErasures(userUUID uuid.UUID) ([]entity.AccountErasure, error)
+
+	// FindDueErasures retrieves up to limit scheduled erasures whose grace period ended before now.
+	// Returns an error if the operation fails.
+	FindDueErasures(now time.Time, limit int) ([]entity.AccountErasure, error)
+
+	// UpdateErasure saves the status, the error, the report and the dates of the erasure.
+	// Returns an error if the operation fails.
+	UpdateErasure(erasure *entity.AccountErasure) error
+
+	// FindUserMediaURLs retrieves the URLs of the media uploaded by the user.
+	// Returns an error if the operation fails.
+	FindUserMediaURLs(userID int) ([]string, error)
+
+	// EraseUserData deletes the personal data of the user and anonymises the records kept for the other users,
+	// in a single transaction. The email of the user is replaced with anonymousEmail.
+	// Returns the report of the erased records and an error if the operation fails.
+	EraseUserData(userID int, anonymousEmail string, erasedAt time.Time) (*entity.ErasureReport, error)
+}
+
+// ErasureService is an interface that represents the contract for the business logic implementation
+// related to the erasure of the accounts of the users.
+type ErasureService interface {
+	// RequestErasure schedules the erasure of the account of the user after the grace period and signs them out.
+	// Returns the erasure, an HTTP status code and an error (if any).
+	RequestErasure(userUUID uuid.UUID) (*entity.AccountErasure, int, error)
+
+	// GetErasure retrieves the scheduled erasure of the account of the user.
+	// Returns the erasure, an HTTP status code and an error (if any).
+	GetErasure(userUUID uuid.UUID) (*entity.AccountErasure, int, error)
+
+	// CancelErasure cancels the scheduled erasure of the account of the user during the grace period.
+	// Returns an HTTP status code and an error (if any).
+	CancelErasure(userUUID uuid.UUID) (int, error)
+
+	// GetUserErasures retrieves every erasure request of the user with its report.
+	// Returns the erasures, an HTTP status code and an error (if any).
+	GetUserErasures(userUUID uuid.UUID) ([]entity.AccountErasure, int, error)
+
+	// ProcessDueErasures erases the accounts whose grace period ended before now.
+	// Returns the number of erased accounts and an error if the erasures could not be retrieved.
+	ProcessDueErasures(now time.Time) (int, error)
+}
@@ -0,0 +1,76 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/google/uuid"
+)
+
+// ExportRepository is an interface that represents the contract that any data access
+// implementation must satisfy in order to export the personal data of the users.
+type ExportRepository interface {
+	FindByUUID(uuid uuid.UUID, out interface{}) (interface{}, error)
+
+	// CreateExport creates a new export job.
+	// Returns an error if the operation fails.
+	CreateExport(export *entity.DataExport) error
+
+	// FindExport retrieves the export of the user with the given UUID, with its archive if withArchive is set.
+	// Returns an error if the operation fails.
+	FindExport(userID int, exportUUID uuid.UUID, withArchive bool) (*entity.DataExport, error)
+
+	// FindActiveExport retrieves the latest export of the user that is pending, processing or completed and not expired.
+	// Returns nil if there is none, and an error if the operation fails.
+	FindActiveExport(userID int, now time.Time) (*entity.DataExport, error)
+
+	// FindPendingExports retrieves up to limit exports waiting for the worker, including the ones processing since before staleBefore.
+	// Returns an error if the operation fails.
+	FindPendingExports(staleBefore time.Time, limit int) ([]entity.DataExport, error)
+
+	// ClaimExport marks the export as processing unless another worker claimed it since it was retrieved.
+	// Returns whether the export was claimed and an error if the operation fails.
+	ClaimExport(export *entity.DataExport, staleBefore time.Time) (bool, error)
+
+	// UpdateExport saves the status, the error, the archive and the dates of the export.
+	// Returns an error if the operation fails.
+	UpdateExport(export *entity.DataExport) error
+
+	// DeleteExpiredExports deletes the exports that expired before now.
+	// Returns the number of deleted exports and an error if the operation fails.
+	DeleteExpiredExports(now time.Time) (int64, error)
+
+	// CountPersonalData counts the records holding personal data of the user.
+	// Returns an error if the operation fails.
+	CountPersonalData(userID int) (int64, error)
+
+	// FindPersonalData retrieves every personal data of the user, the profile being still encrypted.
+	// Returns an error if the operation fails.
+	FindPersonalData(userID int) (*entity.User, *entity.PersonalData, error)
+}
+
+// ExportService is an interface that represents the contract for the business logic implementation
+// related to the export of the personal data of the users.
+type ExportService interface {
+	// ExportPersonalData exports the personal data of the user. Small accounts are exported right away and the
+	// returned export holds the archive, while the larger ones are exported by the worker and the returned export
+	// is the job to poll.
+	// Returns the export, an HTTP status code and an error (if any).
+	ExportPersonalData(userUUID uuid.UUID) (*entity.DataExport, int, error)
+
+	// GetExport retrieves the status of an export of the user.
+	// Returns the export, an HTTP status code and an error (if any).
+	GetExport(userUUID, exportUUID uuid.UUID) (*entity.DataExport, int, error)
+
+	// DownloadExport retrieves a completed export of the user with its archive.
+	// Returns the export, an HTTP status code and an error (if any).
+	DownloadExport(userUUID, exportUUID uuid.UUID) (*entity.DataExport, int, error)
+
+	// ProcessPendingExports builds the archives of the exports waiting for the worker.
+	// Returns the number of completed exports and an error if the exports could not be retrieved.
+	ProcessPendingExports(now time.Time) (int, error)
+
+	// PurgeExpiredExports deletes the exports that expired before now.
+	// Returns the number of deleted exports and an error if the operation fails.
+	PurgeExpiredExports(now time.Time) (int64, error)
+}
@@ -1,6 +1,11 @@
 package ports
 
-import "github.com/emur-uy/backend/internal/pkg/entity"
+import (
+	"context"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/google/uuid"
+)
 
 // ForecastService is an interface defining a contract for business logic operators related to Forecasts.
 // It works with the entity layer to manipulate Forecast data.
@@ -8,6 +13,11 @@ type ForecastService interface {
 	// GetDistinctCountryAndCityUsers retrieves distinct users based on their country and city.
 	// Returns a slice of User entities and an error if any occurred.
 	GetDistinctCountryAndCityUsers() ([]entity.User, error)
+
+	// GetUserForecast retrieves the forecast of the next days for the city of the given user,
+	// along with the heat alerts of the days passing the configured thresholds.
+	// Returns the forecast, the HTTP status code, and an error if the operation fails.
+	GetUserForecast(userUUID uuid.UUID) (*entity.UserForecast, int, error)
 }
 
 // ForecastRepository is an interface that acts as a contract for the data access layer,
@@ -17,8 +27,58 @@ type ForecastRepository interface {
 	// Returns an error if the operation fails.
 	Create(value interface{}) error
 
+	// UpsertForecast adds the Forecast to the data store, or updates the stored Forecast of the same Location and date.
+	// Returns an error if the operation fails.
+	UpsertForecast(forecast *entity.Forecast) error
+
+	// DeleteForecastsBefore removes the Forecasts dated before the given date (yyyy-mm-dd) from the data store.
+	// Returns the number of deleted records and an error if the operation fails.
+	DeleteForecastsBefore(date string) (int64, error)
+
+	// UpsertForecastFetch adds the ForecastFetch to the data store, or updates the stored ForecastFetch of the same Location.
+	// Returns an error if the operation fails.
+	UpsertForecastFetch(fetch *entity.ForecastFetch) error
+
+	// FindOrCreateLocation fills the given Location with the stored Location of the same coordinates,
+	// adding it to the data store when there is none.
+	// Returns an error if the operation fails.
+	FindOrCreateLocation(location *entity.Location) error
+
+	// AssignUserLocation links the users of the given city and country without a Location to the Location,
+	// and sets the timezone of the Location to the users who did not choose a timezone.
+	// Returns an error if the operation fails.
+	AssignUserLocation(city string, country string, location *entity.Location) error
+
+	// GetUnresolvedUserLocations retrieves the distinct cities and countries of the users without a Location,
+	// modifying the provided slice of Users.
+	// Returns an error if the operation fails.
+	GetUnresolvedUserLocations(users *[]entity.User) error
+
+	// GetUserLocations retrieves the Locations of at least one user, modifying the provided slice of Locations.
+	// Returns an error if the operation fails.
+	GetUserLocations(locations *[]*entity.Location) error
+
 	// GetDistinctCountryAndCityUsers retrieves distinct users based on their country and city,
 	// modifying the provided slice of Users.
 	// Returns an error if the operation fails.
 	GetDistinctCountryAndCityUsers(users *[]entity.User) error
+
+	// FindByUUID finds a record by its UUID in the data store.
+	// Returns the found record and an error if the operation fails.
+	FindByUUID(uuid uuid.UUID, out interface{}) (interface{}, error)
+
+	// Find retrieves records that match the given conditions from the data store.
+	// Returns an error if the operation fails.
+	Find(dest interface{}, conditions ...interface{}) error
+}
+
+// ForecastProvider is an interface for the weather providers the forecasts are fetched from.
+type ForecastProvider interface {
+	// Geocode resolves a city and country to a Location with its coordinates and timezone.
+	// Returns the Location and an error if the request fails, is cancelled, or the city is not found.
+	Geocode(ctx context.Context, city string, country string) (*entity.Location, error)
+
+	// GetForecast fetches the forecast of the next days for the given coordinates in the given language.
+	// Returns the forecast and an error if the request fails, is cancelled, or the response has no forecast days.
+	GetForecast(ctx context.Context, lang string, latitude float64, longitude float64) (entity.RequestForecast, error)
 }
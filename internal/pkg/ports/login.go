@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+)
+
+// LoginAttemptRepository is an interface that represents the contract for the audit of the login attempts
+// and the failure counters used to throttle them.
+type LoginAttemptRepository interface {
+	// CreateAttempt adds a new LoginAttempt record to the data store.
+	// Returns an error if the operation fails.
+	CreateAttempt(attempt *entity.LoginAttempt) error
+
+	// FindAttempts retrieves the latest login attempts of the given user, newest first.
+	// Returns an error if the operation fails.
+	FindAttempts(userID int, limit int) ([]entity.LoginAttempt, error)
+
+	// FindThrottles retrieves the failure counters of the given keys.
+	// Returns an error if the operation fails.
+	FindThrottles(keys []string) ([]entity.LoginThrottle, error)
+
+	// IncrementThrottle adds a failure to the counter of the key, restarting it when the last failure
+	// was before windowStart.
+	// Returns the updated counter and an error if the operation fails.
+	IncrementThrottle(key string, failedAt time.Time, windowStart time.Time) (*entity.LoginThrottle, error)
+
+	// LockThrottle locks the key until the given time.
+	// Returns an error if the operation fails.
+	LockThrottle(key string, lockedUntil time.Time) error
+
+	// DeleteThrottle removes the counter and the lock of the key.
+	// Returns an error if the operation fails.
+	DeleteThrottle(key string) error
+}
@@ -0,0 +1,10 @@
+package ports
+
+import "github.com/emur-uy/backend/internal/pkg/entity"
+
+// Mailer is an interface that represents the contract for sending emails to the users.
+type Mailer interface {
+	// Send delivers the given email.
+	// Returns an error if the delivery fails.
+	Send(email *entity.Email) error
+}
@@ -2,8 +2,10 @@ package ports
 
 import (
 	"errors"
+	"time"
 
 	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/gin-gonic/gin"
 )
 
 // ErrInvalidInput is an error instance to be returned when an invalid input is provided to a function or method.
@@ -23,6 +25,24 @@ type MediaRepository interface {
 	// Find retrieves all Media records that match the given conditions.
 	// Returns an error if the operation fails.
 	Find(model interface{}, dest interface{}, conditions ...interface{}) error
+
+	// FindDomainMedia retrieves up to limit media of the domain with an ID greater than afterID, ordered by ID,
+	// the media of the deleted records included.
+	// Returns an error if the operation fails.
+	FindDomainMedia(domain string, afterID int, limit int) ([]entity.Media, error)
+
+	// FindMedia retrieves up to limit media with an ID greater than afterID, ordered by ID.
+	// Returns an error if the operation fails.
+	FindMedia(afterID int, limit int) ([]entity.Media, error)
+
+	// FindOrphanMedia retrieves up to limit media created before the given time and joined to no record of
+	// any domain, with an ID greater than afterID, ordered by ID.
+	// Returns an error if the operation fails.
+	FindOrphanMedia(before time.Time, afterID int, limit int) ([]entity.Media, error)
+
+	// DeleteOrphanMedia deletes the media if it is still joined to no record.
+	// Returns whether it was deleted and an error if the operation fails.
+	DeleteOrphanMedia(id int) (bool, error)
 }
 
 // MediaService is an interface defining a contract for business logic operators related to Media.
@@ -39,6 +59,25 @@ type MediaService interface {
 	// FindByMediaID retrieves a Media entity based on its ID.
 	// Returns an error if the operation fails.
 	FindByMediaID(id int, i *entity.Media) error
+
+	// UploadFormFiles checks the images of the "file" field of the multipart form, strips their metadata
+	// and uploads them with their thumbnail and WebP variant, private if the domain is.
+	// Returns the Media entities to be saved, an HTTP status code and an error (if any).
+	UploadFormFiles(c *gin.Context, domain string) ([]*entity.Media, int, error)
+
+	// SignMedia generates short-lived URLs of the media and its variants, to be returned once the
+	// caller checked the user can read it.
+	// Returns an error if the operation fails.
+	SignMedia(media *entity.Media) (*entity.SignedMedia, error)
+
+	// MakeDomainPrivate makes the uploaded files of the media of the domain private, in batches.
+	// Returns a report of the files updated and an error if the media cannot be read.
+	MakeDomainPrivate(domain string, batchSize int, dryRun bool) (*entity.MediaPrivacyReport, error)
+
+	// CollectOrphans deletes the media joined to no record and the stored objects of no media, once they are
+	// older than the grace period, or only reports them in a dry run.
+	// Returns a report of the orphans and an error if the media or the objects cannot be read.
+	CollectOrphans(now time.Time, gracePeriod time.Duration, dryRun bool) (*entity.MediaGCReport, error)
 }
 
 // ReminderMediaRepository defines an interface for accessing the reminder_media data store.
@@ -25,16 +25,33 @@ type MonitoringRepository interface {
 	// Find retrieves records that match the given conditions from the data store.
 	// Returns an error if the operation fails.
 	Find(out interface{}, conditions ...interface{}) error
+
+	// First retrieves the first record that matches the given conditions from the data store.
+	// Returns an error if the operation fails or no record is found.
+	First(dest interface{}, conditions ...interface{}) error
+
+	// Update updates an existing Monitoring record in the data store.
+	// Returns an error if the operation fails.
+	Update(value interface{}) error
 }
 
 // MonitoringService defines the interface for managing Monitorings in the application.
 // It works with the entity layer to handle Monitoring data.
 type MonitoringService interface {
-	// CreateMonitoring creates a new Monitoring using the provided data and context.
-	// Returns the newly created Monitoring, the HTTP status code, and an error if the operation fails.
+	// CreateMonitoring creates a new Monitoring using the provided data and context, or updates the
+	// existing one if the user already recorded the symptom on the same day in their timezone.
+	// Returns the saved Monitoring, the HTTP status code, and an error if the operation fails.
 	CreateMonitoring(c *gin.Context, userUUID uuid.UUID, createReq *entity.RequestCreateMonitoring) (*entity.Monitoring, int, error)
 
 	// GetAllMonitorings retrieves all Monitoring records for a given user UUID.
 	// Returns the retrieved Monitorings, the HTTP status code, and an error if the operation fails.
 	GetAllMonitorings(c *gin.Context, userUUID uuid.UUID) ([]*entity.Monitoring, int, error)
+
+	// GetMonitoringAnalytics aggregates the Monitoring records of a user for the requested symptoms and date range.
+	// Returns the analytics of each symptom, the HTTP status code, and an error if the operation fails.
+	GetMonitoringAnalytics(c *gin.Context, userUUID uuid.UUID, analyticsReq *entity.RequestMonitoringAnalytics) ([]*entity.SymptomAnalytics, int, error)
+
+	// GetWeatherCorrelation correlates the Monitoring records of a user with the forecasts stored for their city.
+	// Returns the correlation of each symptom, the HTTP status code, and an error if the operation fails.
+	GetWeatherCorrelation(c *gin.Context, userUUID uuid.UUID, correlationReq *entity.RequestWeatherCorrelation) ([]*entity.WeatherCorrelation, int, error)
 }
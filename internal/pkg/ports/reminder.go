@@ -1,6 +1,8 @@
 package ports
 
 import (
+	"time"
+
 	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -42,6 +44,10 @@ type ReminderService interface {
 	// Returns a slice of Reminders and an error if the operation fails.
 	GetAllReminders(c *gin.Context, userUUID uuid.UUID) ([]*entity.GetReminderResponse, error)
 
+	// GetReminderMedia retrieves the media of a Reminder of the user, with short-lived signed URLs.
+	// Returns the media, an HTTP status code and an error if the reminder is not found or the operation fails.
+	GetReminderMedia(c *gin.Context, userUUID uuid.UUID, reminderUUID uuid.UUID) ([]entity.GetReminderMediaResponse, int, error)
+
 	// UpdateReminder updates an existing Reminder using the provided Reminder UUID and update request data.
 	// Returns an HTTP status code and an error if the operation fails.
 	UpdateReminder(c *gin.Context, reminderUUID uuid.UUID, updateReq *entity.RequestUpdateReminder) (int, error)
@@ -50,3 +56,43 @@ type ReminderService interface {
 	// Returns an error if the operation fails.
 	DeleteReminder(c *gin.Context, reminderUUID uuid.UUID) error
 }
+
+// ReminderNotificationRepository defines the interface for interacting with the reminder notification ledger.
+// The ledger records which notifications have already been claimed so each one is delivered only once.
+type ReminderNotificationRepository interface {
+	// Create inserts a new ReminderNotification record into the data store.
+	// Returns an error if the operation fails, including when the notification was already claimed.
+	Create(value interface{}) error
+
+	// Find retrieves ReminderNotification records from the data store that match the given conditions.
+	// Returns an error if the operation fails.
+	Find(model interface{}, dest interface{}, conditions ...interface{}) error
+
+	// UpdateColumns updates a specific column of an existing ReminderNotification record.
+	// Returns an error if the operation fails.
+	UpdateColumns(value interface{}, column string, updateValue interface{}) error
+
+	// Delete removes a ReminderNotification record from the data store.
+	// Returns an error if the operation fails.
+	Delete(value interface{}) error
+
+	// Reclaim claims again the notification at its ClaimedAt, unless it was sent or claimed again after staleBefore.
+	// Returns whether it was claimed and an error if the operation fails.
+	Reclaim(notification *entity.ReminderNotification, staleBefore time.Time) (bool, error)
+}
+
+// ReminderNotificationService defines the methods for dispatching the notifications configured on reminders.
+type ReminderNotificationService interface {
+	// DispatchDueNotifications sends every notification of an active reminder that is due at the given time
+	// and has not been delivered yet.
+	// Returns the number of notifications sent and an error if the operation fails.
+	DispatchDueNotifications(now time.Time) (int, error)
+}
+
+// Notifier defines the contract for delivering a reminder notification to its user.
+// Implementations may push, email or simply log the notification.
+type Notifier interface {
+	// Notify delivers the given notification of the given reminder.
+	// Returns an error if the delivery fails.
+	Notify(reminder *entity.Reminder, notification *entity.ReminderNotification) error
+}
@@ -0,0 +1,92 @@
+package ports
+
+import (
+	"errors"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/google/uuid"
+)
+
+// ErrLastRoleHolder is returned when a role would be revoked from the only user holding it.
+var ErrLastRoleHolder = errors.New("the user is the last holder of the role")
+
+// RoleRepository is an interface that represents the contract for managing the roles, their permissions
+// and the roles held by the users.
+type RoleRepository interface {
+	// FindRoles retrieves every role with its permissions.
+	// Returns an error if the operation fails.
+	FindRoles() ([]entity.Role, error)
+
+	// FindRoleByName retrieves the role with the given name and its permissions.
+	// Returns an error if the operation fails or no record is found.
+	FindRoleByName(name string) (*entity.Role, error)
+
+	// CreateRole adds a new Role record and its permissions to the data store.
+	// Returns an error if the operation fails.
+	CreateRole(role *entity.Role) error
+
+	// UpdateRole replaces the description and the permissions of the role.
+	// Returns an error if the operation fails.
+	UpdateRole(role *entity.Role) error
+
+	// DeleteRole removes the role, its permissions and its assignments.
+	// Returns an error if the operation fails.
+	DeleteRole(roleID int) error
+
+	// AssignRole grants the role to the user. Assigning a role the user already holds has no effect.
+	// Returns an error if the operation fails.
+	AssignRole(userID int, roleID int) error
+
+	// UnassignRole revokes the role from the user.
+	// Returns an error if the operation fails.
+	UnassignRole(userID int, roleID int) error
+
+	// UnassignRoleKeepingHolder revokes the role from the user unless no other user, leaving out the erased ones,
+	// holds it. The check and the revocation are atomic, so concurrent calls cannot leave the role without holders.
+	// Returns ErrLastRoleHolder if the user is the last holder, or an error if the operation fails.
+	UnassignRoleKeepingHolder(userID int, roleID int) error
+
+	// GetUserRoleNames retrieves the names of the roles held by the user.
+	// Returns an error if the operation fails.
+	GetUserRoleNames(userID int) ([]string, error)
+
+	PermissionResolver
+}
+
+// PermissionResolver is an interface for resolving the permissions granted to a user by their roles.
+type PermissionResolver interface {
+	// GetUserPermissions retrieves the permissions granted by every role of the user with the given UUID.
+	// Returns an error if the operation fails.
+	GetUserPermissions(userUUID uuid.UUID) ([]string, error)
+}
+
+// RoleService is the interface that defines the methods for managing the roles, their permissions
+// and the roles held by the users.
+type RoleService interface {
+	// GetRoles retrieves every role with its permissions.
+	// Returns the list of roles and an error if the operation fails.
+	GetRoles() ([]entity.Role, error)
+
+	// GetPermissions retrieves the catalog of the permissions that can be granted to a role.
+	GetPermissions() []string
+
+	// CreateRole creates a new role with the given permissions.
+	// Returns the created role, a status code, and an error if the operation fails.
+	CreateRole(createReq *entity.RequestCreateRole) (*entity.Role, int, error)
+
+	// UpdateRole replaces the description and the permissions of the role with the given name.
+	// Returns the updated role, a status code, and an error if the operation fails.
+	UpdateRole(name string, updateReq *entity.RequestUpdateRole) (*entity.Role, int, error)
+
+	// DeleteRole removes the role with the given name and revokes it from its users.
+	// Returns a status code and an error if the operation fails.
+	DeleteRole(name string) (int, error)
+
+	// AssignRole grants the role with the given name to the user.
+	// Returns a status code and an error if the operation fails.
+	AssignRole(name string, userUUID uuid.UUID) (int, error)
+
+	// UnassignRole revokes the role with the given name from the user.
+	// Returns a status code and an error if the operation fails.
+	UnassignRole(name string, userUUID uuid.UUID) (int, error)
+}
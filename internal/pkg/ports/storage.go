@@ -0,0 +1,96 @@
+package ports
+
+import (
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+)
+
+// ErrBlobNotFound is returned by a BlobStore when the requested object does not exist.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// ErrInvalidSignature is returned when a signed URL is forged or expired.
+var ErrInvalidSignature = errors.New("invalid or expired signature")
+
+// ErrBlobTooLarge is returned when the body sent to a signed URL is larger than the signed size.
+var ErrBlobTooLarge = errors.New("blob larger than the signed size")
+
+// BlobStore is an interface that represents the contract for storing the uploaded files.
+// The objects are identified by their key, a slash separated path such as "reminders/<uuid>.jpg".
+type BlobStore interface {
+	// Put stores the content under the key, readable by anyone when public is true.
+	// The content type is sniffed from the content when empty.
+	// Returns the URL of the object, or an error if the operation fails.
+	Put(key string, body io.Reader, contentType string, public bool) (string, error)
+
+	// Get opens the content of the object with the given key, the caller closes it.
+	// Returns ErrBlobNotFound if the object does not exist, or an error if the operation fails.
+	Get(key string) (io.ReadCloser, error)
+
+	// SetPublic changes the visibility of the object, readable by anyone when public is true.
+	// Returns ErrBlobNotFound if the object does not exist, or an error if the operation fails.
+	SetPublic(key string, public bool) error
+
+	// Delete deletes the object with the given key, deleting a missing object is not an error.
+	// Returns an error if the operation fails.
+	Delete(key string) error
+
+	// List retrieves the objects whose key starts with the prefix.
+	// Returns an error if the operation fails.
+	List(prefix string) ([]entity.BlobObject, error)
+
+	// SignedURL generates a URL reading the object, public or not, until the expiry elapses.
+	// Returns an error if the operation fails.
+	SignedURL(key string, expiry time.Duration) (string, error)
+
+	// Key returns the key of the object from the URL returned when it was stored.
+	Key(url string) string
+
+	// URL returns the URL of the object with the given key, as returned when it was stored.
+	URL(key string) string
+
+	// Stat retrieves the size and the last modification of the object.
+	// Returns ErrBlobNotFound if the object does not exist, or an error if the operation fails.
+	Stat(key string) (*entity.BlobObject, error)
+
+	// SignedPutURL generates a URL uploading a private object of the given size with a single PUT request,
+	// sending the content type, until the expiry elapses.
+	// Returns an error if the operation fails.
+	SignedPutURL(key string, contentType string, size int64, expiry time.Duration) (string, error)
+
+	// CreateMultipartUpload starts the upload of a private object in parts.
+	// Returns the ID of the upload, or an error if the operation fails.
+	CreateMultipartUpload(key string, contentType string) (string, error)
+
+	// SignedPartURL generates a URL uploading a part of the given size of the multipart upload with a PUT
+	// request, until the expiry elapses. The ETag header of the response identifies the uploaded part.
+	// Returns an error if the operation fails.
+	SignedPartURL(key string, uploadID string, partNumber int, size int64, expiry time.Duration) (string, error)
+
+	// CompleteMultipartUpload assembles the uploaded parts, in order, into the object.
+	// Returns an error if a part is missing or the operation fails.
+	CompleteMultipartUpload(key string, uploadID string, parts []entity.UploadPart) error
+
+	// AbortMultipartUpload discards the multipart upload and its uploaded parts.
+	// Returns an error if the operation fails.
+	AbortMultipartUpload(key string, uploadID string) error
+}
+
+// LocalBlobStore is a BlobStore keeping the objects on the local disk, served by the API.
+type LocalBlobStore interface {
+	BlobStore
+
+	// Open opens the object to be served, the private objects requiring the expiry and signature of their signed URL.
+	// Returns ErrBlobNotFound if the object does not exist, ErrInvalidSignature if the signature is not valid,
+	// or an error if the operation fails.
+	Open(key string, expires string, signature string) (*os.File, error)
+
+	// Write stores the body sent to a signed PUT URL, the object or a part of a multipart upload
+	// when the upload ID and the part number are set, reading up to the signed size.
+	// Returns the ETag of the content, ErrInvalidSignature if the signature is not valid,
+	// ErrBlobTooLarge if the body is larger than the signed size, or an error if the operation fails.
+	Write(key string, uploadID string, partNumber string, size string, expires string, signature string, body io.Reader) (string, error)
+}
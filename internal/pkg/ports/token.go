@@ -0,0 +1,57 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenRepository is an interface that represents the contract for storing the refresh tokens
+// and the revoked access tokens of the users.
+type TokenRepository interface {
+	// Create adds a new RefreshToken, RevokedToken or UserActionToken record to the data store.
+	// Returns an error if the operation fails.
+	Create(value interface{}) error
+
+	// First retrieves the first record that matches the given conditions from the data store.
+	// Returns an error if the operation fails or no record is found.
+	First(out interface{}, conditions ...interface{}) error
+
+	// RevokeRefreshToken revokes the RefreshToken with the given ID if it is not revoked yet.
+	// Returns whether the token was revoked by this call, and an error if the operation fails.
+	RevokeRefreshToken(id int, revokedAt time.Time) (bool, error)
+
+	// RevokeRefreshTokenFamily revokes every RefreshToken of the given family.
+	// Returns an error if the operation fails.
+	RevokeRefreshTokenFamily(familyID uuid.UUID, revokedAt time.Time) error
+
+	// RevokeUserRefreshTokens revokes every RefreshToken of the given user.
+	// Returns an error if the operation fails.
+	RevokeUserRefreshTokens(userID int, revokedAt time.Time) error
+
+	// SetTokensValidAfter rejects the access tokens of the given user issued before validAfter.
+	// Returns an error if the operation fails.
+	SetTokensValidAfter(userID int, validAfter time.Time) error
+
+	// UseActionToken marks the UserActionToken with the given ID as used if it was not used yet.
+	// Returns whether the token was used by this call, and an error if the operation fails.
+	UseActionToken(id int, usedAt time.Time) (bool, error)
+
+	// DeleteExpiredTokens removes the refresh tokens, revoked tokens and action tokens expired before the given time.
+	// Returns the number of deleted records and an error if the operation fails.
+	DeleteExpiredTokens(before time.Time) (int64, error)
+
+	RevocationList
+}
+
+// RevocationList is an interface for checking whether an access token was revoked before its expiration.
+type RevocationList interface {
+	// IsRevoked reports whether the access token with the given JWT ID was revoked.
+	// Returns an error if the operation fails.
+	IsRevoked(jti string) (bool, error)
+
+	// TokensValidAfter retrieves the time before which the access tokens of the user are rejected,
+	// or nil when all of them are accepted.
+	// Returns an error if the operation fails.
+	TokensValidAfter(userUUID uuid.UUID) (*time.Time, error)
+}
@@ -0,0 +1,41 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/google/uuid"
+)
+
+// TrashRepository is an interface that represents the contract that any data access
+// implementation must satisfy in order to manage the deleted records.
+type TrashRepository interface {
+	// FindDeleted retrieves a page of the deleted records of the resource, last deleted first.
+	// Returns the records, the total of deleted records and an error if the operation fails.
+	FindDeleted(resource string, limit, offset int) ([]entity.TrashItem, int64, error)
+
+	// Restore restores the deleted record of the resource with the given UUID.
+	// Returns whether a deleted record was restored and an error if the operation fails.
+	Restore(resource string, uuid uuid.UUID) (bool, error)
+
+	// PurgeDeleted hard-deletes up to limit records of the resource deleted before the given time,
+	// with the records referencing them and their media, in a single transaction.
+	// Returns the purged records and an error if the operation fails.
+	PurgeDeleted(resource string, before time.Time, limit int) (*entity.PurgedTrash, error)
+}
+
+// TrashService is an interface that represents the contract for the business logic implementation
+// related to the deleted records.
+type TrashService interface {
+	// GetTrash retrieves a page of the deleted records of the resource.
+	// Returns the page, an HTTP status code and an error (if any).
+	GetTrash(resource string, request *entity.RequestTrash) (*entity.TrashResult, int, error)
+
+	// RestoreItem restores the deleted record of the resource with the given UUID.
+	// Returns an HTTP status code and an error (if any).
+	RestoreItem(resource string, uuid uuid.UUID) (int, error)
+
+	// PurgeTrash hard-deletes the records deleted for longer than the retention and their uploaded files.
+	// Returns the number of purged records and an error (if any).
+	PurgeTrash(now time.Time) (int64, error)
+}
@@ -1,6 +1,8 @@
 package ports
 
 import (
+	"time"
+
 	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -60,4 +62,17 @@ type TreatmentService interface {
 	// GetAllTreatments retrieves all treatment records for a specific user from the application.
 	// Returns a list of treatments and an error if the operation fails.
 	GetAllTreatments(userUUID uuid.UUID) ([]*entity.Treatment, error)
+
+	// GetDoses expands a treatment of the user into its dose occurrences between the given dates,
+	// including the status recorded for each one.
+	// Returns the list of occurrences, a status code, and an error if the operation fails.
+	GetDoses(userUUID uuid.UUID, treatmentUUID uuid.UUID, from time.Time, to time.Time) ([]*entity.DoseOccurrence, int, error)
+
+	// RecordDose marks a dose occurrence of a treatment of the user as taken, late or skipped.
+	// Returns the recorded dose, a status code, and an error if the operation fails.
+	RecordDose(userUUID uuid.UUID, treatmentUUID uuid.UUID, recordReq *entity.RequestRecordDose) (*entity.TreatmentDose, int, error)
+
+	// GetAdherence computes the weekly percentage of doses taken for a treatment of the user between the given dates.
+	// Returns the adherence report, a status code, and an error if the operation fails.
+	GetAdherence(userUUID uuid.UUID, treatmentUUID uuid.UUID, from time.Time, to time.Time) (*entity.AdherenceReport, int, error)
 }
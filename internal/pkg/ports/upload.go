@@ -0,0 +1,64 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/google/uuid"
+)
+
+// UploadRepository is an interface that represents the contract that any data access
+// implementation must satisfy in order to keep the upload sessions.
+type UploadRepository interface {
+	FindByUUID(uuid uuid.UUID, out interface{}) (interface{}, error)
+
+	// FindRecord retrieves the record of the domain, not deleted, the files are uploaded for.
+	// Returns an error if the record does not exist or the operation fails.
+	FindRecord(domain string, recordUUID uuid.UUID) (*entity.UploadRecord, error)
+
+	// CreateUpload creates a new upload session.
+	// Returns an error if the operation fails.
+	CreateUpload(upload *entity.UploadSession) error
+
+	// FindUpload retrieves the upload session of the user with the given UUID.
+	// Returns an error if the session does not exist or the operation fails.
+	FindUpload(userID int, uploadUUID uuid.UUID) (*entity.UploadSession, error)
+
+	// UpdateUpload saves the status, the error and the completion date of the upload session.
+	// Returns an error if the operation fails.
+	UpdateUpload(upload *entity.UploadSession) error
+
+	// AttachMedia creates the media, attaches it to the record of the upload session and completes the session,
+	// in a single transaction.
+	// Returns an error if the operation fails.
+	AttachMedia(upload *entity.UploadSession, media *entity.Media) error
+
+	// FindExpiredUploads retrieves up to limit pending upload sessions that expired before now.
+	// Returns an error if the operation fails.
+	FindExpiredUploads(now time.Time, limit int) ([]entity.UploadSession, error)
+}
+
+// UploadService is an interface that represents the contract for the business logic implementation
+// related to the files uploaded by the clients straight to the blob store.
+type UploadService interface {
+	// CreateUpload opens an upload session for a file of a record of the domain, once the size and the
+	// type of the file are checked.
+	// Returns the session with the URLs uploading the file, an HTTP status code and an error (if any).
+	CreateUpload(userUUID uuid.UUID, request *entity.RequestCreateUpload) (*entity.UploadSessionResponse, int, error)
+
+	// GetUpload retrieves a pending upload session of the user, with new URLs to resume the upload.
+	// Returns the session, an HTTP status code and an error (if any).
+	GetUpload(userUUID, uploadUUID uuid.UUID) (*entity.UploadSessionResponse, int, error)
+
+	// ConfirmUpload checks the uploaded file against the session and attaches it as a media of the record.
+	// Returns the media, an HTTP status code and an error (if any).
+	ConfirmUpload(userUUID, uploadUUID uuid.UUID, request *entity.RequestConfirmUpload) (*entity.Media, int, error)
+
+	// AbortUpload cancels a pending upload session of the user and discards what was uploaded.
+	// Returns an HTTP status code and an error (if any).
+	AbortUpload(userUUID, uploadUUID uuid.UUID) (int, error)
+
+	// ExpireUploads discards the files of the pending upload sessions that expired before now.
+	// Returns the number of expired sessions and an error if the sessions could not be retrieved.
+	ExpireUploads(now time.Time) (int, error)
+}
@@ -2,18 +2,28 @@ package ports
 
 import (
 	"errors"
+	"time"
 
 	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/google/uuid"
 )
 
-var ErrUserNotFound = errors.New("user not found")
+var (
+	ErrUserNotFound     = errors.New("user not found")
+	ErrEmailNotVerified = errors.New("email not verified")
+	// ErrInvalidCredentials is returned for an unknown email and for a wrong password alike, not to reveal the accounts.
+	ErrInvalidCredentials = errors.New("invalid email or password")
+)
 
 // UserRepository is an interface that represents the contract that any data access
 // implementation must satisfy in order to interact with user data.
 type UserRepository interface {
 	FindByUUID(uuid uuid.UUID, out interface{}) (interface{}, error)
 
+	// Create creates a new user record.
+	// Returns an error if the operation fails.
+	Create(value interface{}) error
+
 	// CreateWithOmit creates a new user record while omitting specific fields.
 	// Returns an error if the operation fails.
 	CreateWithOmit(omit string, value interface{}) error
@@ -29,16 +39,60 @@ type UserRepository interface {
 	// UpdateColumns updates specified columns of an existing record in the database using the given value.
 	// Returns an error if the operation fails.
 	UpdateColumns(value interface{}, column string, updateValue interface{}) error
+
+	// FindUsersAfterID retrieves up to limit users with an ID greater than afterID, ordered by ID, the erased users excluded.
+	// Returns an error if the operation fails.
+	FindUsersAfterID(afterID, limit int) ([]entity.User, error)
+
+	// UpdateColumnsWhere updates the given columns of the records of the model that match the conditions.
+	// Returns the number of updated records and an error if the operation fails.
+	UpdateColumnsWhere(model interface{}, conditions map[string]interface{}, columns map[string]interface{}) (int64, error)
+
+	// ReplaceSearchIndexes replaces the blind indexes of the user's name with the given ones.
+	// Returns an error if the operation fails.
+	ReplaceSearchIndexes(userID int, indexes []entity.UserSearchIndex) error
+
+	// SearchUsers retrieves a page of the users matching the filter and the total of matching users.
+	// Returns an error if the operation fails.
+	SearchUsers(filter *entity.UserSearchFilter) ([]entity.User, int64, error)
 }
 
 // UserService is an interface that represents the contract for the business logic implementation
 // related to user operations. This is the primary port in the hexagonal architecture.
 type UserService interface {
 
-	// Login authenticates a user and returns a JWT token if successful, or an error if not.
-	Login(credentials *entity.DefaultCredentials) (string, error)
+	// Login authenticates a user connecting from the given IP and returns a JWT access token and a refresh token.
+	// Repeated failures of the email or the IP are throttled.
+	// Returns the tokens, an HTTP status code and an error (if any).
+	Login(credentials *entity.DefaultCredentials, ip string) (*entity.AuthTokens, int, error)
+
+	// RefreshToken rotates the given refresh token, returning a new access token and refresh token.
+	// Reusing a rotated refresh token revokes every token of its session.
+	// Returns the tokens, an HTTP status code and an error (if any).
+	RefreshToken(refreshToken string) (*entity.AuthTokens, int, error)
+
+	// Logout revokes the access token with the given JWT ID until it expires, and the session of the
+	// given refresh token if any.
+	// Returns an HTTP status code and an error (if any).
+	Logout(userUUID uuid.UUID, jti string, expiresAt time.Time, refreshToken string) (int, error)
 
-	// CreateUser creates a new user with the provided user data.
+	// VerifyEmail marks the email of the owner of the given verification token as verified.
+	// Returns an HTTP status code and an error (if any).
+	VerifyEmail(token string) (int, error)
+
+	// ResendVerificationEmail sends a new verification email to the user with the given email, if it exists and is not verified.
+	// Returns an HTTP status code and an error (if any).
+	ResendVerificationEmail(email string) (int, error)
+
+	// ForgotPassword sends a password reset email to the user with the given email, if it exists.
+	// Returns an HTTP status code and an error (if any).
+	ForgotPassword(email string) (int, error)
+
+	// ResetPassword sets a new password with a password reset token.
+	// Returns an HTTP status code and an error (if any).
+	ResetPassword(request *entity.RequestResetPassword) (int, error)
+
+	// CreateUser creates a new user with the provided user data and sends the verification email.
 	// Returns an HTTP status code and an error (if any).
 	CreateUser(user *entity.User) (int, error)
 
@@ -58,6 +112,18 @@ type UserService interface {
 	// Returns an HTTP status code and an error (if any).
 	UpdateBannedStatus(userUUID uuid.UUID, isBanned bool) (int, error)
 
+	// SearchUsers retrieves a page of the users whose name or email match the request, using the blind indexes of the names.
+	// Returns the page, an HTTP status code and an error (if any).
+	SearchUsers(request *entity.RequestUserSearch) (*entity.UserSearchResult, int, error)
+
+	// GetLoginAttempts retrieves the latest login attempts of the user with the provided UUID.
+	// Returns the attempts, an HTTP status code and an error (if any).
+	GetLoginAttempts(userUUID uuid.UUID) ([]entity.LoginAttempt, int, error)
+
+	// UnlockUser clears the failed logins and the lockout of the user with the provided UUID.
+	// Returns an HTTP status code and an error (if any).
+	UnlockUser(userUUID uuid.UUID) (int, error)
+
 	// GetUserRole retrieves user role information for the user with the provided ID.
 	GetUserRole(userID int) (*entity.UserRole, error)
 
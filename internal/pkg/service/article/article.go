@@ -4,10 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"path"
 
-	"github.com/emur-uy/backend/config"
-	aws "github.com/emur-uy/backend/internal/infra/repositories/spaces"
 	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/ports"
 	"github.com/gin-gonic/gin"
@@ -24,9 +21,7 @@ var (
 	ErrCreatingArticleMedia = errors.New("error creating article media association")
 	ErrDeletingArticleMedia = errors.New("error deleting article media association")
 	ErrDeletingMedia        = errors.New("error deleting media")
-	ErrUnsupportedFileType  = errors.New("unsupported file type")
 	ErrAddingCategory       = errors.New("error adding article to category")
-	ErrFileNotFound         = errors.New("file not found")
 )
 
 const (
@@ -52,9 +47,9 @@ func NewService(articleRepo ports.ArticleRepository, mediaService ports.MediaSer
 
 // CreateArticle is the service for creating an article and saving it in the database.
 func (s *service) CreateArticle(c *gin.Context, createReq *entity.RequestCreateArticle) (*entity.Article, error) {
-	// Call the processUploadRequestFiles function to handle the image upload and create the media entry
-	fileProcessCode, fileUrls, err := processUploadRequestFiles(s, c)
-	if err != nil || fileProcessCode != http.StatusOK {
+	// Upload the images with their variants, the media entries are created with the article
+	medias, _, err := s.mediaService.UploadFormFiles(c, entity.MediaDomainArticles)
+	if err != nil {
 		return nil, fmt.Errorf("error processing content upload file: %s", err)
 	}
 
@@ -71,11 +66,7 @@ func (s *service) CreateArticle(c *gin.Context, createReq *entity.RequestCreateA
 	}
 
 	// For each uploaded file, create a new media entry and then a new ArticleMedia entry
-	for _, fileUrl := range fileUrls {
-		media := &entity.Media{
-			MediaURL: fileUrl,
-		}
-
+	for _, media := range medias {
 		err = s.mediaService.CreateMedia(media)
 		if err != nil {
 			return nil, ErrCreatingMedia
@@ -125,9 +116,9 @@ func (s *service) UpdateArticle(c *gin.Context, articleUUID uuid.UUID, updateReq
 		return http.StatusInternalServerError, fmt.Errorf("error updating article: %s", err)
 	}
 
-	fileProcessCode, fileUrls, err := processUploadRequestFiles(s, c)
-	if err != nil || fileProcessCode != http.StatusOK {
-		return http.StatusInternalServerError, fmt.Errorf("error processing content upload file: %s", err)
+	medias, fileProcessCode, err := s.mediaService.UploadFormFiles(c, entity.MediaDomainArticles)
+	if err != nil {
+		return fileProcessCode, fmt.Errorf("error processing content upload file: %s", err)
 	}
 
 	// Get existing article media data
@@ -138,10 +129,7 @@ func (s *service) UpdateArticle(c *gin.Context, articleUUID uuid.UUID, updateReq
 	}
 
 	// For each uploaded file, create a new media entry and a new article_media association
-	for _, fileUrl := range fileUrls {
-		media := &entity.Media{
-			MediaURL: fileUrl,
-		}
+	for _, media := range medias {
 		err = s.mediaService.CreateMedia(media)
 		if err != nil {
 			return http.StatusInternalServerError, ErrCreatingMedia
@@ -180,7 +168,8 @@ func (s *service) UpdateArticle(c *gin.Context, articleUUID uuid.UUID, updateReq
 	return http.StatusOK, nil
 }
 
-// DeleteArticle deletes an article from the database by its UUID.
+// DeleteArticle moves an article to the trash by its UUID. Its media are kept until the trash is purged,
+// so the article can be restored.
 func (s *service) DeleteArticle(c *gin.Context, articleUUID uuid.UUID) (int, error) {
 	// Retrieve the article from the repository by its UUID.
 	article := &entity.Article{}
@@ -196,36 +185,7 @@ func (s *service) DeleteArticle(c *gin.Context, articleUUID uuid.UUID) (int, err
 		return http.StatusInternalServerError, ErrTypeAssertionFailed
 	}
 
-	// Get article media associations by article ID
-	articleMedias := []*entity.ArticleMedia{}
-	err = s.articleMediaService.FindByArticleID(article.ID, &articleMedias)
-	if err != nil {
-		return http.StatusInternalServerError, ErrFindingArticleMedia
-	}
-
-	// Iterate over each article_media association
-	for _, articleMedia := range articleMedias {
-		media := &entity.Media{}
-		// Find the media by ID
-		err := s.mediaService.FindByMediaID(articleMedia.MediaID, media)
-		if err != nil {
-			return http.StatusInternalServerError, err
-		}
-
-		// Delete the article_media association from the repository
-		err = s.articleMediaService.DeleteArticleMedia(articleMedia)
-		if err != nil {
-			return http.StatusInternalServerError, ErrDeletingArticleMedia
-		}
-
-		// Delete the media from the repository
-		err = s.mediaService.DeleteMedia(media)
-		if err != nil {
-			return http.StatusInternalServerError, ErrDeletingMedia
-		}
-	}
-
-	// Delete the article from the repository
+	// Soft delete the article from the repository
 	err = s.repo.Delete(article)
 	if err != nil {
 		return http.StatusInternalServerError, ErrDeletingArticle
@@ -302,45 +262,3 @@ func (s *service) AddArticleToCategory(categoryUUID uuid.UUID, articleUUID uuid.
 
 	return nil
 }
-
-var uploadFunc = aws.UploadFileToS3Stream
-
-// processUploadRequestFiles processes the file upload request
-func processUploadRequestFiles(s *service, c *gin.Context) (int, []string, error) {
-	form, err := c.MultipartForm()
-	if err != nil {
-		return http.StatusBadRequest, nil, fmt.Errorf("get form err: %s", err.Error())
-	}
-	files := form.File["file"]
-	if files == nil {
-		return http.StatusBadRequest, nil, ErrFileNotFound
-	}
-
-	var fileUrls []string
-
-	for _, file := range files {
-		src, err := file.Open()
-		if err != nil {
-			return http.StatusInternalServerError, nil, fmt.Errorf("failed to open file: %s", err)
-		}
-		defer src.Close()
-
-		fileType := file.Header.Get("Content-Type")
-		if fileType != "image/png" && fileType != "image/jpeg" {
-			return http.StatusBadRequest, nil, ErrUnsupportedFileType
-		}
-
-		fileExt := path.Ext(file.Filename)
-		fileNameUuid := uuid.New()
-
-		uploadPath := fmt.Sprintf("%s/%s", config.Get().AwsFolderName, fmt.Sprintf("%s%s", fileNameUuid.String(), fileExt))
-		url, err := uploadFunc(src, uploadPath, true)
-		if err != nil || url == "" {
-			return http.StatusInternalServerError, nil, fmt.Errorf("s3 upload error: %s", err.Error())
-		}
-
-		fileUrls = append(fileUrls, url)
-	}
-
-	return http.StatusOK, fileUrls, nil
-}
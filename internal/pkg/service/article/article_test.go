@@ -4,15 +4,14 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	aws "github.com/emur-uy/backend/internal/infra/repositories/spaces"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/textproto"
 	"testing"
+	"time"
 
 	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/stretchr/testify/assert"
@@ -58,6 +57,37 @@ func (m *MockArticleRepository) UpdateColumns(value interface{}, column string,
 	return nil
 }
 
+type MockMediaService struct{}
+
+func (m MockMediaService) CreateMedia(media *entity.Media) error {
+	return nil
+}
+
+func (m MockMediaService) DeleteMedia(media *entity.Media) error {
+	return nil
+}
+
+func (m MockMediaService) FindByMediaID(id int, i *entity.Media) error {
+	return nil
+}
+
+type MockArticleMediaService struct{}
+
+func (m MockArticleMediaService) CreateArticleMedia(articleMedia *entity.ArticleMedia) error {
+	return nil
+}
+
+func (m MockArticleMediaService) DeleteArticleMedia(articleMedia *entity.ArticleMedia) error {
+	return nil
+}
+
+func (m MockArticleMediaService) FindByArticleID(id int, i *[]*entity.ArticleMedia) error {
+	if id == 1 {
+		return nil
+	}
+	return errors.New("not found")
+}
+
 // FindByUUID is a mock implementation of the FindByUUID method.
 func (m *MockArticleRepository) FindByUUID(uId uuid.UUID, out interface{}) (interface{}, error) {
 	if uId == testUserUuid {
@@ -84,16 +114,48 @@ func (m *MockArticleRepository) FindByUUID(uId uuid.UUID, out interface{}) (inte
 	return nil, errors.New("not found")
 }
 
-func mockUploadFileToS3Stream(src io.Reader, uploadPath string, isPublic bool) (string, error) {
-	// Mocked implementation of the upload function
-	return "mocked-url", nil
+// UploadFormFiles returns a media for each file of the form, as the media pipeline does.
+func (m MockMediaService) UploadFormFiles(c *gin.Context, domain string) ([]*entity.Media, int, error) {
+	if c.Request == nil {
+		return nil, http.StatusBadRequest, errors.New("file not found")
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	var medias []*entity.Media
+	for range form.File["file"] {
+		medias = append(medias, &entity.Media{MediaURL: "mocked-url", MediaThumb: "mocked-thumb-url"})
+	}
+	if len(medias) == 0 {
+		return nil, http.StatusBadRequest, errors.New("file not found")
+	}
+	return medias, http.StatusOK, nil
+}
+
+// SignMedia returns the URLs of the media with a mocked signature.
+func (m MockMediaService) SignMedia(media *entity.Media) (*entity.SignedMedia, error) {
+	return &entity.SignedMedia{
+		UUID:       media.UUID,
+		MediaURL:   media.MediaURL + "?signature=mocked",
+		MediaThumb: media.MediaThumb + "?signature=mocked",
+		ExpiresAt:  time.Now().Add(15 * time.Minute),
+	}, nil
+}
+
+func (m MockMediaService) MakeDomainPrivate(domain string, batchSize int, dryRun bool) (*entity.MediaPrivacyReport, error) {
+	return &entity.MediaPrivacyReport{Domain: domain}, nil
+}
+
+func (m MockMediaService) CollectOrphans(now time.Time, gracePeriod time.Duration, dryRun bool) (*entity.MediaGCReport, error) {
+	return &entity.MediaGCReport{DryRun: dryRun}, nil
 }
 
 func TestCreateArticle(t *testing.T) {
 
 	// Set up the mock repository and service.
 	mockRepo := &MockArticleRepository{}
-	s := NewService(mockRepo)
+	s := NewService(mockRepo, &MockMediaService{}, &MockArticleMediaService{})
 
 	gin.SetMode(gin.TestMode)
 
@@ -136,12 +198,6 @@ func TestCreateArticle(t *testing.T) {
 		Content: "Test content",
 	}
 
-	uploadFunc = mockUploadFileToS3Stream
-	defer func() {
-		// Restore the original upload function after the test
-		uploadFunc = aws.UploadFileToS3Stream
-	}()
-
 	// Create a test context without request file
 	ctx, _ := gin.CreateTestContext(nil)
 	ctx.Request = httptest.NewRequest(http.MethodPost, "/", fileBuf)
@@ -164,16 +220,16 @@ func TestCreateArticle(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 
-			statusCode, err := s.CreateArticle(&tc.context, tc.uId, createReq)
+			article, err := s.CreateArticle(&tc.context, createReq)
 			// Check the result based on the test case's expected error state.
 			if tc.expectError {
-				// If an error is expected, ensure there is an error returned and the statusCode is not OK.
+				// If an error is expected, ensure there is an error returned and no article.
 				require.Error(t, err)
-				assert.NotEqual(t, http.StatusOK, statusCode)
+				assert.Nil(t, article)
 			} else {
-				// If no error is expected, ensure there is no error returned and the statusCode is OK.
+				// If no error is expected, ensure there is no error returned and the article is created.
 				require.NoError(t, err)
-				assert.Equal(t, http.StatusOK, statusCode)
+				assert.Equal(t, createReq.Title, article.Title)
 			}
 		})
 	}
@@ -182,7 +238,20 @@ func TestCreateArticle(t *testing.T) {
 func TestUpdateArticle(t *testing.T) {
 	// Set up the mock repository and service.
 	mockRepo := &MockArticleRepository{}
-	s := NewService(mockRepo)
+	s := NewService(mockRepo, &MockMediaService{}, &MockArticleMediaService{})
+
+	// Create a test context with an image file
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(nil)
+	fileBuf := &bytes.Buffer{}
+	fileWriter := multipart.NewWriter(fileBuf)
+	part, err := fileWriter.CreateFormFile("file", "image.jpg")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("sample image data"))
+	require.NoError(t, err)
+	require.NoError(t, fileWriter.Close())
+	c.Request = httptest.NewRequest(http.MethodPut, "/", fileBuf)
+	c.Request.Header.Set("Content-Type", fileWriter.FormDataContentType())
 
 	// Create a test request for creating an article
 	req := &entity.RequestUpdateArticle{
@@ -207,7 +276,7 @@ func TestUpdateArticle(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 
-			statusCode, err := s.UpdateArticle(tc.uId, tc.request)
+			statusCode, err := s.UpdateArticle(c, tc.uId, tc.request)
 			// Check the result based on the test case's expected error state.
 			if tc.expectError {
 				// If an error is expected, ensure there is an error returned and the statusCode is not OK.
@@ -225,7 +294,7 @@ func TestUpdateArticle(t *testing.T) {
 func TestDeleteArticle(t *testing.T) {
 	// Set up the mock repository and service.
 	mockRepo := &MockArticleRepository{}
-	s := NewService(mockRepo)
+	s := NewService(mockRepo, &MockMediaService{}, &MockArticleMediaService{})
 
 	// Create a test context
 	gin.SetMode(gin.TestMode)
@@ -264,7 +333,7 @@ func TestDeleteArticle(t *testing.T) {
 func TestGetAllArticles(t *testing.T) {
 	// Set up the mock repository and service.
 	mockRepo := &MockArticleRepository{}
-	s := NewService(mockRepo)
+	s := NewService(mockRepo, &MockMediaService{}, &MockArticleMediaService{})
 
 	// Define test cases.
 	testCases := []struct {
@@ -296,7 +365,7 @@ func TestGetAllArticles(t *testing.T) {
 func TestAddArticleToCategory(t *testing.T) {
 	// Set up the mock repository and service.
 	mockRepo := &MockArticleRepository{}
-	s := NewService(mockRepo)
+	s := NewService(mockRepo, &MockMediaService{}, &MockArticleMediaService{})
 
 	// Define test cases.
 	testCases := []struct {
@@ -0,0 +1,151 @@
+package audit
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidUser         = errors.New("invalid user UUID")
+	ErrInvalidResourceUUID = errors.New("invalid resource UUID")
+	ErrInvalidDate         = errors.New("invalid date, the format is dd/mm/yyyy")
+	ErrInvalidRange        = errors.New("invalid date range, 'to' must not be before 'from'")
+)
+
+const (
+	// DefaultPageSize is the number of records of a page when none is requested.
+	DefaultPageSize = 50
+	// MaxPageSize is the largest page that can be requested.
+	MaxPageSize = 200
+
+	// resourceKey is the key of the gin context holding the UUID of the audited resource.
+	resourceKey = "auditResourceUUID"
+)
+
+// timeNow returns the current time, replaced in the tests.
+var timeNow = time.Now
+
+// service struct holds the necessary dependencies for the audit service
+type service struct {
+	repo ports.AuditRepository
+}
+
+// NewService returns a new instance of the audit service with the given audit repository.
+func NewService(repo ports.AuditRepository) ports.AuditService {
+	return &service{
+		repo: repo,
+	}
+}
+
+// SetResource is the service hook to name the resource of the request when it is not in the path,
+// like the UUID of a newly created record. It is recorded by the audit middleware.
+func SetResource(c *gin.Context, resourceUUID uuid.UUID) {
+	if c != nil && resourceUUID != uuid.Nil {
+		c.Set(resourceKey, resourceUUID)
+	}
+}
+
+// ResourceFromContext returns the resource set with SetResource, or nil when none was set.
+func ResourceFromContext(c *gin.Context) *uuid.UUID {
+	if value, ok := c.Get(resourceKey); ok {
+		if resourceUUID, ok := value.(uuid.UUID); ok {
+			return &resourceUUID
+		}
+	}
+	return nil
+}
+
+// Record appends the action to the audit log, logging the failures.
+func (s *service) Record(auditLog *entity.AuditLog) {
+	if auditLog.CreatedAt.IsZero() {
+		auditLog.CreatedAt = timeNow()
+	}
+	if err := s.repo.CreateAuditLog(auditLog); err != nil {
+		log.Printf("error while recording the audit log: %s", err.Error())
+	}
+}
+
+// GetAuditLogs returns a page of the audit log filtered by user, resource and date.
+func (s *service) GetAuditLogs(request *entity.RequestAuditLogs) (*entity.AuditLogResult, int, error) {
+	filter, err := auditLogFilter(request)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	auditLogs, total, err := s.repo.FindAuditLogs(filter)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return &entity.AuditLogResult{
+		Logs:     auditLogs,
+		Page:     filter.Offset/filter.Limit + 1,
+		PageSize: filter.Limit,
+		Total:    total,
+	}, http.StatusOK, nil
+}
+
+// auditLogFilter validates the query parameters and builds the filter of the repository.
+func auditLogFilter(request *entity.RequestAuditLogs) (*entity.AuditLogFilter, error) {
+	if request == nil {
+		request = &entity.RequestAuditLogs{}
+	}
+	filter := &entity.AuditLogFilter{ResourceType: request.ResourceType}
+
+	if request.User != "" {
+		actorUUID, err := uuid.Parse(request.User)
+		if err != nil {
+			return nil, ErrInvalidUser
+		}
+		filter.ActorUUID = &actorUUID
+	}
+	if request.ResourceUUID != "" {
+		resourceUUID, err := uuid.Parse(request.ResourceUUID)
+		if err != nil {
+			return nil, ErrInvalidResourceUUID
+		}
+		filter.ResourceUUID = &resourceUUID
+	}
+
+	layout := "02/01/2006"
+	if request.From != "" {
+		from, err := time.Parse(layout, request.From)
+		if err != nil {
+			return nil, ErrInvalidDate
+		}
+		filter.From = &from
+	}
+	if request.To != "" {
+		to, err := time.Parse(layout, request.To)
+		if err != nil {
+			return nil, ErrInvalidDate
+		}
+		to = to.Add(24*time.Hour - time.Nanosecond)
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, ErrInvalidRange
+	}
+
+	filter.Limit = request.PageSize
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageSize
+	}
+	if filter.Limit > MaxPageSize {
+		filter.Limit = MaxPageSize
+	}
+	page := request.Page
+	if page < 1 {
+		page = 1
+	}
+	filter.Offset = (page - 1) * filter.Limit
+
+	return filter, nil
+}
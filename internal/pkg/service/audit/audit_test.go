@@ -0,0 +1,164 @@
+package audit_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/service/audit"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testUserUUID     = uuid.MustParse("24df3f36-ca63-11ed-afa1-0242ac120002")
+	testResourceUUID = uuid.MustParse("8c5c1b4e-2f6a-4b43-9a43-1d1f3a6f0c11")
+)
+
+// mockAuditRepository is an in-memory implementation of the AuditRepository interface for testing.
+type mockAuditRepository struct {
+	logs       []entity.AuditLog
+	lastFilter *entity.AuditLogFilter
+	err        error
+}
+
+func (m *mockAuditRepository) CreateAuditLog(auditLog *entity.AuditLog) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.logs = append(m.logs, *auditLog)
+	return nil
+}
+
+func (m *mockAuditRepository) FindAuditLogs(filter *entity.AuditLogFilter) ([]entity.AuditLog, int64, error) {
+	if m.err != nil {
+		return nil, 0, m.err
+	}
+	m.lastFilter = filter
+	return m.logs, int64(len(m.logs)), nil
+}
+
+func TestRecord(t *testing.T) {
+	repo := &mockAuditRepository{}
+	service := audit.NewService(repo)
+
+	service.Record(&entity.AuditLog{ActorUUID: &testUserUUID, Action: entity.AuditActionRead, Outcome: entity.AuditOutcomeSuccess})
+
+	require.Len(t, repo.logs, 1)
+	assert.Equal(t, entity.AuditActionRead, repo.logs[0].Action)
+	assert.False(t, repo.logs[0].CreatedAt.IsZero())
+}
+
+func TestRecordRepositoryError(t *testing.T) {
+	repo := &mockAuditRepository{err: errors.New("database error")}
+	service := audit.NewService(repo)
+
+	assert.NotPanics(t, func() {
+		service.Record(&entity.AuditLog{Action: entity.AuditActionRead})
+	})
+}
+
+func TestGetAuditLogsFilter(t *testing.T) {
+	repo := &mockAuditRepository{}
+	service := audit.NewService(repo)
+
+	result, status, err := service.GetAuditLogs(&entity.RequestAuditLogs{
+		User:         testUserUUID.String(),
+		ResourceType: entity.AuditResourceTreatment,
+		ResourceUUID: testResourceUUID.String(),
+		From:         "01/07/2023",
+		To:           "31/07/2023",
+		Page:         3,
+		PageSize:     20,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 3, result.Page)
+	assert.Equal(t, 20, result.PageSize)
+
+	filter := repo.lastFilter
+	require.NotNil(t, filter)
+	assert.Equal(t, testUserUUID, *filter.ActorUUID)
+	assert.Equal(t, testResourceUUID, *filter.ResourceUUID)
+	assert.Equal(t, entity.AuditResourceTreatment, filter.ResourceType)
+	assert.Equal(t, time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), *filter.From)
+	assert.True(t, filter.To.After(time.Date(2023, 7, 31, 23, 59, 0, 0, time.UTC)))
+	assert.True(t, filter.To.Before(time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 20, filter.Limit)
+	assert.Equal(t, 40, filter.Offset)
+}
+
+func TestGetAuditLogsDefaultPage(t *testing.T) {
+	repo := &mockAuditRepository{}
+	service := audit.NewService(repo)
+
+	result, status, err := service.GetAuditLogs(&entity.RequestAuditLogs{})
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 1, result.Page)
+	assert.Equal(t, audit.DefaultPageSize, repo.lastFilter.Limit)
+	assert.Equal(t, 0, repo.lastFilter.Offset)
+	assert.Nil(t, repo.lastFilter.ActorUUID)
+	assert.Nil(t, repo.lastFilter.From)
+
+	_, _, err = service.GetAuditLogs(&entity.RequestAuditLogs{PageSize: 10000})
+	require.NoError(t, err)
+	assert.Equal(t, audit.MaxPageSize, repo.lastFilter.Limit)
+}
+
+func TestGetAuditLogsInvalidRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *entity.RequestAuditLogs
+		err     error
+	}{
+		{"invalid user", &entity.RequestAuditLogs{User: "not-a-uuid"}, audit.ErrInvalidUser},
+		{"invalid resource", &entity.RequestAuditLogs{ResourceUUID: "not-a-uuid"}, audit.ErrInvalidResourceUUID},
+		{"invalid from", &entity.RequestAuditLogs{From: "2023-07-01"}, audit.ErrInvalidDate},
+		{"invalid to", &entity.RequestAuditLogs{To: "31/13/2023"}, audit.ErrInvalidDate},
+		{"inverted range", &entity.RequestAuditLogs{From: "02/07/2023", To: "01/07/2023"}, audit.ErrInvalidRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := audit.NewService(&mockAuditRepository{})
+
+			_, status, err := service.GetAuditLogs(tt.request)
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+	}
+}
+
+func TestGetAuditLogsRepositoryError(t *testing.T) {
+	service := audit.NewService(&mockAuditRepository{err: errors.New("database error")})
+
+	_, status, err := service.GetAuditLogs(&entity.RequestAuditLogs{})
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, status)
+}
+
+func TestSetResource(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	assert.Nil(t, audit.ResourceFromContext(c))
+
+	audit.SetResource(c, uuid.Nil)
+	assert.Nil(t, audit.ResourceFromContext(c))
+
+	audit.SetResource(c, testResourceUUID)
+	require.NotNil(t, audit.ResourceFromContext(c))
+	assert.Equal(t, testResourceUUID, *audit.ResourceFromContext(c))
+
+	assert.NotPanics(t, func() { audit.SetResource(nil, testResourceUUID) })
+}
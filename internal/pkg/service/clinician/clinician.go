@@ -0,0 +1,415 @@
+package clinician
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/fieldcrypt"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTypeAssertionFailed = errors.New("type assertion failed")
+	ErrFindingUser         = errors.New("user not found")
+	ErrMedicalNotFound     = errors.New("medical professional not found")
+	ErrAlreadyLinked       = errors.New("the user or the medical professional is already linked to a clinician")
+	ErrClinicianNotFound   = errors.New("clinician not found")
+	ErrNotClinician        = errors.New("the user is not linked to a medical professional")
+	ErrSelfConsent         = errors.New("a clinician cannot consent to share data with themselves")
+	ErrInvalidScope        = errors.New("invalid consent scope")
+	ErrMissingScopes       = errors.New("at least one consent scope is required")
+	ErrInvalidExpiration   = errors.New("the consent must expire in the future and within one year")
+	ErrConsentNotFound     = errors.New("consent not found")
+	ErrNoConsent           = errors.New("the patient has not consented to share this data")
+)
+
+const (
+	// MaxConsentDuration is the longest a patient can share their data before consenting again.
+	MaxConsentDuration = 366 * 24 * time.Hour
+	// AccessLogLimit is the number of accesses returned to the patient.
+	AccessLogLimit = 200
+)
+
+// timeNow returns the current time, replaced in the tests.
+var timeNow = time.Now
+
+// service struct holds the necessary dependencies for the clinician service
+type service struct {
+	repo                 ports.ClinicianRepository
+	monitoringService    ports.MonitoringService
+	treatmentService     ports.TreatmentService
+	medicalRecordService ports.MedicalRecordService
+}
+
+// NewService returns a new instance of the clinician service. The patient data is read through the
+// monitoring, treatment and medical record services once the consent is verified.
+func NewService(repo ports.ClinicianRepository, monitoringService ports.MonitoringService, treatmentService ports.TreatmentService, medicalRecordService ports.MedicalRecordService) ports.ClinicianService {
+	return &service{
+		repo:                 repo,
+		monitoringService:    monitoringService,
+		treatmentService:     treatmentService,
+		medicalRecordService: medicalRecordService,
+	}
+}
+
+// LinkClinician links the user to the medical professional and grants them the clinician role.
+func (s *service) LinkClinician(userUUID uuid.UUID, linkReq *entity.RequestLinkClinician) (*entity.Clinician, int, error) {
+	if linkReq == nil {
+		return nil, http.StatusBadRequest, errors.New("request payload is nil")
+	}
+
+	user, status, err := s.findUser(userUUID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	medical := &entity.Medical{}
+	if err := s.repo.First(medical, "id = ?", linkReq.MedicalID); err != nil {
+		return nil, http.StatusNotFound, ErrMedicalNotFound
+	}
+
+	var linked []entity.Clinician
+	if err := s.repo.Find(&linked, "user_id = ? OR medical_id = ?", user.ID, medical.ID); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if len(linked) > 0 {
+		return nil, http.StatusConflict, ErrAlreadyLinked
+	}
+
+	// The clinician is only created along with the role, a user is never left linked without it
+	clinician := &entity.Clinician{UserID: user.ID, MedicalID: medical.ID}
+	if err := s.repo.CreateClinician(clinician, entity.RoleClinician); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	clinician.Medical = medical
+	return clinician, http.StatusCreated, nil
+}
+
+// GetClinicians returns every clinician with their medical professional.
+func (s *service) GetClinicians() ([]entity.Clinician, error) {
+	return s.repo.FindClinicians()
+}
+
+// GrantConsent shares the requested scopes with the clinician, revoking the previous consent to the same clinician.
+func (s *service) GrantConsent(patientUUID uuid.UUID, consentReq *entity.RequestCreateConsent) (*entity.PatientConsent, int, error) {
+	if consentReq == nil {
+		return nil, http.StatusBadRequest, errors.New("request payload is nil")
+	}
+
+	scopes, err := consentScopes(consentReq.Scopes)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	now := timeNow()
+	if !consentReq.ExpiresAt.After(now) || consentReq.ExpiresAt.Sub(now) > MaxConsentDuration {
+		return nil, http.StatusBadRequest, ErrInvalidExpiration
+	}
+
+	patient, status, err := s.findUser(patientUUID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	foundClinician, err := s.repo.FindByUUID(consentReq.ClinicianUUID, &entity.Clinician{})
+	if err != nil {
+		return nil, http.StatusNotFound, ErrClinicianNotFound
+	}
+	clinician, ok := foundClinician.(*entity.Clinician)
+	if !ok {
+		return nil, http.StatusInternalServerError, ErrTypeAssertionFailed
+	}
+	if clinician.UserID == patient.ID {
+		return nil, http.StatusBadRequest, ErrSelfConsent
+	}
+
+	// A patient holds a single active consent per clinician, the new scopes and expiration replace the previous ones.
+	if _, err := s.repo.RevokeConsents(patient.ID, now, map[string]interface{}{"clinician_id": clinician.ID}); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	consent := &entity.PatientConsent{
+		PatientID:   patient.ID,
+		ClinicianID: clinician.ID,
+		Scopes:      scopes,
+		ExpiresAt:   consentReq.ExpiresAt,
+	}
+	if err := s.repo.CreateWithOmit("uuid", consent); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	consent.Clinician = clinician
+	return consent, http.StatusCreated, nil
+}
+
+// GetConsents returns every consent granted by the patient.
+func (s *service) GetConsents(patientUUID uuid.UUID) ([]entity.PatientConsent, int, error) {
+	patient, status, err := s.findUser(patientUUID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	consents, err := s.repo.FindPatientConsents(patient.ID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return consents, http.StatusOK, nil
+}
+
+// RevokeConsent revokes an active consent of the patient.
+func (s *service) RevokeConsent(patientUUID uuid.UUID, consentUUID uuid.UUID) (int, error) {
+	patient, status, err := s.findUser(patientUUID)
+	if err != nil {
+		return status, err
+	}
+
+	revoked, err := s.repo.RevokeConsents(patient.ID, timeNow(), map[string]interface{}{"uuid": consentUUID})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if revoked == 0 {
+		return http.StatusNotFound, ErrConsentNotFound
+	}
+
+	return http.StatusOK, nil
+}
+
+// GetAccessLog returns the latest accesses of the clinicians to the data of the patient.
+func (s *service) GetAccessLog(patientUUID uuid.UUID) ([]entity.PatientAccessLog, int, error) {
+	patient, status, err := s.findUser(patientUUID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	accessLogs, err := s.repo.FindAccessLogs(patient.ID, AccessLogLimit)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return accessLogs, http.StatusOK, nil
+}
+
+// GetPatients returns the patients with an active consent to the clinician, recording the access to each of them.
+func (s *service) GetPatients(clinicianUUID uuid.UUID, ip string) ([]*entity.ConsentingPatient, int, error) {
+	clinician, status, err := s.findClinician(clinicianUUID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	consents, err := s.repo.FindActiveConsents(clinician.ID, timeNow())
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if len(consents) == 0 {
+		return []*entity.ConsentingPatient{}, http.StatusOK, nil
+	}
+
+	patientIDs := make([]int, 0, len(consents))
+	for _, consent := range consents {
+		patientIDs = append(patientIDs, consent.PatientID)
+	}
+	var users []entity.User
+	if err := s.repo.Find(&users, "id IN ?", patientIDs); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	usersByID := make(map[int]*entity.User, len(users))
+	for i := range users {
+		usersByID[users[i].ID] = &users[i]
+	}
+
+	keyring, err := fieldcrypt.FromConfig(config.Get())
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	patients := make([]*entity.ConsentingPatient, 0, len(consents))
+	for i := range consents {
+		user, ok := usersByID[consents[i].PatientID]
+		if !ok {
+			continue
+		}
+		firstName, err := keyring.Decrypt(fieldcrypt.UserField("first_name", user.UUID), user.FirstName)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		lastName, err := keyring.Decrypt(fieldcrypt.UserField("last_name", user.UUID), user.LastName)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+
+		s.recordAccess(clinician, user.ID, &consents[i], entity.AccessResourcePatient, nil, ip)
+		patients = append(patients, &entity.ConsentingPatient{
+			UUID:      user.UUID,
+			FirstName: firstName,
+			LastName:  lastName,
+			Scopes:    consents[i].Scopes,
+			ExpiresAt: consents[i].ExpiresAt,
+		})
+	}
+
+	return patients, http.StatusOK, nil
+}
+
+// GetPatientSymptomTrends returns the monitoring analytics of a consenting patient.
+func (s *service) GetPatientSymptomTrends(c *gin.Context, clinicianUUID uuid.UUID, patientUUID uuid.UUID, analyticsReq *entity.RequestMonitoringAnalytics, ip string) ([]*entity.SymptomAnalytics, int, error) {
+	if analyticsReq == nil {
+		return nil, http.StatusBadRequest, errors.New("request payload is nil")
+	}
+
+	patient, status, err := s.authorizeAccess(clinicianUUID, patientUUID, entity.ConsentScopeSymptoms, entity.AccessResourceSymptomTrends, nil, ip)
+	if err != nil {
+		return nil, status, err
+	}
+
+	if len(analyticsReq.SymptomUUIDs) == 0 {
+		if analyticsReq.SymptomUUIDs, err = s.repo.FindUserSymptomUUIDs(patient.ID); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+	}
+
+	return s.monitoringService.GetMonitoringAnalytics(c, patientUUID, analyticsReq)
+}
+
+// GetPatientTreatments returns the treatments of a consenting patient.
+func (s *service) GetPatientTreatments(clinicianUUID uuid.UUID, patientUUID uuid.UUID, ip string) ([]*entity.Treatment, int, error) {
+	if _, status, err := s.authorizeAccess(clinicianUUID, patientUUID, entity.ConsentScopeTreatments, entity.AccessResourceTreatments, nil, ip); err != nil {
+		return nil, status, err
+	}
+
+	treatments, err := s.treatmentService.GetAllTreatments(patientUUID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return treatments, http.StatusOK, nil
+}
+
+// GetPatientAdherence returns the adherence report of a treatment of a consenting patient.
+func (s *service) GetPatientAdherence(clinicianUUID uuid.UUID, patientUUID uuid.UUID, treatmentUUID uuid.UUID, from time.Time, to time.Time, ip string) (*entity.AdherenceReport, int, error) {
+	if _, status, err := s.authorizeAccess(clinicianUUID, patientUUID, entity.ConsentScopeAdherence, entity.AccessResourceAdherence, &treatmentUUID, ip); err != nil {
+		return nil, status, err
+	}
+
+	// The treatment service verifies that the treatment belongs to the patient.
+	return s.treatmentService.GetAdherence(patientUUID, treatmentUUID, from, to)
+}
+
+// GetPatientMedicalRecord returns the medical record of a consenting patient.
+func (s *service) GetPatientMedicalRecord(c *gin.Context, clinicianUUID uuid.UUID, patientUUID uuid.UUID, ip string) (*entity.MedicalRecord, int, error) {
+	if _, status, err := s.authorizeAccess(clinicianUUID, patientUUID, entity.ConsentScopeMedicalRecord, entity.AccessResourceMedicalRecord, nil, ip); err != nil {
+		return nil, status, err
+	}
+
+	return s.medicalRecordService.GetMedicalRecord(c, patientUUID)
+}
+
+// authorizeAccess verifies that the patient granted the clinician an active consent including the scope,
+// recording the access whether it is granted or not.
+func (s *service) authorizeAccess(clinicianUUID uuid.UUID, patientUUID uuid.UUID, scope string, resource string, resourceUUID *uuid.UUID, ip string) (*entity.User, int, error) {
+	clinician, status, err := s.findClinician(clinicianUUID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	// An unknown patient gets the same answer as a patient without consent, so clinicians cannot probe the users.
+	patient, _, err := s.findUser(patientUUID)
+	if err != nil {
+		return nil, http.StatusForbidden, ErrNoConsent
+	}
+
+	consents, err := s.repo.FindActiveConsents(clinician.ID, timeNow())
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	for i := range consents {
+		if consents[i].PatientID == patient.ID && consents[i].Scopes.Has(scope) {
+			s.recordAccess(clinician, patient.ID, &consents[i], resource, resourceUUID, ip)
+			return patient, http.StatusOK, nil
+		}
+	}
+
+	s.recordAccess(clinician, patient.ID, nil, resource, resourceUUID, ip)
+	return nil, http.StatusForbidden, ErrNoConsent
+}
+
+// recordAccess appends the access to the patient data to the log. The access is granted when it was
+// covered by a consent.
+func (s *service) recordAccess(clinician *entity.Clinician, patientID int, consent *entity.PatientConsent, resource string, resourceUUID *uuid.UUID, ip string) {
+	accessLog := &entity.PatientAccessLog{
+		ClinicianID:  clinician.ID,
+		PatientID:    patientID,
+		Resource:     resource,
+		ResourceUUID: resourceUUID,
+		Granted:      consent != nil,
+		IP:           ip,
+		CreatedAt:    timeNow(),
+	}
+	if consent != nil {
+		accessLog.ConsentID = &consent.ID
+	}
+
+	if err := s.repo.CreateAccessLog(accessLog); err != nil {
+		log.Printf("error while recording the access to the patient data: %s", err.Error())
+	}
+}
+
+// findUser retrieves the user with the given UUID.
+func (s *service) findUser(userUUID uuid.UUID) (*entity.User, int, error) {
+	foundUser, err := s.repo.FindByUUID(userUUID, &entity.User{})
+	if err != nil {
+		return nil, http.StatusNotFound, ErrFindingUser
+	}
+	user, ok := foundUser.(*entity.User)
+	if !ok {
+		return nil, http.StatusInternalServerError, ErrTypeAssertionFailed
+	}
+	return user, http.StatusOK, nil
+}
+
+// findClinician retrieves the clinician linked to the user with the given UUID.
+func (s *service) findClinician(userUUID uuid.UUID) (*entity.Clinician, int, error) {
+	user, status, err := s.findUser(userUUID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	clinician := &entity.Clinician{}
+	if err := s.repo.First(clinician, "user_id = ?", user.ID); err != nil {
+		return nil, http.StatusForbidden, ErrNotClinician
+	}
+	return clinician, http.StatusOK, nil
+}
+
+// consentScopes validates the scopes against the catalog, ignoring the duplicates.
+func consentScopes(requested []string) (entity.ConsentScopes, error) {
+	scopes := entity.ConsentScopes{}
+	for _, scope := range requested {
+		valid := false
+		for _, known := range entity.ConsentScopeList {
+			if scope == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+		if !scopes.Has(scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, ErrMissingScopes
+	}
+	return scopes, nil
+}
@@ -0,0 +1,346 @@
+package clinician
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testPatientUuid   = uuid.MustParse("24df3f36-ca63-11ed-afa1-0242ac120002")
+	testClinicianUuid = uuid.MustParse("5b0f7c3a-2d4e-4f1a-9b8c-7d6e5f4a3b2c")
+	testOtherUuid     = uuid.MustParse("9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c6b")
+	testTreatmentUuid = uuid.MustParse("0f5b8c1e-6a1f-4f0e-9d3a-6a7b8c9d0e1f")
+	testSymptomUuid   = uuid.MustParse("7c2d1e0f-3b4a-4c5d-8e9f-0a1b2c3d4e5f")
+)
+
+// mockClinicianRepository keeps the clinicians, consents and access logs in memory.
+// The users 1 and 3 are patients and the user 2 is linked to the medical professional 10.
+type mockClinicianRepository struct {
+	users      map[uuid.UUID]*entity.User
+	clinicians []*entity.Clinician
+	consents   []*entity.PatientConsent
+	accessLogs []*entity.PatientAccessLog
+	// roles are the names of the roles granted to the users along with their clinician.
+	roles map[int]string
+}
+
+func newMockClinicianRepository() *mockClinicianRepository {
+	return &mockClinicianRepository{
+		users: map[uuid.UUID]*entity.User{
+			testPatientUuid:   {ID: 1, UUID: testPatientUuid},
+			testClinicianUuid: {ID: 2, UUID: testClinicianUuid},
+			testOtherUuid:     {ID: 3, UUID: testOtherUuid},
+		},
+		clinicians: []*entity.Clinician{{ID: 1, UUID: uuid.New(), UserID: 2, MedicalID: 10}},
+		roles:      map[int]string{},
+	}
+}
+
+func (m *mockClinicianRepository) FindByUUID(id uuid.UUID, out interface{}) (interface{}, error) {
+	switch out := out.(type) {
+	case *entity.User:
+		if user, ok := m.users[id]; ok {
+			*out = *user
+			return out, nil
+		}
+	case *entity.Clinician:
+		for _, clinician := range m.clinicians {
+			if clinician.UUID == id {
+				*out = *clinician
+				return out, nil
+			}
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *mockClinicianRepository) CreateClinician(clinician *entity.Clinician, roleName string) error {
+	if err := m.CreateWithOmit("uuid", clinician); err != nil {
+		return err
+	}
+	m.roles[clinician.UserID] = roleName
+	return nil
+}
+
+func (m *mockClinicianRepository) CreateWithOmit(omit string, value interface{}) error {
+	switch value := value.(type) {
+	case *entity.Clinician:
+		value.ID = len(m.clinicians) + 1
+		value.UUID = uuid.New()
+		stored := *value
+		m.clinicians = append(m.clinicians, &stored)
+	case *entity.PatientConsent:
+		value.ID = len(m.consents) + 1
+		value.UUID = uuid.New()
+		stored := *value
+		m.consents = append(m.consents, &stored)
+	}
+	return nil
+}
+
+func (m *mockClinicianRepository) First(out interface{}, conditions ...interface{}) error {
+	switch out := out.(type) {
+	case *entity.Medical:
+		if conditions[1] == int64(10) || conditions[1] == int64(11) {
+			out.ID = conditions[1].(int64)
+			return nil
+		}
+	case *entity.Clinician:
+		for _, clinician := range m.clinicians {
+			if clinician.UserID == conditions[1] {
+				*out = *clinician
+				return nil
+			}
+		}
+	}
+	return errors.New("record not found")
+}
+
+func (m *mockClinicianRepository) Find(out interface{}, conditions ...interface{}) error {
+	if clinicians, ok := out.(*[]entity.Clinician); ok {
+		for _, clinician := range m.clinicians {
+			if clinician.UserID == conditions[1] || clinician.MedicalID == conditions[2] {
+				*clinicians = append(*clinicians, *clinician)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *mockClinicianRepository) FindClinicians() ([]entity.Clinician, error) {
+	return nil, nil
+}
+
+func (m *mockClinicianRepository) FindActiveConsents(clinicianID int, now time.Time) ([]entity.PatientConsent, error) {
+	var consents []entity.PatientConsent
+	for _, consent := range m.consents {
+		if consent.ClinicianID == clinicianID && consent.IsActive(now) {
+			consents = append(consents, *consent)
+		}
+	}
+	return consents, nil
+}
+
+func (m *mockClinicianRepository) FindPatientConsents(patientID int) ([]entity.PatientConsent, error) {
+	return nil, nil
+}
+
+func (m *mockClinicianRepository) RevokeConsents(patientID int, revokedAt time.Time, conditions map[string]interface{}) (int64, error) {
+	var revoked int64
+	for _, consent := range m.consents {
+		if consent.PatientID != patientID || consent.RevokedAt != nil {
+			continue
+		}
+		if id, ok := conditions["clinician_id"]; ok && consent.ClinicianID != id {
+			continue
+		}
+		if id, ok := conditions["uuid"]; ok && consent.UUID != id {
+			continue
+		}
+		at := revokedAt
+		consent.RevokedAt = &at
+		revoked++
+	}
+	return revoked, nil
+}
+
+func (m *mockClinicianRepository) CreateAccessLog(accessLog *entity.PatientAccessLog) error {
+	m.accessLogs = append(m.accessLogs, accessLog)
+	return nil
+}
+
+func (m *mockClinicianRepository) FindAccessLogs(patientID int, limit int) ([]entity.PatientAccessLog, error) {
+	return nil, nil
+}
+
+func (m *mockClinicianRepository) FindUserSymptomUUIDs(userID int) ([]uuid.UUID, error) {
+	return []uuid.UUID{testSymptomUuid}, nil
+}
+
+// mockTreatmentService returns a treatment for any patient.
+type mockTreatmentService struct {
+	ports.TreatmentService
+}
+
+func (m *mockTreatmentService) GetAllTreatments(userUUID uuid.UUID) ([]*entity.Treatment, error) {
+	return []*entity.Treatment{{UUID: testTreatmentUuid}}, nil
+}
+
+func (m *mockTreatmentService) GetAdherence(userUUID uuid.UUID, treatmentUUID uuid.UUID, from time.Time, to time.Time) (*entity.AdherenceReport, int, error) {
+	return &entity.AdherenceReport{}, http.StatusOK, nil
+}
+
+// mockMonitoringService returns the analytics of the requested symptoms.
+type mockMonitoringService struct {
+	ports.MonitoringService
+}
+
+func (m *mockMonitoringService) GetMonitoringAnalytics(c *gin.Context, userUUID uuid.UUID, analyticsReq *entity.RequestMonitoringAnalytics) ([]*entity.SymptomAnalytics, int, error) {
+	analytics := []*entity.SymptomAnalytics{}
+	for _, symptomUUID := range analyticsReq.SymptomUUIDs {
+		analytics = append(analytics, &entity.SymptomAnalytics{SymptomUUID: symptomUUID})
+	}
+	return analytics, http.StatusOK, nil
+}
+
+func newTestService() (*service, *mockClinicianRepository) {
+	repo := newMockClinicianRepository()
+	s := NewService(repo, &mockMonitoringService{}, &mockTreatmentService{}, nil).(*service)
+	return s, repo
+}
+
+func grant(t *testing.T, s *service, repo *mockClinicianRepository, scopes ...string) *entity.PatientConsent {
+	consent, status, err := s.GrantConsent(testPatientUuid, &entity.RequestCreateConsent{
+		ClinicianUUID: repo.clinicians[0].UUID,
+		Scopes:        scopes,
+		ExpiresAt:     time.Now().Add(30 * 24 * time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, status)
+	return consent
+}
+
+func TestLinkClinician(t *testing.T) {
+	s, repo := newTestService()
+
+	clinician, status, err := s.LinkClinician(testOtherUuid, &entity.RequestLinkClinician{MedicalID: 11})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, 3, clinician.UserID)
+	assert.Equal(t, entity.RoleClinician, repo.roles[3])
+	assert.Len(t, repo.clinicians, 2)
+
+	_, status, err = s.LinkClinician(testPatientUuid, &entity.RequestLinkClinician{MedicalID: 10})
+	assert.ErrorIs(t, err, ErrAlreadyLinked)
+	assert.Equal(t, http.StatusConflict, status)
+
+	_, status, err = s.LinkClinician(testPatientUuid, &entity.RequestLinkClinician{MedicalID: 99})
+	assert.ErrorIs(t, err, ErrMedicalNotFound)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestGrantConsentValidation(t *testing.T) {
+	s, repo := newTestService()
+	clinicianUUID := repo.clinicians[0].UUID
+	expiresAt := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name        string
+		patientUUID uuid.UUID
+		request     *entity.RequestCreateConsent
+		err         error
+	}{
+		{"unknown scope", testPatientUuid, &entity.RequestCreateConsent{ClinicianUUID: clinicianUUID, Scopes: []string{"reminders"}, ExpiresAt: expiresAt}, ErrInvalidScope},
+		{"missing scopes", testPatientUuid, &entity.RequestCreateConsent{ClinicianUUID: clinicianUUID, ExpiresAt: expiresAt}, ErrMissingScopes},
+		{"expired", testPatientUuid, &entity.RequestCreateConsent{ClinicianUUID: clinicianUUID, Scopes: []string{entity.ConsentScopeSymptoms}, ExpiresAt: time.Now().Add(-time.Hour)}, ErrInvalidExpiration},
+		{"over a year", testPatientUuid, &entity.RequestCreateConsent{ClinicianUUID: clinicianUUID, Scopes: []string{entity.ConsentScopeSymptoms}, ExpiresAt: time.Now().AddDate(2, 0, 0)}, ErrInvalidExpiration},
+		{"self consent", testClinicianUuid, &entity.RequestCreateConsent{ClinicianUUID: clinicianUUID, Scopes: []string{entity.ConsentScopeSymptoms}, ExpiresAt: expiresAt}, ErrSelfConsent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, status, err := s.GrantConsent(tt.patientUUID, tt.request)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+	}
+	assert.Empty(t, repo.consents)
+}
+
+func TestGrantConsentReplacesPrevious(t *testing.T) {
+	s, repo := newTestService()
+
+	first := grant(t, s, repo, entity.ConsentScopeSymptoms)
+	second := grant(t, s, repo, entity.ConsentScopeTreatments, entity.ConsentScopeTreatments)
+
+	assert.NotNil(t, repo.consents[first.ID-1].RevokedAt)
+	assert.Nil(t, repo.consents[second.ID-1].RevokedAt)
+	assert.Equal(t, entity.ConsentScopes{entity.ConsentScopeTreatments}, second.Scopes)
+}
+
+func TestClinicianAccessRequiresConsent(t *testing.T) {
+	s, repo := newTestService()
+
+	_, status, err := s.GetPatientTreatments(testClinicianUuid, testPatientUuid, "10.0.0.1")
+	assert.ErrorIs(t, err, ErrNoConsent)
+	assert.Equal(t, http.StatusForbidden, status)
+
+	consent := grant(t, s, repo, entity.ConsentScopeTreatments)
+
+	treatments, status, err := s.GetPatientTreatments(testClinicianUuid, testPatientUuid, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, treatments, 1)
+
+	// The consent does not include the adherence.
+	_, status, err = s.GetPatientAdherence(testClinicianUuid, testPatientUuid, testTreatmentUuid, time.Now().AddDate(0, 0, -7), time.Now(), "10.0.0.1")
+	assert.ErrorIs(t, err, ErrNoConsent)
+	assert.Equal(t, http.StatusForbidden, status)
+
+	// Another patient did not consent.
+	_, _, err = s.GetPatientTreatments(testClinicianUuid, testOtherUuid, "10.0.0.1")
+	assert.ErrorIs(t, err, ErrNoConsent)
+
+	require.Len(t, repo.accessLogs, 4)
+	assert.False(t, repo.accessLogs[0].Granted)
+	assert.True(t, repo.accessLogs[1].Granted)
+	assert.Equal(t, consent.ID, *repo.accessLogs[1].ConsentID)
+	assert.Equal(t, entity.AccessResourceTreatments, repo.accessLogs[1].Resource)
+	assert.Equal(t, "10.0.0.1", repo.accessLogs[1].IP)
+	assert.Equal(t, entity.AccessResourceAdherence, repo.accessLogs[2].Resource)
+	assert.Equal(t, testTreatmentUuid, *repo.accessLogs[2].ResourceUUID)
+	assert.False(t, repo.accessLogs[2].Granted)
+	assert.Equal(t, 3, repo.accessLogs[3].PatientID)
+}
+
+func TestClinicianAccessAfterRevocationAndExpiration(t *testing.T) {
+	s, repo := newTestService()
+
+	consent := grant(t, s, repo, entity.ConsentScopeTreatments)
+	status, err := s.RevokeConsent(testPatientUuid, consent.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+
+	_, _, err = s.GetPatientTreatments(testClinicianUuid, testPatientUuid, "")
+	assert.ErrorIs(t, err, ErrNoConsent)
+
+	status, err = s.RevokeConsent(testPatientUuid, consent.UUID)
+	assert.ErrorIs(t, err, ErrConsentNotFound)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	grant(t, s, repo, entity.ConsentScopeTreatments)
+	original := timeNow
+	timeNow = func() time.Time { return time.Now().AddDate(0, 2, 0) }
+	defer func() { timeNow = original }()
+
+	_, _, err = s.GetPatientTreatments(testClinicianUuid, testPatientUuid, "")
+	assert.ErrorIs(t, err, ErrNoConsent)
+}
+
+func TestClinicianAccessNotClinician(t *testing.T) {
+	s, _ := newTestService()
+
+	_, status, err := s.GetPatientTreatments(testOtherUuid, testPatientUuid, "")
+	assert.ErrorIs(t, err, ErrNotClinician)
+	assert.Equal(t, http.StatusForbidden, status)
+}
+
+func TestGetPatientSymptomTrendsDefaultsToFollowedSymptoms(t *testing.T) {
+	s, repo := newTestService()
+	grant(t, s, repo, entity.ConsentScopeSymptoms)
+
+	analytics, status, err := s.GetPatientSymptomTrends(nil, testClinicianUuid, testPatientUuid, &entity.RequestMonitoringAnalytics{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	require.Len(t, analytics, 1)
+	assert.Equal(t, testSymptomUuid, analytics[0].SymptomUUID)
+}
@@ -0,0 +1,265 @@
+package erasure
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUserNotFound      = errors.New("user not found")
+	ErrErasureNotFound   = errors.New("no account deletion is scheduled")
+	ErrCreatingErasure   = errors.New("error scheduling the account deletion")
+	ErrTypeAssertion     = errors.New("type assertion failed")
+	ErrFindingErasures   = errors.New("error finding the due account deletions")
+	ErrRevokingTokens    = errors.New("error signing out the sessions of the user")
+	ErrCancellingErasure = errors.New("error cancelling the account deletion")
+)
+
+const (
+	// GracePeriod is the time during which the user can cancel the deletion of their account.
+	GracePeriod = 30 * 24 * time.Hour
+
+	// erasureBatchSize is the number of accounts erased on each run of the worker.
+	erasureBatchSize = 20
+	// anonymousEmailDomain is the reserved domain of the emails of the erased accounts.
+	anonymousEmailDomain = "erased.invalid"
+)
+
+// timeNow returns the current time, replaced in the tests.
+var timeNow = time.Now
+
+// service struct holds the necessary dependencies for the erasure service
+type service struct {
+	repo      ports.ErasureRepository
+	tokenRepo ports.TokenRepository
+	mailer    ports.Mailer
+	store     ports.BlobStore
+}
+
+// NewService returns a new instance of the erasure service with the given repositories, mailer and blob store.
+func NewService(repo ports.ErasureRepository, tokenRepo ports.TokenRepository, mailer ports.Mailer, store ports.BlobStore) ports.ErasureService {
+	return &service{
+		repo:      repo,
+		tokenRepo: tokenRepo,
+		mailer:    mailer,
+		store:     store,
+	}
+}
+
+// RequestErasure schedules the erasure of the account of the user at the end of the grace period
+// and revokes the tokens of the user, signing out every session. Requesting it again returns
+// the erasure already scheduled.
+func (s *service) RequestErasure(userUUID uuid.UUID) (*entity.AccountErasure, int, error) {
+	user, status, err := s.findUser(userUUID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	erasure, err := s.repo.FindScheduledErasure(user.ID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if erasure != nil {
+		return erasure, http.StatusAccepted, nil
+	}
+
+	now := timeNow().UTC()
+	erasure = &entity.AccountErasure{
+		UserID:      user.ID,
+		UserUUID:    user.UUID,
+		Status:      entity.ErasureStatusScheduled,
+		RequestedAt: now,
+		ScheduledAt: now.Add(GracePeriod),
+	}
+	if err := s.repo.CreateErasure(erasure); err != nil {
+		return nil, http.StatusInternalServerError, ErrCreatingErasure
+	}
+
+	if err := s.tokenRepo.RevokeUserRefreshTokens(user.ID, now); err != nil {
+		return nil, http.StatusInternalServerError, ErrRevokingTokens
+	}
+	// The access tokens already issued are rejected too, the sessions end right away
+	if err := s.tokenRepo.SetTokensValidAfter(user.ID, now); err != nil {
+		return nil, http.StatusInternalServerError, ErrRevokingTokens
+	}
+
+	s.sendEmail(user.Email, "Your account will be deleted", fmt.Sprintf(
+		"We received a request to delete your account.\n\n"+
+			"Your account and your personal data will be deleted on %s. Until then, you can sign in and cancel the deletion.\n\n"+
+			"If you did not request it, sign in and cancel the deletion right away, then change your password.",
+		erasure.ScheduledAt.Format("02/01/2006")))
+
+	return erasure, http.StatusAccepted, nil
+}
+
+// GetErasure retrieves the scheduled erasure of the account of the user.
+func (s *service) GetErasure(userUUID uuid.UUID) (*entity.AccountErasure, int, error) {
+	user, status, err := s.findUser(userUUID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	erasure, err := s.repo.FindScheduledErasure(user.ID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if erasure == nil {
+		return nil, http.StatusNotFound, ErrErasureNotFound
+	}
+	return erasure, http.StatusOK, nil
+}
+
+// CancelErasure cancels the scheduled erasure of the account of the user during the grace period.
+func (s *service) CancelErasure(userUUID uuid.UUID) (int, error) {
+	erasure, status, err := s.GetErasure(userUUID)
+	if err != nil {
+		return status, err
+	}
+
+	now := timeNow().UTC()
+	erasure.Status = entity.ErasureStatusCancelled
+	erasure.CancelledAt = &now
+	if err := s.repo.UpdateErasure(erasure); err != nil {
+		return http.StatusInternalServerError, ErrCancellingErasure
+	}
+	return http.StatusOK, nil
+}
+
+// GetUserErasures retrieves every erasure request of the user with its report.
+// The user is looked up by UUID in the erasures, so the requests of the erased accounts are found too.
+func (s *service) GetUserErasures(userUUID uuid.UUID) ([]entity.AccountErasure, int, error) {
+	erasures, err := s.repo.FindErasures(userUUID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if len(erasures) == 0 {
+		return nil, http.StatusNotFound, ErrErasureNotFound
+	}
+	return erasures, http.StatusOK, nil
+}
+
+// ProcessDueErasures erases the accounts whose grace period ended before now. The uploaded media are deleted
+// from the bucket once the records are erased, the files that could not be deleted being listed in the report.
+// An erasure failing is kept scheduled with its error, so it is retried on the next run.
+func (s *service) ProcessDueErasures(now time.Time) (int, error) {
+	erasures, err := s.repo.FindDueErasures(now, erasureBatchSize)
+	if err != nil {
+		return 0, ErrFindingErasures
+	}
+
+	erased := 0
+	for i := range erasures {
+		if err := s.erase(&erasures[i]); err != nil {
+			log.Printf("error while erasing the account of the user %s: %s", erasures[i].UserUUID, err.Error())
+			erasures[i].Error = err.Error()
+			if err := s.repo.UpdateErasure(&erasures[i]); err != nil {
+				log.Printf("error while saving the erasure %s: %s", erasures[i].UUID, err.Error())
+			}
+			continue
+		}
+		erased++
+	}
+
+	return erased, nil
+}
+
+// erase erases the account of an erasure, completing it with the report.
+func (s *service) erase(erasure *entity.AccountErasure) error {
+	user := &entity.User{}
+	if err := s.repo.First(user, "id = ?", erasure.UserID); err != nil {
+		return err
+	}
+	if user.DeletedAt != nil {
+		// A previous run erased the account but could not complete the erasure.
+		erasure.Status = entity.ErasureStatusCompleted
+		erasure.Error = ""
+		erasure.CompletedAt = user.DeletedAt
+		return s.repo.UpdateErasure(erasure)
+	}
+
+	mediaURLs, err := s.repo.FindUserMediaURLs(user.ID)
+	if err != nil {
+		return err
+	}
+
+	erasedAt := timeNow().UTC()
+	report, err := s.repo.EraseUserData(user.ID, anonymousEmail(user.UUID), erasedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, mediaURL := range mediaURLs {
+		if err := s.store.Delete(s.store.Key(mediaURL)); err != nil {
+			log.Printf("error while deleting the media %s: %s", mediaURL, err.Error())
+			report.MediaFailed = append(report.MediaFailed, mediaURL)
+			continue
+		}
+		report.MediaFiles++
+	}
+
+	erasure.Status = entity.ErasureStatusCompleted
+	erasure.Error = ""
+	erasure.Report = report
+	erasure.CompletedAt = &erasedAt
+	if err := s.repo.UpdateErasure(erasure); err != nil {
+		return err
+	}
+
+	s.sendEmail(user.Email, "Your account was deleted", "Your account was deleted as requested. The following records were erased:\n\n"+formatReport(report))
+	return nil
+}
+
+// findUser retrieves the user with the given UUID.
+func (s *service) findUser(userUUID uuid.UUID) (*entity.User, int, error) {
+	foundUser, err := s.repo.FindByUUID(userUUID, &entity.User{})
+	if err != nil {
+		return nil, http.StatusNotFound, ErrUserNotFound
+	}
+	user, ok := foundUser.(*entity.User)
+	if !ok {
+		return nil, http.StatusInternalServerError, ErrTypeAssertion
+	}
+	if user.DeletedAt != nil {
+		return nil, http.StatusNotFound, ErrUserNotFound
+	}
+	return user, http.StatusOK, nil
+}
+
+// sendEmail sends an email about the erasure to the user, logging the failures.
+func (s *service) sendEmail(to, subject, body string) {
+	if err := s.mailer.Send(&entity.Email{To: to, Subject: subject, Body: body}); err != nil {
+		log.Printf("error while sending the account deletion email: %s", err.Error())
+	}
+}
+
+// anonymousEmail returns the email replacing the one of an erased user, unique as the users' emails are.
+func anonymousEmail(userUUID uuid.UUID) string {
+	return fmt.Sprintf("erased-%s@%s", userUUID, anonymousEmailDomain)
+}
+
+// formatReport formats the report of an erasure for the confirmation email.
+func formatReport(report *entity.ErasureReport) string {
+	var lines []string
+	for table, count := range report.Deleted {
+		if count > 0 {
+			lines = append(lines, fmt.Sprintf("- %s: %d deleted", strings.ReplaceAll(table, "_", " "), count))
+		}
+	}
+	for table, count := range report.Anonymised {
+		if count > 0 {
+			lines = append(lines, fmt.Sprintf("- %s: %d anonymised", strings.ReplaceAll(table, "_", " "), count))
+		}
+	}
+	sort.Strings(lines)
+	lines = append(lines, fmt.Sprintf("- uploaded files: %d deleted", report.MediaFiles))
+	return strings.Join(lines, "\n")
+}
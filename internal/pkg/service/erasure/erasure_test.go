@@ -0,0 +1,307 @@
+package erasure
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/emur-uy/backend/internal/infra/mailer"
+	"github.com/emur-uy/backend/internal/infra/storage"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testUserUUID = uuid.MustParse("24df3f36-ca63-11ed-afa1-0242ac120002")
+	testNow      = time.Date(2023, 7, 31, 10, 0, 0, 0, time.UTC)
+	errDatabase  = errors.New("database error")
+)
+
+func init() {
+	timeNow = func() time.Time { return testNow }
+}
+
+// mockErasureRepository is an in-memory implementation of the ErasureRepository interface for testing.
+type mockErasureRepository struct {
+	user      *entity.User
+	erasures  []*entity.AccountErasure
+	mediaURLs []string
+	eraseErr  error
+	erased    int
+}
+
+func newMockErasureRepository() *mockErasureRepository {
+	return &mockErasureRepository{
+		user: &entity.User{ID: 1, UUID: testUserUUID, Email: "ana@example.com"},
+		mediaURLs: []string{
+			"https://emur.nyc3.digitaloceanspaces.com/reminders/a.png",
+			"https://emur.nyc3.digitaloceanspaces.com/reminders/a_thumb.png",
+		},
+	}
+}
+
+func (m *mockErasureRepository) FindByUUID(id uuid.UUID, out interface{}) (interface{}, error) {
+	if id != m.user.UUID {
+		return nil, errors.New("record not found")
+	}
+	user := *m.user
+	return &user, nil
+}
+
+func (m *mockErasureRepository) First(out interface{}, conditions ...interface{}) error {
+	*out.(*entity.User) = *m.user
+	return nil
+}
+
+func (m *mockErasureRepository) CreateErasure(erasure *entity.AccountErasure) error {
+	erasure.ID = int64(len(m.erasures) + 1)
+	erasure.UUID = uuid.New()
+	stored := *erasure
+	m.erasures = append(m.erasures, &stored)
+	return nil
+}
+
+func (m *mockErasureRepository) FindScheduledErasure(userID int) (*entity.AccountErasure, error) {
+	for _, erasure := range m.erasures {
+		if erasure.UserID == userID && erasure.Status == entity.ErasureStatusScheduled {
+			found := *erasure
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockErasureRepository) FindErasures(userUUID uuid.UUID) ([]entity.AccountErasure, error) {
+	var erasures []entity.AccountErasure
+	for _, erasure := range m.erasures {
+		if erasure.UserUUID == userUUID {
+			erasures = append(erasures, *erasure)
+		}
+	}
+	return erasures, nil
+}
+
+func (m *mockErasureRepository) FindDueErasures(now time.Time, limit int) ([]entity.AccountErasure, error) {
+	var erasures []entity.AccountErasure
+	for _, erasure := range m.erasures {
+		if erasure.Status == entity.ErasureStatusScheduled && !erasure.ScheduledAt.After(now) {
+			erasures = append(erasures, *erasure)
+		}
+	}
+	return erasures, nil
+}
+
+func (m *mockErasureRepository) UpdateErasure(erasure *entity.AccountErasure) error {
+	for i, stored := range m.erasures {
+		if stored.ID == erasure.ID {
+			updated := *erasure
+			m.erasures[i] = &updated
+		}
+	}
+	return nil
+}
+
+func (m *mockErasureRepository) FindUserMediaURLs(userID int) ([]string, error) {
+	return m.mediaURLs, nil
+}
+
+func (m *mockErasureRepository) EraseUserData(userID int, anonymousEmail string, erasedAt time.Time) (*entity.ErasureReport, error) {
+	if m.eraseErr != nil {
+		return nil, m.eraseErr
+	}
+	m.erased++
+	m.user.Email = anonymousEmail
+	m.user.DeletedAt = &erasedAt
+	return &entity.ErasureReport{
+		Deleted:    map[string]int64{"reminders": 2, "monitorings": 5, "media": 1},
+		Anonymised: map[string]int64{"users": 1, "questions": 0},
+	}, nil
+}
+
+// mockTokenRepository records the revocations of the refresh tokens of the users.
+type mockTokenRepository struct {
+	revokedUsers []int
+	validAfter   map[int]time.Time
+}
+
+func (m *mockTokenRepository) Create(value interface{}) error { return nil }
+
+func (m *mockTokenRepository) First(out interface{}, conditions ...interface{}) error { return nil }
+
+func (m *mockTokenRepository) RevokeRefreshToken(id int, revokedAt time.Time) (bool, error) {
+	return false, nil
+}
+
+func (m *mockTokenRepository) RevokeRefreshTokenFamily(familyID uuid.UUID, revokedAt time.Time) error {
+	return nil
+}
+
+func (m *mockTokenRepository) RevokeUserRefreshTokens(userID int, revokedAt time.Time) error {
+	m.revokedUsers = append(m.revokedUsers, userID)
+	return nil
+}
+
+func (m *mockTokenRepository) SetTokensValidAfter(userID int, validAfter time.Time) error {
+	m.validAfter[userID] = validAfter
+	return nil
+}
+
+func (m *mockTokenRepository) UseActionToken(id int, usedAt time.Time) (bool, error) {
+	return false, nil
+}
+
+func (m *mockTokenRepository) DeleteExpiredTokens(before time.Time) (int64, error) { return 0, nil }
+
+func (m *mockTokenRepository) IsRevoked(jti string) (bool, error) { return false, nil }
+
+func (m *mockTokenRepository) TokensValidAfter(userUUID uuid.UUID) (*time.Time, error) {
+	return nil, nil
+}
+
+func newTestService() (*service, *mockErasureRepository, *mockTokenRepository, *mailer.MemoryMailer) {
+	repo := newMockErasureRepository()
+	tokenRepo := &mockTokenRepository{validAfter: map[int]time.Time{}}
+	memoryMailer := mailer.NewMemoryMailer()
+	return NewService(repo, tokenRepo, memoryMailer, storage.NewMemoryStore()).(*service), repo, tokenRepo, memoryMailer
+}
+
+func TestRequestErasure(t *testing.T) {
+	s, repo, tokenRepo, memoryMailer := newTestService()
+
+	erasure, status, err := s.RequestErasure(testUserUUID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.Equal(t, entity.ErasureStatusScheduled, erasure.Status)
+	assert.Equal(t, testNow.Add(GracePeriod), erasure.ScheduledAt)
+	assert.Equal(t, []int{1}, tokenRepo.revokedUsers)
+	assert.Equal(t, map[int]time.Time{1: testNow.UTC()}, tokenRepo.validAfter)
+	assert.Equal(t, "ana@example.com", memoryMailer.Last().To)
+	assert.Contains(t, memoryMailer.Last().Body, "30/08/2023")
+
+	again, status, err := s.RequestErasure(testUserUUID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.Equal(t, erasure.UUID, again.UUID)
+	assert.Len(t, repo.erasures, 1)
+}
+
+func TestRequestErasureUserNotFound(t *testing.T) {
+	s, _, _, _ := newTestService()
+
+	_, status, err := s.RequestErasure(uuid.New())
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestCancelErasure(t *testing.T) {
+	s, repo, _, _ := newTestService()
+
+	status, err := s.CancelErasure(testUserUUID)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.ErrorIs(t, err, ErrErasureNotFound)
+
+	_, _, err = s.RequestErasure(testUserUUID)
+	require.NoError(t, err)
+
+	status, err = s.CancelErasure(testUserUUID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, entity.ErasureStatusCancelled, repo.erasures[0].Status)
+	assert.NotNil(t, repo.erasures[0].CancelledAt)
+
+	_, status, err = s.GetErasure(testUserUUID)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.ErrorIs(t, err, ErrErasureNotFound)
+}
+
+func TestProcessDueErasures(t *testing.T) {
+	s, repo, _, memoryMailer := newTestService()
+
+	_, _, err := s.RequestErasure(testUserUUID)
+	require.NoError(t, err)
+
+	erased, err := s.ProcessDueErasures(testNow.Add(GracePeriod - time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 0, erased)
+	assert.Equal(t, 0, repo.erased)
+
+	erased, err = s.ProcessDueErasures(testNow.Add(GracePeriod))
+	require.NoError(t, err)
+	assert.Equal(t, 1, erased)
+	assert.Equal(t, []string{"reminders/a.png", "reminders/a_thumb.png"}, s.store.(*storage.MemoryStore).Deleted)
+
+	erasure := repo.erasures[0]
+	assert.Equal(t, entity.ErasureStatusCompleted, erasure.Status)
+	require.NotNil(t, erasure.Report)
+	assert.Equal(t, int64(5), erasure.Report.Deleted["monitorings"])
+	assert.Equal(t, 2, erasure.Report.MediaFiles)
+	assert.Empty(t, erasure.Report.MediaFailed)
+	assert.Equal(t, "erased-"+testUserUUID.String()+"@erased.invalid", repo.user.Email)
+
+	assert.Equal(t, "ana@example.com", memoryMailer.Last().To)
+	assert.Contains(t, memoryMailer.Last().Body, "- monitorings: 5 deleted")
+	assert.Contains(t, memoryMailer.Last().Body, "- uploaded files: 2 deleted")
+	assert.NotContains(t, memoryMailer.Last().Body, "questions")
+
+	erasures, status, err := s.GetUserErasures(testUserUUID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, erasures, 1)
+}
+
+func TestProcessDueErasuresMediaFailed(t *testing.T) {
+	s, repo, _, _ := newTestService()
+	s.store.(*storage.MemoryStore).Errors["reminders/a_thumb.png"] = errors.New("access denied")
+
+	_, _, err := s.RequestErasure(testUserUUID)
+	require.NoError(t, err)
+
+	erased, err := s.ProcessDueErasures(testNow.Add(GracePeriod))
+	require.NoError(t, err)
+	assert.Equal(t, 1, erased)
+
+	report := repo.erasures[0].Report
+	assert.Equal(t, 1, report.MediaFiles)
+	assert.Equal(t, []string{repo.mediaURLs[1]}, report.MediaFailed)
+}
+
+func TestProcessDueErasuresFailure(t *testing.T) {
+	s, repo, _, _ := newTestService()
+
+	_, _, err := s.RequestErasure(testUserUUID)
+	require.NoError(t, err)
+
+	repo.eraseErr = errDatabase
+	erased, err := s.ProcessDueErasures(testNow.Add(GracePeriod))
+	require.NoError(t, err)
+	assert.Equal(t, 0, erased)
+	assert.Equal(t, entity.ErasureStatusScheduled, repo.erasures[0].Status)
+	assert.Equal(t, errDatabase.Error(), repo.erasures[0].Error)
+
+	repo.eraseErr = nil
+	erased, err = s.ProcessDueErasures(testNow.Add(GracePeriod))
+	require.NoError(t, err)
+	assert.Equal(t, 1, erased)
+	assert.Equal(t, entity.ErasureStatusCompleted, repo.erasures[0].Status)
+	assert.Empty(t, repo.erasures[0].Error)
+}
+
+func TestProcessDueErasuresAlreadyErased(t *testing.T) {
+	s, repo, _, _ := newTestService()
+
+	_, _, err := s.RequestErasure(testUserUUID)
+	require.NoError(t, err)
+
+	erasedAt := testNow.Add(GracePeriod)
+	repo.user.DeletedAt = &erasedAt
+	erased, err := s.ProcessDueErasures(testNow.Add(GracePeriod))
+	require.NoError(t, err)
+	assert.Equal(t, 1, erased)
+	assert.Equal(t, 0, repo.erased)
+	assert.Empty(t, s.store.(*storage.MemoryStore).Deleted)
+	assert.Equal(t, entity.ErasureStatusCompleted, repo.erasures[0].Status)
+	assert.Equal(t, &erasedAt, repo.erasures[0].CompletedAt)
+}
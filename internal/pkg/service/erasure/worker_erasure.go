@@ -0,0 +1,31 @@
+package erasure
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+type Worker struct {
+	service ports.ErasureService
+}
+
+func NewWorker(service ports.ErasureService) *Worker {
+	return &Worker{
+		service: service,
+	}
+}
+
+// EraseAccounts erases the accounts whose deletion grace period ended.
+func (w *Worker) EraseAccounts() {
+	erased, err := w.service.ProcessDueErasures(time.Now().UTC())
+	if err != nil {
+		fmt.Println("Error erasing the deleted accounts:", err)
+		return
+	}
+
+	if erased > 0 {
+		fmt.Printf("%d deleted accounts erased\n", erased)
+	}
+}
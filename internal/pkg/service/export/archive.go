@@ -0,0 +1,190 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/google/uuid"
+)
+
+// jsonFileName is the name of the file of the archive holding every personal data.
+const jsonFileName = "personal_data.json"
+
+// csvTable is a section of the personal data written as a CSV file.
+type csvTable struct {
+	name   string
+	header []string
+	rows   [][]string
+}
+
+// writeArchive writes the personal data as a zip archive holding a JSON file and a CSV file per section.
+func writeArchive(data *entity.PersonalData) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	archive := zip.NewWriter(buffer)
+
+	file, err := archive.Create(jsonFileName)
+	if err != nil {
+		return nil, err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return nil, err
+	}
+
+	for _, table := range csvTables(data) {
+		file, err := archive.Create("csv/" + table.name + ".csv")
+		if err != nil {
+			return nil, err
+		}
+		writer := csv.NewWriter(file)
+		if err := writer.Write(table.header); err != nil {
+			return nil, err
+		}
+		if err := writer.WriteAll(table.rows); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// csvTables returns the sections of the personal data as CSV tables.
+func csvTables(data *entity.PersonalData) []csvTable {
+	profile := data.Profile
+	tables := []csvTable{{
+		name:   "profile",
+		header: []string{"uuid", "email", "first_name", "last_name", "profile_image", "date_of_birth", "sex", "user_type", "city", "country", "timezone", "is_active", "email_verified_at", "created_at"},
+		rows: [][]string{{
+			formatUUID(profile.UUID), profile.Email, profile.FirstName, profile.LastName, profile.ProfileImage, formatTime(profile.DateOfBirth),
+			profile.Sex, profile.UserType, profile.City, profile.Country, profile.Timezone, strconv.FormatBool(profile.IsActive),
+			formatTimePtr(profile.EmailVerifiedAt), formatTime(profile.CreatedAt),
+		}},
+	}}
+
+	medicalRecord := csvTable{
+		name:   "medical_record",
+		header: []string{"uuid", "health_care_provider", "emergency_medical_service", "multiple_sclerosis_type", "laboral_condition", "conmorbidity", "treating_neurologist", "support_network", "is_disabled", "educational_level", "created_at", "updated_at"},
+	}
+	if record := data.MedicalRecord; record != nil {
+		medicalRecord.rows = append(medicalRecord.rows, []string{
+			formatUUID(record.UUID), record.HealthCareProvider, record.EmergencyMedicalService, record.MultipleSclerosisType, record.LaboralCondition,
+			strconv.FormatBool(record.Conmorbidity), record.TreatingNeurologist, strconv.FormatBool(record.SupportNetwork),
+			strconv.FormatBool(record.IsDisabled), record.EducationalLevel, formatTime(record.CreatedAt), formatTime(record.UpdatedAt),
+		})
+	}
+	tables = append(tables, medicalRecord)
+
+	treatments := csvTable{name: "treatments", header: []string{"uuid", "name", "type", "frequency", "shots", "date_start", "notes", "created_at"}}
+	for _, treatment := range data.Treatments {
+		treatments.rows = append(treatments.rows, []string{
+			formatUUID(treatment.UUID), treatment.Name, treatment.Type, formatJSON(treatment.Frequency), formatJSON(treatment.Shots),
+			formatTime(treatment.DateStart), treatment.Notes, formatTime(treatment.CreatedAt),
+		})
+	}
+	tables = append(tables, treatments)
+
+	doses := csvTable{name: "treatment_doses", header: []string{"uuid", "treatment_uuid", "scheduled_at", "status", "updated_at"}}
+	for _, dose := range data.TreatmentDoses {
+		doses.rows = append(doses.rows, []string{formatUUID(dose.UUID), formatUUID(dose.TreatmentUUID), formatTime(dose.ScheduledAt), dose.Status, formatTime(dose.UpdatedAt)})
+	}
+	tables = append(tables, doses)
+
+	reminders := csvTable{name: "reminders", header: []string{"uuid", "name", "type", "date", "notification", "task", "note", "medical_id", "is_active", "media", "created_at"}}
+	for _, reminder := range data.Reminders {
+		mediaURLs := make([]string, 0, len(reminder.Media))
+		for _, media := range reminder.Media {
+			mediaURLs = append(mediaURLs, media.MediaURL)
+		}
+		reminders.rows = append(reminders.rows, []string{
+			formatUUID(reminder.UUID), reminder.Name, reminder.Type, formatTime(reminder.Date), formatJSON(reminder.Notification), formatJSON(reminder.Task),
+			reminder.Note, strconv.Itoa(reminder.Medical), strconv.FormatBool(reminder.IsActive), strings.Join(mediaURLs, " "), formatTime(reminder.CreatedAt),
+		})
+	}
+	tables = append(tables, reminders)
+
+	monitorings := csvTable{name: "monitorings", header: []string{"symptom", "scale", "date", "local_date"}}
+	for _, monitoring := range data.Monitorings {
+		monitorings.rows = append(monitorings.rows, []string{monitoring.Symptom, strconv.Itoa(monitoring.Scale), formatTime(monitoring.Date), monitoring.LocalDate.Format("2006-01-02")})
+	}
+	tables = append(tables, monitorings)
+
+	subscriptions := csvTable{name: "symptom_subscriptions", header: []string{"symptom", "created_at"}}
+	for _, subscription := range data.SymptomSubscriptions {
+		subscriptions.rows = append(subscriptions.rows, []string{subscription.Symptom, formatTime(subscription.CreatedAt)})
+	}
+	tables = append(tables, subscriptions)
+
+	questions := csvTable{name: "questions", header: []string{"uuid", "text", "created_at"}}
+	for _, question := range data.Questions {
+		questions.rows = append(questions.rows, []string{formatUUID(question.UUID), question.Text, formatTime(question.CreatedAt)})
+	}
+	tables = append(tables, questions)
+
+	answers := csvTable{name: "answers", header: []string{"uuid", "question_uuid", "text", "is_public", "created_at"}}
+	for _, answer := range data.Answers {
+		answers.rows = append(answers.rows, []string{formatUUID(answer.UUID), formatUUID(answer.QuestionUUID), answer.Text, strconv.FormatBool(answer.IsPublic), formatTime(answer.CreatedAt)})
+	}
+	tables = append(tables, answers)
+
+	tables = append(tables, ratingTable("medical_ratings", data.MedicalRatings), ratingTable("health_service_ratings", data.HealthServiceRatings))
+
+	recipeRatings := csvTable{name: "recipe_ratings", header: []string{"recipe_uuid", "recipe", "level"}}
+	for _, rating := range data.RecipeRatings {
+		recipeRatings.rows = append(recipeRatings.rows, []string{formatUUID(rating.RecipeUUID), rating.Recipe, strconv.Itoa(rating.Level)})
+	}
+	tables = append(tables, recipeRatings)
+
+	return tables
+}
+
+// ratingTable returns the ratings given in the reminders as a CSV table.
+func ratingTable(name string, ratings []entity.ExportRating) csvTable {
+	table := csvTable{name: name, header: []string{"reminder_uuid", "name", "rating", "created_at"}}
+	for _, rating := range ratings {
+		table.rows = append(table.rows, []string{formatUUID(rating.ReminderUUID), rating.Name, strconv.Itoa(rating.Rating), formatTime(rating.CreatedAt)})
+	}
+	return table
+}
+
+// formatTime formats a time of the CSV files, leaving the zero time empty.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// formatTimePtr formats an optional time of the CSV files.
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return formatTime(*t)
+}
+
+// formatUUID formats a UUID of the CSV files, leaving the nil UUID empty.
+func formatUUID(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
+
+// formatJSON formats the structured values of the CSV files as JSON.
+func formatJSON(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
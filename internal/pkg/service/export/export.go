@@ -0,0 +1,313 @@
+package export
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/fieldcrypt"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUserNotFound    = errors.New("user not found")
+	ErrExportNotFound  = errors.New("export not found")
+	ErrExportNotReady  = errors.New("the export is not completed yet")
+	ErrExportExpired   = errors.New("the export expired, request a new one")
+	ErrCreatingExport  = errors.New("error creating the export")
+	ErrBuildingArchive = errors.New("error building the export archive")
+	ErrTypeAssertion   = errors.New("type assertion failed")
+	ErrFindingExports  = errors.New("error finding the pending exports")
+)
+
+const (
+	// SyncExportLimit is the number of records up to which an account is exported right away,
+	// the larger ones being exported by the worker.
+	SyncExportLimit = 1000
+	// ExportRetention is the time a completed export can be downloaded.
+	ExportRetention = 7 * 24 * time.Hour
+
+	// exportTimeout is the time after which an export still processing is considered abandoned and retried.
+	exportTimeout = time.Hour
+	// exportBatchSize is the number of exports built on each run of the worker.
+	exportBatchSize = 10
+)
+
+// timeNow returns the current time, replaced in the tests.
+var timeNow = time.Now
+
+// keyringFromConfig returns the keyring decrypting the profile, replaced in the tests.
+var keyringFromConfig = func() (*fieldcrypt.Keyring, error) {
+	return fieldcrypt.FromConfig(config.Get())
+}
+
+// service struct holds the necessary dependencies for the export service
+type service struct {
+	repo ports.ExportRepository
+}
+
+// NewService returns a new instance of the export service with the given export repository.
+func NewService(repo ports.ExportRepository) ports.ExportService {
+	return &service{
+		repo: repo,
+	}
+}
+
+// ExportPersonalData exports the personal data of the user, right away for the small accounts and
+// with an export job for the larger ones. A job still pending or downloadable is returned instead of a new one.
+func (s *service) ExportPersonalData(userUUID uuid.UUID) (*entity.DataExport, int, error) {
+	user, status, err := s.findUser(userUUID)
+	if err != nil {
+		return nil, status, err
+	}
+	now := timeNow().UTC()
+
+	activeExport, err := s.repo.FindActiveExport(user.ID, now)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if activeExport != nil {
+		return activeExport, http.StatusAccepted, nil
+	}
+
+	count, err := s.repo.CountPersonalData(user.ID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if count <= SyncExportLimit {
+		archive, _, err := s.buildArchive(user.ID, now)
+		if err != nil {
+			log.Printf("error while exporting the user %d: %s", user.ID, err.Error())
+			return nil, http.StatusInternalServerError, ErrBuildingArchive
+		}
+		return &entity.DataExport{
+			UserID:      user.ID,
+			Status:      entity.ExportStatusCompleted,
+			Archive:     archive,
+			Size:        int64(len(archive)),
+			CreatedAt:   now,
+			CompletedAt: &now,
+		}, http.StatusOK, nil
+	}
+
+	export := &entity.DataExport{
+		UserID:    user.ID,
+		Status:    entity.ExportStatusPending,
+		CreatedAt: now,
+	}
+	if err := s.repo.CreateExport(export); err != nil {
+		return nil, http.StatusInternalServerError, ErrCreatingExport
+	}
+	return export, http.StatusAccepted, nil
+}
+
+// GetExport retrieves the status of an export of the user.
+func (s *service) GetExport(userUUID, exportUUID uuid.UUID) (*entity.DataExport, int, error) {
+	return s.findExport(userUUID, exportUUID, false)
+}
+
+// DownloadExport retrieves a completed export of the user with its archive.
+func (s *service) DownloadExport(userUUID, exportUUID uuid.UUID) (*entity.DataExport, int, error) {
+	export, status, err := s.findExport(userUUID, exportUUID, true)
+	if err != nil {
+		return nil, status, err
+	}
+	if export.Status != entity.ExportStatusCompleted {
+		return nil, http.StatusConflict, ErrExportNotReady
+	}
+
+	keyring, err := keyringFromConfig()
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	archive, err := keyring.DecryptBytes(archiveField(userUUID), export.Archive)
+	if err != nil {
+		log.Printf("error while decrypting the export %s: %s", export.UUID, err.Error())
+		return nil, http.StatusInternalServerError, err
+	}
+	export.Archive = archive
+	return export, http.StatusOK, nil
+}
+
+// ProcessPendingExports builds the archives of the exports waiting for the worker. The exports whose
+// archive cannot be built are marked as failed, and every export expires after the retention period.
+func (s *service) ProcessPendingExports(now time.Time) (int, error) {
+	staleBefore := now.Add(-exportTimeout)
+	exports, err := s.repo.FindPendingExports(staleBefore, exportBatchSize)
+	if err != nil {
+		return 0, ErrFindingExports
+	}
+
+	completed := 0
+	for i := range exports {
+		export := &exports[i]
+		startedAt := now
+		export.StartedAt = &startedAt
+
+		claimed, err := s.repo.ClaimExport(export, staleBefore)
+		if err != nil {
+			log.Printf("error while claiming the export %s: %s", export.UUID, err.Error())
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		archive, err := s.buildStoredArchive(export.UserID, now)
+		completedAt := timeNow().UTC()
+		expiresAt := completedAt.Add(ExportRetention)
+		export.CompletedAt = &completedAt
+		export.ExpiresAt = &expiresAt
+		if err != nil {
+			log.Printf("error while exporting the user %d: %s", export.UserID, err.Error())
+			export.Status = entity.ExportStatusFailed
+			export.Error = ErrBuildingArchive.Error()
+		} else {
+			export.Status = entity.ExportStatusCompleted
+			export.Archive = archive.sealed
+			export.Size = int64(archive.size)
+		}
+
+		if err := s.repo.UpdateExport(export); err != nil {
+			log.Printf("error while saving the export %s: %s", export.UUID, err.Error())
+			continue
+		}
+		if export.Status == entity.ExportStatusCompleted {
+			completed++
+		}
+	}
+
+	return completed, nil
+}
+
+// PurgeExpiredExports deletes the exports that expired before now.
+func (s *service) PurgeExpiredExports(now time.Time) (int64, error) {
+	return s.repo.DeleteExpiredExports(now)
+}
+
+// storedArchive is an archive encrypted to be kept until it is downloaded.
+type storedArchive struct {
+	sealed []byte
+	size   int
+}
+
+// buildStoredArchive builds the archive of the user and encrypts it, so the personal data is never kept in plaintext.
+func (s *service) buildStoredArchive(userID int, now time.Time) (*storedArchive, error) {
+	archive, user, err := s.buildArchive(userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	keyring, err := keyringFromConfig()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := keyring.EncryptBytes(archiveField(user.UUID), archive)
+	if err != nil {
+		return nil, err
+	}
+	return &storedArchive{sealed: sealed, size: len(archive)}, nil
+}
+
+// archiveField returns the encrypted field of the archives of the user.
+func archiveField(userUUID uuid.UUID) fieldcrypt.Field {
+	return fieldcrypt.Field{Table: "data_exports", Column: "archive", Record: userUUID.String()}
+}
+
+// buildArchive retrieves the personal data of the user, decrypts the profile and writes the archive.
+func (s *service) buildArchive(userID int, now time.Time) ([]byte, *entity.User, error) {
+	user, data, err := s.repo.FindPersonalData(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyring, err := keyringFromConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	profile, err := decryptProfile(keyring, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data.ExportedAt = now
+	data.Profile = *profile
+	archive, err := writeArchive(data)
+	return archive, user, err
+}
+
+// decryptProfile returns the profile of the user with the personal data fields decrypted.
+func decryptProfile(keyring *fieldcrypt.Keyring, user *entity.User) (*entity.ExportProfile, error) {
+	firstName, err := decryptField(keyring, fieldcrypt.UserField("first_name", user.UUID), user.FirstName)
+	if err != nil {
+		return nil, err
+	}
+	lastName, err := decryptField(keyring, fieldcrypt.UserField("last_name", user.UUID), user.LastName)
+	if err != nil {
+		return nil, err
+	}
+	profileImage, err := decryptField(keyring, fieldcrypt.UserField("profile_image", user.UUID), user.ProfileImage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.ExportProfile{
+		UUID:            user.UUID,
+		Email:           user.Email,
+		FirstName:       firstName,
+		LastName:        lastName,
+		ProfileImage:    profileImage,
+		DateOfBirth:     user.DateOfBirth,
+		Sex:             user.Sex,
+		UserType:        user.UserType,
+		City:            user.City,
+		Country:         user.Country,
+		Timezone:        user.Timezone,
+		IsActive:        user.IsActive,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		CreatedAt:       user.CreatedAt,
+	}, nil
+}
+
+// decryptField decrypts a personal data field, the empty fields being left empty.
+func decryptField(keyring *fieldcrypt.Keyring, field fieldcrypt.Field, ciphertext string) (string, error) {
+	if ciphertext == "" {
+		return "", nil
+	}
+	return keyring.Decrypt(field, ciphertext)
+}
+
+// findUser retrieves the user with the given UUID.
+func (s *service) findUser(userUUID uuid.UUID) (*entity.User, int, error) {
+	foundUser, err := s.repo.FindByUUID(userUUID, &entity.User{})
+	if err != nil {
+		return nil, http.StatusNotFound, ErrUserNotFound
+	}
+	user, ok := foundUser.(*entity.User)
+	if !ok {
+		return nil, http.StatusInternalServerError, ErrTypeAssertion
+	}
+	return user, http.StatusOK, nil
+}
+
+// findExport retrieves an export of the user, with its archive if withArchive is set.
+func (s *service) findExport(userUUID, exportUUID uuid.UUID, withArchive bool) (*entity.DataExport, int, error) {
+	user, status, err := s.findUser(userUUID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	export, err := s.repo.FindExport(user.ID, exportUUID, withArchive)
+	if err != nil {
+		return nil, http.StatusNotFound, ErrExportNotFound
+	}
+	if export.ExpiresAt != nil && export.ExpiresAt.Before(timeNow().UTC()) {
+		return nil, http.StatusGone, ErrExportExpired
+	}
+	return export, http.StatusOK, nil
+}
@@ -0,0 +1,379 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/fieldcrypt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var (
+	testUserUUID  = uuid.MustParse("24df3f36-ca63-11ed-afa1-0242ac120002")
+	testNow       = time.Date(2023, 7, 29, 10, 0, 0, 0, time.UTC)
+	testKeyring   *fieldcrypt.Keyring
+	errDatabase   = errors.New("database error")
+	testTreatment = uuid.MustParse("6c1e7e0e-5b5a-4e0f-9a55-0c6c8f1a9b01")
+)
+
+func init() {
+	keyring, err := fieldcrypt.NewKeyring("k1", map[string][]byte{"k1": []byte("0123456789abcdef0123456789abcdef")})
+	if err != nil {
+		panic(err)
+	}
+	testKeyring = keyring
+	keyringFromConfig = func() (*fieldcrypt.Keyring, error) { return testKeyring, nil }
+	timeNow = func() time.Time { return testNow }
+}
+
+// mockExportRepository is an in-memory implementation of the ExportRepository interface for testing.
+type mockExportRepository struct {
+	user        *entity.User
+	data        *entity.PersonalData
+	count       int64
+	exports     []*entity.DataExport
+	claimFails  bool
+	dataErr     error
+	purgeBefore time.Time
+}
+
+func newMockExportRepository(t *testing.T) *mockExportRepository {
+	firstName, err := testKeyring.Encrypt(fieldcrypt.UserField("first_name", testUserUUID), "Ana")
+	require.NoError(t, err)
+	lastName, err := testKeyring.Encrypt(fieldcrypt.UserField("last_name", testUserUUID), "Pérez")
+	require.NoError(t, err)
+
+	return &mockExportRepository{
+		user: &entity.User{ID: 1, UUID: testUserUUID, Email: "ana@example.com", FirstName: firstName, LastName: lastName, City: "Montevideo"},
+		data: &entity.PersonalData{
+			Treatments:  []entity.Treatment{{UUID: testTreatment, Name: "Interferon", Type: "injection"}},
+			Monitorings: []entity.ExportMonitoring{{Symptom: "Fatigue", Scale: 3, Date: testNow}},
+			Reminders: []entity.ExportReminder{{
+				Reminder: entity.Reminder{Name: "Neurologist", Type: "appointment"},
+				Media:    []entity.Media{{MediaURL: "https://bucket/a.png"}, {MediaURL: "https://bucket/b.png"}},
+			}},
+		},
+		count: 3,
+	}
+}
+
+func (m *mockExportRepository) FindByUUID(id uuid.UUID, out interface{}) (interface{}, error) {
+	if id != m.user.UUID {
+		return nil, errors.New("record not found")
+	}
+	user := *m.user
+	return &user, nil
+}
+
+func (m *mockExportRepository) CreateExport(export *entity.DataExport) error {
+	export.ID = int64(len(m.exports) + 1)
+	export.UUID = uuid.New()
+	stored := *export
+	m.exports = append(m.exports, &stored)
+	return nil
+}
+
+func (m *mockExportRepository) FindExport(userID int, exportUUID uuid.UUID, withArchive bool) (*entity.DataExport, error) {
+	for _, export := range m.exports {
+		if export.UserID == userID && export.UUID == exportUUID {
+			found := *export
+			if !withArchive {
+				found.Archive = nil
+			}
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockExportRepository) FindActiveExport(userID int, now time.Time) (*entity.DataExport, error) {
+	for _, export := range m.exports {
+		if export.UserID != userID {
+			continue
+		}
+		if export.Status == entity.ExportStatusPending || export.Status == entity.ExportStatusProcessing ||
+			(export.Status == entity.ExportStatusCompleted && export.ExpiresAt.After(now)) {
+			found := *export
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockExportRepository) FindPendingExports(staleBefore time.Time, limit int) ([]entity.DataExport, error) {
+	var exports []entity.DataExport
+	for _, export := range m.exports {
+		if export.Status == entity.ExportStatusPending {
+			exports = append(exports, *export)
+		}
+	}
+	return exports, nil
+}
+
+func (m *mockExportRepository) ClaimExport(export *entity.DataExport, staleBefore time.Time) (bool, error) {
+	if m.claimFails {
+		return false, nil
+	}
+	for _, stored := range m.exports {
+		if stored.ID == export.ID {
+			stored.Status = entity.ExportStatusProcessing
+			stored.StartedAt = export.StartedAt
+		}
+	}
+	return true, nil
+}
+
+func (m *mockExportRepository) UpdateExport(export *entity.DataExport) error {
+	for i, stored := range m.exports {
+		if stored.ID == export.ID {
+			updated := *export
+			m.exports[i] = &updated
+		}
+	}
+	return nil
+}
+
+func (m *mockExportRepository) DeleteExpiredExports(now time.Time) (int64, error) {
+	m.purgeBefore = now
+	return 2, nil
+}
+
+func (m *mockExportRepository) CountPersonalData(userID int) (int64, error) {
+	return m.count, nil
+}
+
+func (m *mockExportRepository) FindPersonalData(userID int) (*entity.User, *entity.PersonalData, error) {
+	if m.dataErr != nil {
+		return nil, nil, m.dataErr
+	}
+	user := *m.user
+	data := *m.data
+	return &user, &data, nil
+}
+
+// readArchive returns the content of the files of a zip archive.
+func readArchive(t *testing.T, archive []byte) map[string][]byte {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+
+	files := map[string][]byte{}
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		files[file.Name] = content
+	}
+	return files
+}
+
+func TestExportPersonalDataSync(t *testing.T) {
+	repo := newMockExportRepository(t)
+	service := NewService(repo)
+
+	export, status, err := service.ExportPersonalData(testUserUUID)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, entity.ExportStatusCompleted, export.Status)
+	assert.Equal(t, int64(len(export.Archive)), export.Size)
+	assert.Empty(t, repo.exports, "small accounts are exported without a job")
+
+	files := readArchive(t, export.Archive)
+	require.Contains(t, files, jsonFileName)
+
+	data := &entity.PersonalData{}
+	require.NoError(t, json.Unmarshal(files[jsonFileName], data))
+	assert.Equal(t, "Ana", data.Profile.FirstName)
+	assert.Equal(t, "Pérez", data.Profile.LastName)
+	assert.Equal(t, "ana@example.com", data.Profile.Email)
+	assert.Equal(t, testNow, data.ExportedAt)
+	require.Len(t, data.Reminders, 1)
+	assert.Len(t, data.Reminders[0].Media, 2)
+
+	for _, name := range []string{"profile", "medical_record", "treatments", "treatment_doses", "reminders", "monitorings",
+		"symptom_subscriptions", "questions", "answers", "medical_ratings", "health_service_ratings", "recipe_ratings"} {
+		assert.Contains(t, files, "csv/"+name+".csv")
+	}
+
+	profile, err := csv.NewReader(bytes.NewReader(files["csv/profile.csv"])).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, profile, 2)
+	assert.Equal(t, "first_name", profile[0][2])
+	assert.Equal(t, "Ana", profile[1][2])
+
+	reminders, err := csv.NewReader(bytes.NewReader(files["csv/reminders.csv"])).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, reminders, 2)
+	assert.Equal(t, "https://bucket/a.png https://bucket/b.png", reminders[1][9])
+
+	treatments, err := csv.NewReader(bytes.NewReader(files["csv/treatments.csv"])).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, testTreatment.String(), treatments[1][0])
+}
+
+func TestExportPersonalDataAsync(t *testing.T) {
+	repo := newMockExportRepository(t)
+	repo.count = SyncExportLimit + 1
+	service := NewService(repo)
+
+	export, status, err := service.ExportPersonalData(testUserUUID)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.Equal(t, entity.ExportStatusPending, export.Status)
+	assert.Empty(t, export.Archive)
+	require.Len(t, repo.exports, 1)
+
+	// A new request returns the pending export instead of creating another one
+	again, status, err := service.ExportPersonalData(testUserUUID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.Equal(t, export.UUID, again.UUID)
+	assert.Len(t, repo.exports, 1)
+}
+
+func TestExportPersonalDataUserNotFound(t *testing.T) {
+	service := NewService(newMockExportRepository(t))
+
+	_, status, err := service.ExportPersonalData(uuid.New())
+
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestExportPersonalDataBuildError(t *testing.T) {
+	repo := newMockExportRepository(t)
+	repo.dataErr = errDatabase
+	service := NewService(repo)
+
+	_, status, err := service.ExportPersonalData(testUserUUID)
+
+	assert.ErrorIs(t, err, ErrBuildingArchive)
+	assert.Equal(t, http.StatusInternalServerError, status)
+}
+
+func TestProcessPendingExports(t *testing.T) {
+	repo := newMockExportRepository(t)
+	repo.count = SyncExportLimit + 1
+	service := NewService(repo)
+
+	export, _, err := service.ExportPersonalData(testUserUUID)
+	require.NoError(t, err)
+
+	// The export cannot be downloaded until the worker completes it
+	_, status, err := service.DownloadExport(testUserUUID, export.UUID)
+	assert.ErrorIs(t, err, ErrExportNotReady)
+	assert.Equal(t, http.StatusConflict, status)
+
+	completed, err := service.ProcessPendingExports(testNow)
+	require.NoError(t, err)
+	assert.Equal(t, 1, completed)
+
+	polled, status, err := service.GetExport(testUserUUID, export.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, entity.ExportStatusCompleted, polled.Status)
+	assert.Empty(t, polled.Archive)
+	require.NotNil(t, polled.ExpiresAt)
+	assert.Equal(t, testNow.Add(ExportRetention), *polled.ExpiresAt)
+
+	downloaded, status, err := service.DownloadExport(testUserUUID, export.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, readArchive(t, downloaded.Archive), jsonFileName)
+	assert.Equal(t, int64(len(downloaded.Archive)), downloaded.Size)
+
+	// The stored archive is encrypted and bound to its user
+	stored := repo.exports[0].Archive
+	assert.True(t, bytes.HasPrefix(stored, []byte("v1:k1:")))
+	_, err = testKeyring.DecryptBytes(archiveField(uuid.New()), stored)
+	assert.ErrorIs(t, err, fieldcrypt.ErrTampered)
+}
+
+func TestProcessPendingExportsFailure(t *testing.T) {
+	repo := newMockExportRepository(t)
+	repo.count = SyncExportLimit + 1
+	service := NewService(repo)
+
+	export, _, err := service.ExportPersonalData(testUserUUID)
+	require.NoError(t, err)
+
+	repo.dataErr = errDatabase
+	completed, err := service.ProcessPendingExports(testNow)
+	require.NoError(t, err)
+	assert.Equal(t, 0, completed)
+
+	polled, _, err := service.GetExport(testUserUUID, export.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ExportStatusFailed, polled.Status)
+	assert.Equal(t, ErrBuildingArchive.Error(), polled.Error)
+}
+
+func TestProcessPendingExportsClaimedElsewhere(t *testing.T) {
+	repo := newMockExportRepository(t)
+	repo.count = SyncExportLimit + 1
+	service := NewService(repo)
+
+	_, _, err := service.ExportPersonalData(testUserUUID)
+	require.NoError(t, err)
+
+	repo.claimFails = true
+	completed, err := service.ProcessPendingExports(testNow)
+	require.NoError(t, err)
+	assert.Equal(t, 0, completed)
+	assert.Equal(t, entity.ExportStatusPending, repo.exports[0].Status)
+}
+
+func TestGetExportNotFound(t *testing.T) {
+	service := NewService(newMockExportRepository(t))
+
+	_, status, err := service.GetExport(testUserUUID, uuid.New())
+
+	assert.ErrorIs(t, err, ErrExportNotFound)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestGetExportExpired(t *testing.T) {
+	repo := newMockExportRepository(t)
+	expiresAt := testNow.Add(-time.Minute)
+	exportUUID := uuid.New()
+	repo.exports = []*entity.DataExport{{ID: 1, UUID: exportUUID, UserID: 1, Status: entity.ExportStatusCompleted, ExpiresAt: &expiresAt}}
+	service := NewService(repo)
+
+	_, status, err := service.DownloadExport(testUserUUID, exportUUID)
+
+	assert.ErrorIs(t, err, ErrExportExpired)
+	assert.Equal(t, http.StatusGone, status)
+}
+
+func TestPurgeExpiredExports(t *testing.T) {
+	repo := newMockExportRepository(t)
+	service := NewService(repo)
+
+	deleted, err := service.PurgeExpiredExports(testNow)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	assert.Equal(t, testNow, repo.purgeBefore)
+}
+
+func TestDecryptProfileEmptyFields(t *testing.T) {
+	profile, err := decryptProfile(testKeyring, &entity.User{UUID: testUserUUID, Email: "ana@example.com"})
+
+	require.NoError(t, err)
+	assert.Empty(t, profile.FirstName)
+	assert.Empty(t, profile.ProfileImage)
+}
@@ -0,0 +1,44 @@
+package export
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+type Worker struct {
+	service ports.ExportService
+}
+
+func NewWorker(service ports.ExportService) *Worker {
+	return &Worker{
+		service: service,
+	}
+}
+
+// ProcessExports builds the archives of the pending personal data exports.
+func (w *Worker) ProcessExports() {
+	completed, err := w.service.ProcessPendingExports(time.Now().UTC())
+	if err != nil {
+		fmt.Println("Error processing the personal data exports:", err)
+		return
+	}
+
+	if completed > 0 {
+		fmt.Printf("%d personal data exports completed\n", completed)
+	}
+}
+
+// PurgeExpiredExports deletes the personal data exports whose retention period ended.
+func (w *Worker) PurgeExpiredExports() {
+	deleted, err := w.service.PurgeExpiredExports(time.Now().UTC())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if deleted > 0 {
+		fmt.Printf("Purged %d expired personal data exports\n", deleted)
+	}
+}
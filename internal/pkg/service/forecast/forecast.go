@@ -3,11 +3,37 @@ package forecast
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"sort"
+	"time"
 
+	"github.com/emur-uy/backend/config"
 	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/google/uuid"
 )
 
+var (
+	ErrFindingUser         = errors.New("error finding user")
+	ErrAssertingUser       = errors.New("error asserting user entity type")
+	ErrMissingLocation     = errors.New("the user has no city or country set")
+	ErrRetrievingForecasts = errors.New("error retrieving forecasts")
+)
+
+const (
+	// ForecastDays is the number of days returned by the forecast read API.
+	ForecastDays = 3
+	// DefaultHeatAlertMaxTemperature is the maximum temperature, in celsius, from which a heat alert is raised when none is configured.
+	DefaultHeatAlertMaxTemperature = 30
+	// DefaultHeatAlertUV is the UV index from which a heat alert is raised when none is configured.
+	DefaultHeatAlertUV = 8
+
+	forecastDateLayout = "2006-01-02"
+)
+
+// timeNow returns the current time, it is a variable so tests can fix the clock.
+var timeNow = time.Now
+
 type service struct {
 	repo ports.ForecastRepository
 }
@@ -29,6 +55,8 @@ func (s *service) GetDistinctCountryAndCityUsers() ([]entity.User, error) {
 	return users, nil
 }
 
+// CreateForecast saves the forecast of a location and date, updating the stored forecast
+// when the location was already fetched for that date.
 func (s *service) CreateForecast(createReq *entity.Forecast) error {
 
 	if createReq == nil {
@@ -37,6 +65,7 @@ func (s *service) CreateForecast(createReq *entity.Forecast) error {
 
 	// Crea una nueva entidad de pronóstico
 	forecast := &entity.Forecast{
+		LocationID:     createReq.LocationID,
 		Country:        createReq.Country,
 		State:          createReq.State,
 		AvgTemperature: createReq.AvgTemperature,
@@ -48,13 +77,118 @@ func (s *service) CreateForecast(createReq *entity.Forecast) error {
 		Wind:           createReq.Wind,
 		UV:             createReq.UV,
 		Date:           createReq.Date,
+		UpdatedAt:      timeNow().UTC(),
 	}
 
 	// Guarda el registro de pronóstico en la base de datos
-	err := s.repo.Create(forecast)
+	err := s.repo.UpsertForecast(forecast)
 	if err != nil {
 		return fmt.Errorf("error creating forecast record: %s", err)
 	}
 
 	return nil
 }
+
+// GetUserForecast retrieves the forecast of the next days stored for the city of the user
+// and the heat alerts of the days whose maximum temperature or UV index pass the configured thresholds.
+func (s *service) GetUserForecast(userUUID uuid.UUID) (*entity.UserForecast, int, error) {
+	// Find user by UUID
+	foundUser, err := s.repo.FindByUUID(userUUID, &entity.User{})
+	if err != nil {
+		return nil, http.StatusInternalServerError, ErrFindingUser
+	}
+	userEntity, ok := foundUser.(*entity.User)
+	if !ok {
+		return nil, http.StatusInternalServerError, ErrAssertingUser
+	}
+	if userEntity.City == "" || userEntity.Country == "" {
+		return nil, http.StatusBadRequest, ErrMissingLocation
+	}
+
+	// The forecast starts on the current day in the user's timezone
+	location, err := time.LoadLocation(userEntity.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	today := timeNow().In(location).Format(forecastDateLayout)
+
+	// Forecasts are stored per location, the users whose city is not geocoded yet
+	// fall back to the forecasts stored with the city in the state column
+	var forecasts []*entity.Forecast
+	conditions := []interface{}{"country = ? AND state = ? AND date >= ?", userEntity.Country, userEntity.City, today}
+	if userEntity.LocationID != nil {
+		conditions = []interface{}{"location_id = ? AND date >= ?", *userEntity.LocationID, today}
+	}
+	if err := s.repo.Find(&forecasts, conditions...); err != nil {
+		return nil, http.StatusInternalServerError, ErrRetrievingForecasts
+	}
+
+	upcoming := upcomingForecasts(forecasts, ForecastDays)
+	maxTemperature, uv := heatAlertThresholds()
+
+	return &entity.UserForecast{
+		City:      userEntity.City,
+		Country:   userEntity.Country,
+		Forecasts: upcoming,
+		Alerts:    heatAlerts(upcoming, maxTemperature, uv),
+	}, http.StatusOK, nil
+}
+
+// upcomingForecasts keeps the most recently stored forecast of each date and returns the first days sorted by date.
+func upcomingForecasts(forecasts []*entity.Forecast, days int) []*entity.Forecast {
+	forecastsByDate := map[string]*entity.Forecast{}
+	for _, forecast := range forecasts {
+		if existing, ok := forecastsByDate[forecast.Date]; !ok || forecast.ID > existing.ID {
+			forecastsByDate[forecast.Date] = forecast
+		}
+	}
+
+	upcoming := []*entity.Forecast{}
+	for _, forecast := range forecastsByDate {
+		upcoming = append(upcoming, forecast)
+	}
+	sort.Slice(upcoming, func(i, j int) bool { return upcoming[i].Date < upcoming[j].Date })
+
+	if len(upcoming) > days {
+		upcoming = upcoming[:days]
+	}
+	return upcoming
+}
+
+// heatAlerts returns an alert for each forecast whose maximum temperature or UV index is equal or greater than the thresholds.
+func heatAlerts(forecasts []*entity.Forecast, maxTemperature, uv int) []*entity.HeatAlert {
+	alerts := []*entity.HeatAlert{}
+	for _, forecast := range forecasts {
+		if forecast.MaxTemperature >= maxTemperature {
+			alerts = append(alerts, &entity.HeatAlert{
+				Date:      forecast.Date,
+				Type:      entity.HeatAlertTemperature,
+				Value:     forecast.MaxTemperature,
+				Threshold: maxTemperature,
+				Message:   fmt.Sprintf("Se esperan temperaturas de hasta %d°C, evitá la exposición al calor", forecast.MaxTemperature),
+			})
+		}
+		if forecast.UV >= uv {
+			alerts = append(alerts, &entity.HeatAlert{
+				Date:      forecast.Date,
+				Type:      entity.HeatAlertUV,
+				Value:     forecast.UV,
+				Threshold: uv,
+				Message:   fmt.Sprintf("Se espera un índice UV de %d, evitá la exposición al sol", forecast.UV),
+			})
+		}
+	}
+	return alerts
+}
+
+// heatAlertThresholds returns the configured heat alert thresholds, falling back to the defaults.
+func heatAlertThresholds() (int, int) {
+	maxTemperature, uv := DefaultHeatAlertMaxTemperature, DefaultHeatAlertUV
+	if configured := config.Get().HeatAlertMaxTemperature; configured > 0 {
+		maxTemperature = configured
+	}
+	if configured := config.Get().HeatAlertUV; configured > 0 {
+		uv = configured
+	}
+	return maxTemperature, uv
+}
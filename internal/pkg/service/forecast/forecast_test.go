@@ -1,13 +1,39 @@
 package forecast
 
 import (
+	"errors"
 	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"net/http"
 	"testing"
+	"time"
 )
 
-type mockForecastRepository struct{}
+var testUserUuid = uuid.MustParse("24df3f36-ca63-11ed-afa1-0242ac120002")
+var testUserWithoutCityUuid = uuid.MustParse("6f0b5a0e-1c6c-4a0e-9a43-3f0f8f6a2b11")
+
+type mockForecastRepository struct {
+	forecasts []*entity.Forecast
+}
+
+func (m mockForecastRepository) FindByUUID(id uuid.UUID, out interface{}) (interface{}, error) {
+	if id == testUserUuid {
+		return &entity.User{ID: 1, UUID: testUserUuid, City: "Montevideo", Country: "Uruguay", Timezone: "America/Montevideo"}, nil
+	}
+	if id == testUserWithoutCityUuid {
+		return &entity.User{ID: 2, UUID: testUserWithoutCityUuid}, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m mockForecastRepository) Find(dest interface{}, conditions ...interface{}) error {
+	if forecasts, ok := dest.(*[]*entity.Forecast); ok {
+		*forecasts = m.forecasts
+	}
+	return nil
+}
 
 func (m mockForecastRepository) Create(value interface{}) error {
 	return nil
@@ -17,6 +43,34 @@ func (m mockForecastRepository) GetDistinctCountryAndCityUsers(users *[]entity.U
 	return nil
 }
 
+func (m mockForecastRepository) UpsertForecast(forecast *entity.Forecast) error {
+	return nil
+}
+
+func (m mockForecastRepository) DeleteForecastsBefore(date string) (int64, error) {
+	return 0, nil
+}
+
+func (m mockForecastRepository) UpsertForecastFetch(fetch *entity.ForecastFetch) error {
+	return nil
+}
+
+func (m mockForecastRepository) FindOrCreateLocation(location *entity.Location) error {
+	return nil
+}
+
+func (m mockForecastRepository) AssignUserLocation(city string, country string, location *entity.Location) error {
+	return nil
+}
+
+func (m mockForecastRepository) GetUnresolvedUserLocations(users *[]entity.User) error {
+	return nil
+}
+
+func (m mockForecastRepository) GetUserLocations(locations *[]*entity.Location) error {
+	return nil
+}
+
 func TestGetDistinctCountryAndCityUsers(t *testing.T) {
 	// Initialize the mock repository and service.
 	mockRepo := &mockForecastRepository{}
@@ -57,3 +111,55 @@ func TestCreateForecast(t *testing.T) {
 		})
 	}
 }
+
+func TestGetUserForecast(t *testing.T) {
+	original := timeNow
+	timeNow = func() time.Time { return time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC) }
+	defer func() { timeNow = original }()
+
+	repo := &mockForecastRepository{forecasts: []*entity.Forecast{
+		{ID: 1, Date: "2023-01-11", MaxTemperature: 28, UV: 9},
+		{ID: 2, Date: "2023-01-10", MaxTemperature: 25, UV: 5},
+		{ID: 3, Date: "2023-01-12", MaxTemperature: 34, UV: 11},
+		{ID: 4, Date: "2023-01-13", MaxTemperature: 20, UV: 3},
+		// A newer forecast for the same date replaces the older one
+		{ID: 5, Date: "2023-01-10", MaxTemperature: 31, UV: 4},
+	}}
+	svc := NewService(repo)
+
+	forecast, statusCode, err := svc.GetUserForecast(testUserUuid)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, forecast.Forecasts, ForecastDays)
+	assert.Equal(t, "2023-01-10", forecast.Forecasts[0].Date)
+	assert.Equal(t, 31, forecast.Forecasts[0].MaxTemperature)
+	assert.Equal(t, "2023-01-12", forecast.Forecasts[2].Date)
+
+	require.Len(t, forecast.Alerts, 4)
+	assert.Equal(t, entity.HeatAlertTemperature, forecast.Alerts[0].Type)
+	assert.Equal(t, "2023-01-10", forecast.Alerts[0].Date)
+	assert.Equal(t, entity.HeatAlertUV, forecast.Alerts[1].Type)
+	assert.Equal(t, "2023-01-11", forecast.Alerts[1].Date)
+
+	_, statusCode, err = svc.GetUserForecast(testUserWithoutCityUuid)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	_, statusCode, err = svc.GetUserForecast(uuid.New())
+	require.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, statusCode)
+}
+
+func TestHeatAlerts(t *testing.T) {
+	forecasts := []*entity.Forecast{
+		{Date: "2023-01-10", MaxTemperature: 30, UV: 7},
+		{Date: "2023-01-11", MaxTemperature: 29, UV: 8},
+	}
+
+	alerts := heatAlerts(forecasts, 30, 8)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, entity.HeatAlertTemperature, alerts[0].Type)
+	assert.Equal(t, 30, alerts[0].Threshold)
+	assert.Equal(t, entity.HeatAlertUV, alerts[1].Type)
+	assert.Equal(t, "2023-01-11", alerts[1].Date)
+}
@@ -0,0 +1,103 @@
+package forecast
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+)
+
+const (
+	// RefreshInterval is the minimum time between two successful fetches of the same location.
+	RefreshInterval = time.Hour
+	// DefaultRetentionDays is the number of days forecasts are kept when no retention is configured.
+	// Past forecasts are used to correlate the symptoms of the users with the weather, so they are kept for a year.
+	DefaultRetentionDays = 365
+
+	retryBaseDelay = 5 * time.Minute
+	retryMaxDelay  = 2 * time.Hour
+)
+
+// GetForecastFetches retrieves the fetch state of every location indexed by location ID.
+func (s *service) GetForecastFetches() (map[int]*entity.ForecastFetch, error) {
+	var fetches []*entity.ForecastFetch
+	if err := s.repo.Find(&fetches); err != nil {
+		return nil, fmt.Errorf("error retrieving forecast fetches: %s", err)
+	}
+
+	fetchesByLocation := map[int]*entity.ForecastFetch{}
+	for _, fetch := range fetches {
+		fetchesByLocation[fetch.LocationID] = fetch
+	}
+	return fetchesByLocation, nil
+}
+
+// RecordFetchSuccess records a successful fetch of the location and clears its backoff.
+func (s *service) RecordFetchSuccess(fetch *entity.ForecastFetch, now time.Time) error {
+	fetch.LastSuccessAt = &now
+	fetch.LastAttemptAt = &now
+	fetch.NextAttemptAt = nil
+	fetch.Failures = 0
+	fetch.LastError = ""
+
+	if err := s.repo.UpsertForecastFetch(fetch); err != nil {
+		return fmt.Errorf("error recording forecast fetch: %s", err)
+	}
+	return nil
+}
+
+// RecordFetchFailure records a failed fetch of the location and schedules its next attempt with an exponential backoff.
+func (s *service) RecordFetchFailure(fetch *entity.ForecastFetch, now time.Time, fetchErr error) error {
+	fetch.Failures++
+	nextAttempt := now.Add(retryDelay(fetch.Failures))
+	fetch.LastAttemptAt = &now
+	fetch.NextAttemptAt = &nextAttempt
+	fetch.LastError = fetchErr.Error()
+
+	if err := s.repo.UpsertForecastFetch(fetch); err != nil {
+		return fmt.Errorf("error recording forecast fetch: %s", err)
+	}
+	return nil
+}
+
+// PruneForecasts deletes the forecasts older than the retention period.
+// Returns the number of deleted forecasts.
+func (s *service) PruneForecasts(now time.Time) (int64, error) {
+	retentionDays := DefaultRetentionDays
+	if configured := config.Get().ForecastRetentionDays; configured > 0 {
+		retentionDays = configured
+	}
+
+	cutoff := now.AddDate(0, 0, -retentionDays).Format(forecastDateLayout)
+	deleted, err := s.repo.DeleteForecastsBefore(cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("error pruning forecasts: %s", err)
+	}
+	return deleted, nil
+}
+
+// isFetchDue reports whether the location must be fetched, that is when it is not waiting for a retry
+// and it was not fetched successfully within the refresh interval.
+func isFetchDue(fetch *entity.ForecastFetch, now time.Time) bool {
+	if fetch.NextAttemptAt != nil && now.Before(*fetch.NextAttemptAt) {
+		return false
+	}
+	if fetch.Failures == 0 && fetch.LastSuccessAt != nil && now.Sub(*fetch.LastSuccessAt) < RefreshInterval {
+		return false
+	}
+	return true
+}
+
+// retryDelay returns the delay before retrying a location after the given number of consecutive failures,
+// doubling from retryBaseDelay up to retryMaxDelay.
+func retryDelay(failures int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,114 @@
+package forecast
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockIngestionRepository struct {
+	mockForecastRepository
+	fetches       []*entity.ForecastFetch
+	upserted      []*entity.Forecast
+	deletedBefore string
+}
+
+func (m *mockIngestionRepository) Find(dest interface{}, conditions ...interface{}) error {
+	if fetches, ok := dest.(*[]*entity.ForecastFetch); ok {
+		*fetches = m.fetches
+	}
+	return nil
+}
+
+func (m *mockIngestionRepository) UpsertForecast(forecast *entity.Forecast) error {
+	m.upserted = append(m.upserted, forecast)
+	return nil
+}
+
+func (m *mockIngestionRepository) DeleteForecastsBefore(date string) (int64, error) {
+	m.deletedBefore = date
+	return 2, nil
+}
+
+func (m *mockIngestionRepository) UpsertForecastFetch(fetch *entity.ForecastFetch) error {
+	m.fetches = append(m.fetches, fetch)
+	return nil
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 5*time.Minute, retryDelay(1))
+	assert.Equal(t, 10*time.Minute, retryDelay(2))
+	assert.Equal(t, 40*time.Minute, retryDelay(4))
+	assert.Equal(t, retryMaxDelay, retryDelay(10))
+}
+
+func TestIsFetchDue(t *testing.T) {
+	now := time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC)
+	recently := now.Add(-10 * time.Minute)
+	longAgo := now.Add(-2 * time.Hour)
+	later := now.Add(5 * time.Minute)
+
+	testCases := []struct {
+		name     string
+		fetch    *entity.ForecastFetch
+		expected bool
+	}{
+		{"never fetched", &entity.ForecastFetch{}, true},
+		{"fetched recently", &entity.ForecastFetch{LastSuccessAt: &recently}, false},
+		{"fetched long ago", &entity.ForecastFetch{LastSuccessAt: &longAgo}, true},
+		{"waiting for retry", &entity.ForecastFetch{LastSuccessAt: &longAgo, Failures: 1, NextAttemptAt: &later}, false},
+		{"retry due", &entity.ForecastFetch{LastSuccessAt: &recently, Failures: 1, NextAttemptAt: &recently}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isFetchDue(tc.fetch, now))
+		})
+	}
+}
+
+func TestRecordFetch(t *testing.T) {
+	repo := &mockIngestionRepository{}
+	svc := NewService(repo)
+	now := time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC)
+	fetch := &entity.ForecastFetch{LocationID: 1, Country: "Uruguay", State: "Montevideo"}
+
+	require.NoError(t, svc.RecordFetchFailure(fetch, now, errors.New("timeout")))
+	require.NoError(t, svc.RecordFetchFailure(fetch, now, errors.New("timeout")))
+	assert.Equal(t, 2, fetch.Failures)
+	assert.Equal(t, now.Add(10*time.Minute), *fetch.NextAttemptAt)
+	assert.Equal(t, "timeout", fetch.LastError)
+
+	require.NoError(t, svc.RecordFetchSuccess(fetch, now))
+	assert.Equal(t, 0, fetch.Failures)
+	assert.Nil(t, fetch.NextAttemptAt)
+	assert.Equal(t, now, *fetch.LastSuccessAt)
+
+	fetches, err := svc.GetForecastFetches()
+	require.NoError(t, err)
+	assert.Equal(t, fetch, fetches[1])
+}
+
+func TestPruneForecasts(t *testing.T) {
+	repo := &mockIngestionRepository{}
+	svc := NewService(repo)
+
+	deleted, err := svc.PruneForecasts(time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	assert.Equal(t, "2022-01-10", repo.deletedBefore)
+}
+
+func TestCreateForecastUpserts(t *testing.T) {
+	repo := &mockIngestionRepository{}
+	svc := NewService(repo)
+
+	require.NoError(t, svc.CreateForecast(&entity.Forecast{Country: "Uruguay", State: "Montevideo", Date: "2023-01-10", MaxTemperature: 30}))
+	require.NoError(t, svc.CreateForecast(&entity.Forecast{Country: "Uruguay", State: "Montevideo", Date: "2023-01-10", MaxTemperature: 31}))
+	require.Len(t, repo.upserted, 2)
+	assert.Equal(t, 31, repo.upserted[1].MaxTemperature)
+}
@@ -0,0 +1,51 @@
+package forecast
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+)
+
+// coordinatesPrecision is the number of decimals the coordinates of a location are rounded to,
+// about one kilometre, so the geocoding results for the same city share one location.
+const coordinatesPrecision = 2
+
+// GetUnresolvedUserLocations retrieves the distinct cities and countries of the users not linked to a location yet.
+func (s *service) GetUnresolvedUserLocations() ([]entity.User, error) {
+	var users []entity.User
+	if err := s.repo.GetUnresolvedUserLocations(&users); err != nil {
+		return nil, fmt.Errorf("error retrieving unresolved user locations: %s", err)
+	}
+	return users, nil
+}
+
+// ResolveLocation stores the geocoded location, sharing the stored location of the same coordinates if any,
+// and links the users of the city and country to it.
+func (s *service) ResolveLocation(city string, country string, location *entity.Location) error {
+	location.Latitude = roundCoordinate(location.Latitude)
+	location.Longitude = roundCoordinate(location.Longitude)
+
+	if err := s.repo.FindOrCreateLocation(location); err != nil {
+		return fmt.Errorf("error saving location: %s", err)
+	}
+	if err := s.repo.AssignUserLocation(city, country, location); err != nil {
+		return fmt.Errorf("error assigning location to users: %s", err)
+	}
+	return nil
+}
+
+// GetUserLocations retrieves the locations with at least one user.
+func (s *service) GetUserLocations() ([]*entity.Location, error) {
+	var locations []*entity.Location
+	if err := s.repo.GetUserLocations(&locations); err != nil {
+		return nil, fmt.Errorf("error retrieving user locations: %s", err)
+	}
+	return locations, nil
+}
+
+// roundCoordinate rounds a coordinate to coordinatesPrecision decimals.
+func roundCoordinate(coordinate float64) float64 {
+	factor := math.Pow(10, coordinatesPrecision)
+	return math.Round(coordinate*factor) / factor
+}
@@ -1,66 +1,149 @@
 package forecast
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/emur-uy/backend/internal/infra/forecast"
 	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
 )
 
+// fetchTimeout bounds the time spent fetching the forecast of a single location.
+const fetchTimeout = 30 * time.Second
+
 type Worker struct {
-	service *service
+	service  *service
+	provider ports.ForecastProvider
 }
 
-func NewWorker(service *service) *Worker {
+func NewWorker(service *service, provider ports.ForecastProvider) *Worker {
 	return &Worker{
-		service: service,
+		service:  service,
+		provider: provider,
 	}
 }
 
+// CheckForecast geocodes the cities of the users not linked to a location yet, then fetches by coordinates
+// the forecast of every location with users that is due for a refresh and saves it.
+// Locations whose fetch fails are retried on a later run with an exponential backoff,
+// and the forecasts older than the retention period are pruned.
 func (w *Worker) CheckForecast() {
-	users, err := w.service.GetDistinctCountryAndCityUsers()
+	now := timeNow().UTC()
+
+	w.resolveLocations()
+
+	locations, err := w.service.GetUserLocations()
 	if err != nil {
-		fmt.Println("Error getting distinct users:", err)
+		fmt.Println("Error getting user locations:", err)
 		return
 	}
 
-	for _, user := range users {
-		forecastData, _ := forecast.GetForecast("es", user.Country, user.City)
-
-		// Iterate over each day of the forecast
-		for _, d := range forecastData.ForecastInfo.ForecastDay {
-			// Parse the date to the custom format
-			fileName := getNameIcon(d.Day.Condition.Icon)
-
-			// Create the forecast object
-			forecast := &entity.Forecast{
-				Country:        user.Country,
-				State:          user.City,
-				AvgTemperature: int(d.Day.AvgTemperature),
-				MaxTemperature: int(d.Day.MaxTempC),
-				MinTemperature: int(d.Day.MinTempC),
-				Humidity:       d.Day.AvgHumidity,
-				Code:           fileName,
-				Description:    convertDescription(d.Day.Condition.Text),
-				Wind:           d.Day.Wind,
-				Date:           d.Date,
-				UV:             int(d.Day.UV),
-			}
+	fetches, err := w.service.GetForecastFetches()
+	if err != nil {
+		fmt.Println("Error getting forecast fetches:", err)
+		return
+	}
+
+	for _, location := range locations {
+		fetch, ok := fetches[location.ID]
+		if !ok {
+			fetch = &entity.ForecastFetch{LocationID: location.ID}
+		}
+		fetch.Country = location.Country
+		fetch.State = location.City
+		if !isFetchDue(fetch, now) {
+			continue
+		}
 
-			// Call the service function to create the forecast record in the database
-			err := w.service.CreateForecast(forecast)
-			if err != nil {
-				fmt.Printf("Error creating forecast record for user %s (%s, %s): %s\n", user.UUID, user.Country, user.City, err)
-				continue
+		if err := w.fetchLocation(location); err != nil {
+			fmt.Printf("Error fetching forecast for %s, %s (attempt %d): %s\n", location.City, location.Country, fetch.Failures+1, err)
+			if err := w.service.RecordFetchFailure(fetch, now, err); err != nil {
+				fmt.Println(err)
 			}
+			continue
+		}
+
+		if err := w.service.RecordFetchSuccess(fetch, now); err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	deleted, err := w.service.PruneForecasts(now)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if deleted > 0 {
+		fmt.Printf("Pruned %d old forecast records\n", deleted)
+	}
+}
+
+// resolveLocations geocodes the distinct cities of the users without a location and links them to it.
+// Cities that cannot be geocoded are retried on the next run.
+func (w *Worker) resolveLocations() {
+	users, err := w.service.GetUnresolvedUserLocations()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, user := range users {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		location, err := w.provider.Geocode(ctx, user.City, user.Country)
+		cancel()
+		if err != nil {
+			fmt.Printf("Error geocoding %s, %s: %s\n", user.City, user.Country, err)
+			continue
+		}
+
+		if err := w.service.ResolveLocation(user.City, user.Country, location); err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+// fetchLocation fetches the forecast of the location by its coordinates and saves each forecast day.
+func (w *Worker) fetchLocation(location *entity.Location) error {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	forecastData, err := w.provider.GetForecast(ctx, "es", location.Latitude, location.Longitude)
+	if err != nil {
+		return err
+	}
+
+	// Iterate over each day of the forecast
+	for _, d := range forecastData.ForecastInfo.ForecastDay {
+		// Parse the date to the custom format
+		fileName := getNameIcon(d.Day.Condition.Icon)
 
-			// Forecast record created successfully
-			fmt.Println("Forecast record created successfully")
+		// Create the forecast object
+		forecast := &entity.Forecast{
+			LocationID:     &location.ID,
+			Country:        location.Country,
+			State:          location.City,
+			AvgTemperature: int(d.Day.AvgTemperature),
+			MaxTemperature: int(d.Day.MaxTempC),
+			MinTemperature: int(d.Day.MinTempC),
+			Humidity:       d.Day.AvgHumidity,
+			Code:           fileName,
+			Description:    convertDescription(d.Day.Condition.Text),
+			Wind:           d.Day.Wind,
+			Date:           d.Date,
+			UV:             int(d.Day.UV),
+		}
+
+		// Call the service function to save the forecast record in the database
+		if err := w.service.CreateForecast(forecast); err != nil {
+			return err
 		}
 	}
+
+	return nil
 }
 
 func getNameIcon(fileName string) int {
@@ -0,0 +1,121 @@
+package forecast
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	forecastProvider "github.com/emur-uy/backend/internal/infra/forecast"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWorkerRepository struct {
+	mockIngestionRepository
+	users     []entity.User
+	locations []*entity.Location
+}
+
+func (m *mockWorkerRepository) GetUnresolvedUserLocations(users *[]entity.User) error {
+	for _, user := range m.users {
+		if user.LocationID == nil {
+			*users = append(*users, user)
+		}
+	}
+	return nil
+}
+
+func (m *mockWorkerRepository) FindOrCreateLocation(location *entity.Location) error {
+	for _, existing := range m.locations {
+		if existing.Latitude == location.Latitude && existing.Longitude == location.Longitude {
+			*location = *existing
+			return nil
+		}
+	}
+	location.ID = len(m.locations) + 1
+	m.locations = append(m.locations, location)
+	return nil
+}
+
+func (m *mockWorkerRepository) AssignUserLocation(city string, country string, location *entity.Location) error {
+	for i := range m.users {
+		if m.users[i].LocationID == nil && m.users[i].City == city && m.users[i].Country == country {
+			m.users[i].LocationID = &location.ID
+			if !m.users[i].TimezoneSetByUser {
+				m.users[i].Timezone = location.Timezone
+			}
+		}
+	}
+	return nil
+}
+
+func (m *mockWorkerRepository) GetUserLocations(locations *[]*entity.Location) error {
+	*locations = m.locations
+	return nil
+}
+
+func TestCheckForecast(t *testing.T) {
+	original := timeNow
+	now := time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = original }()
+
+	repo := &mockWorkerRepository{
+		users: []entity.User{
+			{ID: 1, Country: "Uruguay", City: "Montevideo", Timezone: "America/Montevideo"},
+			// The same city written differently shares the location
+			{ID: 2, Country: "uruguay", City: "montevideo ", Timezone: "America/Montevideo"},
+			{ID: 3, Country: "Uruguay", City: "Salto", Timezone: "America/Montevideo"},
+			{ID: 4, Country: "Uruguay", City: "Atlantida", Timezone: "America/Montevideo"},
+			// The default timezone follows the location, the timezone chosen by the user is kept
+			{ID: 5, Country: "Spain", City: "Madrid", Timezone: "America/Montevideo"},
+			{ID: 6, Country: "Spain", City: "Madrid", Timezone: "America/Sao_Paulo", TimezoneSetByUser: true},
+		},
+	}
+
+	worker := NewWorker(NewService(repo), forecastProvider.NewFileProvider("testdata"))
+	worker.CheckForecast()
+
+	// The known cities are linked to a location and get its timezone
+	require.Len(t, repo.locations, 3)
+	montevideo := repo.locations[0]
+	assert.Equal(t, -34.90, montevideo.Latitude)
+	assert.Equal(t, -56.19, montevideo.Longitude)
+	assert.Equal(t, montevideo.ID, *repo.users[0].LocationID)
+	assert.Equal(t, montevideo.ID, *repo.users[1].LocationID)
+	assert.Equal(t, "America/Montevideo", repo.users[1].Timezone)
+	assert.Equal(t, "Europe/Madrid", repo.users[4].Timezone)
+	assert.Equal(t, "America/Sao_Paulo", repo.users[5].Timezone)
+
+	// The unknown city is left to be geocoded on the next run
+	assert.Nil(t, repo.users[3].LocationID)
+
+	// Only Montevideo has a forecast fixture, its three days are saved once
+	require.Len(t, repo.upserted, 3)
+	assert.Equal(t, montevideo.ID, *repo.upserted[0].LocationID)
+	assert.Equal(t, "2023-01-10", repo.upserted[0].Date)
+	assert.Equal(t, 31, repo.upserted[0].MaxTemperature)
+	assert.Equal(t, "Montevideo", repo.upserted[0].State)
+	assert.Equal(t, "Uruguay", repo.upserted[0].Country)
+	assert.Equal(t, "Nublado", repo.upserted[1].Description)
+
+	fetches, err := NewService(repo).GetForecastFetches()
+	require.NoError(t, err)
+	require.NotNil(t, fetches[montevideo.ID])
+	assert.Equal(t, now, *fetches[montevideo.ID].LastSuccessAt)
+
+	// Salto has no forecast fixture, its failure is recorded and retried later
+	salto := fetches[repo.locations[1].ID]
+	require.NotNil(t, salto)
+	assert.Equal(t, 1, salto.Failures)
+	assert.Nil(t, salto.LastSuccessAt)
+	assert.Equal(t, now.Add(retryBaseDelay), *salto.NextAttemptAt)
+	assert.True(t, strings.Contains(salto.LastError, "cannot read fixture"))
+
+	assert.Equal(t, "2022-01-10", repo.deletedBefore)
+
+	// Montevideo was just fetched and is not fetched again on the next run
+	worker.CheckForecast()
+	assert.Len(t, repo.upserted, 3)
+}
@@ -0,0 +1,152 @@
+package media
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+var (
+	ErrGracePeriodTooShort = errors.New("the grace period must be longer than the lifetime of the upload sessions")
+	ErrMissingFolderName   = errors.New("the folder of the media is not set, the objects of the whole bucket cannot be collected")
+)
+
+const (
+	// OrphanGracePeriod is the age of the orphaned media and objects before they are deleted. It leaves the time to
+	// save the media of the records being created, and to confirm the upload sessions.
+	OrphanGracePeriod = 7 * 24 * time.Hour
+
+	// gcBatchSize is the number of media read per batch.
+	gcBatchSize = 100
+)
+
+// folderName returns the folder of the uploaded media in the bucket, replaced in the tests.
+var folderName = func() string {
+	return config.Get().MediaFolder()
+}
+
+// CollectOrphans deletes the media joined to no record, with their files, and then the objects of the store that
+// no media references, both once they are older than the grace period. They are left by the records that failed
+// to be created after their files were uploaded and by the deleted joins. The grace period must exceed the lifetime
+// of the upload sessions, as their objects have no media until they are confirmed. Only the objects of the media
+// folder are listed, nothing is collected without one as the bucket may hold the objects of other applications.
+func (s *service) CollectOrphans(now time.Time, gracePeriod time.Duration, dryRun bool) (*entity.MediaGCReport, error) {
+	if gracePeriod < UploadSessionTTL {
+		return nil, ErrGracePeriodTooShort
+	}
+	folder := folderName()
+	if folder == "" {
+		return nil, ErrMissingFolderName
+	}
+	before := now.Add(-gracePeriod)
+	report := &entity.MediaGCReport{DryRun: dryRun}
+
+	if err := s.collectOrphanMedia(before, dryRun, report); err != nil {
+		return report, err
+	}
+
+	referenced, err := s.referencedKeys()
+	if err != nil {
+		return report, err
+	}
+
+	objects, err := s.store.List(folder + "/")
+	if err != nil {
+		return report, fmt.Errorf("error listing the stored objects: %s", err)
+	}
+
+	for _, object := range objects {
+		report.ScannedObjects++
+		if referenced[object.Key] {
+			continue
+		}
+		if !object.LastModified.Before(before) {
+			report.Recent++
+			continue
+		}
+
+		report.OrphanObjects++
+		report.OrphanBytes += object.Size
+		log.Printf("orphan object %s (%d bytes, modified %s)", object.Key, object.Size, object.LastModified.Format(time.RFC3339))
+		if dryRun {
+			continue
+		}
+		if err := s.store.Delete(object.Key); err != nil {
+			log.Printf("error while deleting the orphan object %s: %s", object.Key, err.Error())
+			report.Failed++
+			continue
+		}
+		report.DeletedObjects++
+	}
+	return report, nil
+}
+
+// collectOrphanMedia deletes the media joined to no record created before the given time, then their files.
+// The files that fail to be deleted are left to the next run, as objects of no media.
+func (s *service) collectOrphanMedia(before time.Time, dryRun bool, report *entity.MediaGCReport) error {
+	afterID := 0
+	for {
+		medias, err := s.repo.FindOrphanMedia(before, afterID, gcBatchSize)
+		if err != nil {
+			return fmt.Errorf("error reading the orphan media after %d: %s", afterID, err)
+		}
+		if len(medias) == 0 {
+			return nil
+		}
+
+		for i := range medias {
+			media := &medias[i]
+			report.OrphanMedia++
+			log.Printf("orphan media %d (%s, created %s)", media.ID, media.MediaURL, media.CreatedAt.Format(time.RFC3339))
+			if dryRun {
+				continue
+			}
+
+			deleted, err := s.repo.DeleteOrphanMedia(media.ID)
+			if err != nil {
+				log.Printf("error while deleting the orphan media %d: %s", media.ID, err.Error())
+				report.Failed++
+				continue
+			}
+			if !deleted {
+				continue
+			}
+			report.DeletedMedia++
+
+			for _, url := range media.URLs() {
+				if err := s.store.Delete(s.store.Key(url)); err != nil && !errors.Is(err, ports.ErrBlobNotFound) {
+					log.Printf("error while deleting the media %s: %s", url, err.Error())
+					report.Failed++
+				}
+			}
+		}
+		afterID = medias[len(medias)-1].ID
+	}
+}
+
+// referencedKeys returns the keys of the files of every media.
+func (s *service) referencedKeys() (map[string]bool, error) {
+	keys := map[string]bool{}
+	afterID := 0
+	for {
+		medias, err := s.repo.FindMedia(afterID, gcBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the media after %d: %s", afterID, err)
+		}
+		if len(medias) == 0 {
+			return keys, nil
+		}
+
+		for i := range medias {
+			for _, url := range medias[i].URLs() {
+				keys[s.store.Key(url)] = true
+			}
+		}
+		afterID = medias[len(medias)-1].ID
+	}
+}
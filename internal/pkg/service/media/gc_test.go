@@ -0,0 +1,138 @@
+package media
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emur-uy/backend/internal/infra/storage"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var gcNow = time.Date(2023, 8, 20, 4, 30, 0, 0, time.UTC)
+
+// mockGCRepository is a media repository holding the media in memory, with the IDs of the media joined to a record.
+type mockGCRepository struct {
+	mockMediaRepository
+	medias []entity.Media
+	joined map[int]bool
+}
+
+func (m *mockGCRepository) FindMedia(afterID int, limit int) ([]entity.Media, error) {
+	var medias []entity.Media
+	for _, media := range m.medias {
+		if media.ID > afterID && len(medias) < limit {
+			medias = append(medias, media)
+		}
+	}
+	return medias, nil
+}
+
+func (m *mockGCRepository) FindOrphanMedia(before time.Time, afterID int, limit int) ([]entity.Media, error) {
+	var medias []entity.Media
+	for _, media := range m.medias {
+		if media.ID > afterID && !m.joined[media.ID] && media.CreatedAt.Before(before) && len(medias) < limit {
+			medias = append(medias, media)
+		}
+	}
+	return medias, nil
+}
+
+func (m *mockGCRepository) DeleteOrphanMedia(id int) (bool, error) {
+	for i, media := range m.medias {
+		if media.ID == id && !m.joined[id] {
+			m.medias = append(m.medias[:i], m.medias[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// newTestGC returns a media service with a joined media, two old orphan media and a recent one, and their files.
+// The store also holds an old object and a recent one of no media, the latter being uploaded.
+func newTestGC(t *testing.T) (*service, *mockGCRepository, *storage.MemoryStore) {
+	original := folderName
+	folderName = func() string { return "reminders" }
+	t.Cleanup(func() { folderName = original })
+
+	old, recent := gcNow.Add(-10*24*time.Hour), gcNow.Add(-time.Hour)
+	repo := &mockGCRepository{
+		medias: []entity.Media{
+			{ID: 1, MediaURL: "https://storage.test/reminders/a.jpg", MediaThumb: "https://storage.test/reminders/a_thumb.jpg", CreatedAt: old},
+			{ID: 2, MediaURL: "https://storage.test/reminders/b.jpg", MediaThumb: "https://storage.test/reminders/b_thumb.jpg", CreatedAt: old},
+			{ID: 3, MediaURL: "https://storage.test/reminders/c.jpg", CreatedAt: recent},
+			{ID: 4, MediaURL: "https://storage.test/reminders/d.pdf", CreatedAt: old},
+		},
+		joined: map[int]bool{1: true},
+	}
+
+	store := storage.NewMemoryStore()
+	objects := map[string]time.Time{
+		"reminders/a.jpg": old, "reminders/a_thumb.jpg": old, "reminders/b.jpg": old, "reminders/b_thumb.jpg": old,
+		"reminders/c.jpg": recent, "reminders/d.pdf": old, "reminders/x.jpg": old, "reminders/y.mp4": recent,
+		// The objects out of the media folder are never collected
+		"exports/z.zip": old,
+	}
+	for key, lastModified := range objects {
+		_, err := store.Put(key, strings.NewReader("data"), "", false)
+		require.NoError(t, err)
+		store.Objects[key].LastModified = lastModified
+	}
+	return &service{repo: repo, store: store}, repo, store
+}
+
+func TestCollectOrphansDryRun(t *testing.T) {
+	s, repo, store := newTestGC(t)
+
+	report, err := s.CollectOrphans(gcNow, OrphanGracePeriod, true)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.MediaGCReport{DryRun: true, OrphanMedia: 2, ScannedObjects: 8, OrphanObjects: 1, OrphanBytes: 4, Recent: 1}, report)
+	assert.Len(t, repo.medias, 4)
+	assert.Len(t, store.Objects, 9)
+	assert.Empty(t, store.Deleted)
+}
+
+func TestCollectOrphans(t *testing.T) {
+	s, repo, store := newTestGC(t)
+	store.Errors["reminders/d.pdf"] = errors.New("access denied")
+
+	report, err := s.CollectOrphans(gcNow, OrphanGracePeriod, false)
+	require.NoError(t, err)
+	// The file of the fourth media failed to be deleted with its media, and then as an object of no media
+	assert.Equal(t, &entity.MediaGCReport{OrphanMedia: 2, DeletedMedia: 2, ScannedObjects: 6, OrphanObjects: 2, OrphanBytes: 8, DeletedObjects: 1, Recent: 1, Failed: 2}, report)
+
+	ids := []int{}
+	for _, media := range repo.medias {
+		ids = append(ids, media.ID)
+	}
+	assert.Equal(t, []int{1, 3}, ids)
+	assert.ElementsMatch(t, []string{"reminders/b.jpg", "reminders/b_thumb.jpg", "reminders/x.jpg"}, store.Deleted)
+
+	// The next run deletes the remaining object
+	delete(store.Errors, "reminders/d.pdf")
+	report, err = s.CollectOrphans(gcNow, OrphanGracePeriod, false)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.MediaGCReport{ScannedObjects: 5, OrphanObjects: 1, OrphanBytes: 4, DeletedObjects: 1, Recent: 1}, report)
+	assert.NotContains(t, store.Objects, "reminders/d.pdf")
+}
+
+func TestCollectOrphansGracePeriod(t *testing.T) {
+	s, _, store := newTestGC(t)
+
+	_, err := s.CollectOrphans(gcNow, time.Hour, false)
+	assert.ErrorIs(t, err, ErrGracePeriodTooShort)
+	assert.Empty(t, store.Deleted)
+}
+
+func TestCollectOrphansWithoutFolder(t *testing.T) {
+	s, repo, store := newTestGC(t)
+	folderName = func() string { return "" }
+
+	_, err := s.CollectOrphans(gcNow, OrphanGracePeriod, false)
+	assert.ErrorIs(t, err, ErrMissingFolderName)
+	assert.Len(t, repo.medias, 4)
+	assert.Empty(t, store.Deleted)
+}
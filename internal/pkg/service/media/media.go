@@ -14,13 +14,15 @@ var (
 
 // service struct holds the necessary dependencies for the media service
 type service struct {
-	repo ports.MediaRepository
+	repo  ports.MediaRepository
+	store ports.BlobStore
 }
 
-// NewService returns a new instance of the media service with the given media repository.
-func NewService(mediaRepo ports.MediaRepository) ports.MediaService {
+// NewService returns a new instance of the media service with the given media repository and blob store.
+func NewService(mediaRepo ports.MediaRepository, store ports.BlobStore) ports.MediaService {
 	return &service{
-		repo: mediaRepo,
+		repo:  mediaRepo,
+		store: store,
 	}
 }
 
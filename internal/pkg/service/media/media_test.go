@@ -2,12 +2,14 @@ package media
 
 import (
 	"errors"
+	"github.com/emur-uy/backend/internal/infra/storage"
 	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"reflect"
 	"testing"
+	"time"
 )
 
 var testMediaUuid = uuid.MustParse("bfb23f5c-a664-432b-b6cc-b7cd17bacf5b")
@@ -35,10 +37,38 @@ func (m mockMediaRepository) Find(model interface{}, dest interface{}, condition
 	return errors.New("not found")
 }
 
+// FindDomainMedia returns the reminder media of the tests, after the given ID.
+func (m mockMediaRepository) FindDomainMedia(domain string, afterID int, limit int) ([]entity.Media, error) {
+	if domain != entity.MediaDomainReminders {
+		return nil, errors.New("unknown domain")
+	}
+	var medias []entity.Media
+	for _, media := range testReminderMedia {
+		if media.ID > afterID && len(medias) < limit {
+			medias = append(medias, media)
+		}
+	}
+	return medias, nil
+}
+
+// FindMedia returns the reminder media of the tests, after the given ID.
+func (m mockMediaRepository) FindMedia(afterID int, limit int) ([]entity.Media, error) {
+	return m.FindDomainMedia(entity.MediaDomainReminders, afterID, limit)
+}
+
+// FindOrphanMedia returns no media, the reminder media of the tests are joined to their reminders.
+func (m mockMediaRepository) FindOrphanMedia(before time.Time, afterID int, limit int) ([]entity.Media, error) {
+	return nil, nil
+}
+
+func (m mockMediaRepository) DeleteOrphanMedia(id int) (bool, error) {
+	return false, nil
+}
+
 func TestFindByMediaID(t *testing.T) {
 	// Initialize the mock repository and service.
 	mockRepo := &mockMediaRepository{}
-	s := NewService(mockRepo)
+	s := NewService(mockRepo, storage.NewMemoryStore())
 
 	// Test case 1: media found
 	err := s.FindByMediaID(1, &entity.Media{})
@@ -54,7 +84,7 @@ func TestCreateMedia(t *testing.T) {
 	// Create a mock repository
 	repo := &mockMediaRepository{}
 
-	svc := NewService(repo)
+	svc := NewService(repo, storage.NewMemoryStore())
 
 	testCases := []struct {
 		name        string
@@ -83,7 +113,7 @@ func TestCreateMedia(t *testing.T) {
 func TestDeleteMedia(t *testing.T) {
 	// Initialize the mock repository and service.
 	mockRepo := &mockMediaRepository{}
-	s := NewService(mockRepo)
+	s := NewService(mockRepo, storage.NewMemoryStore())
 
 	testCases := []struct {
 		name        string
@@ -0,0 +1,273 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/disintegration/imaging"
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrFileNotFound        = errors.New("file not found")
+	ErrUnsupportedFileType = errors.New("unsupported file type, must be a PNG or JPEG image")
+	ErrFileTooLarge        = errors.New("file too large")
+	ErrInvalidImage        = errors.New("invalid image")
+	ErrUploadingMedia      = errors.New("error uploading media")
+)
+
+const (
+	// MaxFileSize is the largest file that can be uploaded, in bytes.
+	MaxFileSize = 10 << 20
+	// MaxImageDimension is the largest width or height of a stored image, larger images are downscaled.
+	MaxImageDimension = 2048
+	// ThumbnailSize is the largest width or height of a thumbnail.
+	ThumbnailSize = 320
+
+	// maxPixels bounds the size of the decoded images, rejecting the decompression bombs.
+	maxPixels = 50_000_000
+	// jpegQuality is the quality of the re-encoded JPEG images and thumbnails.
+	jpegQuality = 85
+	// webPQuality is the quality of the WebP variants.
+	webPQuality = "80"
+	// webPTimeout bounds the encoding of a WebP variant.
+	webPTimeout = 30 * time.Second
+	// webPContentType is the content type of the WebP variants.
+	webPContentType = "image/webp"
+)
+
+// supportedFormats are the image formats accepted on upload, by sniffed content type.
+var supportedFormats = map[string]struct {
+	format imaging.Format
+	ext    string
+}{
+	"image/jpeg": {imaging.JPEG, ".jpg"},
+	"image/png":  {imaging.PNG, ".png"},
+}
+
+// encodeWebPFunc encodes the WebP variants, replaced in the tests.
+var encodeWebPFunc = encodeWebP
+
+// processedImage is an uploaded image cleaned of its metadata, with its variants.
+type processedImage struct {
+	contentType string
+	ext         string
+	width       int
+	height      int
+	original    []byte
+	thumb       []byte
+	webp        []byte
+}
+
+// UploadFormFiles processes the images of the "file" field of the multipart form and uploads them with their
+// thumbnail and WebP variant, private when the domain is. Every file is checked before any is uploaded, and
+// the files already uploaded are deleted if an upload fails. The returned media are not saved yet.
+func (s *service) UploadFormFiles(c *gin.Context, domain string) ([]*entity.Media, int, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("get form err: %s", err.Error())
+	}
+	files := form.File["file"]
+	if len(files) == 0 {
+		return nil, http.StatusBadRequest, ErrFileNotFound
+	}
+
+	images := make([]*processedImage, 0, len(files))
+	for _, file := range files {
+		data, err := readFormFile(file)
+		if err != nil {
+			if errors.Is(err, ErrFileTooLarge) {
+				return nil, http.StatusBadRequest, err
+			}
+			return nil, http.StatusInternalServerError, err
+		}
+
+		processed, err := processImage(data)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		images = append(images, processed)
+	}
+
+	var uploaded []string
+	medias := make([]*entity.Media, 0, len(images))
+	for _, processed := range images {
+		media, err := s.uploadImage(processed, !IsPrivateDomain(domain), &uploaded)
+		if err != nil {
+			log.Printf("error while uploading the media: %s", err.Error())
+			s.deleteUploaded(uploaded)
+			return nil, http.StatusInternalServerError, ErrUploadingMedia
+		}
+		medias = append(medias, media)
+	}
+
+	return medias, http.StatusOK, nil
+}
+
+// readFormFile reads an uploaded file, up to MaxFileSize.
+func readFormFile(file *multipart.FileHeader) ([]byte, error) {
+	if file.Size > MaxFileSize {
+		return nil, ErrFileTooLarge
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %s", err)
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, MaxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %s", err)
+	}
+	if len(data) > MaxFileSize {
+		return nil, ErrFileTooLarge
+	}
+	return data, nil
+}
+
+// processImage sniffs the content type of the image from its content, ignoring the name and the header
+// sent by the client. The image is decoded, turned upright according to its EXIF orientation and re-encoded,
+// which drops every metadata, the EXIF and GPS data included. The thumbnail and the WebP variant are generated
+// from the cleaned image; the WebP variant is skipped if it cannot be encoded.
+func processImage(data []byte) (*processedImage, error) {
+	contentType := http.DetectContentType(data)
+	supported, ok := supportedFormats[contentType]
+	if !ok {
+		return nil, ErrUnsupportedFileType
+	}
+
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if imageConfig.Width*imageConfig.Height > maxPixels {
+		return nil, ErrFileTooLarge
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if bounds := img.Bounds(); bounds.Dx() > MaxImageDimension || bounds.Dy() > MaxImageDimension {
+		img = imaging.Fit(img, MaxImageDimension, MaxImageDimension, imaging.Lanczos)
+	}
+
+	original, err := encodeImage(img, supported.format)
+	if err != nil {
+		return nil, err
+	}
+	thumb, err := encodeImage(imaging.Fit(img, ThumbnailSize, ThumbnailSize, imaging.Lanczos), supported.format)
+	if err != nil {
+		return nil, err
+	}
+
+	webp, err := encodeWebPFunc(img)
+	if err != nil {
+		log.Printf("error while encoding the WebP variant: %s", err.Error())
+		webp = nil
+	}
+
+	return &processedImage{
+		contentType: contentType,
+		ext:         supported.ext,
+		width:       img.Bounds().Dx(),
+		height:      img.Bounds().Dy(),
+		original:    original,
+		thumb:       thumb,
+		webp:        webp,
+	}, nil
+}
+
+// encodeImage encodes the image in the given format, without metadata.
+func encodeImage(img image.Image, format imaging.Format) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	if err := imaging.Encode(buffer, img, format, imaging.JPEGQuality(jpegQuality)); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %s", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// encodeWebP encodes the image in WebP with ffmpeg, installed with the application.
+func encodeWebP(img image.Image) ([]byte, error) {
+	input := new(bytes.Buffer)
+	if err := png.Encode(input, img); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webPTimeout)
+	defer cancel()
+
+	output, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	cmd := exec.CommandContext(ctx, "ffmpeg", "-hide_banner", "-loglevel", "error",
+		"-f", "png_pipe", "-i", "pipe:0",
+		"-c:v", "libwebp", "-quality", webPQuality, "-pix_fmt", "yuva420p",
+		"-f", "webp", "pipe:1")
+	cmd.Stdin = input
+	cmd.Stdout = output
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return output.Bytes(), nil
+}
+
+// uploadImage uploads the image and its variants under a new name, adding their URLs to uploaded.
+func (s *service) uploadImage(processed *processedImage, public bool, uploaded *[]string) (*entity.Media, error) {
+	name := fmt.Sprintf("%s/%s", config.Get().MediaFolder(), uuid.New().String())
+
+	upload := func(data []byte, uploadPath string, contentType string) (string, error) {
+		url, err := s.store.Put(uploadPath, bytes.NewReader(data), contentType, public)
+		if err != nil {
+			return "", err
+		}
+		if url == "" {
+			return "", fmt.Errorf("no URL returned for %s", uploadPath)
+		}
+		*uploaded = append(*uploaded, url)
+		return url, nil
+	}
+
+	media := &entity.Media{
+		ContentType: processed.contentType,
+		Width:       processed.width,
+		Height:      processed.height,
+	}
+
+	var err error
+	if media.MediaURL, err = upload(processed.original, name+processed.ext, processed.contentType); err != nil {
+		return nil, err
+	}
+	if media.MediaThumb, err = upload(processed.thumb, name+"_thumb"+processed.ext, processed.contentType); err != nil {
+		return nil, err
+	}
+	if processed.webp != nil {
+		if media.MediaWebP, err = upload(processed.webp, name+".webp", webPContentType); err != nil {
+			return nil, err
+		}
+	}
+	return media, nil
+}
+
+// deleteUploaded deletes the files uploaded before a failure, logging the failures.
+func (s *service) deleteUploaded(urls []string) {
+	for _, url := range urls {
+		if err := s.store.Delete(s.store.Key(url)); err != nil {
+			log.Printf("error while deleting the media %s: %s", url, err.Error())
+		}
+	}
+}
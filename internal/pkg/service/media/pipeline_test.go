@@ -0,0 +1,223 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/emur-uy/backend/internal/infra/storage"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// formFile is a file of the multipart form sent to UploadFormFiles.
+type formFile struct {
+	name        string
+	contentType string
+	data        []byte
+}
+
+// failingStore is a MemoryStore failing to store the keys with the given suffix.
+type failingStore struct {
+	*storage.MemoryStore
+	suffix string
+}
+
+func (f *failingStore) Put(key string, body io.Reader, contentType string, public bool) (string, error) {
+	if strings.HasSuffix(key, f.suffix) {
+		return "", errors.New("access denied")
+	}
+	return f.MemoryStore.Put(key, body, contentType, public)
+}
+
+// newTestPipeline returns a media service storing the files in memory, with a stubbed WebP encoder.
+func newTestPipeline(t *testing.T) (*service, *storage.MemoryStore) {
+	original := encodeWebPFunc
+	encodeWebPFunc = func(img image.Image) ([]byte, error) {
+		return []byte("RIFF----WEBP"), nil
+	}
+	t.Cleanup(func() { encodeWebPFunc = original })
+
+	store := storage.NewMemoryStore()
+	return &service{store: store}, store
+}
+
+// newUploadContext returns a context with a multipart request holding the given files in the "file" field.
+func newUploadContext(t *testing.T, files ...formFile) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for _, file := range files {
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", `form-data; name="file"; filename="`+file.name+`"`)
+		h.Set("Content-Type", file.contentType)
+		part, err := writer.CreatePart(h)
+		require.NoError(t, err)
+		_, err = part.Write(file.data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	c.Request = httptest.NewRequest(http.MethodPost, "/", body)
+	c.Request.Header.Set("Content-Type", writer.FormDataContentType())
+	return c
+}
+
+func testImage(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+func testPNG(t *testing.T, width, height int) []byte {
+	buffer := &bytes.Buffer{}
+	require.NoError(t, png.Encode(buffer, testImage(width, height)))
+	return buffer.Bytes()
+}
+
+// testJPEGWithExif returns a JPEG image with an APP1 segment holding EXIF data with GPS coordinates.
+func testJPEGWithExif(t *testing.T, width, height int) []byte {
+	buffer := &bytes.Buffer{}
+	require.NoError(t, jpeg.Encode(buffer, testImage(width, height), nil))
+	data := buffer.Bytes()
+
+	exif := append([]byte("Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08\x00\x00"), []byte("GPSLatitude -34.9011 GPSLongitude -56.1645")...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(exif)+2))
+	segment = append(segment, exif...)
+
+	// The segment is inserted right after the start of image marker.
+	withExif := append([]byte{}, data[:2]...)
+	withExif = append(withExif, segment...)
+	return append(withExif, data[2:]...)
+}
+
+func TestUploadFormFilesStripsMetadata(t *testing.T) {
+	s, store := newTestPipeline(t)
+	data := testJPEGWithExif(t, 64, 48)
+	require.True(t, bytes.Contains(data, []byte("GPSLatitude")))
+
+	medias, status, err := s.UploadFormFiles(newUploadContext(t, formFile{"photo.jpg", "image/jpeg", data}), entity.MediaDomainArticles)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	require.Len(t, medias, 1)
+
+	media := medias[0]
+	assert.Equal(t, "image/jpeg", media.ContentType)
+	assert.Equal(t, 64, media.Width)
+	assert.Equal(t, 48, media.Height)
+	assert.True(t, strings.HasSuffix(media.MediaURL, ".jpg"))
+	assert.True(t, strings.HasSuffix(media.MediaThumb, "_thumb.jpg"))
+	assert.True(t, strings.HasSuffix(media.MediaWebP, ".webp"))
+
+	require.Len(t, store.Objects, 3)
+	for key, object := range store.Objects {
+		assert.True(t, object.Public, key)
+		assert.False(t, bytes.Contains(object.Data, []byte("Exif")), key)
+		assert.False(t, bytes.Contains(object.Data, []byte("GPSLatitude")), key)
+	}
+	assert.Equal(t, "image/webp", store.Objects[s.store.Key(media.MediaWebP)].ContentType)
+}
+
+func TestUploadFormFilesSniffsContentType(t *testing.T) {
+	s, _ := newTestPipeline(t)
+
+	medias, _, err := s.UploadFormFiles(newUploadContext(t, formFile{"photo.jpg", "image/jpeg", testPNG(t, 10, 10)}), entity.MediaDomainArticles)
+	require.NoError(t, err)
+	require.Len(t, medias, 1)
+	assert.Equal(t, "image/png", medias[0].ContentType)
+	assert.True(t, strings.HasSuffix(medias[0].MediaURL, ".png"))
+
+	_, status, err := s.UploadFormFiles(newUploadContext(t, formFile{"photo.png", "image/png", []byte("<html>not an image</html>")}), entity.MediaDomainArticles)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.ErrorIs(t, err, ErrUnsupportedFileType)
+}
+
+func TestUploadFormFilesResizes(t *testing.T) {
+	s, store := newTestPipeline(t)
+
+	medias, _, err := s.UploadFormFiles(newUploadContext(t, formFile{"large.png", "image/png", testPNG(t, 3000, 1500)}), entity.MediaDomainArticles)
+	require.NoError(t, err)
+	require.Len(t, medias, 1)
+	assert.Equal(t, MaxImageDimension, medias[0].Width)
+	assert.Equal(t, MaxImageDimension/2, medias[0].Height)
+
+	thumb, _, err := image.DecodeConfig(bytes.NewReader(store.Objects[s.store.Key(medias[0].MediaThumb)].Data))
+	require.NoError(t, err)
+	assert.Equal(t, ThumbnailSize, thumb.Width)
+	assert.Equal(t, ThumbnailSize/2, thumb.Height)
+}
+
+func TestUploadFormFilesWithoutWebP(t *testing.T) {
+	s, store := newTestPipeline(t)
+	encodeWebPFunc = func(img image.Image) ([]byte, error) {
+		return nil, errors.New("ffmpeg not found")
+	}
+
+	medias, _, err := s.UploadFormFiles(newUploadContext(t, formFile{"photo.png", "image/png", testPNG(t, 10, 10)}), entity.MediaDomainArticles)
+	require.NoError(t, err)
+	require.Len(t, medias, 1)
+	assert.Empty(t, medias[0].MediaWebP)
+	assert.NotEmpty(t, medias[0].MediaThumb)
+	assert.Len(t, store.Objects, 2)
+}
+
+func TestUploadFormFilesRollback(t *testing.T) {
+	s, store := newTestPipeline(t)
+	s.store = &failingStore{MemoryStore: store, suffix: ".webp"}
+
+	_, status, err := s.UploadFormFiles(newUploadContext(t, formFile{"photo.png", "image/png", testPNG(t, 10, 10)}), entity.MediaDomainArticles)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.ErrorIs(t, err, ErrUploadingMedia)
+
+	assert.Len(t, store.Deleted, 2)
+	assert.Empty(t, store.Objects)
+}
+
+func TestUploadFormFilesErrors(t *testing.T) {
+	s, store := newTestPipeline(t)
+
+	testCases := []struct {
+		name        string
+		files       []formFile
+		expectError error
+	}{
+		{"no file", nil, ErrFileNotFound},
+		{"file too large", []formFile{{"large.png", "image/png", make([]byte, MaxFileSize+1)}}, ErrFileTooLarge},
+		{"corrupted image", []formFile{{"broken.png", "image/png", testPNG(t, 10, 10)[:40]}}, ErrInvalidImage},
+		{
+			"one invalid file",
+			[]formFile{{"photo.png", "image/png", testPNG(t, 10, 10)}, {"notes.txt", "image/png", []byte("some notes")}},
+			ErrUnsupportedFileType,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			medias, status, err := s.UploadFormFiles(newUploadContext(t, tc.files...), entity.MediaDomainArticles)
+			assert.Nil(t, medias)
+			assert.Equal(t, http.StatusBadRequest, status)
+			assert.ErrorIs(t, err, tc.expectError)
+		})
+	}
+	assert.Empty(t, store.Objects)
+}
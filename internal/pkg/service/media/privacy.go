@@ -0,0 +1,112 @@
+package media
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+const (
+	// SignedURLExpiry is the lifetime of the URLs of the private media.
+	SignedURLExpiry = 15 * time.Minute
+	// DefaultPrivacyBatchSize is the number of media made private per batch when none is given.
+	DefaultPrivacyBatchSize = 100
+)
+
+// privateDomains are the domains whose media are stored private and read through signed URLs only.
+// The attachments of the reminders can be lab results or prescriptions.
+var privateDomains = map[string]bool{
+	entity.MediaDomainReminders: true,
+}
+
+// IsPrivateDomain reports whether the media of the domain are private.
+func IsPrivateDomain(domain string) bool {
+	return privateDomains[domain]
+}
+
+// timeNow returns the current time, replaced in the tests.
+var timeNow = time.Now
+
+// SignMedia generates the signed URLs of the media and its variants, expiring after SignedURLExpiry.
+func (s *service) SignMedia(media *entity.Media) (*entity.SignedMedia, error) {
+	signed := &entity.SignedMedia{
+		UUID:      media.UUID,
+		ExpiresAt: timeNow().Add(SignedURLExpiry).UTC(),
+	}
+
+	var err error
+	if signed.MediaURL, err = s.signURL(media.MediaURL); err != nil {
+		return nil, err
+	}
+	if signed.MediaThumb, err = s.signURL(media.MediaThumb); err != nil {
+		return nil, err
+	}
+	if signed.MediaWebP, err = s.signURL(media.MediaWebP); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// signURL returns the signed URL of the uploaded file, or an empty URL for a variant that was not generated.
+func (s *service) signURL(url string) (string, error) {
+	if url == "" {
+		return "", nil
+	}
+	signedURL, err := s.store.SignedURL(s.store.Key(url), SignedURLExpiry)
+	if err != nil {
+		return "", fmt.Errorf("error signing the media %s: %s", url, err)
+	}
+	return signedURL, nil
+}
+
+// MakeDomainPrivate makes private the files of every media of the domain, the original and its variants.
+// The media already private are updated again, so the command can be run until nothing fails.
+func (s *service) MakeDomainPrivate(domain string, batchSize int, dryRun bool) (*entity.MediaPrivacyReport, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultPrivacyBatchSize
+	}
+
+	report := &entity.MediaPrivacyReport{Domain: domain}
+	afterID := 0
+	for {
+		medias, err := s.repo.FindDomainMedia(domain, afterID, batchSize)
+		if err != nil {
+			return report, fmt.Errorf("error reading the media after %d: %s", afterID, err)
+		}
+		if len(medias) == 0 {
+			return report, nil
+		}
+
+		for i := range medias {
+			s.makeMediaPrivate(&medias[i], dryRun, report)
+		}
+		afterID = medias[len(medias)-1].ID
+	}
+}
+
+// makeMediaPrivate makes the files of the media private, adding the outcome to the report.
+func (s *service) makeMediaPrivate(media *entity.Media, dryRun bool, report *entity.MediaPrivacyReport) {
+	report.Scanned++
+
+	for _, url := range media.URLs() {
+		if dryRun {
+			report.Updated++
+			continue
+		}
+
+		err := s.store.SetPublic(s.store.Key(url), false)
+		switch {
+		case errors.Is(err, ports.ErrBlobNotFound):
+			report.Missing++
+		case err != nil:
+			log.Printf("error while making the media %s private: %s", url, err.Error())
+			report.Failed++
+		default:
+			report.Updated++
+		}
+	}
+}
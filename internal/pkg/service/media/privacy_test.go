@@ -0,0 +1,94 @@
+package media
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emur-uy/backend/internal/infra/storage"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testReminderMedia are the media of the reminders returned by the mock repository.
+var testReminderMedia = []entity.Media{
+	{ID: 1, UUID: testMediaUuid, MediaURL: "https://storage.test/reminders/a.jpg", MediaThumb: "https://storage.test/reminders/a_thumb.jpg", MediaWebP: "https://storage.test/reminders/a.webp"},
+	{ID: 2, MediaURL: "https://storage.test/reminders/b.jpg", MediaThumb: "https://storage.test/reminders/b_thumb.jpg"},
+	{ID: 3, MediaURL: "https://storage.test/reminders/c.jpg"},
+}
+
+// newTestStore returns a MemoryStore holding the files of the reminder media as public objects,
+// except the missing file of the third media.
+func newTestStore(t *testing.T) *storage.MemoryStore {
+	store := storage.NewMemoryStore()
+	for _, media := range testReminderMedia[:2] {
+		for _, url := range media.URLs() {
+			_, err := store.Put(store.Key(url), strings.NewReader("image"), "image/jpeg", true)
+			require.NoError(t, err)
+		}
+	}
+	return store
+}
+
+func TestUploadFormFilesPrivateDomain(t *testing.T) {
+	s, store := newTestPipeline(t)
+
+	medias, _, err := s.UploadFormFiles(newUploadContext(t, formFile{"results.png", "image/png", testPNG(t, 10, 10)}), entity.MediaDomainReminders)
+	require.NoError(t, err)
+	require.Len(t, medias, 1)
+	require.Len(t, store.Objects, 3)
+	for key, object := range store.Objects {
+		assert.False(t, object.Public, key)
+	}
+}
+
+func TestSignMedia(t *testing.T) {
+	now := time.Date(2023, 8, 6, 10, 0, 0, 0, time.UTC)
+	original := timeNow
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = original })
+
+	store := newTestStore(t)
+	s := NewService(&mockMediaRepository{}, store)
+
+	signed, err := s.SignMedia(&testReminderMedia[0])
+	require.NoError(t, err)
+	assert.Equal(t, testMediaUuid, signed.UUID)
+	assert.Equal(t, now.Add(SignedURLExpiry), signed.ExpiresAt)
+	assert.True(t, strings.HasPrefix(signed.MediaURL, "https://storage.test/reminders/a.jpg?expires="))
+	assert.True(t, strings.HasPrefix(signed.MediaThumb, "https://storage.test/reminders/a_thumb.jpg?expires="))
+	assert.True(t, strings.HasPrefix(signed.MediaWebP, "https://storage.test/reminders/a.webp?expires="))
+
+	signed, err = s.SignMedia(&testReminderMedia[2])
+	require.NoError(t, err)
+	assert.Empty(t, signed.MediaThumb)
+	assert.Empty(t, signed.MediaWebP)
+
+	store.Errors["reminders/b.jpg"] = errors.New("access denied")
+	_, err = s.SignMedia(&testReminderMedia[1])
+	assert.Error(t, err)
+}
+
+func TestMakeDomainPrivate(t *testing.T) {
+	store := newTestStore(t)
+	store.Errors["reminders/b_thumb.jpg"] = errors.New("access denied")
+	s := NewService(&mockMediaRepository{}, store)
+
+	report, err := s.MakeDomainPrivate(entity.MediaDomainReminders, 2, true)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.MediaPrivacyReport{Domain: entity.MediaDomainReminders, Scanned: 3, Updated: 6}, report)
+	assert.True(t, store.Objects["reminders/a.jpg"].Public)
+
+	report, err = s.MakeDomainPrivate(entity.MediaDomainReminders, 2, false)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.MediaPrivacyReport{Domain: entity.MediaDomainReminders, Scanned: 3, Updated: 4, Missing: 1, Failed: 1}, report)
+	for _, key := range []string{"reminders/a.jpg", "reminders/a_thumb.jpg", "reminders/a.webp", "reminders/b.jpg"} {
+		assert.False(t, store.Objects[key].Public, key)
+	}
+	assert.True(t, store.Objects["reminders/b_thumb.jpg"].Public)
+
+	_, err = s.MakeDomainPrivate("users", 0, false)
+	assert.Error(t, err)
+}
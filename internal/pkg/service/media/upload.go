@@ -0,0 +1,470 @@
+package media
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUserNotFound          = errors.New("user not found")
+	ErrInvalidDomain         = errors.New("invalid domain, must be reminders, articles or recipes")
+	ErrRecordNotFound        = errors.New("record not found")
+	ErrUnsupportedUploadType = errors.New("unsupported file type, must be a PNG or JPEG image, a PDF or an MP4 or WebM video")
+	ErrInvalidFileSize       = errors.New("invalid file size")
+	ErrUploadNotFound        = errors.New("upload not found")
+	ErrUploadNotPending      = errors.New("the upload is already completed or cancelled")
+	ErrUploadExpired         = errors.New("the upload expired, request a new one")
+	ErrIncompleteUpload      = errors.New("the file is not uploaded completely")
+	ErrFileMismatch          = errors.New("the uploaded file does not match the upload")
+	ErrCreatingUpload        = errors.New("error creating the upload")
+	ErrAttachingMedia        = errors.New("error attaching the media")
+	ErrDiscardingUpload      = errors.New("error discarding the upload")
+	ErrFindingUploads        = errors.New("error finding the expired uploads")
+	ErrTypeAssertion         = errors.New("type assertion failed")
+)
+
+const (
+	// MaxUploadSize is the largest file that can be uploaded directly to the store, in bytes.
+	// The images are limited to MaxFileSize, as they are processed once uploaded.
+	MaxUploadSize = 500 << 20
+	// UploadPartSize is the size of the parts of the multipart uploads, the larger files being uploaded in parts.
+	UploadPartSize = 8 << 20
+	// UploadSessionTTL is the time to upload and confirm a file, the upload URLs being signed again until then.
+	UploadSessionTTL = 24 * time.Hour
+	// UploadURLExpiry is the lifetime of the signed upload URLs.
+	UploadURLExpiry = time.Hour
+
+	// expiredUploadsBatchSize is the number of expired upload sessions discarded on each run of the worker.
+	expiredUploadsBatchSize = 100
+	// sniffLength is the number of bytes read to sniff the content type of an uploaded file.
+	sniffLength = 512
+)
+
+// uploadTypes are the extensions of the types of the files uploaded directly to the store, by content type.
+var uploadTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"application/pdf": ".pdf",
+	"video/mp4":       ".mp4",
+	"video/webm":      ".webm",
+}
+
+// uploadService struct holds the necessary dependencies for the upload service.
+// The uploaded images go through the same pipeline as the images of the forms.
+type uploadService struct {
+	repo     ports.UploadRepository
+	store    ports.BlobStore
+	pipeline *service
+}
+
+// NewUploadService returns a new instance of the upload service with the given upload repository and blob store.
+func NewUploadService(repo ports.UploadRepository, store ports.BlobStore) ports.UploadService {
+	return &uploadService{
+		repo:     repo,
+		store:    store,
+		pipeline: &service{store: store},
+	}
+}
+
+// CreateUpload checks the declared type and size of the file and the access of the user to the record, then
+// opens a session with a URL uploading the file, or a URL per part for the files larger than UploadPartSize.
+// The file is private until the upload is confirmed. The reminders can only be uploaded for by their owner,
+// the permission to edit the articles and recipes being checked by the handler.
+func (s *uploadService) CreateUpload(userUUID uuid.UUID, request *entity.RequestCreateUpload) (*entity.UploadSessionResponse, int, error) {
+	if !entity.IsMediaDomain(request.Domain) {
+		return nil, http.StatusBadRequest, ErrInvalidDomain
+	}
+	ext, ok := uploadTypes[request.ContentType]
+	if !ok {
+		return nil, http.StatusBadRequest, ErrUnsupportedUploadType
+	}
+	if request.Size <= 0 {
+		return nil, http.StatusBadRequest, ErrInvalidFileSize
+	}
+	if request.Size > maxUploadSize(request.ContentType) {
+		return nil, http.StatusBadRequest, ErrFileTooLarge
+	}
+
+	user, status, err := s.findUser(userUUID)
+	if err != nil {
+		return nil, status, err
+	}
+	record, err := s.repo.FindRecord(request.Domain, request.RecordUUID)
+	if err != nil || (record.UserID != 0 && record.UserID != user.ID) {
+		return nil, http.StatusNotFound, ErrRecordNotFound
+	}
+
+	now := timeNow().UTC()
+	upload := &entity.UploadSession{
+		UUID:        uuid.New(),
+		UserID:      user.ID,
+		Domain:      request.Domain,
+		RecordID:    record.ID,
+		ContentType: request.ContentType,
+		Size:        request.Size,
+		Status:      entity.UploadStatusPending,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(UploadSessionTTL),
+	}
+	upload.Key = fmt.Sprintf("%s/%s%s", config.Get().MediaFolder(), upload.UUID, ext)
+
+	if upload.Size > UploadPartSize {
+		upload.PartSize = UploadPartSize
+		if upload.UploadID, err = s.store.CreateMultipartUpload(upload.Key, upload.ContentType); err != nil {
+			log.Printf("error while creating the multipart upload of %s: %s", upload.Key, err.Error())
+			return nil, http.StatusInternalServerError, ErrCreatingUpload
+		}
+	}
+
+	if err := s.repo.CreateUpload(upload); err != nil {
+		log.Printf("error while saving the upload %s: %s", upload.UUID, err.Error())
+		s.discardFiles(upload)
+		return nil, http.StatusInternalServerError, ErrCreatingUpload
+	}
+
+	response, err := s.signUpload(upload, now)
+	if err != nil {
+		log.Printf("error while signing the upload %s: %s", upload.UUID, err.Error())
+		return nil, http.StatusInternalServerError, ErrCreatingUpload
+	}
+	return response, http.StatusCreated, nil
+}
+
+// GetUpload retrieves a pending upload session with new upload URLs, so an interrupted upload can be resumed.
+func (s *uploadService) GetUpload(userUUID, uploadUUID uuid.UUID) (*entity.UploadSessionResponse, int, error) {
+	upload, status, err := s.findPendingUpload(userUUID, uploadUUID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	response, err := s.signUpload(upload, timeNow().UTC())
+	if err != nil {
+		log.Printf("error while signing the upload %s: %s", upload.UUID, err.Error())
+		return nil, http.StatusInternalServerError, ErrCreatingUpload
+	}
+	return response, http.StatusOK, nil
+}
+
+// ConfirmUpload assembles the parts of a multipart upload, then checks the size of the uploaded file and its
+// type, sniffed from its content, against the session. A file that does not match is deleted and the session
+// fails. The images are processed as the images of the forms, the other files are made public unless the domain
+// is private. The media is attached to the record and the session completed in a single transaction.
+func (s *uploadService) ConfirmUpload(userUUID, uploadUUID uuid.UUID, request *entity.RequestConfirmUpload) (*entity.Media, int, error) {
+	upload, status, err := s.findPendingUpload(userUUID, uploadUUID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	object, err := s.store.Stat(upload.Key)
+	// The parts are assembled once, the confirmation being retried if the media could not be attached
+	if errors.Is(err, ports.ErrBlobNotFound) && upload.UploadID != "" {
+		if status, err := s.completeParts(upload, request.Parts); err != nil {
+			return nil, status, err
+		}
+		object, err = s.store.Stat(upload.Key)
+	}
+	if errors.Is(err, ports.ErrBlobNotFound) {
+		return nil, http.StatusBadRequest, ErrIncompleteUpload
+	}
+	if err != nil {
+		log.Printf("error while reading the upload %s: %s", upload.UUID, err.Error())
+		return nil, http.StatusInternalServerError, ErrAttachingMedia
+	}
+
+	if err := s.checkFile(upload, object.Size); errors.Is(err, ErrFileMismatch) {
+		s.failUpload(upload, err)
+		return nil, http.StatusBadRequest, err
+	} else if err != nil {
+		log.Printf("error while reading the upload %s: %s", upload.UUID, err.Error())
+		return nil, http.StatusInternalServerError, ErrAttachingMedia
+	}
+
+	media, generated, status, err := s.buildMedia(upload)
+	if err != nil {
+		return nil, status, err
+	}
+
+	completedAt := timeNow().UTC()
+	upload.Status = entity.UploadStatusCompleted
+	upload.CompletedAt = &completedAt
+	if err := s.repo.AttachMedia(upload, media); err != nil {
+		log.Printf("error while attaching the upload %s: %s", upload.UUID, err.Error())
+		s.pipeline.deleteUploaded(generated)
+		return nil, http.StatusInternalServerError, ErrAttachingMedia
+	}
+
+	// The uploaded image is replaced by the processed one
+	if len(generated) > 0 {
+		s.deleteObject(upload.Key)
+	}
+	return media, http.StatusOK, nil
+}
+
+// AbortUpload discards what was uploaded for a pending session and cancels it.
+func (s *uploadService) AbortUpload(userUUID, uploadUUID uuid.UUID) (int, error) {
+	upload, status, err := s.findPendingUpload(userUUID, uploadUUID)
+	if err != nil {
+		return status, err
+	}
+
+	if err := s.discardFiles(upload); err != nil {
+		log.Printf("error while discarding the upload %s: %s", upload.UUID, err.Error())
+		return http.StatusInternalServerError, ErrDiscardingUpload
+	}
+	s.closeUpload(upload, entity.UploadStatusAborted, "")
+	return http.StatusOK, nil
+}
+
+// ExpireUploads discards the files of a batch of the pending sessions that expired before now. A session whose
+// files cannot be discarded stays pending, to be retried on the next run.
+func (s *uploadService) ExpireUploads(now time.Time) (int, error) {
+	uploads, err := s.repo.FindExpiredUploads(now, expiredUploadsBatchSize)
+	if err != nil {
+		return 0, ErrFindingUploads
+	}
+
+	expired := 0
+	for i := range uploads {
+		upload := &uploads[i]
+		if err := s.discardFiles(upload); err != nil {
+			log.Printf("error while discarding the upload %s: %s", upload.UUID, err.Error())
+			continue
+		}
+		s.closeUpload(upload, entity.UploadStatusExpired, "")
+		expired++
+	}
+	return expired, nil
+}
+
+// signUpload returns the session with its upload URLs, valid for UploadURLExpiry but not past the session.
+func (s *uploadService) signUpload(upload *entity.UploadSession, now time.Time) (*entity.UploadSessionResponse, error) {
+	expiry := UploadURLExpiry
+	if remaining := upload.ExpiresAt.Sub(now); remaining < expiry {
+		expiry = remaining
+	}
+
+	response := &entity.UploadSessionResponse{
+		UUID:         upload.UUID,
+		Status:       upload.Status,
+		Method:       http.MethodPut,
+		ContentType:  upload.ContentType,
+		Size:         upload.Size,
+		URLsExpireAt: now.Add(expiry),
+		ExpiresAt:    upload.ExpiresAt,
+	}
+
+	var err error
+	if upload.UploadID == "" {
+		response.URL, err = s.store.SignedPutURL(upload.Key, upload.ContentType, upload.Size, expiry)
+		return response, err
+	}
+
+	response.PartSize = upload.PartSize
+	for part := 1; part <= partCount(upload); part++ {
+		url, err := s.store.SignedPartURL(upload.Key, upload.UploadID, part, partSize(upload, part), expiry)
+		if err != nil {
+			return nil, err
+		}
+		response.Parts = append(response.Parts, entity.UploadPartURL{PartNumber: part, URL: url})
+	}
+	return response, nil
+}
+
+// completeParts assembles the parts of the multipart upload, every part being required once and in order.
+func (s *uploadService) completeParts(upload *entity.UploadSession, parts []entity.UploadPart) (int, error) {
+	if len(parts) != partCount(upload) {
+		return http.StatusBadRequest, fmt.Errorf("%w: %d parts expected, got %d", ErrIncompleteUpload, partCount(upload), len(parts))
+	}
+	for i, part := range parts {
+		if part.PartNumber != i+1 {
+			return http.StatusBadRequest, fmt.Errorf("%w: part %d expected, got %d", ErrIncompleteUpload, i+1, part.PartNumber)
+		}
+	}
+
+	if err := s.store.CompleteMultipartUpload(upload.Key, upload.UploadID, parts); err != nil {
+		log.Printf("error while completing the upload %s: %s", upload.UUID, err.Error())
+		return http.StatusBadRequest, ErrIncompleteUpload
+	}
+	return http.StatusOK, nil
+}
+
+// checkFile checks the size of the uploaded file and its type, sniffed from its first bytes.
+// Returns ErrFileMismatch if the file does not match the session.
+func (s *uploadService) checkFile(upload *entity.UploadSession, size int64) error {
+	if size != upload.Size {
+		return fmt.Errorf("%w: %d bytes uploaded instead of %d", ErrFileMismatch, size, upload.Size)
+	}
+
+	body, err := s.store.Get(upload.Key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(body, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+	if contentType := http.DetectContentType(head[:n]); contentType != upload.ContentType {
+		return fmt.Errorf("%w: %s uploaded instead of %s", ErrFileMismatch, contentType, upload.ContentType)
+	}
+	return nil
+}
+
+// buildMedia returns the media of the uploaded file, with the URLs of the files generated for it. The images
+// are processed and uploaded again with their variants, the other files are kept as uploaded.
+func (s *uploadService) buildMedia(upload *entity.UploadSession) (*entity.Media, []string, int, error) {
+	public := !IsPrivateDomain(upload.Domain)
+
+	if _, ok := supportedFormats[upload.ContentType]; !ok {
+		if public {
+			if err := s.store.SetPublic(upload.Key, true); err != nil {
+				log.Printf("error while publishing the upload %s: %s", upload.UUID, err.Error())
+				return nil, nil, http.StatusInternalServerError, ErrAttachingMedia
+			}
+		}
+		return &entity.Media{
+			UUID:        uuid.New(),
+			MediaURL:    s.store.URL(upload.Key),
+			ContentType: upload.ContentType,
+		}, nil, http.StatusOK, nil
+	}
+
+	data, err := s.readObject(upload.Key)
+	if err != nil {
+		log.Printf("error while reading the upload %s: %s", upload.UUID, err.Error())
+		return nil, nil, http.StatusInternalServerError, ErrAttachingMedia
+	}
+	processed, err := processImage(data)
+	if err != nil {
+		s.failUpload(upload, err)
+		return nil, nil, http.StatusBadRequest, err
+	}
+
+	var generated []string
+	media, err := s.pipeline.uploadImage(processed, public, &generated)
+	if err != nil {
+		log.Printf("error while uploading the media of the upload %s: %s", upload.UUID, err.Error())
+		s.pipeline.deleteUploaded(generated)
+		return nil, nil, http.StatusInternalServerError, ErrUploadingMedia
+	}
+	media.UUID = uuid.New()
+	return media, generated, http.StatusOK, nil
+}
+
+// readObject reads an uploaded image, up to MaxFileSize.
+func (s *uploadService) readObject(key string) ([]byte, error) {
+	body, err := s.store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, MaxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxFileSize {
+		return nil, ErrFileTooLarge
+	}
+	return data, nil
+}
+
+// failUpload deletes the uploaded file that does not match the session and marks the session as failed.
+func (s *uploadService) failUpload(upload *entity.UploadSession, reason error) {
+	s.deleteObject(upload.Key)
+	s.closeUpload(upload, entity.UploadStatusFailed, reason.Error())
+}
+
+// closeUpload saves the final status of a session that did not complete, logging the failures.
+func (s *uploadService) closeUpload(upload *entity.UploadSession, status string, reason string) {
+	closedAt := timeNow().UTC()
+	upload.Status = status
+	upload.Error = reason
+	upload.CompletedAt = &closedAt
+	if err := s.repo.UpdateUpload(upload); err != nil {
+		log.Printf("error while saving the upload %s: %s", upload.UUID, err.Error())
+	}
+}
+
+// discardFiles aborts the multipart upload of the session and deletes the uploaded file, if any.
+func (s *uploadService) discardFiles(upload *entity.UploadSession) error {
+	if upload.UploadID != "" {
+		if err := s.store.AbortMultipartUpload(upload.Key, upload.UploadID); err != nil {
+			return err
+		}
+	}
+	return s.store.Delete(upload.Key)
+}
+
+// deleteObject deletes an uploaded file, logging the failures.
+func (s *uploadService) deleteObject(key string) {
+	if err := s.store.Delete(key); err != nil {
+		log.Printf("error while deleting the upload %s: %s", key, err.Error())
+	}
+}
+
+// findPendingUpload retrieves an upload session of the user that can still be uploaded to.
+func (s *uploadService) findPendingUpload(userUUID, uploadUUID uuid.UUID) (*entity.UploadSession, int, error) {
+	user, status, err := s.findUser(userUUID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	upload, err := s.repo.FindUpload(user.ID, uploadUUID)
+	if err != nil {
+		return nil, http.StatusNotFound, ErrUploadNotFound
+	}
+	if upload.Status != entity.UploadStatusPending {
+		return nil, http.StatusConflict, ErrUploadNotPending
+	}
+	if timeNow().After(upload.ExpiresAt) {
+		return nil, http.StatusGone, ErrUploadExpired
+	}
+	return upload, http.StatusOK, nil
+}
+
+// findUser retrieves the user with the given UUID.
+func (s *uploadService) findUser(userUUID uuid.UUID) (*entity.User, int, error) {
+	foundUser, err := s.repo.FindByUUID(userUUID, &entity.User{})
+	if err != nil {
+		return nil, http.StatusNotFound, ErrUserNotFound
+	}
+	user, ok := foundUser.(*entity.User)
+	if !ok {
+		return nil, http.StatusInternalServerError, ErrTypeAssertion
+	}
+	return user, http.StatusOK, nil
+}
+
+// maxUploadSize returns the largest file of the content type that can be uploaded.
+func maxUploadSize(contentType string) int64 {
+	if strings.HasPrefix(contentType, "image/") {
+		return MaxFileSize
+	}
+	return MaxUploadSize
+}
+
+// partCount returns the number of parts of a multipart upload.
+func partCount(upload *entity.UploadSession) int {
+	return int((upload.Size + upload.PartSize - 1) / upload.PartSize)
+}
+
+// partSize returns the size of the part of the multipart upload, the last part holding the remainder.
+func partSize(upload *entity.UploadSession, part int) int64 {
+	if part < partCount(upload) {
+		return upload.PartSize
+	}
+	return upload.Size - int64(part-1)*upload.PartSize
+}
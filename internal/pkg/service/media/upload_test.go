@@ -0,0 +1,419 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emur-uy/backend/internal/infra/storage"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testUploadNow     = time.Date(2023, 8, 6, 10, 0, 0, 0, time.UTC)
+	testUserUuid      = uuid.MustParse("0f8a3c2e-6b1d-4e7a-9c5f-2d3e4f5a6b71")
+	testOtherUserUuid = uuid.MustParse("1a9b4d3f-7c2e-4f8b-8d6a-3e4f5a6b7c82")
+	testReminderUuid  = uuid.MustParse("2bac5e4a-8d3f-4a9c-9e7b-4f5a6b7c8d93")
+	testArticleUuid   = uuid.MustParse("3cbd6f5b-9e4a-4bad-8f8c-5a6b7c8d9ea4")
+)
+
+// mockUploadRepository is an in-memory implementation of the UploadRepository interface for testing.
+type mockUploadRepository struct {
+	users     map[uuid.UUID]*entity.User
+	records   map[uuid.UUID]*entity.UploadRecord
+	uploads   map[uuid.UUID]*entity.UploadSession
+	attached  []*entity.Media
+	attachErr error
+}
+
+func newMockUploadRepository() *mockUploadRepository {
+	return &mockUploadRepository{
+		users: map[uuid.UUID]*entity.User{
+			testUserUuid:      {ID: 1},
+			testOtherUserUuid: {ID: 2},
+		},
+		records: map[uuid.UUID]*entity.UploadRecord{
+			testReminderUuid: {ID: 10, UserID: 1},
+			testArticleUuid:  {ID: 20},
+		},
+		uploads: map[uuid.UUID]*entity.UploadSession{},
+	}
+}
+
+func (m *mockUploadRepository) FindByUUID(id uuid.UUID, out interface{}) (interface{}, error) {
+	user, ok := m.users[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return user, nil
+}
+
+func (m *mockUploadRepository) FindRecord(domain string, recordUUID uuid.UUID) (*entity.UploadRecord, error) {
+	record, ok := m.records[recordUUID]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return record, nil
+}
+
+func (m *mockUploadRepository) CreateUpload(upload *entity.UploadSession) error {
+	saved := *upload
+	m.uploads[upload.UUID] = &saved
+	return nil
+}
+
+func (m *mockUploadRepository) FindUpload(userID int, uploadUUID uuid.UUID) (*entity.UploadSession, error) {
+	upload, ok := m.uploads[uploadUUID]
+	if !ok || upload.UserID != userID {
+		return nil, errors.New("record not found")
+	}
+	found := *upload
+	return &found, nil
+}
+
+func (m *mockUploadRepository) UpdateUpload(upload *entity.UploadSession) error {
+	saved := *upload
+	m.uploads[upload.UUID] = &saved
+	return nil
+}
+
+func (m *mockUploadRepository) AttachMedia(upload *entity.UploadSession, media *entity.Media) error {
+	if m.attachErr != nil {
+		return m.attachErr
+	}
+	media.ID = len(m.attached) + 1
+	m.attached = append(m.attached, media)
+	upload.MediaID = &media.ID
+	return m.UpdateUpload(upload)
+}
+
+func (m *mockUploadRepository) FindExpiredUploads(now time.Time, limit int) ([]entity.UploadSession, error) {
+	var uploads []entity.UploadSession
+	for _, upload := range m.uploads {
+		if upload.Status == entity.UploadStatusPending && upload.ExpiresAt.Before(now) && len(uploads) < limit {
+			uploads = append(uploads, *upload)
+		}
+	}
+	return uploads, nil
+}
+
+// newTestUploadService returns an upload service keeping the files in memory, at a fixed time.
+func newTestUploadService(t *testing.T) (*uploadService, *mockUploadRepository, *storage.MemoryStore) {
+	originalNow, originalWebP := timeNow, encodeWebPFunc
+	timeNow = func() time.Time { return testUploadNow }
+	encodeWebPFunc = func(img image.Image) ([]byte, error) {
+		return []byte("RIFF----WEBP"), nil
+	}
+	t.Cleanup(func() {
+		timeNow = originalNow
+		encodeWebPFunc = originalWebP
+	})
+
+	repo, store := newMockUploadRepository(), storage.NewMemoryStore()
+	return NewUploadService(repo, store).(*uploadService), repo, store
+}
+
+// createTestUpload opens an upload session of the test user, failing the test on error.
+func createTestUpload(t *testing.T, s *uploadService, domain string, recordUUID uuid.UUID, contentType string, size int) *entity.UploadSession {
+	response, status, err := s.CreateUpload(testUserUuid, &entity.RequestCreateUpload{
+		Domain:      domain,
+		RecordUUID:  recordUUID,
+		ContentType: contentType,
+		Size:        int64(size),
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, status)
+	return s.repo.(*mockUploadRepository).uploads[response.UUID]
+}
+
+// testPDF returns a PDF document of the given size.
+func testPDF(size int) []byte {
+	data := []byte("%PDF-1.7\n")
+	return append(data, bytes.Repeat([]byte("0"), size-len(data))...)
+}
+
+// testMP4 returns an MP4 video of the given size, starting with its ftyp box.
+func testMP4(size int) []byte {
+	data := []byte("\x00\x00\x00\x18ftypisom\x00\x00\x00\x01isommp41")
+	return append(data, make([]byte, size-len(data))...)
+}
+
+func TestCreateUpload(t *testing.T) {
+	s, repo, store := newTestUploadService(t)
+
+	response, status, err := s.CreateUpload(testUserUuid, &entity.RequestCreateUpload{
+		Domain:      entity.MediaDomainReminders,
+		RecordUUID:  testReminderUuid,
+		ContentType: "application/pdf",
+		Size:        1 << 20,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, http.MethodPut, response.Method)
+	assert.Empty(t, response.Parts)
+	assert.Equal(t, testUploadNow.Add(UploadURLExpiry), response.URLsExpireAt)
+	assert.Equal(t, testUploadNow.Add(UploadSessionTTL), response.ExpiresAt)
+
+	upload := repo.uploads[response.UUID]
+	require.NotNil(t, upload)
+	assert.Equal(t, 1, upload.UserID)
+	assert.Equal(t, 10, upload.RecordID)
+	assert.Equal(t, entity.UploadStatusPending, upload.Status)
+	assert.True(t, strings.HasSuffix(upload.Key, response.UUID.String()+".pdf"))
+	assert.False(t, strings.HasPrefix(upload.Key, "/"))
+	assert.True(t, strings.HasPrefix(response.URL, store.URL(upload.Key)+"?method=PUT&size=1048576"))
+
+	// The larger files are uploaded in parts, the last one being shorter
+	response, _, err = s.CreateUpload(testUserUuid, &entity.RequestCreateUpload{
+		Domain:      entity.MediaDomainArticles,
+		RecordUUID:  testArticleUuid,
+		ContentType: "video/mp4",
+		Size:        2*UploadPartSize + 1,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, response.URL)
+	assert.Equal(t, int64(UploadPartSize), response.PartSize)
+	require.Len(t, response.Parts, 3)
+	assert.Equal(t, 3, response.Parts[2].PartNumber)
+	assert.True(t, strings.HasSuffix(response.Parts[0].URL, "&size="+strconv.Itoa(UploadPartSize)))
+	assert.True(t, strings.HasSuffix(response.Parts[2].URL, "&size=1"))
+	assert.NotEmpty(t, repo.uploads[response.UUID].UploadID)
+	assert.Len(t, store.Uploads, 1)
+
+	testCases := []struct {
+		name         string
+		userUUID     uuid.UUID
+		request      entity.RequestCreateUpload
+		expectStatus int
+		expectError  error
+	}{
+		{"invalid domain", testUserUuid, entity.RequestCreateUpload{Domain: "users", RecordUUID: testReminderUuid, ContentType: "application/pdf", Size: 10}, http.StatusBadRequest, ErrInvalidDomain},
+		{"unsupported type", testUserUuid, entity.RequestCreateUpload{Domain: entity.MediaDomainReminders, RecordUUID: testReminderUuid, ContentType: "text/html", Size: 10}, http.StatusBadRequest, ErrUnsupportedUploadType},
+		{"empty file", testUserUuid, entity.RequestCreateUpload{Domain: entity.MediaDomainReminders, RecordUUID: testReminderUuid, ContentType: "application/pdf", Size: 0}, http.StatusBadRequest, ErrInvalidFileSize},
+		{"image too large", testUserUuid, entity.RequestCreateUpload{Domain: entity.MediaDomainReminders, RecordUUID: testReminderUuid, ContentType: "image/png", Size: MaxFileSize + 1}, http.StatusBadRequest, ErrFileTooLarge},
+		{"video too large", testUserUuid, entity.RequestCreateUpload{Domain: entity.MediaDomainReminders, RecordUUID: testReminderUuid, ContentType: "video/mp4", Size: MaxUploadSize + 1}, http.StatusBadRequest, ErrFileTooLarge},
+		{"unknown user", uuid.New(), entity.RequestCreateUpload{Domain: entity.MediaDomainReminders, RecordUUID: testReminderUuid, ContentType: "application/pdf", Size: 10}, http.StatusNotFound, ErrUserNotFound},
+		{"unknown record", testUserUuid, entity.RequestCreateUpload{Domain: entity.MediaDomainReminders, RecordUUID: uuid.New(), ContentType: "application/pdf", Size: 10}, http.StatusNotFound, ErrRecordNotFound},
+		{"reminder of another user", testOtherUserUuid, entity.RequestCreateUpload{Domain: entity.MediaDomainReminders, RecordUUID: testReminderUuid, ContentType: "application/pdf", Size: 10}, http.StatusNotFound, ErrRecordNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response, status, err := s.CreateUpload(tc.userUUID, &tc.request)
+			assert.Nil(t, response)
+			assert.Equal(t, tc.expectStatus, status)
+			assert.ErrorIs(t, err, tc.expectError)
+		})
+	}
+	assert.Len(t, repo.uploads, 2)
+}
+
+func TestConfirmUpload(t *testing.T) {
+	s, repo, store := newTestUploadService(t)
+
+	testCases := []struct {
+		name         string
+		domain       string
+		recordUUID   uuid.UUID
+		expectPublic bool
+	}{
+		{"public domain", entity.MediaDomainArticles, testArticleUuid, true},
+		{"private domain", entity.MediaDomainReminders, testReminderUuid, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			upload := createTestUpload(t, s, tc.domain, tc.recordUUID, "application/pdf", 2048)
+			_, err := store.Put(upload.Key, bytes.NewReader(testPDF(2048)), "application/pdf", false)
+			require.NoError(t, err)
+
+			media, status, err := s.ConfirmUpload(testUserUuid, upload.UUID, &entity.RequestConfirmUpload{})
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, store.URL(upload.Key), media.MediaURL)
+			assert.Equal(t, "application/pdf", media.ContentType)
+			assert.Empty(t, media.MediaThumb)
+			assert.NotEqual(t, uuid.Nil, media.UUID)
+			assert.Equal(t, tc.expectPublic, store.Objects[upload.Key].Public)
+
+			saved := repo.uploads[upload.UUID]
+			assert.Equal(t, entity.UploadStatusCompleted, saved.Status)
+			require.NotNil(t, saved.MediaID)
+			assert.Equal(t, media.ID, *saved.MediaID)
+
+			// A completed upload cannot be confirmed again
+			_, status, err = s.ConfirmUpload(testUserUuid, upload.UUID, &entity.RequestConfirmUpload{})
+			assert.Equal(t, http.StatusConflict, status)
+			assert.ErrorIs(t, err, ErrUploadNotPending)
+		})
+	}
+}
+
+func TestConfirmUploadMultipart(t *testing.T) {
+	s, repo, store := newTestUploadService(t)
+	data := testMP4(UploadPartSize + 100)
+	upload := createTestUpload(t, s, entity.MediaDomainArticles, testArticleUuid, "video/mp4", len(data))
+
+	// The file is not uploaded yet
+	_, status, err := s.ConfirmUpload(testUserUuid, upload.UUID, &entity.RequestConfirmUpload{})
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.ErrorIs(t, err, ErrIncompleteUpload)
+
+	firstETag, err := store.UploadPart(upload.UploadID, 1, data[:UploadPartSize])
+	require.NoError(t, err)
+	parts := []entity.UploadPart{{PartNumber: 1, ETag: firstETag}, {PartNumber: 2, ETag: `"missing"`}}
+	_, status, err = s.ConfirmUpload(testUserUuid, upload.UUID, &entity.RequestConfirmUpload{Parts: parts})
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.ErrorIs(t, err, ErrIncompleteUpload)
+
+	// The upload is resumed with the missing part
+	secondETag, err := store.UploadPart(upload.UploadID, 2, data[UploadPartSize:])
+	require.NoError(t, err)
+	parts[1].ETag = secondETag
+	media, status, err := s.ConfirmUpload(testUserUuid, upload.UUID, &entity.RequestConfirmUpload{Parts: parts})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "video/mp4", media.ContentType)
+	assert.Equal(t, data, store.Objects[upload.Key].Data)
+	assert.Empty(t, store.Uploads)
+	assert.Equal(t, entity.UploadStatusCompleted, repo.uploads[upload.UUID].Status)
+}
+
+func TestConfirmUploadMismatch(t *testing.T) {
+	s, repo, store := newTestUploadService(t)
+
+	testCases := []struct {
+		name string
+		data []byte
+	}{
+		{"different size", testPDF(1024)},
+		{"different type", append([]byte("<html>"), make([]byte, 2042)...)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			upload := createTestUpload(t, s, entity.MediaDomainReminders, testReminderUuid, "application/pdf", 2048)
+			_, err := store.Put(upload.Key, bytes.NewReader(tc.data), "application/pdf", false)
+			require.NoError(t, err)
+
+			media, status, err := s.ConfirmUpload(testUserUuid, upload.UUID, &entity.RequestConfirmUpload{})
+			assert.Nil(t, media)
+			assert.Equal(t, http.StatusBadRequest, status)
+			assert.ErrorIs(t, err, ErrFileMismatch)
+
+			assert.NotContains(t, store.Objects, upload.Key)
+			assert.Equal(t, entity.UploadStatusFailed, repo.uploads[upload.UUID].Status)
+			assert.NotEmpty(t, repo.uploads[upload.UUID].Error)
+		})
+	}
+	assert.Empty(t, repo.attached)
+}
+
+func TestConfirmUploadImage(t *testing.T) {
+	s, repo, store := newTestUploadService(t)
+	data := testJPEGWithExif(t, 64, 48)
+	upload := createTestUpload(t, s, entity.MediaDomainReminders, testReminderUuid, "image/jpeg", len(data))
+	_, err := store.Put(upload.Key, bytes.NewReader(data), "image/jpeg", false)
+	require.NoError(t, err)
+
+	// The processed image is deleted if the media cannot be attached, the uploaded one is kept to retry
+	repo.attachErr = errors.New("database error")
+	_, status, err := s.ConfirmUpload(testUserUuid, upload.UUID, &entity.RequestConfirmUpload{})
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.ErrorIs(t, err, ErrAttachingMedia)
+	assert.Len(t, store.Objects, 1)
+
+	repo.attachErr = nil
+	media, status, err := s.ConfirmUpload(testUserUuid, upload.UUID, &entity.RequestConfirmUpload{})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 64, media.Width)
+	assert.NotEmpty(t, media.MediaThumb)
+	assert.NotEmpty(t, media.MediaWebP)
+
+	// The uploaded image is replaced by the processed one and its variants, private as the domain
+	assert.NotContains(t, store.Objects, upload.Key)
+	require.Len(t, store.Objects, 3)
+	for key, object := range store.Objects {
+		assert.False(t, object.Public, key)
+		assert.False(t, bytes.Contains(object.Data, []byte("GPSLatitude")), key)
+	}
+}
+
+func TestGetUpload(t *testing.T) {
+	s, repo, store := newTestUploadService(t)
+	upload := createTestUpload(t, s, entity.MediaDomainArticles, testArticleUuid, "video/webm", 3*UploadPartSize)
+
+	response, status, err := s.GetUpload(testUserUuid, upload.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, response.Parts, 3)
+
+	_, status, err = s.GetUpload(testOtherUserUuid, upload.UUID)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.ErrorIs(t, err, ErrUploadNotFound)
+
+	// The URLs do not outlive the session
+	timeNow = func() time.Time { return upload.ExpiresAt.Add(-10 * time.Minute) }
+	response, _, err = s.GetUpload(testUserUuid, upload.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, upload.ExpiresAt, response.URLsExpireAt)
+
+	timeNow = func() time.Time { return upload.ExpiresAt.Add(time.Minute) }
+	_, status, err = s.GetUpload(testUserUuid, upload.UUID)
+	assert.Equal(t, http.StatusGone, status)
+	assert.ErrorIs(t, err, ErrUploadExpired)
+
+	repo.uploads[upload.UUID].Status = entity.UploadStatusAborted
+	_, status, err = s.GetUpload(testUserUuid, upload.UUID)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.ErrorIs(t, err, ErrUploadNotPending)
+	assert.Len(t, store.Uploads, 1)
+}
+
+func TestAbortUpload(t *testing.T) {
+	s, repo, store := newTestUploadService(t)
+	upload := createTestUpload(t, s, entity.MediaDomainArticles, testArticleUuid, "video/mp4", 2*UploadPartSize)
+	_, err := store.UploadPart(upload.UploadID, 1, testMP4(UploadPartSize))
+	require.NoError(t, err)
+
+	status, err := s.AbortUpload(testUserUuid, upload.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Empty(t, store.Uploads)
+	assert.Equal(t, entity.UploadStatusAborted, repo.uploads[upload.UUID].Status)
+
+	status, err = s.AbortUpload(testUserUuid, upload.UUID)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.ErrorIs(t, err, ErrUploadNotPending)
+}
+
+func TestExpireUploads(t *testing.T) {
+	s, repo, store := newTestUploadService(t)
+	expired := createTestUpload(t, s, entity.MediaDomainReminders, testReminderUuid, "application/pdf", 2048)
+	_, err := store.Put(expired.Key, bytes.NewReader(testPDF(2048)), "application/pdf", false)
+	require.NoError(t, err)
+	failing := createTestUpload(t, s, entity.MediaDomainReminders, testReminderUuid, "application/pdf", 2048)
+	store.Errors[failing.Key] = errors.New("access denied")
+
+	count, err := s.ExpireUploads(testUploadNow.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	count, err = s.ExpireUploads(testUploadNow.Add(UploadSessionTTL + time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Empty(t, store.Objects)
+	assert.Equal(t, entity.UploadStatusExpired, repo.uploads[expired.UUID].Status)
+
+	// The session whose file cannot be deleted is retried on the next run
+	assert.Equal(t, entity.UploadStatusPending, repo.uploads[failing.UUID].Status)
+}
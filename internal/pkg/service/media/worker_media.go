@@ -0,0 +1,52 @@
+package media
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+type Worker struct {
+	uploads ports.UploadService
+	service ports.MediaService
+	// gcDryRun only reports the orphaned media, without deleting them.
+	gcDryRun bool
+}
+
+func NewWorker(uploads ports.UploadService, service ports.MediaService, gcDryRun bool) *Worker {
+	return &Worker{
+		uploads:  uploads,
+		service:  service,
+		gcDryRun: gcDryRun,
+	}
+}
+
+// ExpireUploads discards the files of the upload sessions that were not confirmed in time.
+func (w *Worker) ExpireUploads() {
+	expired, err := w.uploads.ExpireUploads(time.Now().UTC())
+	if err != nil {
+		fmt.Println("Error expiring the uploads:", err)
+		return
+	}
+
+	if expired > 0 {
+		fmt.Printf("%d expired uploads discarded\n", expired)
+	}
+}
+
+// CollectOrphans deletes the media joined to no record and the stored objects of no media, older than the grace period.
+func (w *Worker) CollectOrphans() {
+	report, err := w.service.CollectOrphans(time.Now().UTC(), OrphanGracePeriod, w.gcDryRun)
+	if err != nil {
+		fmt.Println("Error collecting the orphaned media:", err)
+		if report == nil {
+			return
+		}
+	}
+
+	if report.OrphanMedia > 0 || report.OrphanObjects > 0 || report.Failed > 0 {
+		fmt.Printf("orphaned media (dry run: %t): %d media, %d deleted; %d objects of %d bytes, %d deleted; %d failed\n",
+			report.DryRun, report.OrphanMedia, report.DeletedMedia, report.OrphanObjects, report.OrphanBytes, report.DeletedObjects, report.Failed)
+	}
+}
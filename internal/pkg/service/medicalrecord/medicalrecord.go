@@ -6,6 +6,7 @@ import (
 
 	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/emur-uy/backend/internal/pkg/service/audit"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -70,6 +71,7 @@ func (s *medicalRecordService) CreateMedicalRecord(c *gin.Context, userUUID uuid
 	if err != nil {
 		return nil, http.StatusInternalServerError, ErrCreatingMedicalRecord
 	}
+	audit.SetResource(c, medicalRecord.UUID)
 
 	// Return the created medical record and the HTTP OK status code
 	return medicalRecord, http.StatusOK, nil
@@ -95,6 +97,7 @@ func (s *medicalRecordService) GetMedicalRecord(c *gin.Context, uuid uuid.UUID)
 	if err != nil {
 		return nil, http.StatusInternalServerError, ErrRetrievingMedicalRecord
 	}
+	audit.SetResource(c, medicalRecord.UUID)
 
 	// Return the retrieved medical record and the HTTP OK status code
 	return medicalRecord, http.StatusOK, nil
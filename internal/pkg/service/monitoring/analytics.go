@@ -0,0 +1,187 @@
+package monitoring
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/dates"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrMissingSymptoms       = errors.New("at least one symptom is required")
+	ErrInvalidAnalyticsRange = errors.New("invalid date range, 'to' must be after 'from' and span at most one year")
+	ErrInvalidAnalyticsParam = errors.New("the moving average window and worsening days must be between 1 and 90")
+)
+
+const (
+	// DefaultMovingAverageWindow is the number of days averaged by the moving average when none is requested.
+	DefaultMovingAverageWindow = 7
+	// DefaultWorseningDays is the number of consecutive days a symptom must worsen to be flagged when none is requested.
+	DefaultWorseningDays = 3
+
+	maxAnalyticsRange = 366 * 24 * time.Hour
+	maxAnalyticsParam = 90
+)
+
+// GetMonitoringAnalytics aggregates the monitoring records of a user per day, week and month for each requested symptom.
+func (s *service) GetMonitoringAnalytics(c *gin.Context, userUUID uuid.UUID, analyticsReq *entity.RequestMonitoringAnalytics) ([]*entity.SymptomAnalytics, int, error) {
+	if analyticsReq == nil {
+		return nil, http.StatusBadRequest, errors.New("nil payload")
+	}
+	if len(analyticsReq.SymptomUUIDs) == 0 {
+		return nil, http.StatusBadRequest, ErrMissingSymptoms
+	}
+	if !analyticsReq.To.After(analyticsReq.From) || analyticsReq.To.Sub(analyticsReq.From) > maxAnalyticsRange {
+		return nil, http.StatusBadRequest, ErrInvalidAnalyticsRange
+	}
+
+	window := analyticsReq.MovingAverageWindow
+	if window == 0 {
+		window = DefaultMovingAverageWindow
+	}
+	worseningDays := analyticsReq.WorseningDays
+	if worseningDays == 0 {
+		worseningDays = DefaultWorseningDays
+	}
+	if window < 1 || window > maxAnalyticsParam || worseningDays < 1 || worseningDays > maxAnalyticsParam {
+		return nil, http.StatusBadRequest, ErrInvalidAnalyticsParam
+	}
+
+	// Find user by UUID
+	foundUser, err := s.repo.FindByUUID(userUUID, &entity.User{})
+	if err != nil {
+		return nil, http.StatusInternalServerError, ErrFindingUser
+	}
+	userEntity, ok := foundUser.(*entity.User)
+	if !ok {
+		return nil, http.StatusInternalServerError, ErrAssertingUser
+	}
+
+	analytics := []*entity.SymptomAnalytics{}
+	for _, symptomUUID := range analyticsReq.SymptomUUIDs {
+		// Find symptom by UUID
+		foundSymptom, err := s.repo.FindByUUID(symptomUUID, &entity.Symptom{})
+		if err != nil {
+			if err.Error() == "record not found" {
+				return nil, http.StatusBadRequest, errors.New("symptom not found")
+			}
+			return nil, http.StatusInternalServerError, ErrFindingSymptom
+		}
+		symptomEntity, ok := foundSymptom.(*entity.Symptom)
+		if !ok {
+			return nil, http.StatusInternalServerError, ErrAssertingSymptom
+		}
+
+		// Get the monitorings of the symptom in the range
+		var monitorings []*entity.Monitoring
+		err = s.repo.Find(&monitorings, "user_id = ? AND symptom_id = ? AND date >= ? AND date <= ?",
+			userEntity.ID, symptomEntity.ID, analyticsReq.From, analyticsReq.To)
+		if err != nil {
+			return nil, http.StatusInternalServerError, ErrRetrievingMonitorings
+		}
+
+		symptomAnalytics := &entity.SymptomAnalytics{
+			SymptomUUID: symptomEntity.UUID,
+			Name:        symptomEntity.Name,
+			Daily:       aggregateMonitorings(monitorings, dates.StartOfDay),
+			Weekly:      aggregateMonitorings(monitorings, dates.StartOfWeek),
+			Monthly:     aggregateMonitorings(monitorings, dates.StartOfMonth),
+		}
+		addMovingAverage(symptomAnalytics.Daily, window)
+		symptomAnalytics.WorseningSince = worseningSince(symptomAnalytics.Daily, worseningDays)
+		symptomAnalytics.Worsening = symptomAnalytics.WorseningSince != nil
+
+		analytics = append(analytics, symptomAnalytics)
+	}
+
+	return analytics, http.StatusOK, nil
+}
+
+// aggregateMonitorings groups the monitorings by the period returned by periodStart and computes
+// the count, mean, min and max of each period, sorted by date.
+func aggregateMonitorings(monitorings []*entity.Monitoring, periodStart func(time.Time) time.Time) []*entity.MonitoringAggregate {
+	periods := map[time.Time]*entity.MonitoringAggregate{}
+	sums := map[time.Time]int{}
+
+	for _, monitoring := range monitorings {
+		// Records are grouped by the day they were logged on in the user's timezone
+		date := monitoring.Date
+		if !monitoring.LocalDate.IsZero() {
+			date = monitoring.LocalDate
+		}
+
+		// The local dates are UTC midnights, the periods are computed in UTC
+		start := periodStart(date.UTC())
+		aggregate, ok := periods[start]
+		if !ok {
+			aggregate = &entity.MonitoringAggregate{Start: start, Min: monitoring.Scale, Max: monitoring.Scale}
+			periods[start] = aggregate
+		}
+
+		aggregate.Count++
+		sums[start] += monitoring.Scale
+		if monitoring.Scale < aggregate.Min {
+			aggregate.Min = monitoring.Scale
+		}
+		if monitoring.Scale > aggregate.Max {
+			aggregate.Max = monitoring.Scale
+		}
+	}
+
+	aggregates := []*entity.MonitoringAggregate{}
+	for start, aggregate := range periods {
+		aggregate.Mean = float64(sums[start]) / float64(aggregate.Count)
+		aggregates = append(aggregates, aggregate)
+	}
+	sort.Slice(aggregates, func(i, j int) bool { return aggregates[i].Start.Before(aggregates[j].Start) })
+
+	return aggregates
+}
+
+// addMovingAverage sets on each daily aggregate the mean of the daily means recorded
+// in the window of days ending on that day.
+func addMovingAverage(daily []*entity.MonitoringAggregate, window int) {
+	for i, day := range daily {
+		windowStart := day.Start.AddDate(0, 0, -(window - 1))
+
+		sum, count := 0.0, 0
+		for j := i; j >= 0 && !daily[j].Start.Before(windowStart); j-- {
+			sum += daily[j].Mean
+			count++
+		}
+
+		average := sum / float64(count)
+		day.MovingAverage = &average
+	}
+}
+
+// worseningSince returns the first day of the run of consecutive days ending on the latest day whose daily
+// mean keeps increasing, when it lasts at least the given number of days, or nil if there is no such run.
+// A run that ended before the latest day is over and is not reported.
+func worseningSince(daily []*entity.MonitoringAggregate, days int) *time.Time {
+	if days < 2 {
+		days = 2
+	}
+
+	// Walk back from the latest day while the previous day is the day before with a lower mean
+	runStart := len(daily) - 1
+	for runStart > 0 {
+		previous, current := daily[runStart-1], daily[runStart]
+		consecutive := current.Start.Equal(previous.Start.AddDate(0, 0, 1))
+		if !consecutive || current.Mean <= previous.Mean {
+			break
+		}
+		runStart--
+	}
+
+	if runStart < 0 || len(daily)-runStart < days {
+		return nil
+	}
+	since := daily[runStart].Start
+	return &since
+}
@@ -0,0 +1,177 @@
+package monitoring
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/dates"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAnalyticsRepository struct {
+	mockMonitoringRepository
+	monitorings []*entity.Monitoring
+}
+
+func (m mockAnalyticsRepository) Find(out interface{}, conditions ...interface{}) error {
+	if monitorings, ok := out.(*[]*entity.Monitoring); ok {
+		*monitorings = m.monitorings
+	}
+	return nil
+}
+
+func day(d int) time.Time {
+	return time.Date(2023, time.June, d, 10, 0, 0, 0, time.UTC)
+}
+
+func TestAggregateMonitorings(t *testing.T) {
+	monitorings := []*entity.Monitoring{
+		{Scale: 2, Date: day(5)},
+		{Scale: 4, Date: day(5)},
+		{Scale: 6, Date: day(6)},
+		{Scale: 1, Date: day(12)},
+	}
+
+	daily := aggregateMonitorings(monitorings, dates.StartOfDay)
+	require.Len(t, daily, 3)
+	assert.Equal(t, time.Date(2023, time.June, 5, 0, 0, 0, 0, time.UTC), daily[0].Start)
+	assert.Equal(t, 2, daily[0].Count)
+	assert.Equal(t, 3.0, daily[0].Mean)
+	assert.Equal(t, 2, daily[0].Min)
+	assert.Equal(t, 4, daily[0].Max)
+
+	// June 5th 2023 is a Monday
+	weekly := aggregateMonitorings(monitorings, dates.StartOfWeek)
+	require.Len(t, weekly, 2)
+	assert.Equal(t, 3, weekly[0].Count)
+	assert.Equal(t, 4.0, weekly[0].Mean)
+	assert.Equal(t, time.Date(2023, time.June, 12, 0, 0, 0, 0, time.UTC), weekly[1].Start)
+
+	monthly := aggregateMonitorings(monitorings, dates.StartOfMonth)
+	require.Len(t, monthly, 1)
+	assert.Equal(t, 4, monthly[0].Count)
+	assert.Equal(t, 1, monthly[0].Min)
+	assert.Equal(t, 6, monthly[0].Max)
+}
+
+func TestAddMovingAverage(t *testing.T) {
+	daily := aggregateMonitorings([]*entity.Monitoring{
+		{Scale: 2, Date: day(1)},
+		{Scale: 4, Date: day(2)},
+		{Scale: 6, Date: day(3)},
+		{Scale: 8, Date: day(10)},
+	}, dates.StartOfDay)
+
+	addMovingAverage(daily, 2)
+	assert.Equal(t, 2.0, *daily[0].MovingAverage)
+	assert.Equal(t, 3.0, *daily[1].MovingAverage)
+	assert.Equal(t, 5.0, *daily[2].MovingAverage)
+	// Days outside the window are not averaged
+	assert.Equal(t, 8.0, *daily[3].MovingAverage)
+}
+
+func TestWorseningSince(t *testing.T) {
+	testCases := []struct {
+		name     string
+		scales   map[int]int
+		days     int
+		expected *time.Time
+	}{
+		{
+			name:     "increasing run",
+			scales:   map[int]int{1: 5, 2: 1, 3: 2, 4: 3},
+			days:     3,
+			expected: func() *time.Time { d := dates.StartOfDay(day(2)); return &d }(),
+		},
+		{
+			name:   "run too short",
+			scales: map[int]int{1: 1, 2: 2, 3: 2},
+			days:   3,
+		},
+		{
+			name:   "run ended before the latest day",
+			scales: map[int]int{1: 1, 2: 2, 3: 3, 4: 1},
+			days:   3,
+		},
+		{
+			name:   "gap between days",
+			scales: map[int]int{1: 1, 2: 2, 4: 3},
+			days:   3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			monitorings := []*entity.Monitoring{}
+			for d, scale := range tc.scales {
+				monitorings = append(monitorings, &entity.Monitoring{Scale: scale, Date: day(d)})
+			}
+
+			assert.Equal(t, tc.expected, worseningSince(aggregateMonitorings(monitorings, dates.StartOfDay), tc.days))
+		})
+	}
+}
+
+func TestGetMonitoringAnalytics(t *testing.T) {
+	repo := &mockAnalyticsRepository{monitorings: []*entity.Monitoring{
+		{Scale: 1, Date: day(1)},
+		{Scale: 2, Date: day(2)},
+		{Scale: 3, Date: day(3)},
+	}}
+	svc := NewService(repo)
+	c := &gin.Context{}
+
+	testCases := []struct {
+		name           string
+		request        *entity.RequestMonitoringAnalytics
+		expectedStatus int
+	}{
+		{
+			name:           "missing symptoms",
+			request:        &entity.RequestMonitoringAnalytics{From: day(1), To: day(30)},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid range",
+			request:        &entity.RequestMonitoringAnalytics{SymptomUUIDs: []uuid.UUID{testSymptomUuidExistMonitoring}, From: day(30), To: day(1)},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid window",
+			request:        &entity.RequestMonitoringAnalytics{SymptomUUIDs: []uuid.UUID{testSymptomUuidExistMonitoring}, From: day(1), To: day(30), MovingAverageWindow: -1},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown symptom",
+			request:        &entity.RequestMonitoringAnalytics{SymptomUUIDs: []uuid.UUID{uuid.New()}, From: day(1), To: day(30)},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "success",
+			request:        &entity.RequestMonitoringAnalytics{SymptomUUIDs: []uuid.UUID{testSymptomUuidExistMonitoring}, From: day(1), To: day(30)},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			analytics, status, err := svc.GetMonitoringAnalytics(c, testUserUuid, tc.request)
+			assert.Equal(t, tc.expectedStatus, status)
+			if tc.expectedStatus != http.StatusOK {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, analytics, 1)
+			assert.Equal(t, testSymptomUuidExistMonitoring, analytics[0].SymptomUUID)
+			assert.Len(t, analytics[0].Daily, 3)
+			assert.True(t, analytics[0].Worsening)
+		})
+	}
+}
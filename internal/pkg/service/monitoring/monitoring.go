@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/emur-uy/backend/internal/pkg/dates"
 	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/ports"
 	"github.com/gin-gonic/gin"
@@ -16,13 +17,17 @@ var (
 	ErrAssertingUser         = errors.New("error asserting user entity type")
 	ErrFindingSymptom        = errors.New("error finding symptom")
 	ErrAssertingSymptom      = errors.New("error asserting symptom entity type")
-	ErrExistingMonitoring    = errors.New("a monitoring record for the same symptom already exists for the current day")
+	ErrMonitoringInFuture    = errors.New("a monitoring record cannot be dated in the future")
+	ErrInvalidTimezone       = errors.New("the user has an invalid timezone")
 	ErrCheckingMonitoring    = errors.New("error checking monitoring record")
 	ErrInvalidScale          = errors.New("the scale value in the request is greater than the allowed scale for the symptom")
 	ErrCreatingMonitoring    = errors.New("error creating monitoring")
 	ErrRetrievingMonitorings = errors.New("error retrieving monitorings")
 )
 
+// timeNow returns the current time, it is a variable so tests can fix the clock.
+var timeNow = time.Now
+
 type service struct {
 	repo ports.MonitoringRepository
 }
@@ -67,38 +72,83 @@ func (s *service) CreateMonitoring(c *gin.Context, userUUID uuid.UUID, createReq
 		return nil, http.StatusInternalServerError, ErrAssertingSymptom
 	}
 
-	// Check if the user already has a monitoring record for the same symptom on the current day
-	var existingMonitoring entity.Monitoring
-	err = s.repo.FindItemByIDs(userEntity.ID, symptomEntity.ID, "monitorings", "user_id", "symptom_id", &existingMonitoring)
-	if err == nil {
-		return nil, http.StatusBadRequest, ErrExistingMonitoring
-	} else if err != nil && err.Error() != "record not found" {
-		return nil, http.StatusInternalServerError, ErrCheckingMonitoring
-	}
-
 	// Check if the scale value in createReq is equal or smaller than the scale value in symptomEntity
 	if createReq.Scale > symptomEntity.Scale {
 		return nil, http.StatusBadRequest, ErrInvalidScale
 	}
 
+	// Records default to the current time and can be backdated, but not set in the future
+	date := timeNow().UTC()
+	if createReq.Date != nil {
+		if createReq.Date.After(date) {
+			return nil, http.StatusBadRequest, ErrMonitoringInFuture
+		}
+		date = createReq.Date.UTC()
+	}
+
+	location, err := dates.UserLocation(userEntity)
+	if err != nil {
+		return nil, http.StatusInternalServerError, ErrInvalidTimezone
+	}
+	localDate := dates.LocalDay(date, location)
+
+	// A record for the same symptom on the same local day is updated instead of duplicated
+	monitoring, err := s.findDailyMonitoring(userEntity.ID, symptomEntity.ID, localDate)
+	if err != nil {
+		return nil, http.StatusInternalServerError, ErrCheckingMonitoring
+	}
+	if monitoring != nil {
+		monitoring.Scale = createReq.Scale
+		monitoring.Date = date
+		if err := s.repo.Update(monitoring); err != nil {
+			return nil, http.StatusInternalServerError, ErrCreatingMonitoring
+		}
+		return monitoring, http.StatusOK, nil
+	}
+
 	// Create a new monitoring record
-	monitoring := &entity.Monitoring{
+	monitoring = &entity.Monitoring{
 		UserID:    userEntity.ID,
 		SymptomID: symptomEntity.ID,
 		Scale:     createReq.Scale,
-		Date:      time.Now(),
+		Date:      date,
+		LocalDate: localDate,
 	}
 
 	// Save the monitoring record to the database
 	err = s.repo.Create(monitoring)
 	if err != nil {
-		return nil, http.StatusInternalServerError, ErrCreatingMonitoring
+		// A concurrent request may have created the record for the day, in which case it is updated
+		existing, findErr := s.findDailyMonitoring(userEntity.ID, symptomEntity.ID, localDate)
+		if findErr != nil || existing == nil {
+			return nil, http.StatusInternalServerError, ErrCreatingMonitoring
+		}
+		existing.Scale = createReq.Scale
+		existing.Date = date
+		if err := s.repo.Update(existing); err != nil {
+			return nil, http.StatusInternalServerError, ErrCreatingMonitoring
+		}
+		return existing, http.StatusOK, nil
 	}
 
 	// Return the monitoring record and the HTTP OK status code if the create operation is successful
 	return monitoring, http.StatusOK, nil
 }
 
+// findDailyMonitoring returns the monitoring record of the user for the symptom on the given local date,
+// or nil if there is none.
+func (s *service) findDailyMonitoring(userID, symptomID int, localDate time.Time) (*entity.Monitoring, error) {
+	monitoring := &entity.Monitoring{}
+	err := s.repo.First(monitoring, "user_id = ? AND symptom_id = ? AND local_date = ?", userID, symptomID, localDate)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return monitoring, nil
+}
+
 // GetAllMonitorings retrieves all monitoring records for a user from the database.
 func (s *service) GetAllMonitorings(c *gin.Context, userUUID uuid.UUID) ([]*entity.Monitoring, int, error) {
 	// Find user by UUID
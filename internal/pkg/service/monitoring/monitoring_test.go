@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"net/http"
 	"testing"
+	"time"
 )
 
 var testUserUuid = uuid.MustParse("24df3f36-ca63-11ed-afa1-0242ac120002")
@@ -63,6 +64,21 @@ func (m mockMonitoringRepository) FindByUUID(uuid uuid.UUID, out interface{}) (i
 	return nil, errors.New("not found")
 }
 
+func (m mockMonitoringRepository) First(dest interface{}, conditions ...interface{}) error {
+	if len(conditions) > 2 && conditions[2] == 11 {
+		monitoring := dest.(*entity.Monitoring)
+		monitoring.ID = 1
+		monitoring.UserID = 1
+		monitoring.SymptomID = 11
+		return nil
+	}
+	return errors.New("record not found")
+}
+
+func (m mockMonitoringRepository) Update(value interface{}) error {
+	return nil
+}
+
 func (m mockMonitoringRepository) CreateWithOmit(omit string, value interface{}) error {
 	if value == nil {
 		return errors.New("input value cannot be nil")
@@ -81,6 +97,9 @@ func TestCreateMonitoring(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	c, _ := gin.CreateTestContext(nil)
 
+	pastDate := time.Now().AddDate(0, 0, -3)
+	futureDate := time.Now().AddDate(0, 0, 1)
+
 	testCases := []struct {
 		name        string
 		monitoring  *entity.RequestCreateMonitoring
@@ -91,7 +110,9 @@ func TestCreateMonitoring(t *testing.T) {
 		{"monitoring creation failed, user doesn't exist", &entity.RequestCreateMonitoring{SymptomUUID: testSymptomUuidNewMonitoring}, true, uuid.New()},
 		{"monitoring creation failed, symptom doesn't exist", &entity.RequestCreateMonitoring{SymptomUUID: uuid.New()}, true, testUserUuid},
 		{"monitoring creation failed, invalid request", nil, true, testUserUuid},
-		{"monitoring creation failed, monitoring already exists", &entity.RequestCreateMonitoring{SymptomUUID: testSymptomUuidExistMonitoring}, true, testUserUuid},
+		{"monitoring updated, monitoring already exists for the day", &entity.RequestCreateMonitoring{SymptomUUID: testSymptomUuidExistMonitoring}, false, testUserUuid},
+		{"monitoring creation successful, backdated", &entity.RequestCreateMonitoring{SymptomUUID: testSymptomUuidNewMonitoring, Date: &pastDate}, false, testUserUuid},
+		{"monitoring creation failed, date in the future", &entity.RequestCreateMonitoring{SymptomUUID: testSymptomUuidNewMonitoring, Date: &futureDate}, true, testUserUuid},
 		{"monitoring creation failed, scale comparison failed", &entity.RequestCreateMonitoring{SymptomUUID: testSymptomUuidNewMonitoring, Scale: 2}, true, testUserUuid},
 	}
 
@@ -133,3 +154,28 @@ func TestGetAllMonitoring(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.NotEqual(t, http.StatusOK, statusCode)
 }
+
+func TestCreateMonitoringLocalDate(t *testing.T) {
+	original := timeNow
+	timeNow = func() time.Time { return time.Date(2023, time.June, 6, 1, 30, 0, 0, time.UTC) }
+	defer func() { timeNow = original }()
+
+	repo := &mockTimezoneMonitoringRepository{}
+	svc := NewService(repo)
+
+	monitoring, statusCode, err := svc.CreateMonitoring(&gin.Context{}, testUserUuid, &entity.RequestCreateMonitoring{SymptomUUID: testSymptomUuidNewMonitoring})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, time.Date(2023, time.June, 5, 0, 0, 0, 0, time.UTC), monitoring.LocalDate)
+}
+
+type mockTimezoneMonitoringRepository struct {
+	mockMonitoringRepository
+}
+
+func (m mockTimezoneMonitoringRepository) FindByUUID(id uuid.UUID, out interface{}) (interface{}, error) {
+	if id == testUserUuid {
+		return &entity.User{ID: 1, UUID: testUserUuid, Timezone: "America/Montevideo"}, nil
+	}
+	return m.mockMonitoringRepository.FindByUUID(id, out)
+}
@@ -0,0 +1,194 @@
+package monitoring
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/emur-uy/backend/internal/pkg/dates"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrRetrievingForecasts = errors.New("error retrieving forecasts")
+	ErrRetrievingSymptoms  = errors.New("error retrieving symptoms")
+)
+
+// forecastDateLayout is the layout of the dates stored by the forecast worker.
+const forecastDateLayout = "2006-01-02"
+
+// minCorrelationSamples is the minimum number of days needed to compute a correlation coefficient.
+const minCorrelationSamples = 3
+
+// GetWeatherCorrelation pairs the daily mean scale of each symptom recorded by the user with the forecast stored
+// for the user's city on the same date, and computes the correlation against temperature, humidity and UV.
+func (s *service) GetWeatherCorrelation(c *gin.Context, userUUID uuid.UUID, correlationReq *entity.RequestWeatherCorrelation) ([]*entity.WeatherCorrelation, int, error) {
+	if correlationReq == nil {
+		return nil, http.StatusBadRequest, errors.New("nil payload")
+	}
+	if !correlationReq.To.After(correlationReq.From) || correlationReq.To.Sub(correlationReq.From) > maxAnalyticsRange {
+		return nil, http.StatusBadRequest, ErrInvalidAnalyticsRange
+	}
+
+	// Find user by UUID
+	foundUser, err := s.repo.FindByUUID(userUUID, &entity.User{})
+	if err != nil {
+		return nil, http.StatusInternalServerError, ErrFindingUser
+	}
+	userEntity, ok := foundUser.(*entity.User)
+	if !ok {
+		return nil, http.StatusInternalServerError, ErrAssertingUser
+	}
+
+	// Resolve the requested symptoms, or every symptom recorded by the user when none is requested
+	conditions := []interface{}{"user_id = ? AND date >= ? AND date <= ?", userEntity.ID, correlationReq.From, correlationReq.To}
+	if len(correlationReq.SymptomUUIDs) > 0 {
+		symptomIDs := []int{}
+		for _, symptomUUID := range correlationReq.SymptomUUIDs {
+			foundSymptom, err := s.repo.FindByUUID(symptomUUID, &entity.Symptom{})
+			if err != nil {
+				if err.Error() == "record not found" {
+					return nil, http.StatusBadRequest, errors.New("symptom not found")
+				}
+				return nil, http.StatusInternalServerError, ErrFindingSymptom
+			}
+			symptomEntity, ok := foundSymptom.(*entity.Symptom)
+			if !ok {
+				return nil, http.StatusInternalServerError, ErrAssertingSymptom
+			}
+			symptomIDs = append(symptomIDs, symptomEntity.ID)
+		}
+		conditions = []interface{}{"user_id = ? AND symptom_id IN ? AND date >= ? AND date <= ?", userEntity.ID, symptomIDs, correlationReq.From, correlationReq.To}
+	}
+
+	var monitorings []*entity.Monitoring
+	if err := s.repo.Find(&monitorings, conditions...); err != nil {
+		return nil, http.StatusInternalServerError, ErrRetrievingMonitorings
+	}
+
+	correlations := []*entity.WeatherCorrelation{}
+	if len(monitorings) == 0 {
+		return correlations, http.StatusOK, nil
+	}
+
+	// Get the forecasts stored for the user's location in the range, falling back to
+	// the forecasts stored with the city of the user when it is not geocoded yet
+	var forecasts []*entity.Forecast
+	from, to := correlationReq.From.Format(forecastDateLayout), correlationReq.To.Format(forecastDateLayout)
+	forecastConditions := []interface{}{"country = ? AND state = ? AND date >= ? AND date <= ?", userEntity.Country, userEntity.City, from, to}
+	if userEntity.LocationID != nil {
+		forecastConditions = []interface{}{"location_id = ? AND date >= ? AND date <= ?", *userEntity.LocationID, from, to}
+	}
+	if err := s.repo.Find(&forecasts, forecastConditions...); err != nil {
+		return nil, http.StatusInternalServerError, ErrRetrievingForecasts
+	}
+	forecastsByDate := latestForecastByDate(forecasts)
+
+	// Group the monitorings per symptom
+	monitoringsBySymptom := map[int][]*entity.Monitoring{}
+	symptomIDs := []int{}
+	for _, monitoring := range monitorings {
+		if _, ok := monitoringsBySymptom[monitoring.SymptomID]; !ok {
+			symptomIDs = append(symptomIDs, monitoring.SymptomID)
+		}
+		monitoringsBySymptom[monitoring.SymptomID] = append(monitoringsBySymptom[monitoring.SymptomID], monitoring)
+	}
+
+	var symptoms []*entity.Symptom
+	if err := s.repo.Find(&symptoms, "id IN ?", symptomIDs); err != nil {
+		return nil, http.StatusInternalServerError, ErrRetrievingSymptoms
+	}
+	sort.Slice(symptoms, func(i, j int) bool { return symptoms[i].Name < symptoms[j].Name })
+
+	for _, symptom := range symptoms {
+		points := weatherPoints(aggregateMonitorings(monitoringsBySymptom[symptom.ID], dates.StartOfDay), forecastsByDate)
+
+		scales := make([]float64, len(points))
+		temperatures := make([]float64, len(points))
+		humidities := make([]float64, len(points))
+		uvs := make([]float64, len(points))
+		for i, point := range points {
+			scales[i] = point.Scale
+			temperatures[i] = float64(point.Temperature)
+			humidities[i] = float64(point.Humidity)
+			uvs[i] = float64(point.UV)
+		}
+
+		correlations = append(correlations, &entity.WeatherCorrelation{
+			SymptomUUID: symptom.UUID,
+			Name:        symptom.Name,
+			Samples:     len(points),
+			Temperature: pearson(scales, temperatures),
+			Humidity:    pearson(scales, humidities),
+			UV:          pearson(scales, uvs),
+			Points:      points,
+		})
+	}
+
+	return correlations, http.StatusOK, nil
+}
+
+// latestForecastByDate indexes the forecasts by date, keeping the most recently stored forecast of each date.
+func latestForecastByDate(forecasts []*entity.Forecast) map[string]*entity.Forecast {
+	forecastsByDate := map[string]*entity.Forecast{}
+	for _, forecast := range forecasts {
+		if existing, ok := forecastsByDate[forecast.Date]; !ok || forecast.ID > existing.ID {
+			forecastsByDate[forecast.Date] = forecast
+		}
+	}
+	return forecastsByDate
+}
+
+// weatherPoints pairs each daily aggregate with the forecast of the same date, skipping days without a forecast.
+func weatherPoints(daily []*entity.MonitoringAggregate, forecastsByDate map[string]*entity.Forecast) []*entity.WeatherPoint {
+	points := []*entity.WeatherPoint{}
+	for _, day := range daily {
+		forecast, ok := forecastsByDate[day.Start.Format(forecastDateLayout)]
+		if !ok {
+			continue
+		}
+
+		points = append(points, &entity.WeatherPoint{
+			Date:        day.Start,
+			Scale:       day.Mean,
+			Temperature: forecast.MaxTemperature,
+			Humidity:    forecast.Humidity,
+			UV:          forecast.UV,
+		})
+	}
+	return points
+}
+
+// pearson returns the Pearson correlation coefficient of xs and ys, or nil when there are
+// not enough samples or one of the series is constant.
+func pearson(xs, ys []float64) *float64 {
+	n := len(xs)
+	if n < minCorrelationSamples || n != len(ys) {
+		return nil
+	}
+
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(n)
+	meanY /= float64(n)
+
+	var covariance, varianceX, varianceY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		covariance += dx * dy
+		varianceX += dx * dx
+		varianceY += dy * dy
+	}
+	if varianceX == 0 || varianceY == 0 {
+		return nil
+	}
+
+	coefficient := covariance / math.Sqrt(varianceX*varianceY)
+	return &coefficient
+}
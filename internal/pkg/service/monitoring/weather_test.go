@@ -0,0 +1,85 @@
+package monitoring
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWeatherRepository struct {
+	mockMonitoringRepository
+	monitorings []*entity.Monitoring
+	forecasts   []*entity.Forecast
+}
+
+func (m mockWeatherRepository) Find(out interface{}, conditions ...interface{}) error {
+	switch dest := out.(type) {
+	case *[]*entity.Monitoring:
+		*dest = m.monitorings
+	case *[]*entity.Forecast:
+		*dest = m.forecasts
+	case *[]*entity.Symptom:
+		*dest = []*entity.Symptom{{ID: 11, UUID: testSymptomUuidExistMonitoring, Name: "Fatigue"}}
+	}
+	return nil
+}
+
+func TestPearson(t *testing.T) {
+	coefficient := pearson([]float64{1, 2, 3, 4}, []float64{2, 4, 6, 8})
+	require.NotNil(t, coefficient)
+	assert.InDelta(t, 1.0, *coefficient, 1e-9)
+
+	coefficient = pearson([]float64{1, 2, 3, 4}, []float64{8, 6, 4, 2})
+	require.NotNil(t, coefficient)
+	assert.InDelta(t, -1.0, *coefficient, 1e-9)
+
+	// Not enough samples and constant series have no coefficient
+	assert.Nil(t, pearson([]float64{1, 2}, []float64{1, 2}))
+	assert.Nil(t, pearson([]float64{1, 2, 3}, []float64{5, 5, 5}))
+}
+
+func TestGetWeatherCorrelation(t *testing.T) {
+	repo := &mockWeatherRepository{
+		monitorings: []*entity.Monitoring{
+			{SymptomID: 11, Scale: 1, Date: day(1)},
+			{SymptomID: 11, Scale: 2, Date: day(2)},
+			{SymptomID: 11, Scale: 3, Date: day(3)},
+			{SymptomID: 11, Scale: 3, Date: day(4)},
+		},
+		forecasts: []*entity.Forecast{
+			{ID: 1, Date: "2023-06-01", MaxTemperature: 20, Humidity: 50, UV: 5},
+			{ID: 2, Date: "2023-06-02", MaxTemperature: 25, Humidity: 50, UV: 3},
+			{ID: 3, Date: "2023-06-03", MaxTemperature: 30, Humidity: 50, UV: 1},
+			// A newer forecast for the same date replaces the older one
+			{ID: 4, Date: "2023-06-01", MaxTemperature: 15, Humidity: 50, UV: 5},
+		},
+	}
+	svc := NewService(repo)
+
+	// Invalid range
+	_, statusCode, err := svc.GetWeatherCorrelation(&gin.Context{}, testUserUuid, &entity.RequestWeatherCorrelation{From: day(30), To: day(1)})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	correlations, statusCode, err := svc.GetWeatherCorrelation(&gin.Context{}, testUserUuid, &entity.RequestWeatherCorrelation{From: day(1), To: day(30)})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, correlations, 1)
+
+	correlation := correlations[0]
+	assert.Equal(t, "Fatigue", correlation.Name)
+	// The day without a forecast is skipped
+	assert.Equal(t, 3, correlation.Samples)
+	require.Len(t, correlation.Points, 3)
+	assert.Equal(t, 15, correlation.Points[0].Temperature)
+
+	require.NotNil(t, correlation.Temperature)
+	assert.Greater(t, *correlation.Temperature, 0.9)
+	require.NotNil(t, correlation.UV)
+	assert.InDelta(t, -1.0, *correlation.UV, 1e-9)
+	assert.Nil(t, correlation.Humidity)
+}
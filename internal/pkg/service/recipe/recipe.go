@@ -4,10 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"path"
 
-	"github.com/emur-uy/backend/config"
-	aws "github.com/emur-uy/backend/internal/infra/repositories/spaces"
 	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/ports"
 	"github.com/gin-gonic/gin"
@@ -24,8 +21,6 @@ var (
 	ErrCreatingRecipeMedia = errors.New("error creating recipe media association")
 	ErrDeletingRecipeMedia = errors.New("error deleting recipe media association")
 	ErrDeletingMedia       = errors.New("error deleting media")
-	ErrUnsupportedFileType = errors.New("unsupported file type")
-	ErrFileNotFound        = errors.New("file not found")
 )
 
 const (
@@ -66,9 +61,9 @@ func (s *service) CreateRecipe(c *gin.Context, userUUID uuid.UUID, createReq *en
 		return nil, ErrTypeAssertionFailed
 	}
 
-	// Call the processUploadRequestFile function to handle the image upload and create the media entry
-	fileProcessCode, fileUrls, err := processUploadRequestFiles(s, c)
-	if err != nil || fileProcessCode != http.StatusOK {
+	// Upload the images with their variants, the media entries are created with the recipe
+	medias, _, err := s.mediaService.UploadFormFiles(c, entity.MediaDomainRecipes)
+	if err != nil {
 		return nil, fmt.Errorf("error processing content upload file: %s", err)
 	}
 
@@ -88,11 +83,7 @@ func (s *service) CreateRecipe(c *gin.Context, userUUID uuid.UUID, createReq *en
 	}
 
 	// For each uploaded file, create a new media entry and then a new RecipeMedia entry
-	for _, fileUrl := range fileUrls {
-		media := &entity.Media{
-			MediaURL: fileUrl,
-		}
-
+	for _, media := range medias {
 		err = s.mediaService.CreateMedia(media)
 		if err != nil {
 			return nil, ErrCreatingMedia
@@ -140,9 +131,9 @@ func (s *service) UpdateRecipe(c *gin.Context, recipeUUID uuid.UUID, updateReq *
 		return http.StatusInternalServerError, fmt.Errorf("error updating recipe: %s", err)
 	}
 
-	fileProcessCode, fileUrls, err := processUploadRequestFiles(s, c)
-	if err != nil || fileProcessCode != http.StatusOK {
-		return http.StatusInternalServerError, fmt.Errorf("error processing content upload file: %s", err)
+	medias, fileProcessCode, err := s.mediaService.UploadFormFiles(c, entity.MediaDomainRecipes)
+	if err != nil {
+		return fileProcessCode, fmt.Errorf("error processing content upload file: %s", err)
 	}
 
 	// Get existing recipe media data
@@ -153,10 +144,7 @@ func (s *service) UpdateRecipe(c *gin.Context, recipeUUID uuid.UUID, updateReq *
 	}
 
 	// For each uploaded file, create a new media entry and a new recipe_media association
-	for _, fileUrl := range fileUrls {
-		media := &entity.Media{
-			MediaURL: fileUrl,
-		}
+	for _, media := range medias {
 		err = s.mediaService.CreateMedia(media)
 		if err != nil {
 			return http.StatusInternalServerError, ErrCreatingMedia
@@ -195,7 +183,8 @@ func (s *service) UpdateRecipe(c *gin.Context, recipeUUID uuid.UUID, updateReq *
 	return http.StatusOK, nil
 }
 
-// DeleteRecipe deletes a recipe from the database by its UUID.
+// DeleteRecipe moves a recipe to the trash by its UUID. Its media are kept until the trash is purged,
+// so the recipe can be restored.
 func (s *service) DeleteRecipe(c *gin.Context, recipeUUID uuid.UUID) (int, error) {
 	// Retrieve the recipe from the repository by its UUID.
 	recipe := &entity.Recipe{}
@@ -211,36 +200,7 @@ func (s *service) DeleteRecipe(c *gin.Context, recipeUUID uuid.UUID) (int, error
 		return http.StatusInternalServerError, ErrTypeAssertionFailed
 	}
 
-	// Get recipe media associations by recipe ID
-	recipeMedias := []*entity.RecipeMedia{}
-	err = s.recipeMediaService.FindByRecipeID(recipe.ID, &recipeMedias)
-	if err != nil {
-		return http.StatusInternalServerError, ErrFindingRecipeMedia
-	}
-
-	// Iterate over each recipe_media association
-	for _, recipeMedia := range recipeMedias {
-		media := &entity.Media{}
-		// Find the media by ID
-		err := s.mediaService.FindByMediaID(recipeMedia.MediaID, media)
-		if err != nil {
-			return http.StatusInternalServerError, err
-		}
-
-		// Delete the recipe_media association from the repository
-		err = s.recipeMediaService.DeleteRecipeMedia(recipeMedia)
-		if err != nil {
-			return http.StatusInternalServerError, ErrDeletingRecipeMedia
-		}
-
-		// Delete the media from the repository
-		err = s.mediaService.DeleteMedia(media)
-		if err != nil {
-			return http.StatusInternalServerError, ErrDeletingMedia
-		}
-	}
-
-	// Delete the recipe from the repository
+	// Soft delete the recipe from the repository
 	err = s.repo.Delete(recipe)
 	if err != nil {
 		return http.StatusInternalServerError, ErrDeletingRecipe
@@ -334,45 +294,3 @@ func (s *service) VoteRecipe(c *gin.Context, userUUID uuid.UUID, recipeUUID uuid
 	}
 	return http.StatusOK, s.repo.Create(recipeVote)
 }
-
-var uploadFunc = aws.UploadFileToS3Stream
-
-// processUploadRequestFiles processes the file upload request
-func processUploadRequestFiles(s *service, c *gin.Context) (int, []string, error) {
-	form, err := c.MultipartForm()
-	if err != nil {
-		return http.StatusBadRequest, nil, fmt.Errorf("get form err: %s", err.Error())
-	}
-	files := form.File["file"]
-	if files == nil {
-		return http.StatusBadRequest, nil, ErrFileNotFound
-	}
-
-	var fileUrls []string
-
-	for _, file := range files {
-		src, err := file.Open()
-		if err != nil {
-			return http.StatusInternalServerError, nil, fmt.Errorf("failed to open file: %s", err)
-		}
-		defer src.Close()
-
-		fileType := file.Header.Get("Content-Type")
-		if fileType != "image/png" && fileType != "image/jpeg" {
-			return http.StatusBadRequest, nil, ErrUnsupportedFileType
-		}
-
-		fileExt := path.Ext(file.Filename)
-		fileNameUuid := uuid.New()
-
-		uploadPath := fmt.Sprintf("%s/%s", config.Get().AwsFolderName, fmt.Sprintf("%s%s", fileNameUuid.String(), fileExt))
-		url, err := uploadFunc(src, uploadPath, true)
-		if err != nil || url == "" {
-			return http.StatusInternalServerError, nil, fmt.Errorf("s3 upload error: %s", err.Error())
-		}
-
-		fileUrls = append(fileUrls, url)
-	}
-
-	return http.StatusOK, fileUrls, nil
-}
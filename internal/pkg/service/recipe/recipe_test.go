@@ -4,18 +4,17 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	aws "github.com/emur-uy/backend/internal/infra/repositories/spaces"
 	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/textproto"
 	"testing"
+	"time"
 )
 
 var testUserUuid = uuid.MustParse("24df3f36-ca63-11ed-afa1-0242ac120002")
@@ -110,9 +109,38 @@ func (m *MockRecipeRepository) FindByUUID(uId uuid.UUID, out interface{}) (inter
 	return nil, errors.New("not found")
 }
 
-func mockUploadFileToS3Stream(src io.Reader, uploadPath string, isPublic bool) (string, error) {
-	// Mocked implementation of the upload function
-	return "mocked-url", nil
+// UploadFormFiles returns a media for each file of the form, as the media pipeline does.
+func (m MockMediaService) UploadFormFiles(c *gin.Context, domain string) ([]*entity.Media, int, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	var medias []*entity.Media
+	for range form.File["file"] {
+		medias = append(medias, &entity.Media{MediaURL: "mocked-url", MediaThumb: "mocked-thumb-url"})
+	}
+	if len(medias) == 0 {
+		return nil, http.StatusBadRequest, errors.New("file not found")
+	}
+	return medias, http.StatusOK, nil
+}
+
+// SignMedia returns the URLs of the media with a mocked signature.
+func (m MockMediaService) SignMedia(media *entity.Media) (*entity.SignedMedia, error) {
+	return &entity.SignedMedia{
+		UUID:       media.UUID,
+		MediaURL:   media.MediaURL + "?signature=mocked",
+		MediaThumb: media.MediaThumb + "?signature=mocked",
+		ExpiresAt:  time.Now().Add(15 * time.Minute),
+	}, nil
+}
+
+func (m MockMediaService) MakeDomainPrivate(domain string, batchSize int, dryRun bool) (*entity.MediaPrivacyReport, error) {
+	return &entity.MediaPrivacyReport{Domain: domain}, nil
+}
+
+func (m MockMediaService) CollectOrphans(now time.Time, gracePeriod time.Duration, dryRun bool) (*entity.MediaGCReport, error) {
+	return &entity.MediaGCReport{DryRun: dryRun}, nil
 }
 
 func TestCreateRecipe(t *testing.T) {
@@ -163,12 +191,6 @@ func TestCreateRecipe(t *testing.T) {
 		Category: 1,
 	}
 
-	uploadFunc = mockUploadFileToS3Stream
-	defer func() {
-		// Restore the original upload function after the test
-		uploadFunc = aws.UploadFileToS3Stream
-	}()
-
 	// Create a test context without request file
 	ctx, _ := gin.CreateTestContext(nil)
 	ctx.Request = httptest.NewRequest(http.MethodPost, "/", fileBuf)
@@ -248,12 +270,6 @@ func TestUpdateRecipe(t *testing.T) {
 	c.Request.Header.Set("Content-Type", "multipart/form-data; boundary="+fileWriter.Boundary())
 	//c.Request.Header.Set("Content-Type", "image/jpeg")
 
-	uploadFunc = mockUploadFileToS3Stream
-	defer func() {
-		// Restore the original upload function after the test
-		uploadFunc = aws.UploadFileToS3Stream
-	}()
-
 	// Create a test context without request file
 	ctx, _ := gin.CreateTestContext(nil)
 	ctx.Request = httptest.NewRequest(http.MethodPost, "/", fileBuf)
@@ -4,12 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"path"
 
-	"github.com/emur-uy/backend/config"
-	aws "github.com/emur-uy/backend/internal/infra/repositories/spaces"
 	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/emur-uy/backend/internal/pkg/service/audit"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -24,10 +22,10 @@ var (
 	ErrDeletingReminderMedia = errors.New("error deleting reminder media association")
 	ErrCreatingMedia         = errors.New("error creating media")
 	ErrDeletingMedia         = errors.New("error deleting media")
-	ErrFileNotFound          = errors.New("file not found")
-	ErrUnsupportedFileType   = errors.New("unsupported file type")
 	ErrInvalidVoteValue      = errors.New("invalid vote value, must be between 1 and 5")
 	ErrFindingMedia          = errors.New("error finding media")
+	ErrReminderNotFound      = errors.New("reminder not found")
+	ErrSigningMedia          = errors.New("error signing media")
 )
 
 // service struct holds the necessary dependencies for the reminder service
@@ -62,9 +60,9 @@ func (s *service) CreateReminder(c *gin.Context, userUUID uuid.UUID, createReq *
 		return http.StatusInternalServerError, ErrTypeAssertionFailed
 	}
 
-	fileProcessCode, fileUrls, err := processUploadRequestFiles(s, c) // This now processes multiple files
-	if err != nil || fileProcessCode != http.StatusOK {
-		return http.StatusInternalServerError, fmt.Errorf("error processing content upload file: %s", err)
+	medias, fileProcessCode, err := s.mediaService.UploadFormFiles(c, entity.MediaDomainReminders)
+	if err != nil {
+		return fileProcessCode, fmt.Errorf("error processing content upload file: %s", err)
 	}
 
 	// Create a new reminder
@@ -86,10 +84,7 @@ func (s *service) CreateReminder(c *gin.Context, userUUID uuid.UUID, createReq *
 	}
 
 	// For each uploaded file, create a new media entry and a new reminder_media association
-	for _, fileUrl := range fileUrls {
-		media := &entity.Media{
-			MediaURL: fileUrl,
-		}
+	for _, media := range medias {
 		err = s.mediaService.CreateMedia(media)
 		if err != nil {
 			return http.StatusInternalServerError, ErrCreatingMedia
@@ -147,34 +142,11 @@ func (s *service) GetAllReminders(c *gin.Context, userUUID uuid.UUID) ([]*entity
 			IsActive:     reminder.IsActive,
 		}
 
-		// Get reminder medias
-		reminderMedias := []*entity.ReminderMedia{}
-		err = s.repo.Find(&entity.ReminderMedia{}, &reminderMedias, "reminder_id = ?", reminder.ID)
+		// Get the media of the reminder, the reminders being those of the user
+		getReminderResponse.Media, err = s.getReminderMedia(reminder)
 		if err != nil {
-			// Return error if the user is not found
 			return nil, err
 		}
-
-		reminderMediaResponses := []entity.GetReminderMediaResponse{}
-
-		// Get media details
-		for _, reminderMedia := range reminderMedias {
-			// Get media details
-			media := entity.Media{}
-			err = s.repo.Find(&entity.Media{}, &media, "id = ?", reminderMedia.MediaID)
-			if err != nil {
-				// Return error if the user is not found
-				return nil, err
-			}
-
-			// Add details in response
-			reminderMediaResponse := &entity.GetReminderMediaResponse{
-				MediaURL:   media.MediaURL,
-				MediaThumb: media.MediaThumb,
-			}
-			reminderMediaResponses = append(reminderMediaResponses, *reminderMediaResponse)
-		}
-		getReminderResponse.Media = reminderMediaResponses
 		response = append(response, getReminderResponse)
 	}
 
@@ -186,8 +158,77 @@ func (s *service) GetAllReminders(c *gin.Context, userUUID uuid.UUID) ([]*entity
 	return response, nil
 }
 
+// GetReminderMedia returns fresh signed URLs of the media of the reminder, once checked it belongs to the user.
+// The reminders of other users are reported as not found.
+func (s *service) GetReminderMedia(c *gin.Context, userUUID uuid.UUID, reminderUUID uuid.UUID) ([]entity.GetReminderMediaResponse, int, error) {
+	audit.SetResource(c, reminderUUID)
+
+	foundUser, err := s.repo.FindByUUID(userUUID, &entity.User{})
+	if err != nil {
+		return nil, http.StatusNotFound, err
+	}
+	user, ok := foundUser.(*entity.User)
+	if !ok {
+		return nil, http.StatusInternalServerError, ErrTypeAssertionFailed
+	}
+
+	foundReminder, err := s.repo.FindByUUID(reminderUUID, &entity.Reminder{})
+	if err != nil {
+		return nil, http.StatusNotFound, ErrReminderNotFound
+	}
+	reminder, ok := foundReminder.(*entity.Reminder)
+	if !ok {
+		return nil, http.StatusInternalServerError, ErrTypeAssertionFailed
+	}
+	if reminder.UserID != user.ID {
+		return nil, http.StatusNotFound, ErrReminderNotFound
+	}
+
+	media, err := s.getReminderMedia(reminder)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return media, http.StatusOK, nil
+}
+
+// getReminderMedia returns the media of the reminder with signed URLs, the media of the reminders being private.
+// The caller checks the reminder belongs to the user.
+func (s *service) getReminderMedia(reminder *entity.Reminder) ([]entity.GetReminderMediaResponse, error) {
+	// Get reminder medias
+	reminderMedias := []*entity.ReminderMedia{}
+	err := s.repo.Find(&entity.ReminderMedia{}, &reminderMedias, "reminder_id = ?", reminder.ID)
+	if err != nil {
+		return nil, ErrFindingReminderMedia
+	}
+
+	reminderMediaResponses := []entity.GetReminderMediaResponse{}
+	for _, reminderMedia := range reminderMedias {
+		// Get media details
+		media := entity.Media{}
+		err = s.repo.Find(&entity.Media{}, &media, "id = ?", reminderMedia.MediaID)
+		if err != nil {
+			return nil, ErrFindingMedia
+		}
+
+		signed, err := s.mediaService.SignMedia(&media)
+		if err != nil {
+			return nil, ErrSigningMedia
+		}
+		reminderMediaResponses = append(reminderMediaResponses, entity.GetReminderMediaResponse{
+			UUID:       signed.UUID,
+			MediaURL:   signed.MediaURL,
+			MediaThumb: signed.MediaThumb,
+			MediaWebP:  signed.MediaWebP,
+			ExpiresAt:  signed.ExpiresAt,
+		})
+	}
+	return reminderMediaResponses, nil
+}
+
 // UpdateReminder is the service for updating a reminder in the database.
 func (s *service) UpdateReminder(c *gin.Context, reminderUUID uuid.UUID, updateReq *entity.RequestUpdateReminder) (int, error) {
+	audit.SetResource(c, reminderUUID)
+
 	// Find the existing reminder by UUID
 	reminder := &entity.Reminder{}
 	foundReminder, err := s.repo.FindByUUID(reminderUUID, reminder)
@@ -219,9 +260,9 @@ func (s *service) UpdateReminder(c *gin.Context, reminderUUID uuid.UUID, updateR
 		return http.StatusInternalServerError, ErrUpdatingReminder
 	}
 
-	fileProcessCode, fileUrls, err := processUploadRequestFiles(s, c) // This now processes multiple files
-	if err != nil || fileProcessCode != http.StatusOK {
-		return http.StatusInternalServerError, fmt.Errorf("error processing content upload file: %s", err)
+	medias, fileProcessCode, err := s.mediaService.UploadFormFiles(c, entity.MediaDomainReminders)
+	if err != nil {
+		return fileProcessCode, fmt.Errorf("error processing content upload file: %s", err)
 	}
 
 	// Get existing reminder media data
@@ -232,10 +273,7 @@ func (s *service) UpdateReminder(c *gin.Context, reminderUUID uuid.UUID, updateR
 	}
 
 	// For each uploaded file, create a new media entry and a new reminder_media association
-	for _, fileUrl := range fileUrls {
-		media := &entity.Media{
-			MediaURL: fileUrl,
-		}
+	for _, media := range medias {
 		err = s.mediaService.CreateMedia(media)
 		if err != nil {
 			return http.StatusInternalServerError, ErrCreatingMedia
@@ -274,10 +312,14 @@ func (s *service) UpdateReminder(c *gin.Context, reminderUUID uuid.UUID, updateR
 	return http.StatusOK, nil
 }
 
+// DeleteReminder moves the reminder to the trash. Its media are kept until the trash is purged,
+// so the reminder can be restored.
 func (s *service) DeleteReminder(c *gin.Context, reminderUUID uuid.UUID) error {
+	audit.SetResource(c, reminderUUID)
+
 	reminder := &entity.Reminder{}
 
-	// 1. Find reminder by UUID
+	// Find reminder by UUID
 	foundReminder, err := s.repo.FindByUUID(reminderUUID, reminder)
 	if err != nil {
 		// Return error if the reminder is not found
@@ -289,42 +331,7 @@ func (s *service) DeleteReminder(c *gin.Context, reminderUUID uuid.UUID) error {
 		return ErrTypeAssertionFailed
 	}
 
-	// 2. Find reminder_media associations by reminder ID
-	reminderMedias := []*entity.ReminderMedia{}
-	err = s.reminderMediaService.FindByReminderID(reminder.ID, &reminderMedias)
-	if err != nil {
-		return err
-	}
-
-	// 3. Iterate over each reminder_media association
-	for _, reminderMedia := range reminderMedias {
-		media := &entity.Media{}
-		// Find the media by ID
-		err := s.mediaService.FindByMediaID(reminderMedia.MediaID, media)
-		if err != nil {
-			return err
-		}
-
-		// Delete file from S3
-		err = aws.DeleteObjectFromS3(media.MediaURL)
-		if err != nil {
-			return fmt.Errorf("failed to delete uploaded files from s3: %s", err.Error())
-		}
-
-		// Delete reminder_media association from db
-		err = s.reminderMediaService.DeleteReminderMedia(reminderMedia)
-		if err != nil {
-			return err
-		}
-
-		// Delete media from db
-		err = s.mediaService.DeleteMedia(media)
-		if err != nil {
-			return err
-		}
-	}
-
-	// 4. Delete reminder from db
+	// Soft delete the reminder from db
 	err = s.repo.Delete(reminder)
 	if err != nil {
 		return err
@@ -332,45 +339,3 @@ func (s *service) DeleteReminder(c *gin.Context, reminderUUID uuid.UUID) error {
 
 	return nil
 }
-
-var uploadFunc = aws.UploadFileToS3Stream
-
-// processUploadRequestFiles processes the file upload request
-func processUploadRequestFiles(s *service, c *gin.Context) (int, []string, error) {
-	form, err := c.MultipartForm()
-	if err != nil {
-		return http.StatusBadRequest, nil, fmt.Errorf("get form err: %s", err.Error())
-	}
-	files := form.File["file"]
-	if files == nil {
-		return http.StatusBadRequest, nil, ErrFileNotFound
-	}
-
-	var fileUrls []string
-
-	for _, file := range files {
-		src, err := file.Open()
-		if err != nil {
-			return http.StatusInternalServerError, nil, fmt.Errorf("failed to open file: %s", err)
-		}
-		defer src.Close()
-
-		fileType := file.Header.Get("Content-Type")
-		if fileType != "image/png" && fileType != "image/jpeg" {
-			return http.StatusBadRequest, nil, ErrUnsupportedFileType
-		}
-
-		fileExt := path.Ext(file.Filename)
-		fileNameUuid := uuid.New()
-
-		uploadPath := fmt.Sprintf("%s/%s", config.Get().AwsFolderName, fmt.Sprintf("%s%s", fileNameUuid.String(), fileExt))
-		url, err := uploadFunc(src, uploadPath, true)
-		if err != nil || url == "" {
-			return http.StatusInternalServerError, nil, fmt.Errorf("s3 upload error: %s", err.Error())
-		}
-
-		fileUrls = append(fileUrls, url)
-	}
-
-	return http.StatusOK, fileUrls, nil
-}
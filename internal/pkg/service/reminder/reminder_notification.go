@@ -0,0 +1,167 @@
+package reminder
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+var (
+	ErrFindingActiveReminders       = errors.New("error finding active reminders")
+	ErrFindingReminderNotifications = errors.New("error finding reminder notifications")
+)
+
+const (
+	// NotificationDays is the days_or_hours value meaning hours_before is expressed in days.
+	NotificationDays = "days"
+	// NotificationHours is the days_or_hours value meaning hours_before is expressed in hours.
+	NotificationHours = "hours"
+
+	// NotificationClaimTimeout is the time after which a claimed notification that was not sent, as when the worker
+	// crashed while sending it, is claimed again.
+	NotificationClaimTimeout = 15 * time.Minute
+)
+
+// reminderNotificationService struct holds the necessary dependencies for the reminder notification service
+type reminderNotificationService struct {
+	repo             ports.ReminderRepository
+	notificationRepo ports.ReminderNotificationRepository
+	notifier         ports.Notifier
+}
+
+// NewReminderNotificationService returns a new instance of the reminder notification service.
+func NewReminderNotificationService(reminderRepo ports.ReminderRepository, notificationRepo ports.ReminderNotificationRepository, notifier ports.Notifier) ports.ReminderNotificationService {
+	return &reminderNotificationService{
+		repo:             reminderRepo,
+		notificationRepo: notificationRepo,
+		notifier:         notifier,
+	}
+}
+
+// DispatchDueNotifications sends the due notifications of every active reminder.
+// A notification is claimed in the ledger before it is sent, so it goes out only once
+// even if several workers run at the same time or the worker is restarted. A claim left
+// unsent for longer than NotificationClaimTimeout is taken over, so it is sent again.
+func (s *reminderNotificationService) DispatchDueNotifications(now time.Time) (int, error) {
+	// Get all active reminders that have not happened yet
+	reminders := []*entity.Reminder{}
+	err := s.repo.Find(&entity.Reminder{}, &reminders, "is_active = ? AND date >= ?", true, now)
+	if err != nil {
+		return 0, ErrFindingActiveReminders
+	}
+
+	sent := 0
+	for _, reminder := range reminders {
+		due := dueNotifications(reminder, now)
+		if len(due) == 0 {
+			continue
+		}
+
+		// Get the notifications of this reminder already recorded in the ledger
+		claimed := []*entity.ReminderNotification{}
+		err = s.notificationRepo.Find(&entity.ReminderNotification{}, &claimed, "reminder_id = ?", reminder.ID)
+		if err != nil {
+			return sent, ErrFindingReminderNotifications
+		}
+
+		for _, notification := range due {
+			notification.ClaimedAt = now
+			if existing := findClaim(claimed, notification); existing != nil {
+				// Take over the stale claims only
+				if existing.SentAt != nil || !existing.ClaimedAt.Before(now.Add(-NotificationClaimTimeout)) {
+					continue
+				}
+				existing.ClaimedAt = now
+				reclaimed, err := s.notificationRepo.Reclaim(existing, now.Add(-NotificationClaimTimeout))
+				if err != nil {
+					log.Printf("[ReminderNotification]: cannot reclaim notification for reminder %s: %v", reminder.UUID, err)
+					continue
+				}
+				if !reclaimed {
+					continue
+				}
+				notification = existing
+			} else {
+				// Claim the notification; the unique (reminder_id, notify_at) constraint rejects a second claim
+				err = s.notificationRepo.Create(notification)
+				if err != nil {
+					log.Printf("[ReminderNotification]: cannot claim notification for reminder %s: %v", reminder.UUID, err)
+					continue
+				}
+				claimed = append(claimed, notification)
+			}
+
+			err = s.notifier.Notify(reminder, notification)
+			if err != nil {
+				// Release the claim so the notification is retried on the next run
+				log.Printf("[ReminderNotification]: cannot notify reminder %s: %v", reminder.UUID, err)
+				if err := s.notificationRepo.Delete(notification); err != nil {
+					log.Printf("[ReminderNotification]: cannot release notification for reminder %s: %v", reminder.UUID, err)
+				}
+				continue
+			}
+
+			// Mark the notification as sent
+			err = s.notificationRepo.UpdateColumns(notification, "sent_at", now)
+			if err != nil {
+				log.Printf("[ReminderNotification]: cannot mark notification for reminder %s as sent: %v", reminder.UUID, err)
+			}
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// dueNotifications returns the notifications of the reminder whose time has come at the given moment.
+func dueNotifications(reminder *entity.Reminder, now time.Time) []*entity.ReminderNotification {
+	var due []*entity.ReminderNotification
+
+	// Notifications of a reminder that already happened are never sent
+	if now.After(reminder.Date) {
+		return due
+	}
+
+	for _, n := range reminder.Notification {
+		if n.HoursBefore < 0 {
+			continue
+		}
+
+		notifyAt := reminder.Date.Add(-notificationOffset(n))
+		if notifyAt.After(now) {
+			continue
+		}
+
+		due = append(due, &entity.ReminderNotification{
+			ReminderID:  reminder.ID,
+			NotifyAt:    notifyAt,
+			DaysOrHours: n.DaysOrHours,
+			HoursBefore: n.HoursBefore,
+		})
+	}
+
+	return due
+}
+
+// notificationOffset converts a notification setting to the time it must be sent before the reminder.
+func notificationOffset(n entity.Notification) time.Duration {
+	unit := strings.ToLower(strings.TrimSpace(n.DaysOrHours))
+	if unit == NotificationDays || unit == "day" {
+		return time.Duration(n.HoursBefore) * 24 * time.Hour
+	}
+	return time.Duration(n.HoursBefore) * time.Hour
+}
+
+// findClaim returns the claim of the notification recorded in the ledger, or nil if it was not claimed.
+func findClaim(claimed []*entity.ReminderNotification, notification *entity.ReminderNotification) *entity.ReminderNotification {
+	for _, c := range claimed {
+		if c.ReminderID == notification.ReminderID && c.NotifyAt.Equal(notification.NotifyAt) {
+			return c
+		}
+	}
+	return nil
+}
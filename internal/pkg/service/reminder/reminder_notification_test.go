@@ -0,0 +1,202 @@
+package reminder
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/emur-uy/backend/internal/infra/notifier"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockActiveReminderRepository struct {
+	MockReminderRepository
+	reminders []*entity.Reminder
+}
+
+func (m *mockActiveReminderRepository) Find(model interface{}, dest interface{}, conditions ...interface{}) error {
+	*dest.(*[]*entity.Reminder) = m.reminders
+	return nil
+}
+
+type mockReminderNotificationRepository struct {
+	ledger []*entity.ReminderNotification
+}
+
+func (m *mockReminderNotificationRepository) Create(value interface{}) error {
+	notification := value.(*entity.ReminderNotification)
+	for _, n := range m.ledger {
+		if n.ReminderID == notification.ReminderID && n.NotifyAt.Equal(notification.NotifyAt) {
+			return errors.New("duplicate key value violates unique constraint")
+		}
+	}
+	m.ledger = append(m.ledger, notification)
+	return nil
+}
+
+func (m *mockReminderNotificationRepository) Find(model interface{}, dest interface{}, conditions ...interface{}) error {
+	var found []*entity.ReminderNotification
+	for _, n := range m.ledger {
+		if n.ReminderID == conditions[1] {
+			found = append(found, n)
+		}
+	}
+	*dest.(*[]*entity.ReminderNotification) = found
+	return nil
+}
+
+func (m *mockReminderNotificationRepository) UpdateColumns(value interface{}, column string, updateValue interface{}) error {
+	sentAt := updateValue.(time.Time)
+	value.(*entity.ReminderNotification).SentAt = &sentAt
+	return nil
+}
+
+func (m *mockReminderNotificationRepository) Delete(value interface{}) error {
+	for i, n := range m.ledger {
+		if n == value {
+			m.ledger = append(m.ledger[:i], m.ledger[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (m *mockReminderNotificationRepository) Reclaim(notification *entity.ReminderNotification, staleBefore time.Time) (bool, error) {
+	return notification.SentAt == nil, nil
+}
+
+func TestDueNotifications(t *testing.T) {
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	reminder := &entity.Reminder{
+		ID:   1,
+		Date: time.Date(2023, 7, 2, 10, 0, 0, 0, time.UTC),
+		Notification: entity.NotificationSlice{
+			{DaysOrHours: NotificationDays, HoursBefore: 1},  // 01/07 10:00, due
+			{DaysOrHours: NotificationHours, HoursBefore: 2}, // 02/07 08:00, not due yet
+			{DaysOrHours: NotificationDays, HoursBefore: 3},  // 29/06 10:00, due
+		},
+	}
+
+	due := dueNotifications(reminder, now)
+	require.Len(t, due, 2)
+	assert.Equal(t, time.Date(2023, 7, 1, 10, 0, 0, 0, time.UTC), due[0].NotifyAt)
+	assert.Equal(t, time.Date(2023, 6, 29, 10, 0, 0, 0, time.UTC), due[1].NotifyAt)
+
+	// A reminder that already happened has no due notifications
+	assert.Empty(t, dueNotifications(reminder, reminder.Date.Add(time.Minute)))
+}
+
+func TestDispatchDueNotifications(t *testing.T) {
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	reminderRepo := &mockActiveReminderRepository{
+		reminders: []*entity.Reminder{
+			{
+				ID:       1,
+				UUID:     uuid.New(),
+				Date:     time.Date(2023, 7, 2, 10, 0, 0, 0, time.UTC),
+				IsActive: true,
+				Notification: entity.NotificationSlice{
+					{DaysOrHours: NotificationDays, HoursBefore: 1},
+					{DaysOrHours: NotificationHours, HoursBefore: 2},
+				},
+			},
+		},
+	}
+	ledger := &mockReminderNotificationRepository{}
+	memoryNotifier := notifier.NewMemoryNotifier()
+	s := NewReminderNotificationService(reminderRepo, ledger, memoryNotifier)
+
+	// Test case 1: the due notification is sent and recorded
+	sent, err := s.DispatchDueNotifications(now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, 1, memoryNotifier.Count())
+	require.Len(t, ledger.ledger, 1)
+	assert.NotNil(t, ledger.ledger[0].SentAt)
+
+	// Test case 2: running again, as after a restart, does not send it twice
+	s = NewReminderNotificationService(reminderRepo, ledger, memoryNotifier)
+	sent, err = s.DispatchDueNotifications(now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	assert.Equal(t, 1, memoryNotifier.Count())
+
+	// Test case 3: the second notification becomes due later
+	sent, err = s.DispatchDueNotifications(time.Date(2023, 7, 2, 8, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, 2, memoryNotifier.Count())
+}
+
+func TestDispatchDueNotificationsNotifierFailure(t *testing.T) {
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	reminderRepo := &mockActiveReminderRepository{
+		reminders: []*entity.Reminder{
+			{
+				ID:           1,
+				Date:         time.Date(2023, 7, 1, 13, 0, 0, 0, time.UTC),
+				IsActive:     true,
+				Notification: entity.NotificationSlice{{DaysOrHours: NotificationHours, HoursBefore: 1}},
+			},
+		},
+	}
+	ledger := &mockReminderNotificationRepository{}
+	memoryNotifier := notifier.NewMemoryNotifier()
+	memoryNotifier.Err = errors.New("push provider unavailable")
+	s := NewReminderNotificationService(reminderRepo, ledger, memoryNotifier)
+
+	// Test case 1: a failed delivery releases its claim
+	sent, err := s.DispatchDueNotifications(now)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	assert.Empty(t, ledger.ledger)
+
+	// Test case 2: the notification is retried on the next run
+	memoryNotifier.Err = nil
+	sent, err = s.DispatchDueNotifications(now.Add(5 * time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+}
+
+func TestDispatchDueNotificationsStaleClaim(t *testing.T) {
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	reminderRepo := &mockActiveReminderRepository{
+		reminders: []*entity.Reminder{
+			{
+				ID:           1,
+				Date:         time.Date(2023, 7, 1, 13, 0, 0, 0, time.UTC),
+				IsActive:     true,
+				Notification: entity.NotificationSlice{{DaysOrHours: NotificationHours, HoursBefore: 1}},
+			},
+		},
+	}
+	// The worker crashed after claiming the notification, before sending it
+	ledger := &mockReminderNotificationRepository{
+		ledger: []*entity.ReminderNotification{{ReminderID: 1, NotifyAt: time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC), ClaimedAt: now}},
+	}
+	memoryNotifier := notifier.NewMemoryNotifier()
+	s := NewReminderNotificationService(reminderRepo, ledger, memoryNotifier)
+
+	// Test case 1: the claim is left to the worker holding it until it is stale
+	sent, err := s.DispatchDueNotifications(now.Add(5 * time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	// Test case 2: the stale claim is taken over and the notification sent
+	later := now.Add(NotificationClaimTimeout + time.Minute)
+	sent, err = s.DispatchDueNotifications(later)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, 1, memoryNotifier.Count())
+	require.Len(t, ledger.ledger, 1)
+	assert.Equal(t, later, ledger.ledger[0].ClaimedAt)
+	assert.NotNil(t, ledger.ledger[0].SentAt)
+
+	// Test case 3: once sent, it is not sent again
+	sent, err = s.DispatchDueNotifications(later.Add(NotificationClaimTimeout + time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+}
@@ -4,18 +4,17 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	aws "github.com/emur-uy/backend/internal/infra/repositories/spaces"
 	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/textproto"
 	"testing"
+	"time"
 )
 
 var testUserUuid = uuid.MustParse("24df3f36-ca63-11ed-afa1-0242ac120002")
@@ -33,6 +32,17 @@ func (m *MockReminderRepository) Find(model interface{}, dest interface{}, condi
 	if conditions[0] == "user_id = ?" && conditions[1] == 1 {
 		return nil
 	}
+	if conditions[0] == "reminder_id = ?" && conditions[1] == 1 {
+		*dest.(*[]*entity.ReminderMedia) = []*entity.ReminderMedia{{ReminderID: 1, MediaID: 1}}
+		return nil
+	}
+	if conditions[0] == "reminder_id = ?" && conditions[1] == 2 {
+		return nil
+	}
+	if conditions[0] == "id = ?" && conditions[1] == 1 {
+		*dest.(*entity.Media) = entity.Media{ID: 1, MediaURL: "mocked-url", MediaThumb: "mocked-thumb-url"}
+		return nil
+	}
 	return errors.New("not found")
 }
 
@@ -93,24 +103,55 @@ func (m *MockReminderRepository) FindByUUID(uId uuid.UUID, out interface{}) (int
 	}
 	if uId == testReminderUuid {
 		res := &entity.Reminder{
-			ID:   1,
-			UUID: testReminderUuid,
+			ID:     1,
+			UserID: 1,
+			UUID:   testReminderUuid,
 		}
 		return res, nil
 	}
 	if uId == testReminderUuidWithoutMedias {
 		res := &entity.Reminder{
-			ID:   2,
-			UUID: testReminderUuidWithoutMedias,
+			ID:     2,
+			UserID: 1,
+			UUID:   testReminderUuidWithoutMedias,
 		}
 		return res, nil
 	}
 	return nil, errors.New("not found")
 }
 
-func mockUploadFileToS3Stream(src io.Reader, uploadPath string, isPublic bool) (string, error) {
-	// Mocked implementation of the upload function
-	return "mocked-url", nil
+// UploadFormFiles returns a media for each file of the form, as the media pipeline does.
+func (m MockMediaService) UploadFormFiles(c *gin.Context, domain string) ([]*entity.Media, int, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	var medias []*entity.Media
+	for range form.File["file"] {
+		medias = append(medias, &entity.Media{MediaURL: "mocked-url", MediaThumb: "mocked-thumb-url"})
+	}
+	if len(medias) == 0 {
+		return nil, http.StatusBadRequest, errors.New("file not found")
+	}
+	return medias, http.StatusOK, nil
+}
+
+// SignMedia returns the URLs of the media with a mocked signature.
+func (m MockMediaService) SignMedia(media *entity.Media) (*entity.SignedMedia, error) {
+	return &entity.SignedMedia{
+		UUID:       media.UUID,
+		MediaURL:   media.MediaURL + "?signature=mocked",
+		MediaThumb: media.MediaThumb + "?signature=mocked",
+		ExpiresAt:  time.Now().Add(15 * time.Minute),
+	}, nil
+}
+
+func (m MockMediaService) MakeDomainPrivate(domain string, batchSize int, dryRun bool) (*entity.MediaPrivacyReport, error) {
+	return &entity.MediaPrivacyReport{Domain: domain}, nil
+}
+
+func (m MockMediaService) CollectOrphans(now time.Time, gracePeriod time.Duration, dryRun bool) (*entity.MediaGCReport, error) {
+	return &entity.MediaGCReport{DryRun: dryRun}, nil
 }
 
 func TestCreateReminder(t *testing.T) {
@@ -160,12 +201,6 @@ func TestCreateReminder(t *testing.T) {
 		Medical: 1,
 	}
 
-	uploadFunc = mockUploadFileToS3Stream
-	defer func() {
-		// Restore the original upload function after the test
-		uploadFunc = aws.UploadFileToS3Stream
-	}()
-
 	// Create a test context without request file
 	ctx, _ := gin.CreateTestContext(nil)
 	ctx.Request = httptest.NewRequest(http.MethodPost, "/", fileBuf)
@@ -245,12 +280,6 @@ func TestUpdateReminder(t *testing.T) {
 	c.Request.Header.Set("Content-Type", "multipart/form-data; boundary="+fileWriter.Boundary())
 	//c.Request.Header.Set("Content-Type", "image/jpeg")
 
-	uploadFunc = mockUploadFileToS3Stream
-	defer func() {
-		// Restore the original upload function after the test
-		uploadFunc = aws.UploadFileToS3Stream
-	}()
-
 	// Create a test context without request file
 	ctx, _ := gin.CreateTestContext(nil)
 	ctx.Request = httptest.NewRequest(http.MethodPost, "/", fileBuf)
@@ -374,3 +403,45 @@ func TestGetAllReminders(t *testing.T) {
 		})
 	}
 }
+
+func TestGetReminderMedia(t *testing.T) {
+	// Set up the mock repository and service.
+	s := NewService(&MockReminderRepository{}, &MockMediaService{}, &MockReminderMediaService{})
+
+	// Create a test context
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	// Define test cases.
+	testCases := []struct {
+		name         string
+		userUUID     uuid.UUID
+		reminderUUID uuid.UUID
+		expectStatus int
+		expectMedia  int
+	}{
+		{"reminder of the user with media", testUserUuid, testReminderUuid, http.StatusOK, 1},
+		{"reminder of the user without media", testUserUuid, testReminderUuidWithoutMedias, http.StatusOK, 0},
+		{"reminder of another user", testUserUuidWithoutReminders, testReminderUuid, http.StatusNotFound, 0},
+		{"reminder doesn't exist", testUserUuid, uuid.New(), http.StatusNotFound, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			media, status, err := s.GetReminderMedia(c, tc.userUUID, tc.reminderUUID)
+			assert.Equal(t, tc.expectStatus, status)
+			if tc.expectStatus != http.StatusOK {
+				assert.ErrorIs(t, err, ErrReminderNotFound)
+				assert.Nil(t, media)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, media, tc.expectMedia)
+			for _, m := range media {
+				// The URLs are signed, the media being private
+				assert.Equal(t, "mocked-url?signature=mocked", m.MediaURL)
+				assert.Equal(t, "mocked-thumb-url?signature=mocked", m.MediaThumb)
+			}
+		})
+	}
+}
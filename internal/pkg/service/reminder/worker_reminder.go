@@ -0,0 +1,31 @@
+package reminder
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+type Worker struct {
+	service ports.ReminderNotificationService
+}
+
+func NewWorker(service ports.ReminderNotificationService) *Worker {
+	return &Worker{
+		service: service,
+	}
+}
+
+// CheckNotifications sends the reminder notifications that are due now.
+func (w *Worker) CheckNotifications() {
+	sent, err := w.service.DispatchDueNotifications(time.Now().UTC())
+	if err != nil {
+		fmt.Println("Error dispatching reminder notifications:", err)
+		return
+	}
+
+	if sent > 0 {
+		fmt.Printf("%d reminder notifications sent\n", sent)
+	}
+}
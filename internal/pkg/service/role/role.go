@@ -0,0 +1,227 @@
+package role
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTypeAssertionFailed = errors.New("type assertion failed")
+	ErrInvalidRoleName     = errors.New("the role name must be 2 to 32 lowercase letters, digits, '-' or '_', starting with a letter")
+	ErrInvalidPermission   = errors.New("unknown permission")
+	ErrRoleExists          = errors.New("role already exists")
+	ErrRoleNotFound        = errors.New("role not found")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrBuiltInRole         = errors.New("the built-in roles cannot be deleted")
+	ErrAdminRolesManage    = errors.New("the admin role must keep the roles:manage permission")
+	ErrLastAdmin           = errors.New("the admin role cannot be removed from the last admin")
+)
+
+// roleNamePattern matches the valid role names.
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,31}$`)
+
+// service struct holds the necessary dependencies for the role service
+type service struct {
+	roleRepo ports.RoleRepository
+	userRepo ports.UserRepository
+}
+
+// NewService returns a new instance of the role service with the given role and user repositories.
+func NewService(roleRepo ports.RoleRepository, userRepo ports.UserRepository) ports.RoleService {
+	return &service{
+		roleRepo: roleRepo,
+		userRepo: userRepo,
+	}
+}
+
+// GetRoles returns every role with its permissions.
+func (s *service) GetRoles() ([]entity.Role, error) {
+	return s.roleRepo.FindRoles()
+}
+
+// GetPermissions returns the catalog of permissions.
+func (s *service) GetPermissions() []string {
+	return entity.Permissions
+}
+
+// CreateRole validates the name and the permissions of the new role and stores it.
+func (s *service) CreateRole(createReq *entity.RequestCreateRole) (*entity.Role, int, error) {
+	if createReq == nil {
+		return nil, http.StatusBadRequest, errors.New("request payload is nil")
+	}
+	if !roleNamePattern.MatchString(createReq.Role) {
+		return nil, http.StatusBadRequest, ErrInvalidRoleName
+	}
+
+	permissions, err := rolePermissions(createReq.Permissions)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	if _, status, err := s.findRole(createReq.Role); err == nil {
+		return nil, http.StatusConflict, ErrRoleExists
+	} else if status != http.StatusNotFound {
+		return nil, status, err
+	}
+
+	role := &entity.Role{
+		Role:        createReq.Role,
+		Description: createReq.Description,
+		Permissions: permissions,
+	}
+	if err := s.roleRepo.CreateRole(role); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return role, http.StatusCreated, nil
+}
+
+// UpdateRole replaces the description and the permissions of the role.
+func (s *service) UpdateRole(name string, updateReq *entity.RequestUpdateRole) (*entity.Role, int, error) {
+	if updateReq == nil {
+		return nil, http.StatusBadRequest, errors.New("request payload is nil")
+	}
+
+	role, status, err := s.findRole(name)
+	if err != nil {
+		return nil, status, err
+	}
+
+	permissions, err := rolePermissions(updateReq.Permissions)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	// The admin role could not grant the permission back once it is lost.
+	if role.Role == entity.RoleAdmin && !hasPermission(permissions, entity.PermissionRolesManage) {
+		return nil, http.StatusBadRequest, ErrAdminRolesManage
+	}
+
+	role.Permissions = permissions
+	if updateReq.Description != nil {
+		role.Description = *updateReq.Description
+	}
+	if err := s.roleRepo.UpdateRole(role); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return role, http.StatusOK, nil
+}
+
+// DeleteRole removes a role created by the admins.
+func (s *service) DeleteRole(name string) (int, error) {
+	if name == entity.RoleAdmin || name == entity.RoleUser {
+		return http.StatusBadRequest, ErrBuiltInRole
+	}
+
+	role, status, err := s.findRole(name)
+	if err != nil {
+		return status, err
+	}
+
+	if err := s.roleRepo.DeleteRole(role.ID); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// AssignRole grants the role to the user.
+func (s *service) AssignRole(name string, userUUID uuid.UUID) (int, error) {
+	role, user, status, err := s.findRoleAndUser(name, userUUID)
+	if err != nil {
+		return status, err
+	}
+
+	if err := s.roleRepo.AssignRole(user.ID, role.ID); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// UnassignRole revokes the role from the user.
+func (s *service) UnassignRole(name string, userUUID uuid.UUID) (int, error) {
+	role, user, status, err := s.findRoleAndUser(name, userUUID)
+	if err != nil {
+		return status, err
+	}
+
+	// No one could manage the roles once the last admin loses the role.
+	if role.Role == entity.RoleAdmin {
+		err = s.roleRepo.UnassignRoleKeepingHolder(user.ID, role.ID)
+	} else {
+		err = s.roleRepo.UnassignRole(user.ID, role.ID)
+	}
+	if errors.Is(err, ports.ErrLastRoleHolder) {
+		return http.StatusBadRequest, ErrLastAdmin
+	}
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// findRole retrieves the role with the given name.
+func (s *service) findRole(name string) (*entity.Role, int, error) {
+	role, err := s.roleRepo.FindRoleByName(name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, http.StatusNotFound, ErrRoleNotFound
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+	return role, http.StatusOK, nil
+}
+
+// findRoleAndUser retrieves the role with the given name and the user with the given UUID.
+func (s *service) findRoleAndUser(name string, userUUID uuid.UUID) (*entity.Role, *entity.User, int, error) {
+	role, status, err := s.findRole(name)
+	if err != nil {
+		return nil, nil, status, err
+	}
+
+	foundUser, err := s.userRepo.FindByUUID(userUUID, &entity.User{})
+	if err != nil {
+		return nil, nil, http.StatusNotFound, ErrUserNotFound
+	}
+
+	user, ok := foundUser.(*entity.User)
+	if !ok {
+		return nil, nil, http.StatusInternalServerError, ErrTypeAssertionFailed
+	}
+
+	return role, user, http.StatusOK, nil
+}
+
+// rolePermissions validates the permissions against the catalog, ignoring the duplicates.
+func rolePermissions(permissions []string) ([]entity.RolePermission, error) {
+	rolePermissions := make([]entity.RolePermission, 0, len(permissions))
+	for _, permission := range permissions {
+		if !entity.IsPermission(permission) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPermission, permission)
+		}
+		if !hasPermission(rolePermissions, permission) {
+			rolePermissions = append(rolePermissions, entity.RolePermission{Permission: permission})
+		}
+	}
+	return rolePermissions, nil
+}
+
+// hasPermission reports whether the permission is in the list.
+func hasPermission(permissions []entity.RolePermission, permission string) bool {
+	for _, p := range permissions {
+		if p.Permission == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,264 @@
+package role_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/emur-uy/backend/internal/pkg/service/role"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var testUserUUID = uuid.MustParse("24df3f36-ca63-11ed-afa1-0242ac120002")
+
+// mockRoleRepository is an in-memory implementation of the RoleRepository interface for testing.
+type mockRoleRepository struct {
+	roles       map[string]*entity.Role
+	assignments map[int]map[int]bool
+	nextID      int
+}
+
+func newMockRoleRepository() *mockRoleRepository {
+	m := &mockRoleRepository{roles: map[string]*entity.Role{}, assignments: map[int]map[int]bool{}}
+	_ = m.CreateRole(&entity.Role{Role: entity.RoleAdmin, Permissions: []entity.RolePermission{{Permission: entity.PermissionRolesManage}}})
+	_ = m.CreateRole(&entity.Role{Role: entity.RoleUser, Permissions: []entity.RolePermission{{Permission: entity.PermissionPatientSelf}}})
+	return m
+}
+
+func (m *mockRoleRepository) FindRoles() ([]entity.Role, error) {
+	var roles []entity.Role
+	for _, r := range m.roles {
+		roles = append(roles, *r)
+	}
+	return roles, nil
+}
+
+func (m *mockRoleRepository) FindRoleByName(name string) (*entity.Role, error) {
+	r, ok := m.roles[name]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	stored := *r
+	return &stored, nil
+}
+
+func (m *mockRoleRepository) CreateRole(r *entity.Role) error {
+	m.nextID++
+	r.ID = m.nextID
+	stored := *r
+	m.roles[r.Role] = &stored
+	return nil
+}
+
+func (m *mockRoleRepository) UpdateRole(r *entity.Role) error {
+	stored := *r
+	m.roles[r.Role] = &stored
+	return nil
+}
+
+func (m *mockRoleRepository) DeleteRole(roleID int) error {
+	for name, r := range m.roles {
+		if r.ID == roleID {
+			delete(m.roles, name)
+		}
+	}
+	for _, roles := range m.assignments {
+		delete(roles, roleID)
+	}
+	return nil
+}
+
+func (m *mockRoleRepository) AssignRole(userID int, roleID int) error {
+	if m.assignments[userID] == nil {
+		m.assignments[userID] = map[int]bool{}
+	}
+	m.assignments[userID][roleID] = true
+	return nil
+}
+
+func (m *mockRoleRepository) UnassignRole(userID int, roleID int) error {
+	delete(m.assignments[userID], roleID)
+	return nil
+}
+
+func (m *mockRoleRepository) UnassignRoleKeepingHolder(userID int, roleID int) error {
+	for id, roles := range m.assignments {
+		if id != userID && roles[roleID] {
+			delete(m.assignments[userID], roleID)
+			return nil
+		}
+	}
+	if m.assignments[userID][roleID] {
+		return ports.ErrLastRoleHolder
+	}
+	return nil
+}
+
+func (m *mockRoleRepository) GetUserRoleNames(userID int) ([]string, error) {
+	var names []string
+	for _, r := range m.roles {
+		if m.assignments[userID][r.ID] {
+			names = append(names, r.Role)
+		}
+	}
+	return names, nil
+}
+
+func (m *mockRoleRepository) GetUserPermissions(userUUID uuid.UUID) ([]string, error) {
+	return nil, nil
+}
+
+// mockUserRepository resolves the test user by UUID.
+type mockUserRepository struct {
+	ports.UserRepository
+}
+
+func (m *mockUserRepository) FindByUUID(userUUID uuid.UUID, out interface{}) (interface{}, error) {
+	if userUUID != testUserUUID {
+		return nil, errors.New("record not found")
+	}
+	user := out.(*entity.User)
+	user.ID = 7
+	user.UUID = userUUID
+	return user, nil
+}
+
+func newService() (ports.RoleService, *mockRoleRepository) {
+	roleRepo := newMockRoleRepository()
+	return role.NewService(roleRepo, &mockUserRepository{}), roleRepo
+}
+
+func TestCreateRole(t *testing.T) {
+	s, roleRepo := newService()
+
+	created, status, err := s.CreateRole(&entity.RequestCreateRole{
+		Role:        "editor",
+		Description: "Edita artículos",
+		Permissions: []string{entity.PermissionArticlesWrite, entity.PermissionArticlesRead, entity.PermissionArticlesWrite},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, status)
+	assert.Len(t, created.Permissions, 2)
+	assert.Contains(t, roleRepo.roles, "editor")
+
+	_, status, err = s.CreateRole(&entity.RequestCreateRole{Role: "editor"})
+	assert.ErrorIs(t, err, role.ErrRoleExists)
+	assert.Equal(t, http.StatusConflict, status)
+}
+
+func TestCreateRoleValidation(t *testing.T) {
+	s, _ := newService()
+
+	tests := []struct {
+		name    string
+		request *entity.RequestCreateRole
+		err     error
+	}{
+		{"invalid name", &entity.RequestCreateRole{Role: "Editor Jefe"}, role.ErrInvalidRoleName},
+		{"short name", &entity.RequestCreateRole{Role: "e"}, role.ErrInvalidRoleName},
+		{"unknown permission", &entity.RequestCreateRole{Role: "editor", Permissions: []string{"articles:publish"}}, role.ErrInvalidPermission},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, status, err := s.CreateRole(tt.request)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+	}
+}
+
+func TestUpdateRole(t *testing.T) {
+	s, roleRepo := newService()
+
+	description := "Paciente"
+	updated, status, err := s.UpdateRole(entity.RoleUser, &entity.RequestUpdateRole{
+		Description: &description,
+		Permissions: []string{entity.PermissionPatientSelf, entity.PermissionRecipesVote},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, description, updated.Description)
+	assert.Len(t, roleRepo.roles[entity.RoleUser].Permissions, 2)
+
+	_, status, err = s.UpdateRole("moderator", &entity.RequestUpdateRole{})
+	assert.ErrorIs(t, err, role.ErrRoleNotFound)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestUpdateAdminRoleKeepsRolesManage(t *testing.T) {
+	s, roleRepo := newService()
+
+	_, status, err := s.UpdateRole(entity.RoleAdmin, &entity.RequestUpdateRole{Permissions: []string{entity.PermissionUsersManage}})
+	assert.ErrorIs(t, err, role.ErrAdminRolesManage)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, entity.PermissionRolesManage, roleRepo.roles[entity.RoleAdmin].Permissions[0].Permission)
+}
+
+func TestDeleteRole(t *testing.T) {
+	s, roleRepo := newService()
+
+	_, _, err := s.CreateRole(&entity.RequestCreateRole{Role: "moderator"})
+	require.NoError(t, err)
+	_, err = s.AssignRole("moderator", testUserUUID)
+	require.NoError(t, err)
+
+	status, err := s.DeleteRole("moderator")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.NotContains(t, roleRepo.roles, "moderator")
+	assert.Empty(t, roleRepo.assignments[7])
+
+	for _, name := range []string{entity.RoleAdmin, entity.RoleUser} {
+		status, err = s.DeleteRole(name)
+		assert.ErrorIs(t, err, role.ErrBuiltInRole)
+		assert.Equal(t, http.StatusBadRequest, status)
+	}
+}
+
+func TestAssignRole(t *testing.T) {
+	s, roleRepo := newService()
+
+	status, err := s.AssignRole(entity.RoleAdmin, testUserUUID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	_, err = s.AssignRole(entity.RoleUser, testUserUUID)
+	require.NoError(t, err)
+
+	names, _ := roleRepo.GetUserRoleNames(7)
+	assert.ElementsMatch(t, []string{entity.RoleAdmin, entity.RoleUser}, names)
+
+	// Another admin is left once the test user loses the role.
+	require.NoError(t, roleRepo.AssignRole(8, roleRepo.roles[entity.RoleAdmin].ID))
+	status, err = s.UnassignRole(entity.RoleAdmin, testUserUUID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	names, _ = roleRepo.GetUserRoleNames(7)
+	assert.Equal(t, []string{entity.RoleUser}, names)
+
+	status, err = s.AssignRole(entity.RoleAdmin, uuid.New())
+	assert.ErrorIs(t, err, role.ErrUserNotFound)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, err = s.AssignRole("moderator", testUserUUID)
+	assert.ErrorIs(t, err, role.ErrRoleNotFound)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestUnassignLastAdmin(t *testing.T) {
+	s, roleRepo := newService()
+
+	_, err := s.AssignRole(entity.RoleAdmin, testUserUUID)
+	require.NoError(t, err)
+
+	status, err := s.UnassignRole(entity.RoleAdmin, testUserUUID)
+	assert.ErrorIs(t, err, role.ErrLastAdmin)
+	assert.Equal(t, http.StatusBadRequest, status)
+	names, _ := roleRepo.GetUserRoleNames(7)
+	assert.Equal(t, []string{entity.RoleAdmin}, names)
+}
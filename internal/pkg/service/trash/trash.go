@@ -0,0 +1,129 @@
+package trash
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownResource = errors.New("unknown resource, must be one of reminders, articles or recipes")
+	ErrItemNotFound    = errors.New("deleted record not found")
+	ErrRestoringItem   = errors.New("error restoring the deleted record")
+	ErrPurgingTrash    = errors.New("error purging the trash")
+)
+
+const (
+	// TrashRetention is the time the deleted records are kept in the trash before being purged.
+	TrashRetention = 30 * 24 * time.Hour
+	// DefaultPageSize is the number of records of a page when none is requested.
+	DefaultPageSize = 50
+	// MaxPageSize is the largest page that can be requested.
+	MaxPageSize = 200
+
+	// purgeBatchSize is the number of records purged in each transaction.
+	purgeBatchSize = 100
+)
+
+// service struct holds the necessary dependencies for the trash service
+type service struct {
+	repo  ports.TrashRepository
+	store ports.BlobStore
+}
+
+// NewService returns a new instance of the trash service with the given trash repository and blob store.
+func NewService(repo ports.TrashRepository, store ports.BlobStore) ports.TrashService {
+	return &service{
+		repo:  repo,
+		store: store,
+	}
+}
+
+// GetTrash returns a page of the deleted records of the resource, with the date they will be purged.
+func (s *service) GetTrash(resource string, request *entity.RequestTrash) (*entity.TrashResult, int, error) {
+	if !entity.IsTrashResource(resource) {
+		return nil, http.StatusBadRequest, ErrUnknownResource
+	}
+	if request == nil {
+		request = &entity.RequestTrash{}
+	}
+
+	limit := request.PageSize
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+	page := request.Page
+	if page < 1 {
+		page = 1
+	}
+
+	items, total, err := s.repo.FindDeleted(resource, limit, (page-1)*limit)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	for i := range items {
+		items[i].PurgeAt = items[i].DeletedAt.Add(TrashRetention)
+	}
+
+	return &entity.TrashResult{
+		Resource: resource,
+		Items:    items,
+		Page:     page,
+		PageSize: limit,
+		Total:    total,
+	}, http.StatusOK, nil
+}
+
+// RestoreItem restores the deleted record of the resource with the given UUID, with its media.
+func (s *service) RestoreItem(resource string, itemUUID uuid.UUID) (int, error) {
+	if !entity.IsTrashResource(resource) {
+		return http.StatusBadRequest, ErrUnknownResource
+	}
+
+	restored, err := s.repo.Restore(resource, itemUUID)
+	if err != nil {
+		return http.StatusInternalServerError, ErrRestoringItem
+	}
+	if !restored {
+		return http.StatusNotFound, ErrItemNotFound
+	}
+	return http.StatusOK, nil
+}
+
+// PurgeTrash hard-deletes the records deleted before the retention, with the records referencing them and
+// their media. The uploaded files are deleted from the bucket once the records are purged, the failures being logged.
+func (s *service) PurgeTrash(now time.Time) (int64, error) {
+	before := now.Add(-TrashRetention)
+
+	var total int64
+	for _, resource := range entity.TrashResources {
+		for {
+			purged, err := s.repo.PurgeDeleted(resource, before, purgeBatchSize)
+			if err != nil {
+				log.Printf("error while purging the deleted %s: %s", resource, err.Error())
+				return total, ErrPurgingTrash
+			}
+			total += purged.Purged
+
+			for _, mediaURL := range purged.MediaURLs {
+				if err := s.store.Delete(s.store.Key(mediaURL)); err != nil {
+					log.Printf("error while deleting the media %s: %s", mediaURL, err.Error())
+				}
+			}
+
+			if purged.Purged < purgeBatchSize {
+				break
+			}
+		}
+	}
+
+	return total, nil
+}
@@ -0,0 +1,164 @@
+package trash
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/emur-uy/backend/internal/infra/storage"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testNow      = time.Date(2023, 8, 2, 10, 0, 0, 0, time.UTC)
+	testItemUUID = uuid.MustParse("7b0e5c7a-2d4f-4c1e-9f3a-5e6b7c8d9e01")
+	errDatabase  = errors.New("database error")
+)
+
+// mockTrashRepository is an in-memory implementation of the TrashRepository interface for testing.
+type mockTrashRepository struct {
+	items     map[string][]entity.TrashItem
+	mediaURLs map[string][]string
+	restored  []uuid.UUID
+	purgeErr  error
+}
+
+func newMockTrashRepository() *mockTrashRepository {
+	return &mockTrashRepository{
+		items: map[string][]entity.TrashItem{
+			entity.TrashResourceReminders: {
+				{UUID: testItemUUID, Name: "Neurologist", DeletedAt: testNow.Add(-40 * 24 * time.Hour)},
+				{UUID: uuid.New(), Name: "Blood test", DeletedAt: testNow.Add(-time.Hour)},
+			},
+			entity.TrashResourceRecipes: {
+				{UUID: uuid.New(), Name: "Salad", DeletedAt: testNow.Add(-31 * 24 * time.Hour)},
+			},
+		},
+		mediaURLs: map[string][]string{
+			entity.TrashResourceReminders: {
+				"https://emur.nyc3.digitaloceanspaces.com/reminders/a.png",
+				"https://emur.nyc3.digitaloceanspaces.com/reminders/a_thumb.png",
+			},
+		},
+	}
+}
+
+func (m *mockTrashRepository) FindDeleted(resource string, limit, offset int) ([]entity.TrashItem, int64, error) {
+	items := m.items[resource]
+	total := int64(len(items))
+	if offset >= len(items) {
+		return nil, total, nil
+	}
+	items = items[offset:]
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return append([]entity.TrashItem{}, items...), total, nil
+}
+
+func (m *mockTrashRepository) Restore(resource string, itemUUID uuid.UUID) (bool, error) {
+	for i, item := range m.items[resource] {
+		if item.UUID == itemUUID {
+			m.items[resource] = append(m.items[resource][:i], m.items[resource][i+1:]...)
+			m.restored = append(m.restored, itemUUID)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockTrashRepository) PurgeDeleted(resource string, before time.Time, limit int) (*entity.PurgedTrash, error) {
+	if m.purgeErr != nil {
+		return nil, m.purgeErr
+	}
+	purged := &entity.PurgedTrash{Resource: resource}
+	var kept []entity.TrashItem
+	for _, item := range m.items[resource] {
+		if item.DeletedAt.Before(before) && purged.Purged < int64(limit) {
+			purged.Purged++
+			continue
+		}
+		kept = append(kept, item)
+	}
+	m.items[resource] = kept
+	if purged.Purged > 0 {
+		purged.MediaURLs = m.mediaURLs[resource]
+	}
+	return purged, nil
+}
+
+func TestGetTrash(t *testing.T) {
+	s := NewService(newMockTrashRepository(), storage.NewMemoryStore())
+
+	result, status, err := s.GetTrash(entity.TrashResourceReminders, &entity.RequestTrash{PageSize: 1})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, int64(2), result.Total)
+	assert.Equal(t, 1, result.Page)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, testItemUUID, result.Items[0].UUID)
+	assert.Equal(t, result.Items[0].DeletedAt.Add(TrashRetention), result.Items[0].PurgeAt)
+
+	result, _, err = s.GetTrash(entity.TrashResourceReminders, &entity.RequestTrash{Page: 2, PageSize: 1})
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, "Blood test", result.Items[0].Name)
+
+	result, _, err = s.GetTrash(entity.TrashResourceArticles, nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPageSize, result.PageSize)
+	assert.Empty(t, result.Items)
+
+	_, status, err = s.GetTrash("users", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.ErrorIs(t, err, ErrUnknownResource)
+}
+
+func TestRestoreItem(t *testing.T) {
+	repo := newMockTrashRepository()
+	s := NewService(repo, storage.NewMemoryStore())
+
+	status, err := s.RestoreItem(entity.TrashResourceReminders, testItemUUID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []uuid.UUID{testItemUUID}, repo.restored)
+
+	status, err = s.RestoreItem(entity.TrashResourceReminders, testItemUUID)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.ErrorIs(t, err, ErrItemNotFound)
+
+	status, err = s.RestoreItem("users", testItemUUID)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.ErrorIs(t, err, ErrUnknownResource)
+}
+
+func TestPurgeTrash(t *testing.T) {
+	repo := newMockTrashRepository()
+	store := storage.NewMemoryStore()
+	store.Errors["reminders/a_thumb.png"] = errors.New("access denied")
+	s := NewService(repo, store)
+
+	purged, err := s.PurgeTrash(testNow)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+	assert.Equal(t, []string{"reminders/a.png"}, store.Deleted)
+
+	// The records deleted within the retention are kept
+	require.Len(t, repo.items[entity.TrashResourceReminders], 1)
+	assert.Equal(t, "Blood test", repo.items[entity.TrashResourceReminders][0].Name)
+	assert.Empty(t, repo.items[entity.TrashResourceRecipes])
+}
+
+func TestPurgeTrashFailure(t *testing.T) {
+	repo := newMockTrashRepository()
+	repo.purgeErr = errDatabase
+	s := NewService(repo, storage.NewMemoryStore())
+
+	purged, err := s.PurgeTrash(testNow)
+	assert.ErrorIs(t, err, ErrPurgingTrash)
+	assert.Equal(t, int64(0), purged)
+}
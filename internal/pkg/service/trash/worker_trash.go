@@ -0,0 +1,30 @@
+package trash
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+type Worker struct {
+	service ports.TrashService
+}
+
+func NewWorker(service ports.TrashService) *Worker {
+	return &Worker{
+		service: service,
+	}
+}
+
+// PurgeTrash hard-deletes the records kept in the trash for longer than the retention.
+func (w *Worker) PurgeTrash() {
+	purged, err := w.service.PurgeTrash(time.Now().UTC())
+	if err != nil {
+		fmt.Println("Error purging the trash:", err)
+	}
+
+	if purged > 0 {
+		fmt.Printf("%d deleted records purged from the trash\n", purged)
+	}
+}
@@ -0,0 +1,307 @@
+package treatment
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/dates"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrFindingTreatment  = errors.New("error finding treatment")
+	ErrUnauthorizedDose  = errors.New("user is not authorized to access the treatment doses")
+	ErrInvalidDoseRange  = errors.New("invalid date range, 'to' must be after 'from' and span at most one year")
+	ErrInvalidDoseStatus = errors.New("invalid dose status, must be taken, late or skipped")
+	ErrDoseNotScheduled  = errors.New("the treatment has no dose scheduled at the given time")
+	ErrDoseInFuture      = errors.New("a dose cannot be recorded before its scheduled time")
+	ErrRetrievingDoses   = errors.New("error retrieving treatment doses")
+	ErrRecordingDose     = errors.New("error recording treatment dose")
+	ErrInvalidTimezone   = errors.New("the timezone of the user is invalid")
+)
+
+// maxDoseRange is the longest period a dose schedule can be expanded over.
+const maxDoseRange = 366 * 24 * time.Hour
+
+// timeNow returns the current time; tests replace it to get a fixed clock.
+var timeNow = time.Now
+
+var (
+	// recordableDoseStatuses are the statuses a patient can record for a dose.
+	recordableDoseStatuses = []string{entity.DoseStatusTaken, entity.DoseStatusLate, entity.DoseStatusSkipped}
+	// everyDayFrequencyLabels are the frequency days meaning the dose is taken every day.
+	everyDayFrequencyLabels = []string{"", "daily", "everyday", "every day", "diario", "todos los dias", "todos los días"}
+)
+
+// weekdays maps the day names accepted in a treatment frequency to their weekday.
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "domingo": time.Sunday,
+	"monday": time.Monday, "lunes": time.Monday,
+	"tuesday": time.Tuesday, "martes": time.Tuesday,
+	"wednesday": time.Wednesday, "miercoles": time.Wednesday, "miércoles": time.Wednesday,
+	"thursday": time.Thursday, "jueves": time.Thursday,
+	"friday": time.Friday, "viernes": time.Friday,
+	"saturday": time.Saturday, "sabado": time.Saturday, "sábado": time.Saturday,
+}
+
+// GetDoses returns the dose occurrences of a treatment between two dates with their recorded status.
+func (s *service) GetDoses(userUUID uuid.UUID, treatmentUUID uuid.UUID, from time.Time, to time.Time) ([]*entity.DoseOccurrence, int, error) {
+	if !to.After(from) || to.Sub(from) > maxDoseRange {
+		return nil, http.StatusBadRequest, ErrInvalidDoseRange
+	}
+
+	treatment, location, statusCode, err := s.findUserTreatment(userUUID, treatmentUUID)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	// Get the doses already recorded in the range
+	var doses []*entity.TreatmentDose
+	err = s.repo.Find(&doses, "treatment_id = ? AND scheduled_at >= ? AND scheduled_at <= ?", treatment.ID, from, to)
+	if err != nil {
+		return nil, http.StatusInternalServerError, ErrRetrievingDoses
+	}
+
+	return buildOccurrences(treatment, from, to, location, doses, timeNow()), http.StatusOK, nil
+}
+
+// RecordDose marks a scheduled dose as taken, late or skipped. Recording the same dose again replaces its status.
+func (s *service) RecordDose(userUUID uuid.UUID, treatmentUUID uuid.UUID, recordReq *entity.RequestRecordDose) (*entity.TreatmentDose, int, error) {
+	if recordReq == nil {
+		return nil, http.StatusBadRequest, errors.New("request payload is nil")
+	}
+
+	status := strings.ToLower(strings.TrimSpace(recordReq.Status))
+	if !contains(recordableDoseStatuses, status) {
+		return nil, http.StatusBadRequest, ErrInvalidDoseStatus
+	}
+
+	treatment, location, statusCode, err := s.findUserTreatment(userUUID, treatmentUUID)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	// The dose must be one of the occurrences generated by the treatment frequency
+	scheduledAt := recordReq.ScheduledAt.UTC()
+	if !isScheduled(treatment, scheduledAt, location) {
+		return nil, http.StatusBadRequest, ErrDoseNotScheduled
+	}
+	if status != entity.DoseStatusSkipped && scheduledAt.After(timeNow()) {
+		return nil, http.StatusBadRequest, ErrDoseInFuture
+	}
+
+	// Update the dose if it was already recorded, otherwise create it
+	dose := &entity.TreatmentDose{}
+	err = s.repo.First(dose, "treatment_id = ? AND scheduled_at = ?", treatment.ID, scheduledAt)
+	if err == nil {
+		dose.Status = status
+		dose.UpdatedAt = timeNow()
+		if err := s.repo.Update(dose); err != nil {
+			return nil, http.StatusInternalServerError, ErrRecordingDose
+		}
+		return dose, http.StatusOK, nil
+	}
+
+	dose = &entity.TreatmentDose{
+		TreatmentID: treatment.ID,
+		ScheduledAt: scheduledAt,
+		Status:      status,
+	}
+	if err := s.repo.CreateWithOmit("uuid", dose); err != nil {
+		return nil, http.StatusInternalServerError, ErrRecordingDose
+	}
+
+	return dose, http.StatusOK, nil
+}
+
+// GetAdherence returns the percentage of doses taken per week between two dates.
+// Doses scheduled in the future are not counted.
+func (s *service) GetAdherence(userUUID uuid.UUID, treatmentUUID uuid.UUID, from time.Time, to time.Time) (*entity.AdherenceReport, int, error) {
+	occurrences, statusCode, err := s.GetDoses(userUUID, treatmentUUID, from, to)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	report := &entity.AdherenceReport{
+		TreatmentUUID: treatmentUUID,
+		From:          from,
+		To:            to,
+		Weeks:         []*entity.WeeklyAdherence{},
+	}
+
+	total := &entity.WeeklyAdherence{}
+	weeks := map[time.Time]*entity.WeeklyAdherence{}
+	for _, occurrence := range occurrences {
+		if occurrence.Status == entity.DoseStatusPending {
+			continue
+		}
+
+		// The occurrences are in the user's timezone, so are their weeks
+		weekStart := dates.StartOfWeek(occurrence.ScheduledAt)
+		week, ok := weeks[weekStart]
+		if !ok {
+			week = &entity.WeeklyAdherence{WeekStart: weekStart}
+			weeks[weekStart] = week
+			report.Weeks = append(report.Weeks, week)
+		}
+
+		countDose(week, occurrence.Status)
+		countDose(total, occurrence.Status)
+	}
+
+	for _, week := range report.Weeks {
+		week.TakenPercentage = takenPercentage(week)
+	}
+	report.TakenPercentage = takenPercentage(total)
+
+	return report, http.StatusOK, nil
+}
+
+// findUserTreatment finds a treatment by UUID and checks that it belongs to the user.
+// It also returns the timezone of the user, in which the doses are scheduled.
+func (s *service) findUserTreatment(userUUID uuid.UUID, treatmentUUID uuid.UUID) (*entity.Treatment, *time.Location, int, error) {
+	foundUser, err := s.repo.FindByUUID(userUUID, &entity.User{})
+	if err != nil {
+		return nil, nil, http.StatusNotFound, ErrFindingUser
+	}
+	user, ok := foundUser.(*entity.User)
+	if !ok {
+		return nil, nil, http.StatusInternalServerError, ErrTypeAssertionFailed
+	}
+
+	foundTreatment, err := s.repo.FindByUUID(treatmentUUID, &entity.Treatment{})
+	if err != nil {
+		return nil, nil, http.StatusNotFound, ErrFindingTreatment
+	}
+	treatment, ok := foundTreatment.(*entity.Treatment)
+	if !ok {
+		return nil, nil, http.StatusInternalServerError, ErrTypeAssertionFailed
+	}
+
+	if treatment.UserID != user.ID {
+		return nil, nil, http.StatusForbidden, ErrUnauthorizedDose
+	}
+
+	location, err := dates.UserLocation(user)
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, ErrInvalidTimezone
+	}
+
+	return treatment, location, http.StatusOK, nil
+}
+
+// expandDoses returns every time a dose of the treatment is scheduled between from and to, in order.
+// The days and the times of the frequency are those of the given location, the timezone of the user.
+func expandDoses(treatment *entity.Treatment, from time.Time, to time.Time, location *time.Location) []time.Time {
+	var scheduled []time.Time
+
+	if treatment.DateStart.After(from) {
+		from = treatment.DateStart
+	}
+	from = from.In(location)
+
+	for day := dates.StartOfDay(from); !day.After(to); day = day.AddDate(0, 0, 1) {
+		for _, frequency := range treatment.Frequency {
+			if !matchesDay(frequency.Day, day.Weekday()) {
+				continue
+			}
+			for _, clock := range frequency.Time {
+				t, err := time.Parse("15:04", strings.TrimSpace(clock))
+				if err != nil {
+					continue
+				}
+				at := time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, location)
+				if at.Before(from) || at.After(to) {
+					continue
+				}
+				scheduled = append(scheduled, at)
+			}
+		}
+	}
+
+	sort.Slice(scheduled, func(i, j int) bool { return scheduled[i].Before(scheduled[j]) })
+	return scheduled
+}
+
+// buildOccurrences combines the expanded schedule with the recorded doses.
+func buildOccurrences(treatment *entity.Treatment, from time.Time, to time.Time, location *time.Location, doses []*entity.TreatmentDose, now time.Time) []*entity.DoseOccurrence {
+	recorded := map[int64]string{}
+	for _, dose := range doses {
+		recorded[dose.ScheduledAt.UTC().Unix()] = dose.Status
+	}
+
+	occurrences := []*entity.DoseOccurrence{}
+	for _, at := range expandDoses(treatment, from, to, location) {
+		status, ok := recorded[at.Unix()]
+		if !ok {
+			status = entity.DoseStatusPending
+			if at.Before(now) {
+				status = entity.DoseStatusMissed
+			}
+		}
+		occurrences = append(occurrences, &entity.DoseOccurrence{
+			ScheduledAt: at,
+			Status:      status,
+			Shots:       treatment.Shots,
+		})
+	}
+
+	return occurrences
+}
+
+// isScheduled reports whether the treatment has a dose at exactly the given time.
+func isScheduled(treatment *entity.Treatment, at time.Time, location *time.Location) bool {
+	day := dates.StartOfDay(at.In(location))
+	for _, scheduled := range expandDoses(treatment, day, day.AddDate(0, 0, 1).Add(-time.Second), location) {
+		if scheduled.Equal(at) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesDay reports whether a frequency day applies to the given weekday.
+func matchesDay(day string, weekday time.Weekday) bool {
+	d := strings.ToLower(strings.TrimSpace(day))
+	if contains(everyDayFrequencyLabels, d) {
+		return true
+	}
+	w, ok := weekdays[d]
+	return ok && w == weekday
+}
+
+// countDose adds a dose with the given status to the weekly counters.
+func countDose(week *entity.WeeklyAdherence, status string) {
+	week.Scheduled++
+	switch status {
+	case entity.DoseStatusTaken:
+		week.Taken++
+	case entity.DoseStatusLate:
+		week.Late++
+	case entity.DoseStatusSkipped:
+		week.Skipped++
+	default:
+		week.Missed++
+	}
+}
+
+// takenPercentage returns the share of scheduled doses that were taken, on time or late.
+func takenPercentage(week *entity.WeeklyAdherence) float64 {
+	if week.Scheduled == 0 {
+		return 0
+	}
+	return float64(week.Taken+week.Late) * 100 / float64(week.Scheduled)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
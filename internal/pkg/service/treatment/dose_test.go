@@ -0,0 +1,197 @@
+package treatment
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testOwnedTreatmentUuid = uuid.MustParse("0f5b8c1e-6a1f-4f0e-9d3a-6a7b8c9d0e1f")
+var testForeignTreatmentUuid = uuid.MustParse("7c2d1e0f-3b4a-4c5d-8e9f-0a1b2c3d4e5f")
+
+// mockDoseRepository is a treatment repository that keeps the recorded doses in memory.
+type mockDoseRepository struct {
+	mockTreatmentRepository
+	doses []*entity.TreatmentDose
+}
+
+func (m *mockDoseRepository) FindByUUID(id uuid.UUID, out interface{}) (interface{}, error) {
+	switch id {
+	case testUserUuid:
+		return &entity.User{ID: 1, UUID: testUserUuid}, nil
+	case testOwnedTreatmentUuid:
+		return &entity.Treatment{
+			ID:        1,
+			UserID:    1,
+			UUID:      testOwnedTreatmentUuid,
+			DateStart: time.Date(2023, 7, 3, 0, 0, 0, 0, time.UTC),
+			Frequency: entity.FrequencySlice{
+				{Day: "lunes", Time: []string{"08:00", "20:00"}},
+				{Day: "thursday", Time: []string{"09:30"}},
+			},
+		}, nil
+	case testForeignTreatmentUuid:
+		return &entity.Treatment{ID: 2, UserID: 2, UUID: testForeignTreatmentUuid}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockDoseRepository) Find(out interface{}, conditions ...interface{}) error {
+	*out.(*[]*entity.TreatmentDose) = m.doses
+	return nil
+}
+
+func (m *mockDoseRepository) First(out interface{}, conditions ...interface{}) error {
+	for _, dose := range m.doses {
+		if dose.ScheduledAt.Equal(conditions[2].(time.Time)) {
+			*out.(*entity.TreatmentDose) = *dose
+			return nil
+		}
+	}
+	return errors.New("record not found")
+}
+
+func (m *mockDoseRepository) CreateWithOmit(omit string, value interface{}) error {
+	m.doses = append(m.doses, value.(*entity.TreatmentDose))
+	return nil
+}
+
+func (m *mockDoseRepository) Update(value interface{}) error {
+	dose := value.(*entity.TreatmentDose)
+	for i, d := range m.doses {
+		if d.ScheduledAt.Equal(dose.ScheduledAt) {
+			m.doses[i] = dose
+		}
+	}
+	return nil
+}
+
+func withFixedNow(t *testing.T, now time.Time) {
+	previous := timeNow
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = previous })
+}
+
+func TestExpandDoses(t *testing.T) {
+	repo := &mockDoseRepository{}
+	found, _ := repo.FindByUUID(testOwnedTreatmentUuid, nil)
+	treatment := found.(*entity.Treatment)
+
+	// Monday 26/06 is before the treatment starts, so only the week of 03/07 counts.
+	from := time.Date(2023, 6, 26, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 7, 9, 23, 59, 59, 0, time.UTC)
+
+	doses := expandDoses(treatment, from, to, time.UTC)
+	require.Len(t, doses, 3)
+	assert.Equal(t, time.Date(2023, 7, 3, 8, 0, 0, 0, time.UTC), doses[0])
+	assert.Equal(t, time.Date(2023, 7, 3, 20, 0, 0, 0, time.UTC), doses[1])
+	assert.Equal(t, time.Date(2023, 7, 6, 9, 30, 0, 0, time.UTC), doses[2])
+}
+
+func TestExpandDosesInUserTimezone(t *testing.T) {
+	repo := &mockDoseRepository{}
+	found, _ := repo.FindByUUID(testOwnedTreatmentUuid, nil)
+	treatment := found.(*entity.Treatment)
+
+	montevideo, err := time.LoadLocation("America/Montevideo")
+	require.NoError(t, err)
+
+	// The doses are taken at the clock times of Montevideo (UTC-3), the Monday dose at 20:00
+	// falls on Tuesday in UTC and the range ending on Monday 21:00 UTC leaves it out.
+	from := time.Date(2023, 7, 3, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 7, 3, 21, 0, 0, 0, time.UTC)
+	doses := expandDoses(treatment, from, to, montevideo)
+	require.Len(t, doses, 1)
+	assert.True(t, time.Date(2023, 7, 3, 11, 0, 0, 0, time.UTC).Equal(doses[0]))
+
+	to = time.Date(2023, 7, 9, 23, 59, 59, 0, time.UTC)
+	doses = expandDoses(treatment, from, to, montevideo)
+	require.Len(t, doses, 3)
+	assert.True(t, time.Date(2023, 7, 3, 23, 0, 0, 0, time.UTC).Equal(doses[1]))
+	assert.True(t, time.Date(2023, 7, 6, 12, 30, 0, 0, time.UTC).Equal(doses[2]))
+
+	assert.True(t, isScheduled(treatment, time.Date(2023, 7, 3, 23, 0, 0, 0, time.UTC), montevideo))
+	assert.False(t, isScheduled(treatment, time.Date(2023, 7, 3, 20, 0, 0, 0, time.UTC), montevideo))
+}
+
+func TestRecordDose(t *testing.T) {
+	withFixedNow(t, time.Date(2023, 7, 4, 12, 0, 0, 0, time.UTC))
+	repo := &mockDoseRepository{}
+	svc := NewService(repo)
+
+	testCases := []struct {
+		name          string
+		treatmentUUID uuid.UUID
+		request       *entity.RequestRecordDose
+		expectedCode  int
+	}{
+		{"dose recorded", testOwnedTreatmentUuid, &entity.RequestRecordDose{ScheduledAt: time.Date(2023, 7, 3, 8, 0, 0, 0, time.UTC), Status: "taken"}, http.StatusOK},
+		{"dose recorded again", testOwnedTreatmentUuid, &entity.RequestRecordDose{ScheduledAt: time.Date(2023, 7, 3, 8, 0, 0, 0, time.UTC), Status: "late"}, http.StatusOK},
+		{"future dose skipped", testOwnedTreatmentUuid, &entity.RequestRecordDose{ScheduledAt: time.Date(2023, 7, 6, 9, 30, 0, 0, time.UTC), Status: "skipped"}, http.StatusOK},
+		{"future dose taken", testOwnedTreatmentUuid, &entity.RequestRecordDose{ScheduledAt: time.Date(2023, 7, 6, 9, 30, 0, 0, time.UTC), Status: "taken"}, http.StatusBadRequest},
+		{"dose not scheduled", testOwnedTreatmentUuid, &entity.RequestRecordDose{ScheduledAt: time.Date(2023, 7, 3, 9, 0, 0, 0, time.UTC), Status: "taken"}, http.StatusBadRequest},
+		{"invalid status", testOwnedTreatmentUuid, &entity.RequestRecordDose{ScheduledAt: time.Date(2023, 7, 3, 8, 0, 0, 0, time.UTC), Status: "forgotten"}, http.StatusBadRequest},
+		{"treatment of another user", testForeignTreatmentUuid, &entity.RequestRecordDose{ScheduledAt: time.Date(2023, 7, 3, 8, 0, 0, 0, time.UTC), Status: "taken"}, http.StatusForbidden},
+		{"treatment doesn't exist", uuid.New(), &entity.RequestRecordDose{ScheduledAt: time.Date(2023, 7, 3, 8, 0, 0, 0, time.UTC), Status: "taken"}, http.StatusNotFound},
+		{"nil payload", testOwnedTreatmentUuid, nil, http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dose, statusCode, err := svc.RecordDose(testUserUuid, tc.treatmentUUID, tc.request)
+			assert.Equal(t, tc.expectedCode, statusCode)
+			if tc.expectedCode == http.StatusOK {
+				require.NoError(t, err)
+				assert.Equal(t, tc.request.Status, dose.Status)
+			} else {
+				require.Error(t, err)
+				assert.Nil(t, dose)
+			}
+		})
+	}
+
+	// The same dose recorded twice is stored once
+	assert.Len(t, repo.doses, 2)
+}
+
+func TestGetAdherence(t *testing.T) {
+	withFixedNow(t, time.Date(2023, 7, 11, 12, 0, 0, 0, time.UTC))
+	repo := &mockDoseRepository{
+		doses: []*entity.TreatmentDose{
+			{ScheduledAt: time.Date(2023, 7, 3, 8, 0, 0, 0, time.UTC), Status: entity.DoseStatusTaken},
+			{ScheduledAt: time.Date(2023, 7, 3, 20, 0, 0, 0, time.UTC), Status: entity.DoseStatusLate},
+			{ScheduledAt: time.Date(2023, 7, 6, 9, 30, 0, 0, time.UTC), Status: entity.DoseStatusSkipped},
+			{ScheduledAt: time.Date(2023, 7, 10, 8, 0, 0, 0, time.UTC), Status: entity.DoseStatusTaken},
+		},
+	}
+	svc := NewService(repo)
+
+	from := time.Date(2023, 7, 3, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 7, 16, 23, 59, 59, 0, time.UTC)
+
+	// Test case 1: two weeks, the last one partly in the future
+	report, statusCode, err := svc.GetAdherence(testUserUuid, testOwnedTreatmentUuid, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, report.Weeks, 2)
+
+	assert.Equal(t, 3, report.Weeks[0].Scheduled)
+	assert.InDelta(t, 66.66, report.Weeks[0].TakenPercentage, 0.01)
+
+	// 10/07 08:00 taken, 10/07 20:00 missed, 13/07 is still pending
+	assert.Equal(t, 2, report.Weeks[1].Scheduled)
+	assert.Equal(t, 1, report.Weeks[1].Missed)
+	assert.InDelta(t, 50, report.Weeks[1].TakenPercentage, 0.01)
+	assert.InDelta(t, 60, report.TakenPercentage, 0.01)
+
+	// Test case 2: invalid range
+	_, statusCode, err = svc.GetAdherence(testUserUuid, testOwnedTreatmentUuid, to, from)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
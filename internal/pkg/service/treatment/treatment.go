@@ -7,6 +7,7 @@ import (
 
 	"github.com/emur-uy/backend/internal/pkg/entity"
 	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/emur-uy/backend/internal/pkg/service/audit"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -66,6 +67,7 @@ func (s *service) CreateTreatment(c *gin.Context, userUUID uuid.UUID, createReq
 	if err != nil {
 		return nil, http.StatusInternalServerError, fmt.Errorf("error creating treatment: %s", err)
 	}
+	audit.SetResource(c, treatment.UUID)
 
 	return treatment, http.StatusOK, nil
 }
@@ -0,0 +1,222 @@
+package user
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+)
+
+var (
+	ErrInvalidActionToken = errors.New("invalid or expired token")
+)
+
+// Lifetimes of the tokens sent by email.
+const (
+	EmailVerificationTokenTTL = 48 * time.Hour
+	PasswordResetTokenTTL     = time.Hour
+)
+
+// VerifyEmail marks the email of the owner of the verification token as verified.
+func (s *service) VerifyEmail(token string) (int, error) {
+	actionToken, status, err := s.useActionToken(token, entity.TokenPurposeEmailVerification)
+	if err != nil {
+		return status, err
+	}
+
+	user := &entity.User{ID: actionToken.UserID}
+	if err := s.repo.UpdateColumns(user, "email_verified_at", timeNow()); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// ResendVerificationEmail sends a new verification email to the user with the given email if it is not verified yet.
+// Unknown emails are not reported, so the endpoint cannot be used to find out who is registered.
+func (s *service) ResendVerificationEmail(email string) (int, error) {
+	user := &entity.User{}
+	if err := s.repo.First(user, "email = ?", email); err != nil {
+		log.Printf("verification email requested for an unknown email: %s", err.Error())
+		return http.StatusOK, nil
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return http.StatusOK, nil
+	}
+
+	// A failure is logged only, an error would reveal the email is registered
+	if err := s.sendVerificationEmail(user); err != nil {
+		log.Printf("error while sending the verification email: %s", err.Error())
+	}
+
+	return http.StatusOK, nil
+}
+
+// ForgotPassword sends a password reset email to the user with the given email.
+// Unknown emails and the failures are not reported, so the endpoint cannot be used to find out who is registered.
+func (s *service) ForgotPassword(email string) (int, error) {
+	user := &entity.User{}
+	if err := s.repo.First(user, "email = ?", email); err != nil {
+		log.Printf("password reset requested for an unknown email: %s", err.Error())
+		return http.StatusOK, nil
+	}
+
+	// The failures are logged only, an error would reveal the email is registered
+	token, err := s.createActionToken(user, entity.TokenPurposePasswordReset, PasswordResetTokenTTL)
+	if err != nil {
+		log.Printf("error while creating the password reset token: %s", err.Error())
+		return http.StatusOK, nil
+	}
+
+	body := "Recibimos un pedido para restablecer tu contraseña.\n\n"
+	if resetURL := config.Get().PasswordResetURL; resetURL != "" {
+		body += "Para elegir una nueva contraseña ingresá a: " + withToken(resetURL, token) + "\n\n"
+	} else {
+		body += "Tu código para restablecer la contraseña es: " + token + "\n\n"
+	}
+	body += fmt.Sprintf("El enlace vence en %d minutos. Si no lo pediste, ignorá este correo.", int(PasswordResetTokenTTL.Minutes()))
+
+	if err := s.mailer.Send(&entity.Email{To: user.Email, Subject: "Restablecer contraseña", Body: body}); err != nil {
+		log.Printf("error while sending the password reset email: %s", err.Error())
+	}
+
+	return http.StatusOK, nil
+}
+
+// ResetPassword sets the new password of the owner of the password reset token and ends all the user's sessions.
+// Following the reset link proves the ownership of the email, so it is also marked as verified.
+func (s *service) ResetPassword(request *entity.RequestResetPassword) (int, error) {
+	actionToken, status, err := s.useActionToken(request.Token, entity.TokenPurposePasswordReset)
+	if err != nil {
+		return status, err
+	}
+
+	encryptedPass, err := encryptPassword(request.Password)
+	if err != nil {
+		log.Printf("error while encrypting the password: %s", err.Error())
+		return http.StatusInternalServerError, err
+	}
+
+	user := &entity.User{}
+	if err := s.repo.First(user, "id = ?", actionToken.UserID); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	// The access tokens already issued are rejected from now on, a stolen session ends with the reset
+	now := timeNow()
+	user.Password = encryptedPass
+	user.TokensValidAfter = &now
+	if user.EmailVerifiedAt == nil {
+		user.EmailVerifiedAt = &now
+	}
+	if err := s.repo.Update(user); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if err := s.tokenRepo.RevokeUserRefreshTokens(user.ID, now); err != nil {
+		log.Printf("error while revoking the sessions after a password reset: %s", err.Error())
+	}
+
+	return http.StatusOK, nil
+}
+
+// sendVerificationEmail sends the email with the verification link to a new user.
+func (s *service) sendVerificationEmail(user *entity.User) error {
+	token, err := s.createActionToken(user, entity.TokenPurposeEmailVerification, EmailVerificationTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	link := withToken(strings.TrimSuffix(config.Get().AppURL, "/")+"/api/v1/users/verify", token)
+	body := "¡Bienvenido/a a EMUR!\n\n" +
+		"Para verificar tu correo ingresá a: " + link + "\n\n" +
+		fmt.Sprintf("El enlace vence en %d horas.", int(EmailVerificationTokenTTL.Hours()))
+
+	return s.mailer.Send(&entity.Email{To: user.Email, Subject: "Verificá tu correo", Body: body})
+}
+
+// createActionToken generates a signed token for the given purpose and stores its hash.
+func (s *service) createActionToken(user *entity.User, purpose string, ttl time.Duration) (string, error) {
+	random, err := generateRefreshToken()
+	if err != nil {
+		return "", err
+	}
+	token := random + "." + signActionToken(purpose, random)
+
+	actionToken := &entity.UserActionToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		ExpiresAt: timeNow().Add(ttl),
+	}
+	if err := s.tokenRepo.Create(actionToken); err != nil {
+		log.Printf("error while storing the %s token: %s", purpose, err.Error())
+		return "", ErrIssuingTokens
+	}
+
+	return token, nil
+}
+
+// useActionToken checks the signature, purpose and expiration of the token and marks it as used.
+func (s *service) useActionToken(token, purpose string) (*entity.UserActionToken, int, error) {
+	random, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(signActionToken(purpose, random))) {
+		return nil, http.StatusBadRequest, ErrInvalidActionToken
+	}
+
+	actionToken := &entity.UserActionToken{}
+	if err := s.tokenRepo.First(actionToken, "token_hash = ?", hashToken(token)); err != nil {
+		return nil, http.StatusBadRequest, ErrInvalidActionToken
+	}
+
+	now := timeNow()
+	if actionToken.Purpose != purpose || actionToken.UsedAt != nil || !now.Before(actionToken.ExpiresAt) {
+		return nil, http.StatusBadRequest, ErrInvalidActionToken
+	}
+
+	used, err := s.tokenRepo.UseActionToken(actionToken.ID, now)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if !used {
+		return nil, http.StatusBadRequest, ErrInvalidActionToken
+	}
+
+	return actionToken, http.StatusOK, nil
+}
+
+// signActionToken returns the HMAC of the random part of a token bound to its purpose,
+// so a token cannot be forged nor used for another purpose.
+func signActionToken(purpose, random string) string {
+	mac := hmac.New(sha256.New, []byte(config.Get().SecretKey))
+	mac.Write([]byte(purpose + "." + random))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// withToken appends the token as a query parameter of the link.
+func withToken(link, token string) string {
+	separator := "?"
+	if strings.Contains(link, "?") {
+		separator = "&"
+	}
+	return link + separator + "token=" + url.QueryEscape(token)
+}
+
+// checkEmailVerified returns ports.ErrEmailNotVerified when the verification is required and the user did not verify the email.
+func checkEmailVerified(user *entity.User) error {
+	if config.Get().RequireEmailVerification && user.EmailVerifiedAt == nil {
+		return ports.ErrEmailNotVerified
+	}
+	return nil
+}
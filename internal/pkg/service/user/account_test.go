@@ -0,0 +1,155 @@
+package user_test
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/emur-uy/backend/internal/infra/mailer"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/service/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAccountUserRepository records the updates of the users.
+type mockAccountUserRepository struct {
+	mockSessionUserRepository
+	updated       *entity.User
+	updatedColumn string
+}
+
+func (m *mockAccountUserRepository) Update(value interface{}) error {
+	m.updated = value.(*entity.User)
+	return nil
+}
+
+func (m *mockAccountUserRepository) UpdateColumns(value interface{}, column string, updateValue interface{}) error {
+	m.updatedColumn = column
+	return nil
+}
+
+var emailTokenPattern = regexp.MustCompile(`[A-Za-z0-9_-]{20,}\.[A-Za-z0-9_-]{20,}`)
+
+// emailToken returns the token included in the last email sent.
+func emailToken(t *testing.T, memoryMailer *mailer.MemoryMailer) string {
+	email := memoryMailer.Last()
+	require.NotNil(t, email)
+	token := emailTokenPattern.FindString(email.Body)
+	require.NotEmpty(t, token)
+	return token
+}
+
+func TestForgotAndResetPassword(t *testing.T) {
+	userRepo := &mockAccountUserRepository{}
+	tokenRepo := newMockTokenRepository()
+	memoryMailer := mailer.NewMemoryMailer()
+	s := user.NewService(userRepo, tokenRepo, newMockLoginAttemptRepository(), newMockRoleRepository(), memoryMailer)
+
+	tokens, _, err := s.Login(&entity.DefaultCredentials{Email: testEmail, Password: "password"}, testIP)
+	require.NoError(t, err)
+
+	status, err := s.ForgotPassword(testEmail)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, testEmail, memoryMailer.Last().To)
+	token := emailToken(t, memoryMailer)
+
+	status, err = s.ResetPassword(&entity.RequestResetPassword{Token: token, Password: "new password"})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	require.NotNil(t, userRepo.updated)
+	assert.NotEqual(t, testPassword, userRepo.updated.Password)
+	assert.NotNil(t, userRepo.updated.EmailVerifiedAt)
+	require.NotNil(t, userRepo.updated.TokensValidAfter)
+	assert.False(t, userRepo.updated.TokensValidAfter.Before(*userRepo.updated.EmailVerifiedAt))
+
+	// The sessions started before the reset are revoked
+	_, _, err = s.RefreshToken(tokens.RefreshToken)
+	assert.Error(t, err)
+
+	// The token can only be used once
+	_, err = s.ResetPassword(&entity.RequestResetPassword{Token: token, Password: "other password"})
+	assert.ErrorIs(t, err, user.ErrInvalidActionToken)
+}
+
+func TestForgotPasswordUnknownEmail(t *testing.T) {
+	memoryMailer := mailer.NewMemoryMailer()
+	s := user.NewService(&mockAccountUserRepository{}, newMockTokenRepository(), newMockLoginAttemptRepository(), newMockRoleRepository(), memoryMailer)
+
+	status, err := s.ForgotPassword("unknown@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Nil(t, memoryMailer.Last())
+}
+
+func TestForgotPasswordMailerFailure(t *testing.T) {
+	memoryMailer := mailer.NewMemoryMailer()
+	memoryMailer.Err = errors.New("connection refused")
+	s := user.NewService(&mockAccountUserRepository{}, newMockTokenRepository(), newMockLoginAttemptRepository(), newMockRoleRepository(), memoryMailer)
+
+	// The response is the one of an unknown email
+	status, err := s.ForgotPassword(testEmail)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestResetPasswordInvalidToken(t *testing.T) {
+	tokenRepo := newMockTokenRepository()
+	memoryMailer := mailer.NewMemoryMailer()
+	s := user.NewService(&mockAccountUserRepository{}, tokenRepo, newMockLoginAttemptRepository(), newMockRoleRepository(), memoryMailer)
+
+	_, err := s.ForgotPassword(testEmail)
+	require.NoError(t, err)
+	token := emailToken(t, memoryMailer)
+
+	testCases := []struct {
+		name  string
+		token string
+	}{
+		{"unsigned token", "token"},
+		{"tampered signature", token + "x"},
+		{"unknown token", "a" + token},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, err := s.ResetPassword(&entity.RequestResetPassword{Token: tc.token, Password: "new password"})
+			assert.ErrorIs(t, err, user.ErrInvalidActionToken)
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+	}
+
+	t.Run("expired token", func(t *testing.T) {
+		tokenRepo.actionTokens[0].ExpiresAt = time.Now().Add(-time.Minute)
+		_, err := s.ResetPassword(&entity.RequestResetPassword{Token: token, Password: "new password"})
+		assert.ErrorIs(t, err, user.ErrInvalidActionToken)
+	})
+}
+
+func TestVerifyEmail(t *testing.T) {
+	userRepo := &mockAccountUserRepository{}
+	memoryMailer := mailer.NewMemoryMailer()
+	s := user.NewService(userRepo, newMockTokenRepository(), newMockLoginAttemptRepository(), newMockRoleRepository(), memoryMailer)
+
+	// A password reset token cannot verify the email
+	_, err := s.ForgotPassword(testEmail)
+	require.NoError(t, err)
+	_, err = s.VerifyEmail(emailToken(t, memoryMailer))
+	assert.ErrorIs(t, err, user.ErrInvalidActionToken)
+
+	_, err = s.ResendVerificationEmail(testEmail)
+	require.NoError(t, err)
+	token := emailToken(t, memoryMailer)
+	assert.Equal(t, "Verificá tu correo", memoryMailer.Last().Subject)
+
+	status, err := s.VerifyEmail(token)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "email_verified_at", userRepo.updatedColumn)
+
+	_, err = s.VerifyEmail(token)
+	assert.ErrorIs(t, err, user.ErrInvalidActionToken)
+}
@@ -0,0 +1,85 @@
+package user
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/fieldcrypt"
+)
+
+// DefaultReencryptionBatchSize is the number of users re-encrypted per batch when none is given.
+const DefaultReencryptionBatchSize = 100
+
+// ReencryptUsers re-encrypts with the primary key of the keyring the first name, last name and profile image
+// of every user written with another key or with the legacy cipher. In a dry run nothing is written.
+// Each update is conditioned on the ciphertexts read, so a user updated meanwhile is skipped instead of overwritten.
+func (s *service) ReencryptUsers(keyring *fieldcrypt.Keyring, batchSize int, dryRun bool) (*entity.ReencryptionReport, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultReencryptionBatchSize
+	}
+
+	report := &entity.ReencryptionReport{}
+	afterID := 0
+	for {
+		users, err := s.repo.FindUsersAfterID(afterID, batchSize)
+		if err != nil {
+			return report, fmt.Errorf("error reading the users after %d: %s", afterID, err)
+		}
+		if len(users) == 0 {
+			return report, nil
+		}
+
+		for i := range users {
+			s.reencryptUser(keyring, &users[i], dryRun, report)
+		}
+		afterID = users[len(users)-1].ID
+	}
+}
+
+// reencryptUser re-encrypts the fields of the user that need it, adding the outcome to the report.
+func (s *service) reencryptUser(keyring *fieldcrypt.Keyring, user *entity.User, dryRun bool, report *entity.ReencryptionReport) {
+	report.Scanned++
+
+	fields := map[string]string{
+		"first_name":    user.FirstName,
+		"last_name":     user.LastName,
+		"profile_image": user.ProfileImage,
+	}
+
+	conditions := map[string]interface{}{"id": user.ID}
+	columns := map[string]interface{}{}
+	for column, ciphertext := range fields {
+		if !keyring.NeedsReencryption(ciphertext) {
+			continue
+		}
+		reencrypted, err := keyring.Reencrypt(fieldcrypt.UserField(column, user.UUID), ciphertext)
+		if err != nil {
+			log.Printf("error while re-encrypting the %s of the user %d: %s", column, user.ID, err.Error())
+			report.Failed++
+			return
+		}
+		conditions[column] = ciphertext
+		columns[column] = reencrypted
+	}
+
+	if len(columns) == 0 {
+		return
+	}
+	if dryRun {
+		report.Reencrypted++
+		return
+	}
+
+	updated, err := s.repo.UpdateColumnsWhere(&entity.User{}, conditions, columns)
+	if err != nil {
+		log.Printf("error while updating the user %d: %s", user.ID, err.Error())
+		report.Failed++
+		return
+	}
+	if updated == 0 {
+		report.Skipped++
+		return
+	}
+	report.Reencrypted++
+}
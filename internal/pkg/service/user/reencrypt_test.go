@@ -0,0 +1,137 @@
+package user_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/emur-uy/backend/internal/infra/mailer"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/fieldcrypt"
+	"github.com/emur-uy/backend/internal/pkg/service/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockReencryptionUserRepository keeps the users in memory, applying the conditional updates.
+type mockReencryptionUserRepository struct {
+	MockUserRepository
+	users []entity.User
+	// concurrentUpdate, when set, is applied to the users right after they are read.
+	concurrentUpdate func(users []entity.User)
+}
+
+func (m *mockReencryptionUserRepository) FindUsersAfterID(afterID, limit int) ([]entity.User, error) {
+	var users []entity.User
+	for _, u := range m.users {
+		if u.ID > afterID && len(users) < limit {
+			users = append(users, u)
+		}
+	}
+	if m.concurrentUpdate != nil {
+		m.concurrentUpdate(m.users)
+	}
+	return users, nil
+}
+
+func (m *mockReencryptionUserRepository) UpdateColumnsWhere(model interface{}, conditions map[string]interface{}, columns map[string]interface{}) (int64, error) {
+	for i := range m.users {
+		u := &m.users[i]
+		current := map[string]interface{}{"id": u.ID, "first_name": u.FirstName, "last_name": u.LastName, "profile_image": u.ProfileImage}
+		matches := true
+		for column, value := range conditions {
+			if current[column] != value {
+				matches = false
+			}
+		}
+		if !matches {
+			continue
+		}
+		for column, value := range columns {
+			switch column {
+			case "first_name":
+				u.FirstName = value.(string)
+			case "last_name":
+				u.LastName = value.(string)
+			case "profile_image":
+				u.ProfileImage = value.(string)
+			}
+		}
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func encryptedUser(t *testing.T, keyring *fieldcrypt.Keyring, id int, name string) entity.User {
+	userUUID := uuid.New()
+	firstName, err := keyring.Encrypt(fieldcrypt.UserField("first_name", userUUID), name)
+	require.NoError(t, err)
+	lastName, err := keyring.Encrypt(fieldcrypt.UserField("last_name", userUUID), "Pérez")
+	require.NoError(t, err)
+	profileImage, err := keyring.Encrypt(fieldcrypt.UserField("profile_image", userUUID), "")
+	require.NoError(t, err)
+	return entity.User{ID: id, UUID: userUUID, FirstName: firstName, LastName: lastName, ProfileImage: profileImage}
+}
+
+func TestReencryptUsers(t *testing.T) {
+	oldKeyring, err := fieldcrypt.NewKeyring("k1", map[string][]byte{"k1": []byte("0123456789abcdef0123456789abcdef")})
+	require.NoError(t, err)
+	keyring, err := fieldcrypt.NewKeyring("k2", map[string][]byte{
+		"k1": []byte("0123456789abcdef0123456789abcdef"),
+		"k2": []byte("fedcba9876543210fedcba9876543210"),
+	})
+	require.NoError(t, err)
+
+	repo := &mockReencryptionUserRepository{users: []entity.User{
+		encryptedUser(t, oldKeyring, 1, "Ana"),
+		encryptedUser(t, keyring, 2, "Juan"),
+		encryptedUser(t, oldKeyring, 3, "Lucía"),
+		{ID: 4, FirstName: "v1:k9:broken", LastName: "v1:k9:broken", ProfileImage: "v1:k9:broken"},
+	}}
+	s := user.NewService(repo, newMockTokenRepository(), newMockLoginAttemptRepository(), newMockRoleRepository(), mailer.NewMemoryMailer())
+
+	report, err := s.ReencryptUsers(keyring, 2, true)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.ReencryptionReport{Scanned: 4, Reencrypted: 2, Failed: 1}, report)
+	assert.True(t, strings.HasPrefix(repo.users[0].FirstName, "v1:k1:"), "a dry run writes nothing")
+
+	report, err = s.ReencryptUsers(keyring, 2, false)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.ReencryptionReport{Scanned: 4, Reencrypted: 2, Failed: 1}, report)
+
+	for _, u := range repo.users[:3] {
+		assert.False(t, keyring.NeedsReencryption(u.FirstName))
+		assert.False(t, keyring.NeedsReencryption(u.LastName))
+		assert.False(t, keyring.NeedsReencryption(u.ProfileImage))
+	}
+	firstName, err := keyring.Decrypt(fieldcrypt.UserField("first_name", repo.users[2].UUID), repo.users[2].FirstName)
+	require.NoError(t, err)
+	assert.Equal(t, "Lucía", firstName)
+
+	report, err = s.ReencryptUsers(keyring, 2, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Reencrypted)
+}
+
+func TestReencryptUsersSkipsConcurrentUpdates(t *testing.T) {
+	oldKeyring, err := fieldcrypt.NewKeyring("k1", map[string][]byte{"k1": []byte("0123456789abcdef0123456789abcdef")})
+	require.NoError(t, err)
+	keyring, err := fieldcrypt.NewKeyring("k2", map[string][]byte{
+		"k1": []byte("0123456789abcdef0123456789abcdef"),
+		"k2": []byte("fedcba9876543210fedcba9876543210"),
+	})
+	require.NoError(t, err)
+
+	repo := &mockReencryptionUserRepository{users: []entity.User{encryptedUser(t, oldKeyring, 1, "Ana")}}
+	updatedName, err := oldKeyring.Encrypt(fieldcrypt.UserField("first_name", repo.users[0].UUID), "Anabel")
+	require.NoError(t, err)
+	repo.concurrentUpdate = func(users []entity.User) {
+		users[0].FirstName = updatedName
+	}
+	s := user.NewService(repo, newMockTokenRepository(), newMockLoginAttemptRepository(), newMockRoleRepository(), mailer.NewMemoryMailer())
+
+	report, err := s.ReencryptUsers(keyring, 10, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, updatedName, repo.users[0].FirstName, "the concurrent update is not overwritten")
+}
@@ -0,0 +1,93 @@
+package user_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// mockRoleRepository is an in-memory implementation of the RoleRepository interface for testing,
+// holding the user role and the roles assigned to each user ID.
+type mockRoleRepository struct {
+	roles       []entity.Role
+	assignments map[int][]int
+}
+
+func newMockRoleRepository() *mockRoleRepository {
+	return &mockRoleRepository{
+		roles:       []entity.Role{{ID: 1, Role: entity.RoleUser}, {ID: 2, Role: entity.RoleAdmin}},
+		assignments: map[int][]int{1: {1}},
+	}
+}
+
+func (m *mockRoleRepository) FindRoles() ([]entity.Role, error) {
+	return m.roles, nil
+}
+
+func (m *mockRoleRepository) FindRoleByName(name string) (*entity.Role, error) {
+	for i := range m.roles {
+		if m.roles[i].Role == name {
+			return &m.roles[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockRoleRepository) CreateRole(role *entity.Role) error {
+	return nil
+}
+
+func (m *mockRoleRepository) UpdateRole(role *entity.Role) error {
+	return nil
+}
+
+func (m *mockRoleRepository) DeleteRole(roleID int) error {
+	return nil
+}
+
+func (m *mockRoleRepository) AssignRole(userID int, roleID int) error {
+	for _, id := range m.assignments[userID] {
+		if id == roleID {
+			return nil
+		}
+	}
+	m.assignments[userID] = append(m.assignments[userID], roleID)
+	return nil
+}
+
+func (m *mockRoleRepository) UnassignRole(userID int, roleID int) error {
+	return nil
+}
+
+func (m *mockRoleRepository) UnassignRoleKeepingHolder(userID int, roleID int) error {
+	return m.UnassignRole(userID, roleID)
+}
+
+func (m *mockRoleRepository) GetUserRoleNames(userID int) ([]string, error) {
+	var names []string
+	for _, id := range m.assignments[userID] {
+		names = append(names, m.roles[id-1].Role)
+	}
+	return names, nil
+}
+
+func (m *mockRoleRepository) GetUserPermissions(userUUID uuid.UUID) ([]string, error) {
+	return nil, nil
+}
+
+func TestLoginRolesClaim(t *testing.T) {
+	tokenRepo := newMockTokenRepository()
+	tokens, _ := login(t, tokenRepo)
+
+	claims := jwt.MapClaims{}
+	_, _, err := new(jwt.Parser).ParseUnverified(strings.TrimPrefix(tokens.Token, "Bearer "), claims)
+	require.NoError(t, err)
+	assert.Equal(t, entity.RoleUser, claims["role"])
+	assert.Equal(t, []interface{}{entity.RoleUser}, claims["roles"])
+}
@@ -0,0 +1,202 @@
+package user
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/fieldcrypt"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrEmptySearch       = errors.New("name or email required")
+	ErrSearchTooShort    = errors.New("every word of the name must have at least 3 characters")
+	ErrInvalidMatchParam = errors.New("match must be prefix or exact")
+)
+
+const (
+	// minSearchPrefix is the shortest prefix indexed, shorter ones would match too many users.
+	minSearchPrefix = 3
+	// maxSearchPrefix is the longest prefix indexed, longer words are looked up by their first maxSearchPrefix characters.
+	maxSearchPrefix = 20
+
+	DefaultSearchPageSize = 20
+	MaxSearchPageSize     = 100
+)
+
+// SearchUsers returns a page of the users whose first or last name and email match the request.
+// Names are encrypted, so they are matched through their blind indexes.
+func (s *service) SearchUsers(request *entity.RequestUserSearch) (*entity.UserSearchResult, int, error) {
+	filter, err := searchFilter(request, fieldcrypt.BlindIndexerFromConfig(config.Get()))
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	users, total, err := s.repo.SearchUsers(filter)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	for i := range users {
+		if err := decryptUserFields(&users[i]); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+	}
+
+	return &entity.UserSearchResult{
+		Users:    users,
+		Page:     filter.Offset/filter.Limit + 1,
+		PageSize: filter.Limit,
+		Total:    total,
+	}, http.StatusOK, nil
+}
+
+// ReindexUsers recomputes the blind indexes of the names of every user, in batches.
+// Returns the number of users indexed.
+func (s *service) ReindexUsers(batchSize int) (int, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultReencryptionBatchSize
+	}
+
+	indexed := 0
+	afterID := 0
+	for {
+		users, err := s.repo.FindUsersAfterID(afterID, batchSize)
+		if err != nil {
+			return indexed, err
+		}
+		if len(users) == 0 {
+			return indexed, nil
+		}
+
+		for i := range users {
+			user := &users[i]
+			if err := decryptUserFields(user); err != nil {
+				log.Printf("error while decrypting the user %d: %s", user.ID, err.Error())
+				continue
+			}
+			if err := s.indexUserName(user.ID, user.FirstName, user.LastName); err != nil {
+				return indexed, err
+			}
+			indexed++
+		}
+		afterID = users[len(users)-1].ID
+	}
+}
+
+// indexUserName replaces the blind indexes of the user with the ones of the given plaintext names.
+func (s *service) indexUserName(userID int, firstName, lastName string) error {
+	return s.repo.ReplaceSearchIndexes(userID, nameIndexes(userID, fieldcrypt.BlindIndexerFromConfig(config.Get()), firstName, lastName))
+}
+
+// nameIndexes returns the blind indexes of every word of the names and of their prefixes.
+func nameIndexes(userID int, indexer *fieldcrypt.BlindIndexer, names ...string) []entity.UserSearchIndex {
+	seen := map[entity.UserSearchIndex]bool{}
+	var indexes []entity.UserSearchIndex
+	add := func(kind, value string) {
+		index := entity.UserSearchIndex{UserID: userID, Kind: kind, Hash: indexer.Index(kind, value)}
+		if !seen[index] {
+			seen[index] = true
+			indexes = append(indexes, index)
+		}
+	}
+
+	for _, name := range names {
+		for _, word := range searchWords(name) {
+			add(entity.SearchIndexNameWord, word)
+			letters := []rune(word)
+			for length := minSearchPrefix; length <= len(letters) && length <= maxSearchPrefix; length++ {
+				add(entity.SearchIndexNamePrefix, string(letters[:length]))
+			}
+		}
+	}
+	return indexes
+}
+
+// searchFilter validates the request and returns the filter with the blind indexes of the searched name.
+func searchFilter(request *entity.RequestUserSearch, indexer *fieldcrypt.BlindIndexer) (*entity.UserSearchFilter, error) {
+	words := searchWords(request.Name)
+	email := strings.TrimSpace(request.Email)
+	if len(words) == 0 && email == "" {
+		return nil, ErrEmptySearch
+	}
+
+	match := request.Match
+	if match == "" {
+		match = entity.SearchMatchPrefix
+	}
+	if match != entity.SearchMatchPrefix && match != entity.SearchMatchExact {
+		return nil, ErrInvalidMatchParam
+	}
+
+	filter := &entity.UserSearchFilter{Email: email, EmailExact: match == entity.SearchMatchExact}
+
+	filter.Kind = entity.SearchIndexNamePrefix
+	if match == entity.SearchMatchExact {
+		filter.Kind = entity.SearchIndexNameWord
+	}
+	seen := map[string]bool{}
+	for _, word := range words {
+		letters := []rune(word)
+		if len(letters) < minSearchPrefix {
+			return nil, ErrSearchTooShort
+		}
+		if match == entity.SearchMatchPrefix && len(letters) > maxSearchPrefix {
+			word = string(letters[:maxSearchPrefix])
+		}
+		hash := indexer.Index(filter.Kind, word)
+		if !seen[hash] {
+			seen[hash] = true
+			filter.Hashes = append(filter.Hashes, hash)
+		}
+	}
+
+	filter.Limit = request.PageSize
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultSearchPageSize
+	}
+	if filter.Limit > MaxSearchPageSize {
+		filter.Limit = MaxSearchPageSize
+	}
+	page := request.Page
+	if page < 1 {
+		page = 1
+	}
+	filter.Offset = (page - 1) * filter.Limit
+
+	return filter, nil
+}
+
+// accentRemover removes the diacritics, so "Lucía" and "lucia" are the same word.
+var accentRemover = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// searchWords returns the normalized words of a name: lowercase, without diacritics nor punctuation.
+func searchWords(name string) []string {
+	normalized, _, err := transform.String(accentRemover, strings.ToLower(name))
+	if err != nil {
+		normalized = strings.ToLower(name)
+	}
+	return strings.FieldsFunc(normalized, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// decryptUserFields decrypts the first name, last name and profile image of the user.
+func decryptUserFields(user *entity.User) error {
+	var err error
+	if user.FirstName, err = decryptString("first_name", user.UUID, user.FirstName); err != nil {
+		return err
+	}
+	if user.LastName, err = decryptString("last_name", user.UUID, user.LastName); err != nil {
+		return err
+	}
+	user.ProfileImage, err = decryptString("profile_image", user.UUID, user.ProfileImage)
+	return err
+}
@@ -0,0 +1,102 @@
+package user_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/emur-uy/backend/internal/infra/mailer"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/emur-uy/backend/internal/pkg/service/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSearchUserRepository records the filters of the searches.
+type mockSearchUserRepository struct {
+	MockUserRepository
+	filters []*entity.UserSearchFilter
+}
+
+func (m *mockSearchUserRepository) SearchUsers(filter *entity.UserSearchFilter) ([]entity.User, int64, error) {
+	m.filters = append(m.filters, filter)
+	return nil, 0, nil
+}
+
+func newSearchService() (ports.UserService, *mockSearchUserRepository) {
+	repo := &mockSearchUserRepository{}
+	return user.NewService(repo, newMockTokenRepository(), newMockLoginAttemptRepository(), newMockRoleRepository(), mailer.NewMemoryMailer()), repo
+}
+
+func TestSearchUsersNormalizesNames(t *testing.T) {
+	s, repo := newSearchService()
+
+	for _, name := range []string{"Lucía Pérez", "  lucia   PEREZ ", "Lucía-Pérez"} {
+		_, status, err := s.SearchUsers(&entity.RequestUserSearch{Name: name})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, status)
+	}
+
+	require.Len(t, repo.filters, 3)
+	assert.Equal(t, entity.SearchIndexNamePrefix, repo.filters[0].Kind)
+	assert.Len(t, repo.filters[0].Hashes, 2)
+	assert.Equal(t, repo.filters[0].Hashes, repo.filters[1].Hashes)
+	assert.Equal(t, repo.filters[0].Hashes, repo.filters[2].Hashes)
+}
+
+func TestSearchUsersMatchModes(t *testing.T) {
+	s, repo := newSearchService()
+
+	_, _, err := s.SearchUsers(&entity.RequestUserSearch{Name: "Ana", Email: "Ana@Example", Match: entity.SearchMatchPrefix})
+	require.NoError(t, err)
+	_, _, err = s.SearchUsers(&entity.RequestUserSearch{Name: "Ana", Email: "ana@example.com", Match: entity.SearchMatchExact})
+	require.NoError(t, err)
+
+	require.Len(t, repo.filters, 2)
+	prefix, exact := repo.filters[0], repo.filters[1]
+	assert.Equal(t, entity.SearchIndexNamePrefix, prefix.Kind)
+	assert.False(t, prefix.EmailExact)
+	assert.Equal(t, entity.SearchIndexNameWord, exact.Kind)
+	assert.True(t, exact.EmailExact)
+	assert.NotEqual(t, prefix.Hashes, exact.Hashes, "the kinds of index hash the same word differently")
+}
+
+func TestSearchUsersPagination(t *testing.T) {
+	s, repo := newSearchService()
+
+	result, _, err := s.SearchUsers(&entity.RequestUserSearch{Email: "ana"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Page)
+	assert.Equal(t, user.DefaultSearchPageSize, result.PageSize)
+	assert.Empty(t, repo.filters[0].Hashes)
+
+	result, _, err = s.SearchUsers(&entity.RequestUserSearch{Email: "ana", Page: 3, PageSize: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Page)
+	assert.Equal(t, user.MaxSearchPageSize, repo.filters[1].Limit)
+	assert.Equal(t, 2*user.MaxSearchPageSize, repo.filters[1].Offset)
+}
+
+func TestSearchUsersInvalidRequest(t *testing.T) {
+	s, repo := newSearchService()
+
+	testCases := []struct {
+		name    string
+		request *entity.RequestUserSearch
+		err     error
+	}{
+		{"empty search", &entity.RequestUserSearch{}, user.ErrEmptySearch},
+		{"punctuation only", &entity.RequestUserSearch{Name: " - "}, user.ErrEmptySearch},
+		{"short word", &entity.RequestUserSearch{Name: "Ana Li"}, user.ErrSearchTooShort},
+		{"invalid match", &entity.RequestUserSearch{Name: "Ana", Match: "fuzzy"}, user.ErrInvalidMatchParam},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, status, err := s.SearchUsers(tc.request)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+	}
+	assert.Empty(t, repo.filters)
+}
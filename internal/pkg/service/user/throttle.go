@@ -0,0 +1,196 @@
+package user
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrTooManyAttempts = errors.New("too many failed login attempts, try again later")
+	ErrAccountLocked   = errors.New("account temporarily locked after too many failed login attempts")
+)
+
+// Defaults of the login throttling when none is configured. IPs get a higher limit as many users can share one.
+const (
+	DefaultLoginMaxFailures   = 5
+	DefaultLoginIPMaxFailures = 20
+	DefaultLoginLockout       = 15 * time.Minute
+)
+
+const (
+	// loginFreeFailures is the number of failures allowed before the delays start.
+	loginFreeFailures = 2
+	// loginBaseDelay is the delay after the first failure past the free ones, it doubles with every failure.
+	loginBaseDelay = time.Second
+	loginMaxDelay  = 30 * time.Second
+	// loginAttemptsLimit is the number of attempts returned by the audit.
+	loginAttemptsLimit = 100
+)
+
+// dummyPasswordHash is compared against the password of unknown emails, so they take as long as a wrong password.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), JWTCost)
+
+// loginLimits are the configured thresholds of the login throttling.
+type loginLimits struct {
+	emailMaxFailures int
+	ipMaxFailures    int
+	lockout          time.Duration
+}
+
+// GetLoginAttempts returns the latest login attempts of the user, newest first.
+func (s *service) GetLoginAttempts(userUUID uuid.UUID) ([]entity.LoginAttempt, int, error) {
+	user := &entity.User{}
+	if err := s.repo.First(user, "uuid= ?", userUUID); err != nil {
+		return nil, http.StatusNotFound, err
+	}
+
+	attempts, err := s.attemptRepo.FindAttempts(user.ID, loginAttemptsLimit)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return attempts, http.StatusOK, nil
+}
+
+// UnlockUser clears the failed logins and the lockout of the user's email.
+func (s *service) UnlockUser(userUUID uuid.UUID) (int, error) {
+	user := &entity.User{}
+	if err := s.repo.First(user, "uuid= ?", userUUID); err != nil {
+		return http.StatusNotFound, err
+	}
+
+	if err := s.attemptRepo.DeleteThrottle(emailThrottleKey(user.Email)); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// checkLoginThrottle returns an error when any of the keys is locked, or when the delay since its last failure
+// has not passed yet. The returned reason is the one recorded in the audit.
+func (s *service) checkLoginThrottle(keys []string, now time.Time) (string, error) {
+	throttles, err := s.attemptRepo.FindThrottles(keys)
+	if err != nil {
+		return "", err
+	}
+
+	for _, throttle := range throttles {
+		if throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil) {
+			if strings.HasPrefix(throttle.Key, "email:") {
+				return entity.LoginReasonLocked, ErrAccountLocked
+			}
+			return entity.LoginReasonLocked, ErrTooManyAttempts
+		}
+		if now.Before(throttle.LastFailureAt.Add(loginDelay(throttle.Failures))) {
+			return entity.LoginReasonThrottled, ErrTooManyAttempts
+		}
+	}
+
+	return "", nil
+}
+
+// recordLoginFailure audits the failed attempt and counts it for the email and the IP,
+// locking them once they reach their limit.
+func (s *service) recordLoginFailure(user *entity.User, email, ip, reason string, now time.Time) {
+	s.recordLoginAttempt(user, email, ip, reason)
+
+	limits := loginThrottleLimits()
+	keys := map[string]int{
+		emailThrottleKey(email): limits.emailMaxFailures,
+		ipThrottleKey(ip):       limits.ipMaxFailures,
+	}
+	for key, maxFailures := range keys {
+		throttle, err := s.attemptRepo.IncrementThrottle(key, now, now.Add(-limits.lockout))
+		if err != nil {
+			log.Printf("error while counting the failed login of %s: %s", key, err.Error())
+			continue
+		}
+		if throttle.Failures >= maxFailures {
+			if err := s.attemptRepo.LockThrottle(key, now.Add(limits.lockout)); err != nil {
+				log.Printf("error while locking %s: %s", key, err.Error())
+			}
+		}
+	}
+}
+
+// recordLoginSuccess audits the attempt and clears the failures of the email.
+// The failures of the IP are kept, a valid password does not make the other attempts from it legitimate.
+func (s *service) recordLoginSuccess(user *entity.User, email, ip, reason string) {
+	s.recordLoginAttempt(user, email, ip, reason)
+
+	if err := s.attemptRepo.DeleteThrottle(emailThrottleKey(email)); err != nil {
+		log.Printf("error while clearing the failed logins of %s: %s", email, err.Error())
+	}
+}
+
+// recordLoginAttempt adds the attempt to the audit, logging the failures.
+func (s *service) recordLoginAttempt(user *entity.User, email, ip, reason string) {
+	attempt := &entity.LoginAttempt{
+		Email:   email,
+		IP:      ip,
+		Success: reason == entity.LoginReasonSuccess,
+		Reason:  reason,
+	}
+	if user != nil {
+		attempt.UserID = &user.ID
+	}
+
+	if err := s.attemptRepo.CreateAttempt(attempt); err != nil {
+		log.Printf("error while auditing the login attempt of %s: %s", email, err.Error())
+	}
+}
+
+// loginDelay returns the time to wait after the last failure before trying again,
+// doubling with every failure past the free ones.
+func loginDelay(failures int) time.Duration {
+	if failures <= loginFreeFailures {
+		return 0
+	}
+
+	delay := loginBaseDelay
+	for i := loginFreeFailures + 1; i < failures && delay < loginMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > loginMaxDelay {
+		return loginMaxDelay
+	}
+	return delay
+}
+
+// loginThrottleLimits returns the configured limits, or their defaults.
+func loginThrottleLimits() loginLimits {
+	cfg := config.Get()
+	limits := loginLimits{
+		emailMaxFailures: DefaultLoginMaxFailures,
+		ipMaxFailures:    DefaultLoginIPMaxFailures,
+		lockout:          DefaultLoginLockout,
+	}
+	if cfg.LoginMaxFailures > 0 {
+		limits.emailMaxFailures = cfg.LoginMaxFailures
+	}
+	if cfg.LoginIPMaxFailures > 0 {
+		limits.ipMaxFailures = cfg.LoginIPMaxFailures
+	}
+	if cfg.LoginLockoutMinutes > 0 {
+		limits.lockout = time.Duration(cfg.LoginLockoutMinutes) * time.Minute
+	}
+	return limits
+}
+
+// emailThrottleKey returns the key of the failure counter of an email.
+func emailThrottleKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// ipThrottleKey returns the key of the failure counter of an IP.
+func ipThrottleKey(ip string) string {
+	return "ip:" + ip
+}
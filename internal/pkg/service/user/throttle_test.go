@@ -0,0 +1,194 @@
+package user_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/emur-uy/backend/internal/infra/mailer"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/emur-uy/backend/internal/pkg/service/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIP = "203.0.113.10"
+
+// mockLoginAttemptRepository is an in-memory implementation of the LoginAttemptRepository interface for testing.
+type mockLoginAttemptRepository struct {
+	attempts  []entity.LoginAttempt
+	throttles map[string]*entity.LoginThrottle
+}
+
+func newMockLoginAttemptRepository() *mockLoginAttemptRepository {
+	return &mockLoginAttemptRepository{throttles: map[string]*entity.LoginThrottle{}}
+}
+
+func (m *mockLoginAttemptRepository) CreateAttempt(attempt *entity.LoginAttempt) error {
+	m.attempts = append(m.attempts, *attempt)
+	return nil
+}
+
+func (m *mockLoginAttemptRepository) FindAttempts(userID int, limit int) ([]entity.LoginAttempt, error) {
+	var attempts []entity.LoginAttempt
+	for i := len(m.attempts) - 1; i >= 0 && len(attempts) < limit; i-- {
+		if m.attempts[i].UserID != nil && *m.attempts[i].UserID == userID {
+			attempts = append(attempts, m.attempts[i])
+		}
+	}
+	return attempts, nil
+}
+
+func (m *mockLoginAttemptRepository) FindThrottles(keys []string) ([]entity.LoginThrottle, error) {
+	var throttles []entity.LoginThrottle
+	for _, key := range keys {
+		if throttle, ok := m.throttles[key]; ok {
+			throttles = append(throttles, *throttle)
+		}
+	}
+	return throttles, nil
+}
+
+func (m *mockLoginAttemptRepository) IncrementThrottle(key string, failedAt time.Time, windowStart time.Time) (*entity.LoginThrottle, error) {
+	throttle, ok := m.throttles[key]
+	if !ok {
+		throttle = &entity.LoginThrottle{Key: key}
+		m.throttles[key] = throttle
+	}
+	if throttle.LastFailureAt.Before(windowStart) {
+		throttle.Failures = 0
+	}
+	throttle.Failures++
+	throttle.LastFailureAt = failedAt
+	updated := *throttle
+	return &updated, nil
+}
+
+func (m *mockLoginAttemptRepository) LockThrottle(key string, lockedUntil time.Time) error {
+	m.throttles[key].LockedUntil = &lockedUntil
+	return nil
+}
+
+func (m *mockLoginAttemptRepository) DeleteThrottle(key string) error {
+	delete(m.throttles, key)
+	return nil
+}
+
+// rewind moves the failures and locks back in time, as if the given duration had passed.
+func (m *mockLoginAttemptRepository) rewind(d time.Duration) {
+	for _, throttle := range m.throttles {
+		throttle.LastFailureAt = throttle.LastFailureAt.Add(-d)
+		if throttle.LockedUntil != nil {
+			lockedUntil := throttle.LockedUntil.Add(-d)
+			throttle.LockedUntil = &lockedUntil
+		}
+	}
+}
+
+// newThrottleService returns a service with in-memory repositories.
+func newThrottleService() (ports.UserService, *mockLoginAttemptRepository) {
+	attemptRepo := newMockLoginAttemptRepository()
+	return user.NewService(&MockUserRepository{}, newMockTokenRepository(), attemptRepo, newMockRoleRepository(), mailer.NewMemoryMailer()), attemptRepo
+}
+
+var (
+	validCredentials = &entity.DefaultCredentials{Email: testEmail, Password: "password"}
+	wrongCredentials = &entity.DefaultCredentials{Email: testEmail, Password: "wrong password"}
+)
+
+func TestLoginProgressiveDelay(t *testing.T) {
+	s, attemptRepo := newThrottleService()
+
+	for i := 0; i < 3; i++ {
+		_, status, err := s.Login(wrongCredentials, testIP)
+		require.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, status)
+	}
+
+	// The third failure delays the next attempt, even with the right password
+	_, status, err := s.Login(validCredentials, testIP)
+	assert.ErrorIs(t, err, user.ErrTooManyAttempts)
+	assert.Equal(t, http.StatusTooManyRequests, status)
+
+	attemptRepo.rewind(time.Minute)
+	_, status, err = s.Login(validCredentials, testIP)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+
+	// A successful login clears the failures of the email but not of the IP
+	assert.NotContains(t, attemptRepo.throttles, "email:"+testEmail)
+	assert.Equal(t, 3, attemptRepo.throttles["ip:"+testIP].Failures)
+}
+
+func TestLoginLockoutAndUnlock(t *testing.T) {
+	s, attemptRepo := newThrottleService()
+
+	for i := 0; i < user.DefaultLoginMaxFailures; i++ {
+		_, _, err := s.Login(wrongCredentials, testIP)
+		require.Error(t, err)
+		attemptRepo.rewind(time.Minute)
+	}
+
+	_, status, err := s.Login(validCredentials, "198.51.100.1")
+	assert.ErrorIs(t, err, user.ErrAccountLocked)
+	assert.Equal(t, http.StatusTooManyRequests, status)
+
+	status, err = s.UnlockUser(testUuid)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+
+	_, _, err = s.Login(validCredentials, testIP)
+	require.NoError(t, err)
+}
+
+func TestLoginLockoutExpires(t *testing.T) {
+	s, attemptRepo := newThrottleService()
+
+	for i := 0; i < user.DefaultLoginMaxFailures; i++ {
+		_, _, err := s.Login(wrongCredentials, testIP)
+		require.Error(t, err)
+		attemptRepo.rewind(time.Minute)
+	}
+
+	attemptRepo.rewind(user.DefaultLoginLockout)
+	_, _, err := s.Login(validCredentials, testIP)
+	require.NoError(t, err)
+}
+
+func TestLoginIPLockout(t *testing.T) {
+	s, attemptRepo := newThrottleService()
+
+	for i := 0; i < user.DefaultLoginIPMaxFailures; i++ {
+		credentials := &entity.DefaultCredentials{Email: fmt.Sprintf("user%d@example.com", i), Password: "password"}
+		_, _, err := s.Login(credentials, testIP)
+		require.Error(t, err)
+		attemptRepo.rewind(time.Minute)
+	}
+
+	_, _, err := s.Login(validCredentials, testIP)
+	assert.ErrorIs(t, err, user.ErrTooManyAttempts)
+
+	_, _, err = s.Login(validCredentials, "198.51.100.1")
+	require.NoError(t, err)
+}
+
+func TestLoginAttemptsAudit(t *testing.T) {
+	s, _ := newThrottleService()
+
+	_, _, _ = s.Login(&entity.DefaultCredentials{Email: "unknown@example.com", Password: "password"}, testIP)
+	_, _, _ = s.Login(wrongCredentials, testIP)
+	_, _, err := s.Login(validCredentials, testIP)
+	require.NoError(t, err)
+
+	attempts, status, err := s.GetLoginAttempts(testUuid)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	require.Len(t, attempts, 2)
+	assert.True(t, attempts[0].Success)
+	assert.Equal(t, entity.LoginReasonSuccess, attempts[0].Reason)
+	assert.False(t, attempts[1].Success)
+	assert.Equal(t, entity.LoginReasonWrongPassword, attempts[1].Reason)
+	assert.Equal(t, testIP, attempts[1].IP)
+}
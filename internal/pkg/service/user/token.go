@@ -0,0 +1,174 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token already used, the session was revoked")
+	ErrIssuingTokens       = errors.New("error issuing tokens")
+	ErrRevokingToken       = errors.New("error revoking token")
+)
+
+// DefaultRefreshTokenExpired is the lifetime of the refresh tokens in hours when none is configured.
+const DefaultRefreshTokenExpired = 30 * 24
+
+// refreshTokenBytes is the number of random bytes of a refresh token.
+const refreshTokenBytes = 32
+
+// timeNow returns the current time, it is a variable so tests can fix the clock.
+var timeNow = time.Now
+
+// RefreshToken rotates the refresh token: the presented token is revoked and a new one of the same family is issued
+// along with a new access token. Presenting a token that was already rotated means it leaked, so its whole family is revoked.
+func (s *service) RefreshToken(refreshToken string) (*entity.AuthTokens, int, error) {
+	now := timeNow()
+
+	storedToken := &entity.RefreshToken{}
+	if err := s.tokenRepo.First(storedToken, "token_hash = ?", hashToken(refreshToken)); err != nil {
+		return nil, http.StatusUnauthorized, ErrInvalidRefreshToken
+	}
+
+	if storedToken.RevokedAt != nil {
+		s.revokeFamily(storedToken.FamilyID, now)
+		return nil, http.StatusUnauthorized, ErrRefreshTokenReused
+	}
+	if !now.Before(storedToken.ExpiresAt) {
+		return nil, http.StatusUnauthorized, ErrInvalidRefreshToken
+	}
+
+	// Revoking atomically ensures a concurrent refresh with the same token is detected as a reuse
+	revoked, err := s.tokenRepo.RevokeRefreshToken(storedToken.ID, now)
+	if err != nil {
+		return nil, http.StatusInternalServerError, ErrRevokingToken
+	}
+	if !revoked {
+		s.revokeFamily(storedToken.FamilyID, now)
+		return nil, http.StatusUnauthorized, ErrRefreshTokenReused
+	}
+
+	user := &entity.User{}
+	if err := s.repo.First(user, "id = ?", storedToken.UserID); err != nil {
+		return nil, http.StatusUnauthorized, ErrInvalidRefreshToken
+	}
+
+	tokens, err := s.issueTokens(user, storedToken.FamilyID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return tokens, http.StatusOK, nil
+}
+
+// Logout adds the access token to the revocation list until it expires, and revokes the session
+// of the refresh token when it is given and belongs to the user.
+func (s *service) Logout(userUUID uuid.UUID, jti string, expiresAt time.Time, refreshToken string) (int, error) {
+	now := timeNow()
+
+	if jti != "" && expiresAt.After(now) {
+		if err := s.tokenRepo.Create(&entity.RevokedToken{JTI: jti, ExpiresAt: expiresAt}); err != nil {
+			return http.StatusInternalServerError, ErrRevokingToken
+		}
+	}
+
+	if refreshToken == "" {
+		return http.StatusOK, nil
+	}
+
+	user := &entity.User{}
+	if err := s.repo.First(user, "uuid= ?", userUUID); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	storedToken := &entity.RefreshToken{}
+	if err := s.tokenRepo.First(storedToken, "token_hash = ?", hashToken(refreshToken)); err != nil || storedToken.UserID != user.ID {
+		return http.StatusBadRequest, ErrInvalidRefreshToken
+	}
+
+	if err := s.tokenRepo.RevokeRefreshTokenFamily(storedToken.FamilyID, now); err != nil {
+		return http.StatusInternalServerError, ErrRevokingToken
+	}
+
+	return http.StatusOK, nil
+}
+
+// PurgeExpiredTokens deletes the refresh tokens and revoked access tokens that already expired.
+// Returns the number of deleted tokens.
+func (s *service) PurgeExpiredTokens(now time.Time) (int64, error) {
+	deleted, err := s.tokenRepo.DeleteExpiredTokens(now)
+	if err != nil {
+		return 0, fmt.Errorf("error purging expired tokens: %s", err)
+	}
+	return deleted, nil
+}
+
+// issueTokens generates an access token and a refresh token of the given family for the user,
+// storing the hash of the refresh token.
+func (s *service) issueTokens(user *entity.User, familyID uuid.UUID) (*entity.AuthTokens, error) {
+	accessToken, expiresAt, err := s.generateJWTToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := generateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshTokenExpired := DefaultRefreshTokenExpired
+	if configured := config.Get().RefreshTokenExpired; configured > 0 {
+		refreshTokenExpired = configured
+	}
+
+	storedToken := &entity.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: timeNow().Add(time.Duration(refreshTokenExpired) * time.Hour),
+	}
+	if err := s.tokenRepo.Create(storedToken); err != nil {
+		log.Printf("error while storing the refresh token: %s", err.Error())
+		return nil, ErrIssuingTokens
+	}
+
+	return &entity.AuthTokens{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+// revokeFamily revokes every refresh token of the family, logging the failures.
+func (s *service) revokeFamily(familyID uuid.UUID, now time.Time) {
+	if err := s.tokenRepo.RevokeRefreshTokenFamily(familyID, now); err != nil {
+		log.Printf("error while revoking the refresh token family %s: %s", familyID, err.Error())
+	}
+}
+
+// generateRefreshToken returns a random URL safe refresh token.
+func generateRefreshToken() (string, error) {
+	token := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// hashToken returns the hex encoded SHA-256 hash of a token, which is what is stored in the database.
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,221 @@
+package user_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/emur-uy/backend/internal/infra/mailer"
+	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
+	"github.com/emur-uy/backend/internal/pkg/service/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTokenRepository is an in-memory implementation of the TokenRepository interface for testing.
+type mockTokenRepository struct {
+	refreshTokens []*entity.RefreshToken
+	revokedTokens map[string]time.Time
+	actionTokens  []*entity.UserActionToken
+}
+
+func newMockTokenRepository() *mockTokenRepository {
+	return &mockTokenRepository{revokedTokens: map[string]time.Time{}}
+}
+
+func (m *mockTokenRepository) Create(value interface{}) error {
+	switch token := value.(type) {
+	case *entity.RefreshToken:
+		token.ID = len(m.refreshTokens) + 1
+		stored := *token
+		m.refreshTokens = append(m.refreshTokens, &stored)
+	case *entity.RevokedToken:
+		m.revokedTokens[token.JTI] = token.ExpiresAt
+	case *entity.UserActionToken:
+		token.ID = len(m.actionTokens) + 1
+		stored := *token
+		m.actionTokens = append(m.actionTokens, &stored)
+	}
+	return nil
+}
+
+func (m *mockTokenRepository) First(out interface{}, conditions ...interface{}) error {
+	switch out := out.(type) {
+	case *entity.RefreshToken:
+		for _, token := range m.refreshTokens {
+			if conditions[0] == "token_hash = ?" && token.TokenHash == conditions[1] {
+				*out = *token
+				return nil
+			}
+		}
+	case *entity.UserActionToken:
+		for _, token := range m.actionTokens {
+			if conditions[0] == "token_hash = ?" && token.TokenHash == conditions[1] {
+				*out = *token
+				return nil
+			}
+		}
+	}
+	return errors.New("not found")
+}
+
+func (m *mockTokenRepository) RevokeRefreshToken(id int, revokedAt time.Time) (bool, error) {
+	for _, token := range m.refreshTokens {
+		if token.ID == id && token.RevokedAt == nil {
+			token.RevokedAt = &revokedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockTokenRepository) RevokeRefreshTokenFamily(familyID uuid.UUID, revokedAt time.Time) error {
+	for _, token := range m.refreshTokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+func (m *mockTokenRepository) RevokeUserRefreshTokens(userID int, revokedAt time.Time) error {
+	for _, token := range m.refreshTokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+func (m *mockTokenRepository) UseActionToken(id int, usedAt time.Time) (bool, error) {
+	for _, token := range m.actionTokens {
+		if token.ID == id && token.UsedAt == nil {
+			token.UsedAt = &usedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockTokenRepository) DeleteExpiredTokens(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockTokenRepository) IsRevoked(jti string) (bool, error) {
+	_, ok := m.revokedTokens[jti]
+	return ok, nil
+}
+
+func (m *mockTokenRepository) SetTokensValidAfter(userID int, validAfter time.Time) error {
+	return nil
+}
+
+func (m *mockTokenRepository) TokensValidAfter(userUUID uuid.UUID) (*time.Time, error) {
+	return nil, nil
+}
+
+// activeTokens returns the number of refresh tokens of the family that are not revoked.
+func (m *mockTokenRepository) activeTokens(familyID uuid.UUID) int {
+	active := 0
+	for _, token := range m.refreshTokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			active++
+		}
+	}
+	return active
+}
+
+// mockSessionUserRepository resolves the users by ID, as the refresh token does.
+type mockSessionUserRepository struct {
+	MockUserRepository
+}
+
+func (m *mockSessionUserRepository) First(out interface{}, conditions ...interface{}) error {
+	if conditions[0] == "id = ?" && conditions[1] == 1 {
+		out.(*entity.User).ID = 1
+		out.(*entity.User).UUID = testUuid
+		out.(*entity.User).Email = testEmail
+		return nil
+	}
+	return m.MockUserRepository.First(out, conditions...)
+}
+
+// login starts a session for the test user, returning its tokens and the service.
+func login(t *testing.T, tokenRepo *mockTokenRepository) (*entity.AuthTokens, ports.UserService) {
+	var s ports.UserService = user.NewService(&mockSessionUserRepository{}, tokenRepo, newMockLoginAttemptRepository(), newMockRoleRepository(), mailer.NewMemoryMailer())
+	tokens, _, err := s.Login(&entity.DefaultCredentials{Email: testEmail, Password: "password"}, testIP)
+	require.NoError(t, err)
+	return tokens, s
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	tokenRepo := newMockTokenRepository()
+	tokens, s := login(t, tokenRepo)
+	require.NotEmpty(t, tokens.RefreshToken)
+	assert.NotEqual(t, tokens.RefreshToken, tokenRepo.refreshTokens[0].TokenHash)
+
+	rotated, status, err := s.RefreshToken(tokens.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.NotEqual(t, tokens.RefreshToken, rotated.RefreshToken)
+	assert.NotEmpty(t, rotated.Token)
+
+	familyID := tokenRepo.refreshTokens[0].FamilyID
+	assert.Equal(t, familyID, tokenRepo.refreshTokens[1].FamilyID)
+	assert.Equal(t, 1, tokenRepo.activeTokens(familyID))
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	tokenRepo := newMockTokenRepository()
+	tokens, s := login(t, tokenRepo)
+
+	rotated, _, err := s.RefreshToken(tokens.RefreshToken)
+	require.NoError(t, err)
+
+	_, status, err := s.RefreshToken(tokens.RefreshToken)
+	assert.ErrorIs(t, err, user.ErrRefreshTokenReused)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, 0, tokenRepo.activeTokens(tokenRepo.refreshTokens[0].FamilyID))
+
+	_, _, err = s.RefreshToken(rotated.RefreshToken)
+	assert.ErrorIs(t, err, user.ErrRefreshTokenReused)
+}
+
+func TestRefreshTokenInvalid(t *testing.T) {
+	tokenRepo := newMockTokenRepository()
+	_, s := login(t, tokenRepo)
+
+	_, status, err := s.RefreshToken("unknown")
+	assert.ErrorIs(t, err, user.ErrInvalidRefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	tokenRepo.refreshTokens[0].ExpiresAt = time.Now().Add(-time.Hour)
+	tokens, s := login(t, tokenRepo)
+	tokenRepo.refreshTokens[1].ExpiresAt = time.Now().Add(-time.Hour)
+	_, _, err = s.RefreshToken(tokens.RefreshToken)
+	assert.ErrorIs(t, err, user.ErrInvalidRefreshToken)
+}
+
+func TestLogout(t *testing.T) {
+	tokenRepo := newMockTokenRepository()
+	tokens, s := login(t, tokenRepo)
+
+	status, err := s.Logout(testUuid, "token-id", time.Now().Add(time.Hour), tokens.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+
+	revoked, err := tokenRepo.IsRevoked("token-id")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	assert.Equal(t, 0, tokenRepo.activeTokens(tokenRepo.refreshTokens[0].FamilyID))
+
+	_, _, err = s.RefreshToken(tokens.RefreshToken)
+	assert.Error(t, err)
+
+	status, err = s.Logout(testUuid, "", time.Time{}, "unknown")
+	assert.ErrorIs(t, err, user.ErrInvalidRefreshToken)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
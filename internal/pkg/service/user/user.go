@@ -1,10 +1,7 @@
 package user
 
 import (
-	"crypto/aes"
-	"crypto/cipher"
-	"crypto/rand"
-	"encoding/base64"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,7 +9,9 @@ import (
 	"time"
 
 	"github.com/emur-uy/backend/config"
+	"github.com/emur-uy/backend/internal/pkg/dates"
 	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/fieldcrypt"
 	"github.com/emur-uy/backend/internal/pkg/ports"
 	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
@@ -29,37 +28,76 @@ const (
 // service is a private struct implementing the ports.UserService interface, which
 // encapsulates the business logic related to user operations.
 type service struct {
-	repo ports.UserRepository // repo is an instance of the UserRepository interface for data persistence.
+	repo        ports.UserRepository         // repo is an instance of the UserRepository interface for data persistence.
+	tokenRepo   ports.TokenRepository        // tokenRepo stores the refresh tokens, the revoked access tokens and the action tokens.
+	attemptRepo ports.LoginAttemptRepository // attemptRepo audits the login attempts and counts the failed ones.
+	roleRepo    ports.RoleRepository         // roleRepo assigns the roles of the users.
+	mailer      ports.Mailer                 // mailer sends the verification and password reset emails.
 }
 
 // NewService is a factory function that returns a new service instance, initialized with
-// the provided UserRepository for data persistence.
-func NewService(repo ports.UserRepository) *service {
+// the provided UserRepository for data persistence, TokenRepository for the tokens,
+// LoginAttemptRepository for the login throttling, RoleRepository for the roles and Mailer for the emails.
+func NewService(repo ports.UserRepository, tokenRepo ports.TokenRepository, attemptRepo ports.LoginAttemptRepository, roleRepo ports.RoleRepository, mailer ports.Mailer) *service {
 	return &service{
-		repo: repo,
+		repo:        repo,
+		tokenRepo:   tokenRepo,
+		attemptRepo: attemptRepo,
+		roleRepo:    roleRepo,
+		mailer:      mailer,
 	}
 }
 
-// Login authenticates a user against the database.
-func (s *service) Login(credentials *entity.DefaultCredentials) (string, error) {
+// Login authenticates a user against the database and starts a new session,
+// returning an access token and a refresh token.
+// Failed attempts are counted per email and per IP, delaying and then locking further attempts.
+func (s *service) Login(credentials *entity.DefaultCredentials, ip string) (*entity.AuthTokens, int, error) {
+	now := timeNow()
+
+	reason, err := s.checkLoginThrottle([]string{emailThrottleKey(credentials.Email), ipThrottleKey(ip)}, now)
+	if err != nil {
+		if reason == "" {
+			return nil, http.StatusInternalServerError, err
+		}
+		s.recordLoginAttempt(nil, credentials.Email, ip, reason)
+		return nil, http.StatusTooManyRequests, err
+	}
+
 	user, err := s.findUserByEmail(credentials.Email)
 	if err != nil {
-		return "", err
+		if !errors.Is(err, ports.ErrInvalidCredentials) {
+			return nil, http.StatusInternalServerError, err
+		}
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(credentials.Password))
+		s.recordLoginFailure(nil, credentials.Email, ip, entity.LoginReasonUnknownEmail, now)
+		return nil, http.StatusBadRequest, err
 	}
 
 	if err := s.verifyPassword(user.Password, credentials.Password); err != nil {
-		return "", err
+		s.recordLoginFailure(user, credentials.Email, ip, entity.LoginReasonWrongPassword, now)
+		return nil, http.StatusBadRequest, err
+	}
+
+	if err := checkEmailVerified(user); err != nil {
+		s.recordLoginSuccess(user, credentials.Email, ip, entity.LoginReasonNotVerified)
+		return nil, http.StatusForbidden, err
+	}
+
+	tokens, err := s.issueTokens(user, uuid.New())
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
 	}
 
-	return s.generateJWTToken(user)
+	s.recordLoginSuccess(user, credentials.Email, ip, entity.LoginReasonSuccess)
+	return tokens, http.StatusOK, nil
 }
 
-// findUserByEmail retrieves a user from the database by email.
+// findUserByEmail retrieves a user from the database by email, an unknown email being invalid credentials.
 func (s *service) findUserByEmail(email string) (*entity.User, error) {
 	user := &entity.User{}
 	if err := s.repo.First(user, "email = ?", email); err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("user not found")
+			return nil, ports.ErrInvalidCredentials
 		}
 		return nil, err
 	}
@@ -70,7 +108,7 @@ func (s *service) findUserByEmail(email string) (*entity.User, error) {
 func (s *service) verifyPassword(userPassword, credentialsPassword string) error {
 	if err := bcrypt.CompareHashAndPassword([]byte(userPassword), []byte(credentialsPassword)); err != nil {
 		if err == bcrypt.ErrMismatchedHashAndPassword {
-			return fmt.Errorf("incorrect/mismatch password")
+			return ports.ErrInvalidCredentials
 		}
 		return err
 	}
@@ -78,33 +116,39 @@ func (s *service) verifyPassword(userPassword, credentialsPassword string) error
 }
 
 // generateJWTToken generates a JWT token with custom claims for the authenticated user.
-func (s *service) generateJWTToken(user *entity.User) (string, error) {
+// Each token gets a unique ID so it can be revoked before it expires.
+// The roles are informative, the permissions are resolved on each request.
+func (s *service) generateJWTToken(user *entity.User) (string, time.Time, error) {
 	type jwtCustomClaims struct {
 		Email    string    `json:"email"`
 		UserUIID uuid.UUID `json:"user_uuid"`
 		Role     string    `json:"role"`
+		Roles    []string  `json:"roles"`
 		jwt.StandardClaims
 	}
 
 	jwtKey := []byte(config.Get().JWTTokenKey)
-	expirationTime := time.Now().Add(time.Duration(config.Get().JWTTokenExpired) * time.Hour)
+	now := timeNow()
+	expirationTime := now.Add(time.Duration(config.Get().JWTTokenExpired) * time.Hour)
 
-	userRoleData, _ := s.GetUserRole(user.ID)
-	var roleData *entity.Role
-	if userRoleData != nil {
-		roleData, _ = s.GetRole(userRoleData.RoleID)
+	roles, err := s.roleRepo.GetUserRoleNames(user.ID)
+	if err != nil {
+		return "", time.Time{}, err
 	}
 
 	var role string
-	if roleData != nil {
-		role = roleData.Role
+	if len(roles) > 0 {
+		role = roles[0]
 	}
 
 	claims := &jwtCustomClaims{
 		Email:    user.Email,
 		UserUIID: user.UUID,
 		Role:     role,
+		Roles:    roles,
 		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.NewString(),
+			IssuedAt:  now.Unix(),
 			ExpiresAt: expirationTime.Unix(),
 		},
 	}
@@ -112,10 +156,10 @@ func (s *service) generateJWTToken(user *entity.User) (string, error) {
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	strToken, err := jwtToken.SignedString(jwtKey)
 	if err != nil {
-		return "", err
+		return "", time.Time{}, err
 	}
 
-	return "Bearer " + strToken, nil
+	return "Bearer " + strToken, expirationTime, nil
 }
 
 // CreateUser is a method that creates a new user in the database, performing data validation
@@ -133,39 +177,68 @@ func (s *service) CreateUser(user *entity.User) (int, error) {
 		return http.StatusInternalServerError, err
 	}
 
-	encryptedFirstName, err := encryptString(user.FirstName)
+	// The UUID is set before the insert, the encrypted fields are bound to it
+	user.UUID = uuid.New()
+
+	encryptedFirstName, err := encryptString("first_name", user.UUID, user.FirstName)
 	if err != nil {
 		log.Printf("error while encrypting the first name: %s", err.Error())
 		return http.StatusInternalServerError, err
 	}
 
-	encryptedLastName, err := encryptString(user.LastName)
+	encryptedLastName, err := encryptString("last_name", user.UUID, user.LastName)
 	if err != nil {
 		log.Printf("error while encrypting the last name: %s", err.Error())
 		return http.StatusInternalServerError, err
 	}
 
-	encryptedProfileImage, err := encryptString(user.ProfileImage)
+	encryptedProfileImage, err := encryptString("profile_image", user.UUID, user.ProfileImage)
 	if err != nil {
 		log.Printf("error while encrypting the profile image: %s", err.Error())
 		return http.StatusInternalServerError, err
 	}
 
+	firstName, lastName := user.FirstName, user.LastName
 	user.Password = encryptedPass
 	user.FirstName = encryptedFirstName
 	user.LastName = encryptedLastName
 	user.ProfileImage = encryptedProfileImage
 	user.IsActive = true
 
-	err = s.repo.CreateWithOmit("uuid", user)
+	err = s.repo.Create(user)
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 
 	log.Printf("Creating user with values: %+v", user)
+
+	if err := s.assignDefaultRole(user.ID); err != nil {
+		log.Printf("error while assigning the default role: %s", err.Error())
+		return http.StatusInternalServerError, err
+	}
+
+	// A missing index only hides the user from the admin search until it is reindexed.
+	if err := s.indexUserName(user.ID, firstName, lastName); err != nil {
+		log.Printf("error while indexing the user name: %s", err.Error())
+	}
+
+	// The user is already created, a failed email can be sent again with a password reset.
+	if err := s.sendVerificationEmail(user); err != nil {
+		log.Printf("error while sending the verification email: %s", err.Error())
+	}
+
 	return http.StatusCreated, nil
 }
 
+// assignDefaultRole grants the user role to a new user.
+func (s *service) assignDefaultRole(userID int) error {
+	role, err := s.roleRepo.FindRoleByName(entity.RoleUser)
+	if err != nil {
+		return err
+	}
+	return s.roleRepo.AssignRole(userID, role.ID)
+}
+
 // encryptPassword is a helper function that takes a plain-text password and returns
 // its bcrypt hash. This function is used to securely store user passwords.
 func encryptPassword(password string) (string, error) {
@@ -202,12 +275,12 @@ func (s *service) UpdateUser(userUUID uuid.UUID, updateData *entity.UpdateUser)
 	}
 
 	// Encrypt the updated fields
-	encryptedFirstName, err := encryptString(*updateData.FirstName)
+	encryptedFirstName, err := encryptString("first_name", user.UUID, *updateData.FirstName)
 	if err != nil {
 		log.Printf("error while encrypting the first name: %s", err.Error())
 		return http.StatusInternalServerError, err
 	}
-	encryptedLastName, err := encryptString(*updateData.LastName)
+	encryptedLastName, err := encryptString("last_name", user.UUID, *updateData.LastName)
 	if err != nil {
 		log.Printf("error while encrypting the last name: %s", err.Error())
 		return http.StatusInternalServerError, err
@@ -219,14 +292,29 @@ func (s *service) UpdateUser(userUUID uuid.UUID, updateData *entity.UpdateUser)
 	user.DateOfBirth = dateOfBirth
 	user.Sex = *updateData.Sex
 	user.UserType = *updateData.UserType
+	// A new city is geocoded again by the forecast worker
+	if user.City != *updateData.City || user.Country != *updateData.Country {
+		user.LocationID = nil
+	}
 	user.City = *updateData.City
 	user.Country = *updateData.Country
+	if updateData.Timezone != nil {
+		if _, err := dates.ParseTimezone(*updateData.Timezone); err != nil {
+			return http.StatusBadRequest, err
+		}
+		user.Timezone = *updateData.Timezone
+		user.TimezoneSetByUser = true
+	}
 
 	err = s.repo.Update(user)
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 
+	if err := s.indexUserName(user.ID, *updateData.FirstName, *updateData.LastName); err != nil {
+		log.Printf("error while indexing the user name: %s", err.Error())
+	}
+
 	return http.StatusOK, nil
 }
 
@@ -238,19 +326,19 @@ func (s *service) GetUser(userUUID uuid.UUID) (*entity.User, error) {
 		return nil, err
 	}
 
-	decryptedFirstName, err := decryptString(user.FirstName)
+	decryptedFirstName, err := decryptString("first_name", user.UUID, user.FirstName)
 	if err != nil {
 		log.Printf("error while decrypting the first name: %s", err.Error())
 		return nil, err
 	}
 
-	decryptedLastName, err := decryptString(user.LastName)
+	decryptedLastName, err := decryptString("last_name", user.UUID, user.LastName)
 	if err != nil {
 		log.Printf("error while decrypting the last name: %s", err.Error())
 		return nil, err
 	}
 
-	decryptedProfileImage, err := decryptString(user.ProfileImage)
+	decryptedProfileImage, err := decryptString("profile_image", user.UUID, user.ProfileImage)
 	if err != nil {
 		log.Printf("error while decrypting the profile image: %s", err.Error())
 		return nil, err
@@ -349,45 +437,20 @@ func (s *service) GetRole(roleID int) (*entity.Role, error) {
 	return role, nil
 }
 
-func encryptString(text string) (string, error) {
-	plaintext := []byte(text)
-	block, err := aes.NewCipher([]byte(config.Get().EncryptionKey))
+// encryptString encrypts a personal data column of the user with the primary key of the keyring.
+func encryptString(column string, userUUID uuid.UUID, text string) (string, error) {
+	keyring, err := fieldcrypt.FromConfig(config.Get())
 	if err != nil {
 		return "", err
 	}
-
-	ciphertext := make([]byte, aes.BlockSize+len(plaintext))
-	iv := ciphertext[:aes.BlockSize]
-	if _, err := rand.Read(iv); err != nil {
-		return "", err
-	}
-
-	stream := cipher.NewCFBEncrypter(block, iv)
-	stream.XORKeyStream(ciphertext[aes.BlockSize:], plaintext)
-
-	return base64.URLEncoding.EncodeToString(ciphertext), nil
+	return keyring.Encrypt(fieldcrypt.UserField(column, userUUID), text)
 }
 
-func decryptString(ciphertext string) (string, error) {
-	data, err := base64.URLEncoding.DecodeString(ciphertext)
-	if err != nil {
-		return "", err
-	}
-
-	block, err := aes.NewCipher([]byte(config.Get().EncryptionKey))
+// decryptString decrypts a personal data column of the user written with any key of the keyring.
+func decryptString(column string, userUUID uuid.UUID, ciphertext string) (string, error) {
+	keyring, err := fieldcrypt.FromConfig(config.Get())
 	if err != nil {
 		return "", err
 	}
-
-	if len(data) < aes.BlockSize {
-		return "", fmt.Errorf("ciphertext too short")
-	}
-
-	iv := data[:aes.BlockSize]
-	ciphertext = ciphertext[aes.BlockSize:]
-
-	stream := cipher.NewCFBDecrypter(block, iv)
-	stream.XORKeyStream(data[aes.BlockSize:], data[aes.BlockSize:])
-
-	return string(data[aes.BlockSize:]), nil
+	return keyring.Decrypt(fieldcrypt.UserField(column, userUUID), ciphertext)
 }
@@ -10,10 +10,13 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/emur-uy/backend/internal/infra/mailer"
 	"github.com/emur-uy/backend/internal/pkg/entity"
+	"github.com/emur-uy/backend/internal/pkg/ports"
 	"github.com/emur-uy/backend/internal/pkg/service/user"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 // Define a test UUID for testing purposes.
@@ -28,6 +31,11 @@ const testPassword = "$2a$08$dawrvtxhIXVQZ0pz7o809uSsNkSaZp2gW2vwNQERHn37bwFOWIk
 // MockUserRepository is a mock implementation of the UserRepository interface for testing.
 type MockUserRepository struct{}
 
+// Create is a mock implementation of the Create method.
+func (m *MockUserRepository) Create(value interface{}) error {
+	return nil
+}
+
 // CreateWithOmit is a mock implementation of the CreateWithOmit method.
 func (m *MockUserRepository) CreateWithOmit(omit string, value interface{}) error {
 	return nil
@@ -74,7 +82,7 @@ func (m *MockUserRepository) First(out interface{}, conditions ...interface{}) e
 		out.(*entity.Role).ID = 1
 		return nil
 	}
-	return errors.New("not found")
+	return gorm.ErrRecordNotFound
 }
 
 // UpdateColumns is a mock implementation of the UpdateColumns method.
@@ -82,6 +90,26 @@ func (m *MockUserRepository) UpdateColumns(value interface{}, column string, upd
 	return nil
 }
 
+// FindUsersAfterID is a mock implementation of the FindUsersAfterID method.
+func (m *MockUserRepository) FindUsersAfterID(afterID, limit int) ([]entity.User, error) {
+	return nil, nil
+}
+
+// UpdateColumnsWhere is a mock implementation of the UpdateColumnsWhere method.
+func (m *MockUserRepository) UpdateColumnsWhere(model interface{}, conditions map[string]interface{}, columns map[string]interface{}) (int64, error) {
+	return 0, nil
+}
+
+// ReplaceSearchIndexes is a mock implementation of the ReplaceSearchIndexes method.
+func (m *MockUserRepository) ReplaceSearchIndexes(userID int, indexes []entity.UserSearchIndex) error {
+	return nil
+}
+
+// SearchUsers is a mock implementation of the SearchUsers method.
+func (m *MockUserRepository) SearchUsers(filter *entity.UserSearchFilter) ([]entity.User, int64, error) {
+	return nil, 0, nil
+}
+
 // FindByUUID is a mock implementation of the FindByUUID method.
 func (m *MockUserRepository) FindByUUID(userUUID uuid.UUID, out interface{}) (interface{}, error) {
 	if userUUID == testUuid {
@@ -165,7 +193,7 @@ func TestMain(m *testing.M) {
 func TestLogin(t *testing.T) {
 	// Set up the mock repository and service.
 	mockRepo := &MockUserRepository{}
-	s := user.NewService(mockRepo)
+	s := user.NewService(mockRepo, newMockTokenRepository(), newMockLoginAttemptRepository(), newMockRoleRepository(), mailer.NewMemoryMailer())
 
 	// Define test cases.
 	testCases := []struct {
@@ -175,7 +203,7 @@ func TestLogin(t *testing.T) {
 	}{
 		{"successful login", &entity.DefaultCredentials{Email: "test@gmail.com", Password: "password"}, false},
 		{"failed login - incorrect email", &entity.DefaultCredentials{Email: "nonexistent@example.com", Password: "password"}, true},
-		{"failed login - incorrect password", &entity.DefaultCredentials{Email: "test@example.com", Password: "wrong_password"}, true},
+		{"failed login - incorrect password", &entity.DefaultCredentials{Email: testEmail, Password: "wrong_password"}, true},
 	}
 
 	// Execute test cases.
@@ -183,11 +211,12 @@ func TestLogin(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// Call the Login method with the test case's credentials.
-			token, err := s.Login(tc.credentials)
+			token, status, err := s.Login(tc.credentials, testIP)
 			// Check the result based on the test case's expected error state.
 			if tc.expectError {
-				// If an error is expected, ensure there is an error returned and the token is empty.
-				require.Error(t, err)
+				// If an error is expected, ensure the same error is returned for an unknown email and a wrong password.
+				require.ErrorIs(t, err, ports.ErrInvalidCredentials)
+				assert.Equal(t, http.StatusBadRequest, status)
 				assert.Empty(t, token)
 			} else {
 				// If no error is expected, ensure there is no error returned and the token is not empty.
@@ -202,7 +231,7 @@ func TestLogin(t *testing.T) {
 func TestCreateUser(t *testing.T) {
 	// Initialize the mock repository and service.
 	mockRepo := &MockUserRepository{}
-	s := user.NewService(mockRepo)
+	s := user.NewService(mockRepo, newMockTokenRepository(), newMockLoginAttemptRepository(), newMockRoleRepository(), mailer.NewMemoryMailer())
 
 	// Case 1: Valid user, should return HTTP status 201.
 	u := &entity.User{
@@ -230,7 +259,7 @@ func TestCreateUser(t *testing.T) {
 func TestUpdateUser(t *testing.T) {
 	// Initialize the mock repository and service.
 	mockRepo := &MockUserRepository{}
-	s := user.NewService(mockRepo)
+	s := user.NewService(mockRepo, newMockTokenRepository(), newMockLoginAttemptRepository(), newMockRoleRepository(), mailer.NewMemoryMailer())
 
 	dateOfBirth := time.Now().String()
 
@@ -272,7 +301,7 @@ func TestUpdateUser(t *testing.T) {
 func TestGetUser(t *testing.T) {
 	// Initialize the mock repository and service.
 	mockRepo := &MockUserRepository{}
-	s := user.NewService(mockRepo)
+	s := user.NewService(mockRepo, newMockTokenRepository(), newMockLoginAttemptRepository(), newMockRoleRepository(), mailer.NewMemoryMailer())
 
 	// Test case 1: user found
 	mockUser, err := s.GetUser(testUuid)
@@ -288,7 +317,7 @@ func TestGetUser(t *testing.T) {
 
 func TestUpdateActiveStatus(t *testing.T) {
 	mockRepo := &MockUserRepository{}
-	s := user.NewService(mockRepo)
+	s := user.NewService(mockRepo, newMockTokenRepository(), newMockLoginAttemptRepository(), newMockRoleRepository(), mailer.NewMemoryMailer())
 	// Test case 1: user found and updated successfully
 	status, err := s.UpdateActiveStatus(testUuid, true)
 	assert.Nil(t, err)
@@ -302,7 +331,7 @@ func TestUpdateActiveStatus(t *testing.T) {
 
 func TestUpdateBannedStatus(t *testing.T) {
 	mockRepo := &MockUserRepository{}
-	s := user.NewService(mockRepo)
+	s := user.NewService(mockRepo, newMockTokenRepository(), newMockLoginAttemptRepository(), newMockRoleRepository(), mailer.NewMemoryMailer())
 	// Test case 1: user found and updated successfully
 	status, err := s.UpdateBannedStatus(testUuid, true) //some random non-existing UUID
 	assert.Nil(t, err)
@@ -316,7 +345,7 @@ func TestUpdateBannedStatus(t *testing.T) {
 
 func TestGetUserRole(t *testing.T) {
 	mockRepo := &MockUserRepository{}
-	s := user.NewService(mockRepo)
+	s := user.NewService(mockRepo, newMockTokenRepository(), newMockLoginAttemptRepository(), newMockRoleRepository(), mailer.NewMemoryMailer())
 	// Test case 1: user role found
 	mockUserRole, err := s.GetUserRole(1)
 	assert.Nil(t, err)
@@ -330,7 +359,7 @@ func TestGetUserRole(t *testing.T) {
 
 func TestGetRole(t *testing.T) {
 	mockRepo := &MockUserRepository{}
-	s := user.NewService(mockRepo)
+	s := user.NewService(mockRepo, newMockTokenRepository(), newMockLoginAttemptRepository(), newMockRoleRepository(), mailer.NewMemoryMailer())
 	// Test case 1: role found
 	mockRole, err := s.GetRole(1)
 	assert.Nil(t, err)
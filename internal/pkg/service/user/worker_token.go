@@ -0,0 +1,29 @@
+package user
+
+import (
+	"fmt"
+	"time"
+)
+
+type Worker struct {
+	service *service
+}
+
+func NewWorker(service *service) *Worker {
+	return &Worker{
+		service: service,
+	}
+}
+
+// PurgeExpiredTokens deletes the refresh tokens and revoked access tokens that already expired.
+func (w *Worker) PurgeExpiredTokens() {
+	deleted, err := w.service.PurgeExpiredTokens(time.Now())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if deleted > 0 {
+		fmt.Printf("Purged %d expired tokens\n", deleted)
+	}
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS reminder_notifications;
\ No newline at end of file
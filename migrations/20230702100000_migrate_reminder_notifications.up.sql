@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS reminder_notifications (
+    id BIGSERIAL PRIMARY KEY,
+    reminder_id INT NOT NULL,
+    notify_at TIMESTAMP NOT NULL,
+    days_or_hours VARCHAR(20) NOT NULL,
+    hours_before INT NOT NULL,
+    sent_at TIMESTAMP DEFAULT NULL,
+    -- The time a worker claimed the notification, the claims left unsent are taken over once they are stale.
+    claimed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT FK_reminder_notification FOREIGN KEY(reminder_id)
+    REFERENCES reminders(id) ON DELETE CASCADE,
+
+    CONSTRAINT UQ_reminder_notification UNIQUE (reminder_id, notify_at)
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS treatment_doses;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS treatment_doses (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    uuid UUID NOT NULL DEFAULT gen_random_uuid(),
+    treatment_id INT NOT NULL,
+    scheduled_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT FK_treatment_dose FOREIGN KEY(treatment_id)
+    REFERENCES treatments(id) ON DELETE CASCADE,
+
+    CONSTRAINT UQ_treatment_dose UNIQUE (treatment_id, scheduled_at)
+);
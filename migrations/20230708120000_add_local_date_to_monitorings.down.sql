@@ -0,0 +1,2 @@
+ALTER TABLE monitorings DROP CONSTRAINT IF EXISTS UQ_monitoring_local_date;
+ALTER TABLE monitorings DROP COLUMN IF EXISTS local_date;
\ No newline at end of file
@@ -0,0 +1,18 @@
+ALTER TABLE monitorings ADD COLUMN local_date DATE;
+
+-- Monitoring dates are stored in UTC, the local date is the calendar day in the user's timezone.
+UPDATE monitorings m
+SET local_date = (m.date AT TIME ZONE 'UTC' AT TIME ZONE u.timezone)::DATE
+FROM users u
+WHERE u.id = m.user_id;
+
+-- Keep only the latest record of each user, symptom and local date.
+DELETE FROM monitorings m
+USING monitorings newer
+WHERE m.user_id = newer.user_id
+    AND m.symptom_id = newer.symptom_id
+    AND m.local_date = newer.local_date
+    AND (m.date < newer.date OR (m.date = newer.date AND m.id < newer.id));
+
+ALTER TABLE monitorings ALTER COLUMN local_date SET NOT NULL;
+ALTER TABLE monitorings ADD CONSTRAINT UQ_monitoring_local_date UNIQUE (user_id, symptom_id, local_date);
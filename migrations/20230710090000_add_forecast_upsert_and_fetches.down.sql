@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS forecast_fetches;
+ALTER TABLE forecasts DROP CONSTRAINT IF EXISTS UQ_forecast_location_date;
\ No newline at end of file
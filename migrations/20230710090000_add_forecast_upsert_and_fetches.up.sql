@@ -0,0 +1,22 @@
+-- Keep only the latest forecast of each location and date before enforcing uniqueness.
+DELETE FROM forecasts f
+USING forecasts newer
+WHERE f.country = newer.country
+    AND f.state = newer.state
+    AND f.date = newer.date
+    AND f.id < newer.id;
+
+ALTER TABLE forecasts ADD CONSTRAINT UQ_forecast_location_date UNIQUE (country, state, date);
+
+CREATE TABLE IF NOT EXISTS forecast_fetches (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    country VARCHAR(255) NOT NULL,
+    state VARCHAR(255) NOT NULL,
+    last_success_at TIMESTAMP NULL DEFAULT NULL,
+    last_attempt_at TIMESTAMP NULL DEFAULT NULL,
+    next_attempt_at TIMESTAMP NULL DEFAULT NULL,
+    failures INT NOT NULL DEFAULT 0,
+    last_error TEXT NULL DEFAULT NULL,
+
+    CONSTRAINT UQ_forecast_fetch_location UNIQUE (country, state)
+);
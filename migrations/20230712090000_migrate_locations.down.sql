@@ -0,0 +1,11 @@
+DELETE FROM forecast_fetches;
+ALTER TABLE forecast_fetches DROP CONSTRAINT IF EXISTS UQ_forecast_fetch_location;
+ALTER TABLE forecast_fetches DROP COLUMN IF EXISTS location_id;
+ALTER TABLE forecast_fetches ADD CONSTRAINT UQ_forecast_fetch_location UNIQUE (country, state);
+DELETE FROM forecasts WHERE location_id IS NOT NULL;
+ALTER TABLE forecasts DROP CONSTRAINT IF EXISTS UQ_forecast_location_date;
+ALTER TABLE forecasts DROP COLUMN IF EXISTS location_id;
+ALTER TABLE forecasts ADD CONSTRAINT UQ_forecast_location_date UNIQUE (country, state, date);
+ALTER TABLE users DROP COLUMN IF EXISTS timezone_set_by_user;
+ALTER TABLE users DROP COLUMN IF EXISTS location_id;
+DROP TABLE IF EXISTS locations;
\ No newline at end of file
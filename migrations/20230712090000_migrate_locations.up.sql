@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS locations (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    city VARCHAR(255) NOT NULL,
+    country VARCHAR(255) NOT NULL,
+    latitude DOUBLE PRECISION NOT NULL,
+    longitude DOUBLE PRECISION NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT UQ_location_coordinates UNIQUE (latitude, longitude)
+);
+
+-- Users are linked to their location by the forecast worker once their city is geocoded.
+ALTER TABLE users ADD COLUMN location_id INT NULL DEFAULT NULL;
+ALTER TABLE users ADD CONSTRAINT FK_user_location FOREIGN KEY(location_id) REFERENCES locations(id);
+-- The timezone of the users follows their location until they choose one.
+ALTER TABLE users ADD COLUMN timezone_set_by_user BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Forecasts are keyed by location, the rows stored before the locations existed keep a NULL location.
+ALTER TABLE forecasts ADD COLUMN location_id INT NULL DEFAULT NULL;
+ALTER TABLE forecasts ADD CONSTRAINT FK_forecast_location FOREIGN KEY(location_id) REFERENCES locations(id) ON DELETE CASCADE;
+ALTER TABLE forecasts DROP CONSTRAINT IF EXISTS UQ_forecast_location_date;
+ALTER TABLE forecasts ADD CONSTRAINT UQ_forecast_location_date UNIQUE (location_id, date);
+
+-- The fetch states are rebuilt per location by the next worker run.
+DELETE FROM forecast_fetches;
+ALTER TABLE forecast_fetches DROP CONSTRAINT IF EXISTS UQ_forecast_fetch_location;
+ALTER TABLE forecast_fetches ADD COLUMN location_id INT NOT NULL;
+ALTER TABLE forecast_fetches ADD CONSTRAINT FK_forecast_fetch_location FOREIGN KEY(location_id) REFERENCES locations(id) ON DELETE CASCADE;
+ALTER TABLE forecast_fetches ADD CONSTRAINT UQ_forecast_fetch_location UNIQUE (location_id);
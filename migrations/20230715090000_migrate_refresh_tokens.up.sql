@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    user_id INT NOT NULL,
+    family_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT FK_user FOREIGN KEY(user_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    CONSTRAINT UQ_refresh_token_hash UNIQUE (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_refresh_token_family ON refresh_tokens(family_id);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    jti VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT UQ_revoked_token_jti UNIQUE (jti)
+);
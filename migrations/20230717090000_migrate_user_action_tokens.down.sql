@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS user_action_tokens;
+
+ALTER TABLE users DROP COLUMN IF EXISTS tokens_valid_after;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
\ No newline at end of file
@@ -0,0 +1,22 @@
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP NULL DEFAULT NULL;
+
+-- The access tokens issued before this time are rejected, it is set when the password is reset.
+ALTER TABLE users ADD COLUMN tokens_valid_after TIMESTAMP NULL DEFAULT NULL;
+
+-- Users registered before the verification flow existed are considered verified.
+UPDATE users SET email_verified_at = created_at;
+
+CREATE TABLE IF NOT EXISTS user_action_tokens (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    user_id INT NOT NULL,
+    purpose VARCHAR(32) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT FK_user FOREIGN KEY(user_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    CONSTRAINT UQ_user_action_token_hash UNIQUE (token_hash)
+);
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS login_attempts (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    user_id INT NULL,
+    email VARCHAR(255) NOT NULL,
+    ip VARCHAR(64) NOT NULL,
+    success BOOLEAN NOT NULL DEFAULT FALSE,
+    reason VARCHAR(32) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT FK_user FOREIGN KEY(user_id)
+    REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS IDX_login_attempt_user ON login_attempts(user_id, created_at);
+CREATE INDEX IF NOT EXISTS IDX_login_attempt_email ON login_attempts(email, created_at);
+
+-- Failure counters keyed by "email:<email>" or "ip:<ip>".
+CREATE TABLE IF NOT EXISTS login_throttles (
+    key VARCHAR(300) NOT NULL PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP NULL DEFAULT NULL
+);
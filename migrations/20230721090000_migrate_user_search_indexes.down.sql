@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS IDX_user_email_lower;
+
+DROP TABLE IF EXISTS user_search_indexes;
\ No newline at end of file
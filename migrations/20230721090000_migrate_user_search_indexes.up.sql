@@ -0,0 +1,15 @@
+-- Blind indexes of the encrypted names: HMACs of the normalized words and their prefixes.
+CREATE TABLE IF NOT EXISTS user_search_indexes (
+    user_id INT NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    hash CHAR(32) NOT NULL,
+
+    CONSTRAINT FK_user FOREIGN KEY(user_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    PRIMARY KEY (user_id, kind, hash)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_user_search_index_hash ON user_search_indexes(kind, hash);
+
+CREATE INDEX IF NOT EXISTS IDX_user_email_lower ON users(LOWER(email) text_pattern_ops);
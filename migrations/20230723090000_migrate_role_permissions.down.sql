@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS role_permissions;
+
+ALTER TABLE role_user DROP CONSTRAINT IF EXISTS UQ_role_user;
+
+ALTER TABLE roles DROP CONSTRAINT IF EXISTS UQ_role;
+
+ALTER TABLE roles DROP COLUMN IF EXISTS description;
\ No newline at end of file
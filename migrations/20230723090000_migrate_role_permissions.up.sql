@@ -0,0 +1,48 @@
+ALTER TABLE roles ADD COLUMN description VARCHAR(255) NOT NULL DEFAULT '';
+
+INSERT INTO roles (role) SELECT 'admin' WHERE NOT EXISTS (SELECT 1 FROM roles WHERE role = 'admin');
+INSERT INTO roles (role) SELECT 'user' WHERE NOT EXISTS (SELECT 1 FROM roles WHERE role = 'user');
+
+ALTER TABLE roles ADD CONSTRAINT UQ_role UNIQUE (role);
+
+UPDATE roles SET description = 'Administrador' WHERE role = 'admin';
+UPDATE roles SET description = 'Paciente' WHERE role = 'user';
+
+-- A user holds each role once.
+DELETE FROM role_user a USING role_user b WHERE a.id > b.id AND a.user_id = b.user_id AND a.role_id = b.role_id;
+
+ALTER TABLE role_user ADD CONSTRAINT UQ_role_user UNIQUE (user_id, role_id);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id INT NOT NULL,
+    permission VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT FK_role FOREIGN KEY(role_id)
+    REFERENCES roles(id) ON DELETE CASCADE,
+
+    PRIMARY KEY (role_id, permission)
+);
+
+-- The permissions of the admin and user roles match the routes they could access before.
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, permission FROM roles, UNNEST(ARRAY[
+    'articles:read', 'articles:write', 'categories:write',
+    'recipes:read', 'recipes:write', 'recipes:vote',
+    'maps:read', 'maps:write',
+    'medical:read', 'medical:import',
+    'healthservices:read', 'healthservices:write',
+    'questions:read', 'questions:write', 'answers:write',
+    'symptoms:read', 'symptoms:write',
+    'users:manage', 'roles:manage'
+]) AS permission
+WHERE roles.role = 'admin';
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, permission FROM roles, UNNEST(ARRAY[
+    'articles:read', 'recipes:read', 'recipes:vote', 'maps:read',
+    'medical:read', 'medical:rate', 'healthservices:read', 'healthservices:rate',
+    'questions:read', 'questions:write', 'answers:write',
+    'symptoms:read', 'patient:self'
+]) AS permission
+WHERE roles.role = 'user';
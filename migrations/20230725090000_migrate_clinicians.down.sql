@@ -0,0 +1,7 @@
+DELETE FROM roles WHERE role = 'clinician';
+
+DROP TABLE IF EXISTS patient_access_logs;
+
+DROP TABLE IF EXISTS patient_consents;
+
+DROP TABLE IF EXISTS clinicians;
\ No newline at end of file
@@ -0,0 +1,70 @@
+CREATE TABLE IF NOT EXISTS clinicians (
+    id SERIAL NOT NULL PRIMARY KEY,
+    uuid UUID NOT NULL DEFAULT gen_random_uuid(),
+    user_id INT NOT NULL,
+    medical_id BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT FK_user FOREIGN KEY(user_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    CONSTRAINT FK_medical FOREIGN KEY(medical_id)
+    REFERENCES medicals(id) ON DELETE CASCADE,
+
+    CONSTRAINT UQ_clinician_uuid UNIQUE (uuid),
+    CONSTRAINT UQ_clinician_user UNIQUE (user_id),
+    CONSTRAINT UQ_clinician_medical UNIQUE (medical_id)
+);
+
+CREATE TABLE IF NOT EXISTS patient_consents (
+    id SERIAL NOT NULL PRIMARY KEY,
+    uuid UUID NOT NULL DEFAULT gen_random_uuid(),
+    patient_id INT NOT NULL,
+    clinician_id INT NOT NULL,
+    scopes JSONB NOT NULL DEFAULT '[]',
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT FK_patient FOREIGN KEY(patient_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    CONSTRAINT FK_clinician FOREIGN KEY(clinician_id)
+    REFERENCES clinicians(id) ON DELETE CASCADE,
+
+    CONSTRAINT UQ_patient_consent_uuid UNIQUE (uuid)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_patient_consent_clinician ON patient_consents(clinician_id, patient_id);
+CREATE INDEX IF NOT EXISTS IDX_patient_consent_patient ON patient_consents(patient_id);
+
+-- Append-only record of the clinicians accessing the patient data, kept after the consent is revoked.
+CREATE TABLE IF NOT EXISTS patient_access_logs (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    clinician_id INT NOT NULL,
+    patient_id INT NOT NULL,
+    consent_id INT NULL,
+    resource VARCHAR(32) NOT NULL,
+    resource_uuid UUID NULL,
+    granted BOOLEAN NOT NULL DEFAULT FALSE,
+    ip VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT FK_clinician FOREIGN KEY(clinician_id)
+    REFERENCES clinicians(id) ON DELETE CASCADE,
+
+    CONSTRAINT FK_patient FOREIGN KEY(patient_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    CONSTRAINT FK_consent FOREIGN KEY(consent_id)
+    REFERENCES patient_consents(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS IDX_patient_access_log_patient ON patient_access_logs(patient_id, created_at);
+
+INSERT INTO roles (role, description) VALUES ('clinician', 'Neurólogo tratante') ON CONFLICT (role) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, permission FROM roles, UNNEST(ARRAY['patients:read', 'medical:read']) AS permission
+WHERE roles.role = 'clinician'
+ON CONFLICT DO NOTHING;
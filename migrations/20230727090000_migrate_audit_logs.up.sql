@@ -0,0 +1,33 @@
+-- The actor is kept as a UUID without a foreign key, so the audit trail outlives the users.
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    actor_uuid UUID NULL,
+    action VARCHAR(16) NOT NULL,
+    resource_type VARCHAR(32) NOT NULL,
+    resource_uuid UUID NULL,
+    method VARCHAR(8) NOT NULL,
+    route VARCHAR(255) NOT NULL DEFAULT '',
+    ip VARCHAR(64) NOT NULL DEFAULT '',
+    outcome VARCHAR(16) NOT NULL,
+    status INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS IDX_audit_log_actor ON audit_logs(actor_uuid, created_at);
+CREATE INDEX IF NOT EXISTS IDX_audit_log_resource ON audit_logs(resource_type, resource_uuid, created_at);
+CREATE INDEX IF NOT EXISTS IDX_audit_log_created_at ON audit_logs(created_at);
+
+-- The audit log is append-only.
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER TRG_audit_logs_append_only
+BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_logs
+FOR EACH STATEMENT EXECUTE FUNCTION audit_logs_append_only();
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, 'audit:read' FROM roles WHERE roles.role = 'admin'
+ON CONFLICT DO NOTHING;
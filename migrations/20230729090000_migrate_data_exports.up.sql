@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS data_exports (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    uuid UUID NOT NULL DEFAULT gen_random_uuid(),
+    user_id INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    error TEXT DEFAULT NULL,
+    archive BYTEA DEFAULT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP DEFAULT NULL,
+    completed_at TIMESTAMP DEFAULT NULL,
+    expires_at TIMESTAMP DEFAULT NULL,
+
+    CONSTRAINT UQ_data_exports_uuid UNIQUE(uuid),
+
+    CONSTRAINT FK_user FOREIGN KEY(user_id)
+    REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_data_exports_user ON data_exports(user_id, created_at);
+CREATE INDEX IF NOT EXISTS IDX_data_exports_status ON data_exports(status);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS account_erasures;
\ No newline at end of file
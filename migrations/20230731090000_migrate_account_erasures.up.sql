@@ -0,0 +1,25 @@
+-- The user is kept as a UUID next to the ID, so the erasure report outlives the personal data.
+CREATE TABLE IF NOT EXISTS account_erasures (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    uuid UUID NOT NULL DEFAULT gen_random_uuid(),
+    user_id INT NOT NULL,
+    user_uuid UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    error TEXT DEFAULT NULL,
+    report JSONB DEFAULT NULL,
+    requested_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    scheduled_at TIMESTAMP NOT NULL,
+    cancelled_at TIMESTAMP DEFAULT NULL,
+    completed_at TIMESTAMP DEFAULT NULL,
+
+    CONSTRAINT UQ_account_erasures_uuid UNIQUE(uuid),
+
+    CONSTRAINT FK_user FOREIGN KEY(user_id)
+    REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_account_erasures_user ON account_erasures(user_uuid, requested_at);
+CREATE INDEX IF NOT EXISTS IDX_account_erasures_due ON account_erasures(status, scheduled_at);
+
+-- A user has at most one scheduled erasure.
+CREATE UNIQUE INDEX IF NOT EXISTS UQ_account_erasures_scheduled ON account_erasures(user_id) WHERE status = 'scheduled';
@@ -0,0 +1,9 @@
+DELETE FROM role_permissions WHERE permission = 'trash:manage';
+
+DROP INDEX IF EXISTS IDX_reminders_deleted_at;
+DROP INDEX IF EXISTS IDX_articles_deleted_at;
+DROP INDEX IF EXISTS IDX_recipes_deleted_at;
+
+ALTER TABLE reminders DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE articles DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE recipes DROP COLUMN IF EXISTS deleted_at;
\ No newline at end of file
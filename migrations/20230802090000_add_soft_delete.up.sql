@@ -0,0 +1,12 @@
+-- The deleted reminders, articles and recipes are kept in the trash until purged.
+ALTER TABLE reminders ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS IDX_reminders_deleted_at ON reminders (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS IDX_articles_deleted_at ON articles (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS IDX_recipes_deleted_at ON recipes (deleted_at) WHERE deleted_at IS NOT NULL;
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, 'trash:manage' FROM roles WHERE roles.role = 'admin'
+ON CONFLICT DO NOTHING;
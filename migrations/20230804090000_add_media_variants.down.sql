@@ -0,0 +1,6 @@
+ALTER TABLE media ALTER COLUMN media_thumb DROP DEFAULT;
+
+ALTER TABLE media DROP COLUMN IF EXISTS height;
+ALTER TABLE media DROP COLUMN IF EXISTS width;
+ALTER TABLE media DROP COLUMN IF EXISTS content_type;
+ALTER TABLE media DROP COLUMN IF EXISTS media_webp;
\ No newline at end of file
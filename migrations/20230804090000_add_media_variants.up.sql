@@ -0,0 +1,8 @@
+-- The WebP variant and the properties of the images, read on upload.
+ALTER TABLE media ADD COLUMN IF NOT EXISTS media_webp TEXT NOT NULL DEFAULT '';
+ALTER TABLE media ADD COLUMN IF NOT EXISTS content_type VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE media ADD COLUMN IF NOT EXISTS width INT NOT NULL DEFAULT 0;
+ALTER TABLE media ADD COLUMN IF NOT EXISTS height INT NOT NULL DEFAULT 0;
+
+-- The thumbnail is empty for the media uploaded before the thumbnails were generated.
+ALTER TABLE media ALTER COLUMN media_thumb SET DEFAULT '';
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS upload_sessions (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    uuid UUID NOT NULL DEFAULT gen_random_uuid(),
+    user_id INT NOT NULL,
+    domain VARCHAR(20) NOT NULL,
+    record_id INT NOT NULL,
+    key TEXT NOT NULL,
+    content_type VARCHAR(64) NOT NULL,
+    size BIGINT NOT NULL,
+    upload_id TEXT NOT NULL DEFAULT '',
+    part_size BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    error TEXT DEFAULT NULL,
+    media_id INT DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    completed_at TIMESTAMP DEFAULT NULL,
+
+    CONSTRAINT UQ_upload_sessions_uuid UNIQUE(uuid),
+
+    CONSTRAINT FK_user FOREIGN KEY(user_id)
+    REFERENCES users(id),
+
+    -- The media can be purged from the trash after the upload
+    CONSTRAINT FK_media FOREIGN KEY(media_id)
+    REFERENCES media(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS IDX_upload_sessions_user ON upload_sessions(user_id, uuid);
+CREATE INDEX IF NOT EXISTS IDX_upload_sessions_expiry ON upload_sessions(status, expires_at);